@@ -4,6 +4,7 @@ import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getVariableValues(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,15 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+// The server validates the values of the variables against their options and interpolates them into the queries
+function getVariableValues(): Record<string, string[]> {
+  const variables: Record<string, string[]> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if ('current' in variable && variable.current?.value !== undefined) {
+      const value = variable.current.value;
+      variables[variable.name] = Array.isArray(value) ? value : [value];
+    }
+  }
+  return variables;
+}
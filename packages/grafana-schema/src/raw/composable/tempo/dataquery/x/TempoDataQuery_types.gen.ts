@@ -26,6 +26,10 @@ export interface TempoQuery extends common.DataQuery {
    * @deprecated Define the maximum duration to select traces. Use duration format, for example: 1.2s, 100ms
    */
   maxDuration?: string;
+  /**
+   * The type of the metrics query, range or instant. TraceQL queries are only run as metrics queries when it is set
+   */
+  metricsQueryType?: MetricsQueryType;
   /**
    * @deprecated Define the minimum duration to select traces. Use duration format, for example: 1.2s, 100ms
    */
@@ -75,6 +79,14 @@ export const defaultTempoQuery: Partial<TempoQuery> = {
 
 export type TempoQueryType = ('traceql' | 'traceqlSearch' | 'serviceMap' | 'upload' | 'nativeSearch' | 'traceId' | 'clear');
 
+/**
+ * The type of the metrics query, range or instant
+ */
+export enum MetricsQueryType {
+  Instant = 'instant',
+  Range = 'range',
+}
+
 /**
  * The state of the TraceQL streaming search query
  */
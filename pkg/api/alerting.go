@@ -1,11 +1,15 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/plugins"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	ngnotifier "github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func (hs *HTTPServer) GetAlertNotifiers() func(*contextmodel.ReqContext) response.Response {
@@ -13,3 +17,21 @@ func (hs *HTTPServer) GetAlertNotifiers() func(*contextmodel.ReqContext) respons
 		return response.JSON(http.StatusOK, channels_config.GetAvailableNotifiers())
 	}
 }
+
+// GetAlertNotifierPlugin returns the metadata of the notifier implemented by a backend plugin. It describes the
+// settings of the contact points of type plugin that send notifications through the plugin. Only plugins that declare
+// a notifier are called.
+func (hs *HTTPServer) GetAlertNotifierPlugin(c *contextmodel.ReqContext) response.Response {
+	if hs.AlertNG == nil || hs.AlertNG.PluginNotifications == nil {
+		return response.Error(http.StatusNotFound, "Plugin notifiers are not supported", nil)
+	}
+	pluginID := web.Params(c.Req)[":pluginId"]
+	notifier, err := hs.AlertNG.PluginNotifications.GetNotifier(c.Req.Context(), c.SignedInUser.GetOrgID(), pluginID)
+	if err != nil {
+		if errors.Is(err, plugins.ErrPluginNotRegistered) || errors.Is(err, ngnotifier.ErrNotNotifierPlugin) {
+			return response.Error(http.StatusNotFound, "Plugin not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get notifier of plugin", err)
+	}
+	return response.JSON(http.StatusOK, notifier)
+}
@@ -3,9 +3,14 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -22,7 +27,10 @@ import (
 	"github.com/grafana/grafana/pkg/web"
 )
 
-const defaultAnnotationsLimit = 100
+const (
+	defaultAnnotationsLimit   = 100
+	maxAnnotationStatsBuckets = 10000
+)
 
 // swagger:route GET /annotations annotations getAnnotations
 //
@@ -48,6 +56,7 @@ func (hs *HTTPServer) GetAnnotations(c *contextmodel.ReqContext) response.Respon
 		Tags:         c.QueryStrings("tags"),
 		Type:         c.Query("type"),
 		MatchAny:     c.QueryBool("matchAny"),
+		Text:         c.Query("text"),
 		SignedInUser: c.SignedInUser,
 	}
 	if query.Limit == 0 {
@@ -582,6 +591,193 @@ func (hs *HTTPServer) GetAnnotationTags(c *contextmodel.ReqContext) response.Res
 	return response.JSON(http.StatusOK, annotations.GetAnnotationTagsResponse{Result: result})
 }
 
+// swagger:route GET /annotations/stats annotations getAnnotationStats
+//
+// Get annotation statistics.
+//
+// Counts the annotations that match the filters per tag, per dashboard and per time bucket, and sums the durations of the region annotations.
+//
+// Responses:
+// 200: getAnnotationStatsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationStats(c *contextmodel.ReqContext) response.Response {
+	query := &annotations.StatsQuery{
+		ItemQuery: annotations.ItemQuery{
+			From:         c.QueryInt64("from"),
+			To:           c.QueryInt64("to"),
+			OrgID:        c.SignedInUser.GetOrgID(),
+			UserID:       c.QueryInt64("userId"),
+			AlertID:      c.QueryInt64("alertId"),
+			DashboardID:  c.QueryInt64("dashboardId"),
+			DashboardUID: c.Query("dashboardUID"),
+			PanelID:      c.QueryInt64("panelId"),
+			Tags:         c.QueryStrings("tags"),
+			Type:         c.Query("type"),
+			MatchAny:     c.QueryBool("matchAny"),
+			Text:         c.Query("text"),
+			SignedInUser: c.SignedInUser,
+		},
+	}
+
+	if value := c.Query("interval"); value != "" {
+		interval, err := gtime.ParseDuration(value)
+		if err != nil || interval < time.Second {
+			return response.Error(http.StatusBadRequest, "interval must be a duration of at least 1s, for example 1h, 1d or 1w", err)
+		}
+		query.Interval = interval.Milliseconds()
+		if query.From <= 0 || query.To < query.From {
+			return response.Error(http.StatusBadRequest, "from and to are required with interval, and to must not be before from", nil)
+		}
+		if (query.To-query.From)/query.Interval > maxAnnotationStatsBuckets {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("interval is too small, the time range must not have more than %d buckets", maxAnnotationStatsBuckets), nil)
+		}
+	}
+
+	// When dashboard UID present in the request, we ignore dashboard ID
+	if query.DashboardUID != "" {
+		dq := dashboards.GetDashboardQuery{UID: query.DashboardUID, OrgID: c.SignedInUser.GetOrgID()}
+		dqResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dq)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid dashboard UID in annotation request", err)
+		}
+		query.DashboardID = dqResult.ID
+	}
+
+	result, err := hs.annotationsRepo.Stats(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation statistics", err)
+	}
+
+	for _, dashboard := range result.Dashboards {
+		if dashboard.DashboardID == 0 || dashboard.DashboardUID != nil {
+			continue
+		}
+		query := dashboards.GetDashboardQuery{ID: dashboard.DashboardID, OrgID: c.SignedInUser.GetOrgID()}
+		queryResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &query)
+		if err == nil && queryResult != nil {
+			dashboard.DashboardUID = &queryResult.UID
+		}
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /annotations/import annotations importAnnotations
+//
+// Import annotations.
+//
+// Creates annotations in bulk from newline delimited JSON or CSV. Annotations that cannot be read, that the user cannot create
+// or that cannot be saved are skipped, and the first errors are returned with the line they were read from.
+//
+// Responses:
+// 200: importAnnotationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ImportAnnotations(c *contextmodel.ReqContext) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = annotations.FormatNDJSON
+		if mediaType, _, err := mime.ParseMediaType(c.Req.Header.Get("Content-Type")); err == nil && mediaType == "text/csv" {
+			format = annotations.FormatCSV
+		}
+	}
+
+	result, err := hs.annotationsRepo.Import(c.Req.Context(), &annotations.ImportCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		UserID:       c.SignedInUser.UserID,
+		SignedInUser: c.SignedInUser,
+		Format:       format,
+		Reader:       c.Req.Body,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to import annotations", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /annotations/export annotations exportAnnotations
+//
+// Export annotations.
+//
+// Exports all annotations that match the filters as newline delimited JSON, in the format accepted by the import.
+//
+// Produces:
+// - application/x-ndjson
+//
+// Responses:
+// 200: exportAnnotationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ExportAnnotations(c *contextmodel.ReqContext) response.Response {
+	query := &annotations.ItemQuery{
+		From:         c.QueryInt64("from"),
+		To:           c.QueryInt64("to"),
+		OrgID:        c.SignedInUser.GetOrgID(),
+		UserID:       c.QueryInt64("userId"),
+		AlertID:      c.QueryInt64("alertId"),
+		DashboardID:  c.QueryInt64("dashboardId"),
+		DashboardUID: c.Query("dashboardUID"),
+		PanelID:      c.QueryInt64("panelId"),
+		Tags:         c.QueryStrings("tags"),
+		Type:         c.Query("type"),
+		MatchAny:     c.QueryBool("matchAny"),
+		Text:         c.Query("text"),
+		SignedInUser: c.SignedInUser,
+	}
+
+	// When dashboard UID present in the request, we ignore dashboard ID
+	if query.DashboardUID != "" {
+		dq := dashboards.GetDashboardQuery{UID: query.DashboardUID, OrgID: c.SignedInUser.GetOrgID()}
+		dqResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dq)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid dashboard UID in annotation request", err)
+		}
+		query.DashboardID = dqResult.ID
+	}
+
+	// The annotations are written to the response batch by batch, errors can only be returned until the first one is written.
+	w := &exportWriter{resp: c.Resp}
+	count, err := hs.annotationsRepo.Export(c.Req.Context(), query, w)
+	if err != nil {
+		if !w.started {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export annotations", err)
+		}
+		c.Logger.Error("Failed to export annotations, the export is incomplete", "exported", count, "error", err)
+		return nil
+	}
+	w.start()
+	return nil
+}
+
+// exportWriter writes the headers of an annotation export on the first write.
+type exportWriter struct {
+	resp    web.ResponseWriter
+	started bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.resp.Write(p)
+}
+
+func (w *exportWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.resp.Header().Set("Content-Type", "application/x-ndjson")
+	w.resp.Header().Set("Content-Disposition", `attachment; filename="annotations.ndjson"`)
+	w.resp.WriteHeader(http.StatusOK)
+}
+
 // AnnotationTypeScopeResolver provides an ScopeAttributeResolver able to
 // resolve annotation types. Scope "annotations:id:<id>" will be translated to "annotations:type:<type>,
 // where <type> is the type of annotation with id <id>.
@@ -782,6 +978,135 @@ type GetAnnotationsParams struct {
 	// in:query
 	// required:false
 	MatchAny bool `json:"matchAny"`
+	// Find annotations whose text contains this text, ignoring case.
+	// in:query
+	// required:false
+	Text string `json:"text"`
+}
+
+// swagger:parameters getAnnotationStats
+type GetAnnotationStatsParams struct {
+	// Count annotations created after specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Count annotations created before specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Size of the time buckets, for example 1h, 1d or 1w. Buckets start at from. No time buckets are returned if it is not set.
+	// in:query
+	// required:false
+	Interval string `json:"interval"`
+	// Limit statistics to annotations created by specific user.
+	// in:query
+	// required:false
+	UserID int64 `json:"userId"`
+	// Count annotations for a specified alert.
+	// in:query
+	// required:false
+	AlertID int64 `json:"alertId"`
+	// Count annotations that are scoped to a specific dashboard
+	// in:query
+	// required:false
+	DashboardID int64 `json:"dashboardId"`
+	// Count annotations that are scoped to a specific dashboard
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUID"`
+	// Count annotations that are scoped to a specific panel
+	// in:query
+	// required:false
+	PanelID int64 `json:"panelId"`
+	// Count annotations with these tags.
+	// in:query
+	// required:false
+	// type: array
+	// collectionFormat: multi
+	Tags []string `json:"tags"`
+	// Count alerts or user created annotations
+	// in:query
+	// required:false
+	// Description:
+	// * `alert`
+	// * `annotation`
+	// enum: alert,annotation
+	Type string `json:"type"`
+	// Match any or all tags
+	// in:query
+	// required:false
+	MatchAny bool `json:"matchAny"`
+	// Count annotations whose text contains this text, ignoring case.
+	// in:query
+	// required:false
+	Text string `json:"text"`
+}
+
+// swagger:parameters importAnnotations
+type ImportAnnotationsParams struct {
+	// Format of the annotations, ndjson or csv. Defaults to csv if the content type is text/csv, and to ndjson otherwise.
+	// in:query
+	// required:false
+	// enum: ndjson,csv
+	Format string `json:"format"`
+	// Newline delimited JSON with one annotation per line, or CSV with a header row naming the columns.
+	// in:body
+	// required:true
+	Body string `json:"body"`
+}
+
+// swagger:parameters exportAnnotations
+type ExportAnnotationsParams struct {
+	// Export annotations created after specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Export annotations created before specific epoch datetime in milliseconds.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// Limit export to annotations created by specific user.
+	// in:query
+	// required:false
+	UserID int64 `json:"userId"`
+	// Export annotations for a specified alert.
+	// in:query
+	// required:false
+	AlertID int64 `json:"alertId"`
+	// Export annotations that are scoped to a specific dashboard
+	// in:query
+	// required:false
+	DashboardID int64 `json:"dashboardId"`
+	// Export annotations that are scoped to a specific dashboard
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUID"`
+	// Export annotations that are scoped to a specific panel
+	// in:query
+	// required:false
+	PanelID int64 `json:"panelId"`
+	// Export annotations with these tags.
+	// in:query
+	// required:false
+	// type: array
+	// collectionFormat: multi
+	Tags []string `json:"tags"`
+	// Export alerts or user created annotations
+	// in:query
+	// required:false
+	// Description:
+	// * `alert`
+	// * `annotation`
+	// enum: alert,annotation
+	Type string `json:"type"`
+	// Match any or all tags
+	// in:query
+	// required:false
+	MatchAny bool `json:"matchAny"`
+	// Export annotations whose text contains this text, ignoring case.
+	// in:query
+	// required:false
+	Text string `json:"text"`
 }
 
 // swagger:parameters getAnnotationTags
@@ -874,3 +1199,24 @@ type GetAnnotationTagsResponse struct {
 	// in: body
 	Body annotations.GetAnnotationTagsResponse `json:"body"`
 }
+
+// swagger:response getAnnotationStatsResponse
+type GetAnnotationStatsResponse struct {
+	// The response message
+	// in: body
+	Body annotations.StatsResult `json:"body"`
+}
+
+// swagger:response importAnnotationsResponse
+type ImportAnnotationsResponse struct {
+	// The response message
+	// in: body
+	Body annotations.ImportResult `json:"body"`
+}
+
+// swagger:response exportAnnotationsResponse
+type ExportAnnotationsResponse struct {
+	// Newline delimited JSON with one annotation per line.
+	// in: body
+	Body []annotations.BulkItem `json:"body"`
+}
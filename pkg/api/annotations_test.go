@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -106,6 +107,71 @@ func TestAPI_Annotations(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			permissions:  []accesscontrol.Permission{},
 		},
+		{
+			desc:         "should be able to fetch annotation statistics with correct permission",
+			path:         "/api/annotations/stats?from=1704067200000&to=1706140800000&interval=1d",
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should not be able to fetch annotation statistics without correct permission",
+			path:         "/api/annotations/stats",
+			method:       http.MethodGet,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{},
+		},
+		{
+			desc:         "should not be able to fetch annotation statistics with an invalid interval",
+			path:         "/api/annotations/stats?interval=1ms",
+			method:       http.MethodGet,
+			expectedCode: http.StatusBadRequest,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should not be able to fetch annotation statistics with an interval and no time range",
+			path:         "/api/annotations/stats?interval=1d",
+			method:       http.MethodGet,
+			expectedCode: http.StatusBadRequest,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should not be able to fetch annotation statistics with too many time buckets",
+			path:         "/api/annotations/stats?from=1&to=1704067200000&interval=1s",
+			method:       http.MethodGet,
+			expectedCode: http.StatusBadRequest,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should be able to import annotations with correct permission",
+			path:         "/api/annotations/import",
+			method:       http.MethodPost,
+			body:         `{"time":1000,"text":"imported"}`,
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}},
+		},
+		{
+			desc:         "should not be able to import annotations without correct permission",
+			path:         "/api/annotations/import",
+			method:       http.MethodPost,
+			body:         `{"time":1000,"text":"imported"}`,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{},
+		},
+		{
+			desc:         "should be able to export annotations with correct permission",
+			path:         "/api/annotations/export",
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead}},
+		},
+		{
+			desc:         "should not be able to export annotations without correct permission",
+			path:         "/api/annotations/export",
+			method:       http.MethodGet,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{},
+		},
 		{
 			desc:         "should be able to update dashboard annotation with correct permission",
 			path:         "/api/annotations/2",
@@ -529,3 +595,51 @@ func setUpRBACGuardian(t *testing.T) {
 
 	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanEditValue: true, CanViewValue: true})
 }
+
+func TestAPI_ExportAnnotations(t *testing.T) {
+	permissions := []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsRead, Scope: accesscontrol.ScopeAnnotationsAll}}
+
+	export := func(t *testing.T, repo annotations.Repository) (*http.Response, string) {
+		t.Helper()
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.annotationsRepo = repo
+		})
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/annotations/export"), authedUserWithPermissions(1, 1, permissions))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res, string(body)
+	}
+
+	t.Run("should write the annotations to the response", func(t *testing.T) {
+		repo := annotationstest.NewFakeAnnotationsRepo()
+		_ = repo.Save(context.Background(), &annotations.Item{ID: 1, Epoch: 1, Text: "deploy"})
+
+		res, body := export(t, repo)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="annotations.ndjson"`, res.Header.Get("Content-Disposition"))
+		assert.JSONEq(t, `{"time":1,"text":"deploy"}`, body)
+	})
+
+	t.Run("should write the headers of an empty export", func(t *testing.T) {
+		repo := &annotations.FakeAnnotationsRepo{}
+		repo.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil)
+
+		res, body := export(t, repo)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+		assert.Empty(t, body)
+	})
+
+	t.Run("should return an error if the export fails before writing", func(t *testing.T) {
+		repo := &annotations.FakeAnnotationsRepo{}
+		repo.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), errors.New("failed"))
+
+		res, _ := export(t, repo)
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+}
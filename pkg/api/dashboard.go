@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
@@ -955,6 +956,135 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 	return response.JSON(http.StatusOK, dashVersionMeta)
 }
 
+// swagger:route GET /dashboards/id/{DashboardID}/versions/{DashboardVersionID}/diff dashboard_versions getDashboardVersionDiffByID
+//
+// Get the diff of a dashboard version.
+//
+// Please refer to [updated API](#/dashboard_versions/getDashboardVersionDiffByUID) instead
+//
+// Deprecated: true
+//
+// Produces:
+// - application/json
+// - text/html
+//
+// Responses:
+// 200: calculateDashboardDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+
+// swagger:route GET /dashboards/uid/{uid}/versions/{DashboardVersionID}/diff dashboard_versions getDashboardVersionDiffByUID
+//
+// Get the diff of a dashboard version using UID.
+//
+// Compares the dashboard version with its parent version, or with the version of the base parameter.
+// The semantic diff is returned by default.
+//
+// Produces:
+// - application/json
+// - text/html
+//
+// Responses:
+// 200: calculateDashboardDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionDiff(c *contextmodel.ReqContext) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "api.GetDashboardVersionDiff")
+	defer span.End()
+	c.Req = c.Req.WithContext(ctx)
+
+	var dashID int64
+
+	var err error
+	dashUID := web.Params(c.Req)[":uid"]
+	if dashUID == "" {
+		dashID, err = strconv.ParseInt(web.Params(c.Req)[":dashboardId"], 10, 64)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "dashboardId is invalid", err)
+		}
+	}
+
+	version, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 32)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), dashID, dashUID)
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	getVersion := func(version int) (*dashver.DashboardVersionDTO, response.Response) {
+		res, err := hs.dashboardVersionService.Get(c.Req.Context(), &dashver.GetDashboardVersionQuery{
+			OrgID:        c.SignedInUser.GetOrgID(),
+			DashboardID:  dash.ID,
+			DashboardUID: dash.UID,
+			Version:      version,
+		})
+		if err != nil {
+			if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+				return nil, response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+			}
+			return nil, response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+		}
+		return res, nil
+	}
+
+	newVersion, rsp := getVersion(int(version))
+	if rsp != nil {
+		return rsp
+	}
+
+	baseVersionID := newVersion.ParentVersion
+	if c.Query("base") != "" {
+		baseVersionID = c.QueryInt("base")
+	}
+	if baseVersionID <= 0 {
+		return response.Error(http.StatusBadRequest, "Dashboard version has no parent version, set the base version to compare with", nil)
+	}
+	baseVersion, rsp := getVersion(baseVersionID)
+	if rsp != nil {
+		return rsp
+	}
+
+	diffType := dashdiffs.DiffSemantic
+	if c.Query("diffType") != "" {
+		diffType = dashdiffs.ParseDiffType(c.Query("diffType"))
+	}
+	options := dashdiffs.Options{
+		OrgId:    c.SignedInUser.GetOrgID(),
+		DiffType: diffType,
+		Base:     dashdiffs.DiffTarget{DashboardId: dash.ID, Version: baseVersion.Version},
+		New:      dashdiffs.DiffTarget{DashboardId: dash.ID, Version: newVersion.Version},
+	}
+
+	result, err := dashdiffs.CalculateDiff(c.Req.Context(), &options, baseVersion.Data, newVersion.Data)
+	if err != nil {
+		if !errors.Is(err, dashdiffs.ErrNilDiff) {
+			return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+		}
+		// identical versions have no changes
+		result = &dashdiffs.Result{}
+	}
+
+	return dashboardDiffResponse(diffType, result)
+}
+
 // swagger:route POST /dashboards/calculate-diff dashboards calculateDashboardDiff
 //
 // Perform diff on two dashboards.
@@ -1051,13 +1181,40 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *contextmodel.ReqContext) respons
 		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	return dashboardDiffResponse(options.DiffType, result)
+}
+
+func dashboardDiffResponse(diffType dashdiffs.DiffType, result *dashdiffs.Result) response.Response {
+	if diffType == dashdiffs.DiffDelta || diffType == dashdiffs.DiffSemantic {
 		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
 	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
 }
 
+// swagger:route POST /dashboards/lint dashboards lintDashboard
+//
+// Lint a dashboard.
+//
+// Checks the dashboard JSON model for problems like panels without a data source, deprecated panel types,
+// duplicate variable names and links without a URL. The dashboard is not saved.
+//
+// Responses:
+// 200: lintDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+func (hs *HTTPServer) LintDashboard(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.LintDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.Dashboard == nil {
+		return response.Error(http.StatusBadRequest, "dashboard is required", nil)
+	}
+
+	return response.JSON(http.StatusOK, hs.dashboardLinter.Lint(cmd.Dashboard))
+}
+
 // swagger:route POST /dashboards/id/{DashboardID}/restore dashboard_versions restoreDashboardVersionByID
 //
 // Restore a dashboard to a given dashboard version.
@@ -1242,6 +1399,38 @@ type GetDashboardVersionByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters getDashboardVersionDiffByID
+type GetDashboardVersionDiffByIDParams struct {
+	// in:path
+	DashboardID int64
+	// in:path
+	DashboardVersionID int64
+}
+
+// swagger:parameters getDashboardVersionDiffByUID
+type GetDashboardVersionDiffByUIDParams struct {
+	// in:path
+	DashboardVersionID int64
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters getDashboardVersionDiffByID getDashboardVersionDiffByUID
+type GetDashboardVersionDiffParams struct {
+	// Version to compare with, the parent version of the dashboard version by default
+	// in:query
+	// required:false
+	Base int `json:"base"`
+
+	// The type of diff to return
+	// in:query
+	// required:false
+	// default:semantic
+	// Enum: basic,json,semantic
+	DiffType string `json:"diffType"`
+}
+
 // swagger:parameters getDashboardVersions getDashboardVersionsByUID
 type GetDashboardVersionsParams struct {
 	// Maximum number of results to return
@@ -1296,7 +1485,8 @@ type CalcDashboardDiffParams struct {
 		// Description:
 		// * `basic`
 		// * `json`
-		// Enum: basic,json
+		// * `semantic`: the changes of panels, queries, variables and annotations
+		// Enum: basic,json,semantic
 		DiffType string `json:"diffType" binding:"Required"`
 	}
 }
@@ -1370,6 +1560,19 @@ type PostDashboardResponse struct {
 	} `json:"body"`
 }
 
+// swagger:parameters lintDashboard
+type LintDashboardParams struct {
+	// in:body
+	// required:true
+	Body dtos.LintDashboardCommand
+}
+
+// swagger:response lintDashboardResponse
+type LintDashboardResponse struct {
+	// in: body
+	Body lint.Result `json:"body"`
+}
+
 // swagger:response calculateDashboardDiffResponse
 type CalculateDashboardDiffResponse struct {
 	// in: body
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
@@ -34,6 +36,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/dashboards/service"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
@@ -46,6 +49,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/librarypanels"
 	"github.com/grafana/grafana/pkg/services/licensing/licensingtest"
 	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	pref "github.com/grafana/grafana/pkg/services/preference"
@@ -278,7 +282,7 @@ func TestHTTPServer_DeleteDashboardByUID_AccessControl(t *testing.T) {
 			middleware := publicdashboards.NewFakePublicDashboardMiddleware(t)
 			license := licensingtest.NewFakeLicensing()
 			license.On("FeatureEnabled", publicdashboardModels.FeaturePublicDashboardsEmailSharing).Return(false)
-			hs.PublicDashboardsApi = api.ProvideApi(pubDashService, nil, hs.AccessControl, featuremgmt.WithFeatures(), middleware, hs.Cfg, license)
+			hs.PublicDashboardsApi = api.ProvideApi(pubDashService, nil, hs.AccessControl, featuremgmt.WithFeatures(), middleware, hs.Cfg, license, loginattempttest.FakeLoginAttemptService{ExpectedValid: true})
 
 			guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
 		})
@@ -379,6 +383,112 @@ func TestHTTPServer_GetDashboardVersions_AccessControl(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_GetDashboardVersionDiff(t *testing.T) {
+	setup := func(versions ...*dashver.DashboardVersionDTO) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			dash := dashboards.NewDashboard("some dash")
+			dash.ID = 1
+			dash.UID = "1"
+
+			dashSvc := dashboards.NewFakeDashboardService(t)
+			dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(dash, nil).Maybe()
+			hs.DashboardService = dashSvc
+
+			hs.Cfg = setting.NewCfg()
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+			hs.dashboardVersionService = &dashvertest.FakeDashboardVersionService{ExpectedDashboardVersions: versions}
+
+			guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+		})
+	}
+
+	permissions := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:1"},
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:1"},
+	}
+	getDiff := func(server *webtest.Server, url string, permissions []accesscontrol.Permission) *http.Response {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest(url), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+	version := func(version, parentVersion int, dashboard string) *dashver.DashboardVersionDTO {
+		data, err := simplejson.NewJson([]byte(dashboard))
+		require.NoError(t, err)
+		return &dashver.DashboardVersionDTO{DashboardID: 1, Version: version, ParentVersion: parentVersion, Data: data}
+	}
+
+	t.Run("Should return the semantic diff with the parent version", func(t *testing.T) {
+		server := setup(
+			version(2, 1, `{"panels": [{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "rate(cpu[1m])"}]}]}`),
+			version(1, 0, `{"panels": [{"id": 1, "title": "CPU", "targets": [{"refId": "A", "expr": "rate(cpu[5m])"}]}]}`),
+		)
+
+		res := getDiff(server, "/api/dashboards/uid/1/versions/2/diff", permissions)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+		var diff dashdiffs.SemanticDiff
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&diff))
+		require.Len(t, diff.Changes, 1)
+		assert.Equal(t, `Panel "CPU" query A changed expr`, diff.Changes[0].Message)
+	})
+
+	t.Run("Should return no changes for identical versions", func(t *testing.T) {
+		server := setup(version(2, 1, `{"title": "Same"}`), version(1, 0, `{"title": "Same"}`))
+
+		res := getDiff(server, "/api/dashboards/uid/1/versions/2/diff?base=1", permissions)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var diff dashdiffs.SemanticDiff
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&diff))
+		assert.Empty(t, diff.Changes)
+	})
+
+	t.Run("Should fail for the first version without a base version", func(t *testing.T) {
+		server := setup(version(1, 0, `{}`))
+
+		res := getDiff(server, "/api/dashboards/uid/1/versions/1/diff", permissions)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Should not be able to diff dashboard versions without correct permission", func(t *testing.T) {
+		server := setup()
+
+		res := getDiff(server, "/api/dashboards/uid/1/versions/2/diff", []accesscontrol.Permission{})
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func TestHTTPServer_LintDashboard(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.dashboardLinter = lint.ProvideService()
+	})
+
+	lintDashboard := func(body string) *http.Response {
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(server.NewPostRequest("/api/dashboards/lint", strings.NewReader(body)), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("Should return the issues of the dashboard", func(t *testing.T) {
+		res := lintDashboard(`{"dashboard": {"uid": "lint", "panels": [{"type": "graph", "title": "CPU"}]}}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var result lint.Result
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, "panel-type-deprecated", result.Issues[0].Rule)
+		assert.Equal(t, 1, result.Warnings)
+	})
+
+	t.Run("Should fail without a dashboard", func(t *testing.T) {
+		res := lintDashboard(`{}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
 func TestDashboardAPIEndpoint(t *testing.T) {
 	t.Run("Given two dashboards with the same title in different folders", func(t *testing.T) {
 		dashOne := dashboards.NewDashboard("dash")
@@ -58,6 +58,10 @@ type CalculateDiffTarget struct {
 	UnsavedDashboard *simplejson.Json `json:"unsavedDashboard"`
 }
 
+type LintDashboardCommand struct {
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+}
+
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
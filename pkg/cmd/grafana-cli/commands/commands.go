@@ -226,6 +226,29 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "test-rules",
+		Usage:  "test-rules <test file>... Run unit tests of alert rules",
+		Action: runPluginCommand(testRulesCommand),
+	},
+}
+
+var dashboardCommands = []*cli.Command{
+	{
+		Name:   "lint",
+		Usage:  "lint <dashboard file>... Check dashboard JSON files for problems",
+		Action: runPluginCommand(lintDashboardsCommand),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "Fail on warnings too",
+				Value: false,
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -237,4 +260,14 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana alerting commands",
+		Subcommands: alertingCommands,
+	},
+	{
+		Name:        "dashboards",
+		Usage:       "Grafana dashboard commands",
+		Subcommands: dashboardCommands,
+	},
 }
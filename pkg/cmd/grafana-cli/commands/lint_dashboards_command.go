@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
+)
+
+var (
+	errMissingDashboardFile = errors.New("missing dashboard file argument")
+	errDashboardLintFailed  = errors.New("dashboard lint failed")
+)
+
+func lintDashboardsCommand(c utils.CommandLine) error {
+	files := c.Args().Slice()
+	if len(files) == 0 {
+		return errMissingDashboardFile
+	}
+
+	linter := lint.ProvideService()
+	failed := false
+	for _, file := range files {
+		result, err := lintDashboardFile(linter, file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if len(result.Issues) == 0 {
+			logger.Infof("%s %s\n", color.GreenString("OK"), file)
+			continue
+		}
+
+		logger.Infof("%s\n", file)
+		for _, issue := range result.Issues {
+			severity := color.YellowString("WARN")
+			if issue.Severity == lint.SeverityError {
+				severity = color.RedString("ERROR")
+			}
+			logger.Infof("  %s %s: %s (%s)\n", severity, issue.Path, issue.Message, issue.Rule)
+		}
+		if result.Errors > 0 || (c.Bool("strict") && result.Warnings > 0) {
+			failed = true
+		}
+	}
+	if failed {
+		return errDashboardLintFailed
+	}
+	return nil
+}
+
+func lintDashboardFile(linter *lint.Linter, path string) (lint.Result, error) {
+	// nolint:gosec
+	// the path is provided by the user running the command
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return lint.Result{}, err
+	}
+	dashboard, err := simplejson.NewJson(content)
+	if err != nil {
+		return lint.Result{}, fmt.Errorf("failed to parse dashboard: %w", err)
+	}
+
+	// dashboards saved from the HTTP API have their JSON model in the dashboard property
+	if model, ok := dashboard.CheckGet("dashboard"); ok && len(model.MustMap()) > 0 {
+		dashboard = model
+	}
+	return linter.Lint(dashboard), nil
+}
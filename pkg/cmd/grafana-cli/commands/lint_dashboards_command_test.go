@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
+)
+
+func TestLintDashboardFile(t *testing.T) {
+	dir := t.TempDir()
+	linter := lint.ProvideService()
+
+	t.Run("Should lint a dashboard JSON model", func(t *testing.T) {
+		path := filepath.Join(dir, "model.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"uid": "lint", "panels": [{"type": "graph"}]}`), 0o600))
+
+		result, err := lintDashboardFile(linter, path)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		require.Equal(t, "panel-type-deprecated", result.Issues[0].Rule)
+	})
+
+	t.Run("Should lint a dashboard saved from the HTTP API", func(t *testing.T) {
+		path := filepath.Join(dir, "saved.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"dashboard": {"uid": "lint", "links": [{"type": "link"}]}, "meta": {}}`), 0o600))
+
+		result, err := lintDashboardFile(linter, path)
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		require.Equal(t, 1, result.Errors)
+	})
+
+	t.Run("Should fail on invalid JSON", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+
+		_, err := lintDashboardFile(linter, path)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+var (
+	errMissingTestFile = errors.New("missing test file argument")
+	errRuleTestsFailed = errors.New("rule tests failed")
+)
+
+// ruleTestFile is a file with unit tests of alert rules. The rules are read from files in the
+// provisioning file format, for example exported from Grafana. Paths are relative to the test file.
+type ruleTestFile struct {
+	RuleFiles               []string `yaml:"rule_files"`
+	apimodels.RuleUnitTests `yaml:",inline"`
+}
+
+func testRulesCommand(c utils.CommandLine) error {
+	files := c.Args().Slice()
+	if len(files) == 0 {
+		return errMissingTestFile
+	}
+
+	tester := backtesting.NewRuleTester(nil, featuremgmt.WithFeatures(), tracing.NewNoopTracerService())
+	failed := false
+	for _, file := range files {
+		logger.Infof("Testing %s\n", file)
+		result, err := runRuleTestFile(context.Background(), tester, file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for _, test := range result.Tests {
+			if test.Success {
+				logger.Infof("  %s %s\n", color.GreenString("PASS"), test.Name)
+				continue
+			}
+			failed = true
+			logger.Infof("  %s %s\n", color.RedString("FAIL"), test.Name)
+			for _, e := range test.Errors {
+				logger.Infof("    %s\n", e)
+			}
+		}
+	}
+	if failed {
+		return errRuleTestsFailed
+	}
+	return nil
+}
+
+func runRuleTestFile(ctx context.Context, tester *backtesting.RuleTester, path string) (apimodels.RuleUnitTestResults, error) {
+	// nolint:gosec
+	// the path is provided by the user running the command
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return apimodels.RuleUnitTestResults{}, err
+	}
+	var file ruleTestFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return apimodels.RuleUnitTestResults{}, fmt.Errorf("failed to parse test file: %w", err)
+	}
+
+	rules := make([]*ngmodels.AlertRule, 0)
+	for _, ruleFile := range file.RuleFiles {
+		if !filepath.IsAbs(ruleFile) {
+			ruleFile = filepath.Join(filepath.Dir(path), ruleFile)
+		}
+		r, err := readRuleFile(ruleFile)
+		if err != nil {
+			return apimodels.RuleUnitTestResults{}, fmt.Errorf("failed to read rule file %s: %w", ruleFile, err)
+		}
+		rules = append(rules, r...)
+	}
+
+	return tester.Run(ctx, &user.SignedInUser{OrgID: 1}, rules, file.RuleUnitTests)
+}
+
+// readRuleFile reads the alert rules from a file in the provisioning file format. The folder of a rule group
+// is a title or path, which cannot be resolved to a folder UID without a Grafana server, so the rules have no
+// namespace UID. Rule tests do not depend on the folder of the rules.
+func readRuleFile(path string) ([]*ngmodels.AlertRule, error) {
+	// nolint:gosec
+	// the path is provided by the user running the command
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file alerting.AlertingFileV1
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*ngmodels.AlertRule, 0)
+	for _, groupV1 := range file.Groups {
+		group, err := groupV1.MapToModel()
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range group.Rules {
+			rule.OrgID = group.OrgID
+			rule.RuleGroup = group.Title
+			rule.IntervalSeconds = group.Interval
+			rules = append(rules, &rule)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
+)
+
+const testRuleFile = `
+apiVersion: 1
+groups:
+  - orgId: 1
+    name: instances
+    folder: infra
+    interval: 1m
+    rules:
+      - uid: instance-down
+        title: Instance down
+        condition: B
+        for: 1m
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ $labels.job }} is down"
+        data:
+          - refId: A
+            datasourceUid: prometheus
+            relativeTimeRange:
+              from: 300
+              to: 0
+            model:
+              expr: up
+              instant: true
+          - refId: B
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: $A < 1
+`
+
+const testRuleTestFile = `
+rule_files:
+  - rules.yaml
+tests:
+  - name: instance goes down
+    interval: 1m
+    input_series:
+      - series: 'up{job="api"}'
+        values: '1 0 0'
+    alert_rule_test:
+      - eval_time: 1m
+        rule_title: Instance down
+        exp_alerts:
+          - exp_state: Pending
+            exp_labels:
+              job: api
+              severity: critical
+            exp_annotations:
+              summary: api is down
+      - eval_time: 2m
+        rule_uid: instance-down
+        exp_alerts:
+          - exp_labels:
+              job: api
+              severity: critical
+            exp_annotations:
+              summary: api is down
+`
+
+func TestRunRuleTestFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(testRuleFile), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tests.yaml"), []byte(testRuleTestFile), 0o600))
+
+	tester := backtesting.NewRuleTester(nil, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+	result, err := runRuleTestFile(context.Background(), tester, filepath.Join(dir, "tests.yaml"))
+	require.NoError(t, err)
+	require.Len(t, result.Tests, 1)
+	require.Equal(t, "instance goes down", result.Tests[0].Name)
+	require.Truef(t, result.Success, "%v", result.Tests[0].Errors)
+}
+
+func TestReadRuleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRuleFile), 0o600))
+
+	rules, err := readRuleFile(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "instance-down", rules[0].UID)
+	require.Equal(t, "instances", rules[0].RuleGroup)
+	// the folder is a title, not a UID
+	require.Empty(t, rules[0].NamespaceUID)
+}
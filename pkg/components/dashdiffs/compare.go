@@ -23,6 +23,7 @@ const (
 	DiffJSON DiffType = iota
 	DiffBasic
 	DiffDelta
+	DiffSemantic
 )
 
 type Options struct {
@@ -50,6 +51,8 @@ func ParseDiffType(diff string) DiffType {
 		return DiffBasic
 	case "delta":
 		return DiffDelta
+	case "semantic":
+		return DiffSemantic
 	}
 	return DiffBasic
 }
@@ -57,6 +60,19 @@ func ParseDiffType(diff string) DiffType {
 // CompareDashboardVersionsCommand computes the JSON diff of two versions,
 // assigning the delta of the diff to the `Delta` field.
 func CalculateDiff(ctx context.Context, options *Options, baseData, newData *simplejson.Json) (*Result, error) {
+	// The semantic diff does not need the JSON diff, and lists no changes for identical versions instead of failing
+	if options.DiffType == DiffSemantic {
+		semanticDiff, err := CalculateSemanticDiff(baseData, newData)
+		if err != nil {
+			return nil, err
+		}
+		delta, err := json.Marshal(semanticDiff)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Delta: delta}, nil
+	}
+
 	left, jsonDiff, err := getDiff(baseData, newData)
 	if err != nil {
 		return nil, err
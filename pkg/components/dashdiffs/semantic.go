@@ -0,0 +1,364 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type SemanticChangeType string
+
+const (
+	SemanticAdded   SemanticChangeType = "added"
+	SemanticRemoved SemanticChangeType = "removed"
+	SemanticChanged SemanticChangeType = "changed"
+	SemanticMoved   SemanticChangeType = "moved"
+)
+
+// SemanticChange is a change of a dashboard, a panel, a query, a variable or an annotation.
+type SemanticChange struct {
+	Type SemanticChangeType `json:"type"`
+	// Object is what changed: dashboard, panel, query, variable or annotation.
+	Object string `json:"object"`
+	// Path identifies what changed, for example panels[id=2].targets[refId=A].expr.
+	Path string `json:"path"`
+	// Message describes the change, for example Panel "CPU" query A changed expr.
+	Message string `json:"message"`
+	Old     any    `json:"old,omitempty"`
+	New     any    `json:"new,omitempty"`
+}
+
+// SemanticDiff is the diff of two dashboards in terms of their panels, queries, variables and annotations,
+// instead of their JSON trees. Panels are matched by ID, queries by ref ID, and variables and annotations by name,
+// so that moving them is not reported as changes of all their properties.
+type SemanticDiff struct {
+	Changes []SemanticChange `json:"changes"`
+}
+
+// dashboardProperties are the properties of dashboards that are not compared as dashboard properties,
+// because they are compared separately or change with every save.
+var dashboardProperties = map[string]bool{
+	"panels":      true,
+	"rows":        true,
+	"templating":  true,
+	"annotations": true,
+	"id":          true,
+	"version":     true,
+}
+
+// panelProperties are the properties of panels that are not compared as panel properties.
+var panelProperties = map[string]bool{
+	"id":      true,
+	"gridPos": true,
+	"targets": true,
+	"panels":  true,
+}
+
+// CalculateSemanticDiff computes the semantic diff of two dashboard versions.
+func CalculateSemanticDiff(baseData, newData *simplejson.Json) (*SemanticDiff, error) {
+	base, err := toMap(baseData)
+	if err != nil {
+		return nil, err
+	}
+	newDash, err := toMap(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &semanticDiffer{changes: []SemanticChange{}}
+	d.properties("dashboard", "", "Dashboard", base, newDash, dashboardProperties)
+	d.panels(base, newDash)
+	d.namedList("variable", "templating.list", "Variable", listOf(base, "templating", "list"), listOf(newDash, "templating", "list"))
+	d.namedList("annotation", "annotations.list", "Annotation", listOf(base, "annotations", "list"), listOf(newDash, "annotations", "list"))
+
+	return &SemanticDiff{Changes: d.changes}, nil
+}
+
+type semanticDiffer struct {
+	changes []SemanticChange
+}
+
+func (d *semanticDiffer) add(change SemanticChange) {
+	d.changes = append(d.changes, change)
+}
+
+// properties reports the properties that differ between two objects, in alphabetical order.
+func (d *semanticDiffer) properties(object, path, name string, base, newObj map[string]any, ignore map[string]bool) {
+	for _, key := range sortedKeys(base, newObj) {
+		if ignore[key] {
+			continue
+		}
+		oldValue, newValue := base[key], newObj[key]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		d.add(SemanticChange{
+			Type:    SemanticChanged,
+			Object:  object,
+			Path:    joinPath(path, key),
+			Message: fmt.Sprintf("%s changed %s", name, key),
+			Old:     oldValue,
+			New:     newValue,
+		})
+	}
+}
+
+// panelEntry is a panel with the row that contains it, if the row is collapsed or the dashboard uses legacy rows.
+type panelEntry struct {
+	key    string
+	panel  map[string]any
+	parent string
+}
+
+func (d *semanticDiffer) panels(base, newDash map[string]any) {
+	basePanels, baseOrder := panelsOf(base)
+	newPanels, newOrder := panelsOf(newDash)
+
+	for _, key := range baseOrder {
+		if _, ok := newPanels[key]; !ok {
+			d.add(SemanticChange{
+				Type:    SemanticRemoved,
+				Object:  "panel",
+				Path:    panelPath(key),
+				Message: fmt.Sprintf("%s removed", panelName(basePanels[key].panel)),
+			})
+		}
+	}
+
+	for _, key := range newOrder {
+		newPanel := newPanels[key]
+		basePanel, ok := basePanels[key]
+		if !ok {
+			d.add(SemanticChange{
+				Type:    SemanticAdded,
+				Object:  "panel",
+				Path:    panelPath(key),
+				Message: fmt.Sprintf("%s added", panelName(newPanel.panel)),
+			})
+			continue
+		}
+
+		name := panelName(newPanel.panel)
+		path := panelPath(key)
+		oldPos, newPos := mapOf(basePanel.panel["gridPos"]), mapOf(newPanel.panel["gridPos"])
+		if basePanel.parent != newPanel.parent || !reflect.DeepEqual(oldPos["x"], newPos["x"]) || !reflect.DeepEqual(oldPos["y"], newPos["y"]) {
+			d.add(SemanticChange{
+				Type:    SemanticMoved,
+				Object:  "panel",
+				Path:    joinPath(path, "gridPos"),
+				Message: fmt.Sprintf("%s moved", name),
+				Old:     basePanel.panel["gridPos"],
+				New:     newPanel.panel["gridPos"],
+			})
+		}
+		if !reflect.DeepEqual(oldPos["w"], newPos["w"]) || !reflect.DeepEqual(oldPos["h"], newPos["h"]) {
+			d.add(SemanticChange{
+				Type:    SemanticChanged,
+				Object:  "panel",
+				Path:    joinPath(path, "gridPos"),
+				Message: fmt.Sprintf("%s resized", name),
+				Old:     basePanel.panel["gridPos"],
+				New:     newPanel.panel["gridPos"],
+			})
+		}
+
+		d.properties("panel", path, name, basePanel.panel, newPanel.panel, panelProperties)
+		d.queries(path, name, listOf(basePanel.panel, "targets"), listOf(newPanel.panel, "targets"))
+	}
+}
+
+func (d *semanticDiffer) queries(panelPath, panelName string, base, newQueries []any) {
+	baseByRef, baseOrder := byKey(base, queryKey)
+	newByRef, newOrder := byKey(newQueries, queryKey)
+
+	for _, ref := range baseOrder {
+		if _, ok := newByRef[ref]; !ok {
+			d.add(SemanticChange{
+				Type:    SemanticRemoved,
+				Object:  "query",
+				Path:    joinPath(panelPath, fmt.Sprintf("targets[refId=%s]", ref)),
+				Message: fmt.Sprintf("%s query %s removed", panelName, ref),
+			})
+		}
+	}
+	for _, ref := range newOrder {
+		path := joinPath(panelPath, fmt.Sprintf("targets[refId=%s]", ref))
+		baseQuery, ok := baseByRef[ref]
+		if !ok {
+			d.add(SemanticChange{
+				Type:    SemanticAdded,
+				Object:  "query",
+				Path:    path,
+				Message: fmt.Sprintf("%s query %s added", panelName, ref),
+			})
+			continue
+		}
+		d.properties("query", path, fmt.Sprintf("%s query %s", panelName, ref), baseQuery, newByRef[ref], nil)
+	}
+}
+
+// namedList reports the added, removed and changed items of a list of named objects, like variables and annotations.
+func (d *semanticDiffer) namedList(object, listPath, title string, base, newItems []any) {
+	nameOf := func(i int, item map[string]any) string {
+		if name, ok := item["name"].(string); ok && name != "" {
+			return name
+		}
+		return fmt.Sprintf("#%d", i)
+	}
+	baseByName, baseOrder := byKey(base, nameOf)
+	newByName, newOrder := byKey(newItems, nameOf)
+
+	for _, name := range baseOrder {
+		if _, ok := newByName[name]; !ok {
+			d.add(SemanticChange{
+				Type:    SemanticRemoved,
+				Object:  object,
+				Path:    fmt.Sprintf("%s[name=%s]", listPath, name),
+				Message: fmt.Sprintf("%s %q removed", title, name),
+			})
+		}
+	}
+	for _, name := range newOrder {
+		path := fmt.Sprintf("%s[name=%s]", listPath, name)
+		baseItem, ok := baseByName[name]
+		if !ok {
+			d.add(SemanticChange{
+				Type:    SemanticAdded,
+				Object:  object,
+				Path:    path,
+				Message: fmt.Sprintf("%s %q added", title, name),
+			})
+			continue
+		}
+		d.properties(object, path, fmt.Sprintf("%s %q", title, name), baseItem, newByName[name], nil)
+	}
+}
+
+// panelsOf returns the panels of a dashboard by key, and the keys in the order of the dashboard.
+// This includes the panels of collapsed rows, and the panels of the rows of dashboards older than schema version 16.
+func panelsOf(dash map[string]any) (map[string]*panelEntry, []string) {
+	panels := make(map[string]*panelEntry)
+	var order []string
+	addPanel := func(fallbackKey string, panel map[string]any, parent string) string {
+		key := fallbackKey
+		if id, ok := panel["id"]; ok && id != nil {
+			key = fmt.Sprintf("id=%v", id)
+		}
+		if _, exists := panels[key]; exists {
+			key = fallbackKey
+		}
+		panels[key] = &panelEntry{key: key, panel: panel, parent: parent}
+		order = append(order, key)
+		return key
+	}
+
+	for i, value := range listOf(dash, "panels") {
+		panel := mapOf(value)
+		key := addPanel(fmt.Sprintf("panels[%d]", i), panel, "")
+		for j, nested := range listOf(panel, "panels") {
+			addPanel(fmt.Sprintf("panels[%d].panels[%d]", i, j), mapOf(nested), key)
+		}
+	}
+	for i, row := range listOf(dash, "rows") {
+		for j, value := range listOf(mapOf(row), "panels") {
+			addPanel(fmt.Sprintf("rows[%d].panels[%d]", i, j), mapOf(value), fmt.Sprintf("rows[%d]", i))
+		}
+	}
+	return panels, order
+}
+
+func panelPath(key string) string {
+	if len(key) > 3 && key[:3] == "id=" {
+		return fmt.Sprintf("panels[%s]", key)
+	}
+	return key
+}
+
+func panelName(panel map[string]any) string {
+	kind := "Panel"
+	if panel["type"] == "row" {
+		kind = "Row"
+	}
+	if title, ok := panel["title"].(string); ok && title != "" {
+		return fmt.Sprintf("%s %q", kind, title)
+	}
+	if id, ok := panel["id"]; ok && id != nil {
+		return fmt.Sprintf("%s %v", kind, id)
+	}
+	return kind
+}
+
+func queryKey(i int, query map[string]any) string {
+	if ref, ok := query["refId"].(string); ok && ref != "" {
+		return ref
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// byKey returns the objects of a list by key, and the keys in the order of the list.
+func byKey(list []any, key func(i int, item map[string]any) string) (map[string]map[string]any, []string) {
+	items := make(map[string]map[string]any, len(list))
+	order := make([]string, 0, len(list))
+	for i, value := range list {
+		item := mapOf(value)
+		k := key(i, item)
+		if _, exists := items[k]; exists {
+			k = fmt.Sprintf("#%d", i)
+		}
+		items[k] = item
+		order = append(order, k)
+	}
+	return items, order
+}
+
+func listOf(obj map[string]any, keys ...string) []any {
+	var value any = obj
+	for _, key := range keys {
+		value = mapOf(value)[key]
+	}
+	list, _ := value.([]any)
+	return list
+}
+
+func mapOf(value any) map[string]any {
+	obj, _ := value.(map[string]any)
+	return obj
+}
+
+func sortedKeys(objs ...map[string]any) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, obj := range objs {
+		for key := range obj {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// toMap decodes a dashboard into plain maps, so that numbers compare equal however the dashboard was decoded.
+func toMap(data *simplejson.Json) (map[string]any, error) {
+	raw, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	dash := make(map[string]any)
+	if err := json.Unmarshal(raw, &dash); err != nil {
+		return nil, err
+	}
+	return dash, nil
+}
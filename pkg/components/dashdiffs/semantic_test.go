@@ -0,0 +1,120 @@
+package dashdiffs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	base, err := simplejson.NewJson([]byte(`{
+		"id": 1,
+		"version": 3,
+		"title": "Hosts",
+		"panels": [
+			{ "id": 1, "type": "timeseries", "title": "CPU", "gridPos": { "x": 0, "y": 0, "w": 12, "h": 8 },
+			  "targets": [{ "refId": "A", "expr": "rate(cpu[5m])" }, { "refId": "B", "expr": "up" }] },
+			{ "id": 2, "type": "stat", "title": "Memory", "gridPos": { "x": 12, "y": 0, "w": 12, "h": 8 } },
+			{ "id": 3, "type": "text", "title": "Notes", "gridPos": { "x": 0, "y": 8, "w": 24, "h": 4 } }
+		],
+		"templating": { "list": [{ "name": "host", "query": "label_values(host)" }] },
+		"annotations": { "list": [{ "name": "Deploys", "enable": true }] }
+	}`))
+	require.NoError(t, err)
+	newDash, err := simplejson.NewJson([]byte(`{
+		"id": 1,
+		"version": 4,
+		"title": "Hosts",
+		"panels": [
+			{ "id": 2, "type": "stat", "title": "Memory", "gridPos": { "x": 0, "y": 0, "w": 12, "h": 8 } },
+			{ "id": 1, "type": "timeseries", "title": "CPU", "gridPos": { "x": 12, "y": 0, "w": 12, "h": 8 },
+			  "targets": [{ "refId": "A", "expr": "rate(cpu[1m])" }, { "refId": "C", "expr": "load" }] },
+			{ "id": 4, "type": "gauge", "title": "Disk", "gridPos": { "x": 0, "y": 8, "w": 24, "h": 6 } }
+		],
+		"templating": { "list": [{ "name": "host", "query": "label_values(host)" }, { "name": "env", "query": "label_values(env)" }] },
+		"annotations": { "list": [{ "name": "Deploys", "enable": false }] }
+	}`))
+	require.NoError(t, err)
+
+	t.Run("Should report changes of panels, queries, variables and annotations", func(t *testing.T) {
+		res, err := CalculateSemanticDiff(base, newDash)
+		require.NoError(t, err)
+
+		messages := make([]string, 0, len(res.Changes))
+		for _, change := range res.Changes {
+			messages = append(messages, change.Message)
+		}
+		assert.Equal(t, []string{
+			`Panel "Notes" removed`,
+			`Panel "Memory" moved`,
+			`Panel "CPU" moved`,
+			`Panel "CPU" query B removed`,
+			`Panel "CPU" query A changed expr`,
+			`Panel "CPU" query C added`,
+			`Panel "Disk" added`,
+			`Variable "env" added`,
+			`Annotation "Deploys" changed enable`,
+		}, messages)
+
+		expr := res.Changes[4]
+		assert.Equal(t, SemanticChange{
+			Type:    SemanticChanged,
+			Object:  "query",
+			Path:    "panels[id=1].targets[refId=A].expr",
+			Message: `Panel "CPU" query A changed expr`,
+			Old:     "rate(cpu[5m])",
+			New:     "rate(cpu[1m])",
+		}, expr)
+	})
+
+	t.Run("Should report panels moved into collapsed rows and resized panels", func(t *testing.T) {
+		base, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{ "id": 1, "type": "row", "title": "Details", "collapsed": true, "gridPos": { "x": 0, "y": 0, "w": 24, "h": 1 }, "panels": [] },
+				{ "id": 2, "title": "CPU", "gridPos": { "x": 0, "y": 1, "w": 12, "h": 8 } }
+			]
+		}`))
+		require.NoError(t, err)
+		newDash, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{ "id": 1, "type": "row", "title": "Details", "collapsed": true, "gridPos": { "x": 0, "y": 0, "w": 24, "h": 1 }, "panels": [
+					{ "id": 2, "title": "CPU", "gridPos": { "x": 0, "y": 1, "w": 24, "h": 8 } }
+				] }
+			]
+		}`))
+		require.NoError(t, err)
+
+		res, err := CalculateSemanticDiff(base, newDash)
+		require.NoError(t, err)
+		require.Len(t, res.Changes, 2)
+		assert.Equal(t, SemanticMoved, res.Changes[0].Type)
+		assert.Equal(t, `Panel "CPU" moved`, res.Changes[0].Message)
+		assert.Equal(t, SemanticChanged, res.Changes[1].Type)
+		assert.Equal(t, `Panel "CPU" resized`, res.Changes[1].Message)
+		assert.Equal(t, "panels[id=2].gridPos", res.Changes[1].Path)
+	})
+
+	t.Run("Should calculate semantic diffs", func(t *testing.T) {
+		res, err := CalculateDiff(context.Background(), &Options{DiffType: ParseDiffType("semantic")}, base, newDash)
+		require.NoError(t, err)
+
+		var diff SemanticDiff
+		require.NoError(t, json.Unmarshal(res.Delta, &diff))
+		assert.Len(t, diff.Changes, 9)
+	})
+
+	t.Run("Should calculate an empty semantic diff of identical versions", func(t *testing.T) {
+		res, err := CalculateDiff(context.Background(), &Options{DiffType: DiffSemantic}, base, base)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"changes":[]}`, string(res.Delta))
+
+		// the other diff types still fail, because there is no JSON diff to format
+		_, err = CalculateDiff(context.Background(), &Options{DiffType: DiffBasic}, base, base)
+		require.ErrorIs(t, err, ErrNilDiff)
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"gonum.org/v1/gonum/graph/simple"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -356,6 +357,12 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	if err != nil {
 		return mathexp.Results{}, err
 	}
+
+	// Core data sources, such as the Grafana data source, read the requester from the context.
+	if _, err := identity.GetRequester(ctx); err != nil && dn.request.User != nil {
+		ctx = identity.WithRequester(ctx, dn.request.User)
+	}
+
 	span.SetAttributes(
 		attribute.String("datasource.type", dn.datasource.Type),
 		attribute.String("datasource.uid", dn.datasource.UID),
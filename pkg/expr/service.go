@@ -91,6 +91,52 @@ func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider
 	}
 }
 
+// NewServiceWithDataHandler creates a Service that sends the queries of data source nodes to the given handler
+// instead of the data source plugins. It is used to execute expressions over data that does not come from
+// a data source, for example in unit tests of alert rules.
+func NewServiceWithDataHandler(cfg *setting.Cfg, handler backend.QueryDataHandler, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *Service {
+	return &Service{
+		cfg:          cfg,
+		dataService:  handler,
+		pCtxProvider: dataHandlerContextProvider{},
+		features:     features,
+		tracer:       tracer,
+		metrics:      newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracer,
+		},
+	}
+}
+
+// dataHandlerContextProvider builds plugin contexts that only identify the data source, because the queries
+// are not sent to a plugin.
+type dataHandlerContextProvider struct{}
+
+func (dataHandlerContextProvider) Get(_ context.Context, pluginID string, _ identity.Requester, orgID int64) (backend.PluginContext, error) {
+	return backend.PluginContext{
+		OrgID:    orgID,
+		PluginID: pluginID,
+	}, nil
+}
+
+func (p dataHandlerContextProvider) GetWithDataSource(ctx context.Context, pluginID string, user identity.Requester, ds *datasources.DataSource) (backend.PluginContext, error) {
+	var orgID int64
+	if user != nil {
+		orgID = user.GetOrgID()
+	}
+	pCtx, err := p.Get(ctx, pluginID, user, orgID)
+	if ds != nil {
+		pCtx.DataSourceInstanceSettings = &backend.DataSourceInstanceSettings{
+			ID:   ds.ID,
+			UID:  ds.UID,
+			Type: ds.Type,
+			Name: ds.Name,
+		}
+	}
+	return pCtx, err
+}
+
 func (s *Service) isDisabled() bool {
 	if s.cfg == nil {
 		return true
@@ -184,6 +184,45 @@ func TestDSQueryError(t *testing.T) {
 	require.Equal(t, fp(42), resp.Responses["C"].Frames[0].Fields[0].At(0))
 }
 
+func TestServiceWithDataHandler(t *testing.T) {
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{data.NewFrame("test",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+				data.NewField("value", data.Labels{"test": "label"}, []*float64{fp(2)}))}},
+		},
+	}
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	s := NewServiceWithDataHandler(cfg, me, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "test" } }`),
+			TimeRange: AbsoluteTimeRange{From: time.Unix(0, 0), To: time.Unix(2, 0)},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{OrgID: 1}})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["B"].Error)
+	require.Equal(t, fp(4), res.Responses["B"].Frames[0].Fields[1].At(0))
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
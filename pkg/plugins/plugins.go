@@ -100,6 +100,9 @@ type JSONData struct {
 	Preload      bool         `json:"preload"`
 	Backend      bool         `json:"backend"`
 	Routes       []*Route     `json:"routes"`
+	// AlertingNotifier is true if the backend plugin implements a notifier that contact points can send
+	// notifications through.
+	AlertingNotifier bool `json:"alertingNotifier,omitempty"`
 
 	// AccessControl settings
 	Roles      []RoleRegistration `json:"roles,omitempty"`
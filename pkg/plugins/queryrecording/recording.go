@@ -0,0 +1,36 @@
+// Package queryrecording contains the format of recorded query data responses, which are
+// written by the recording client middleware and replayed by the TestData replay scenario.
+package queryrecording
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// ReplayDirectoryConfigKey is the key of the Grafana config passed with every request to
+// the TestData data source that holds the directory recordings are replayed from. It is
+// set from the `replay_directory` setting in the `[plugin.grafana-testdata-datasource]` section.
+const ReplayDirectoryConfigKey = "GF_TESTDATA_REPLAY_DIRECTORY"
+
+// Recording is a recorded query data response together with the time range it was
+// recorded for. Recordings are stored as JSON files.
+type Recording struct {
+	From     time.Time                  `json:"from"`
+	To       time.Time                  `json:"to"`
+	Response *backend.QueryDataResponse `json:"response"`
+}
+
+// New creates a recording for the given request and response.
+func New(req *backend.QueryDataRequest, resp *backend.QueryDataResponse) Recording {
+	r := Recording{Response: resp}
+	for _, q := range req.Queries {
+		if r.From.IsZero() || q.TimeRange.From.Before(r.From) {
+			r.From = q.TimeRange.From
+		}
+		if q.TimeRange.To.After(r.To) {
+			r.To = q.TimeRange.To
+		}
+	}
+	return r
+}
@@ -3,6 +3,7 @@ package annotations
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/setting"
@@ -21,6 +22,9 @@ type Repository interface {
 	Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error)
 	Delete(ctx context.Context, params *DeleteParams) error
 	FindTags(ctx context.Context, query *TagsQuery) (FindTagsResult, error)
+	Stats(ctx context.Context, query *StatsQuery) (StatsResult, error)
+	Import(ctx context.Context, cmd *ImportCommand) (ImportResult, error)
+	Export(ctx context.Context, query *ItemQuery, w io.Writer) (int64, error)
 }
 
 // Cleaner is responsible for cleaning up old annotations
@@ -4,6 +4,7 @@ package annotations
 
 import (
 	context "context"
+	io "io"
 	testing "testing"
 
 	mock "github.com/stretchr/testify/mock"
@@ -28,6 +29,27 @@ func (_m *FakeAnnotationsRepo) Delete(ctx context.Context, params *DeleteParams)
 	return r0
 }
 
+// Export provides a mock function with given fields: ctx, query, w
+func (_m *FakeAnnotationsRepo) Export(ctx context.Context, query *ItemQuery, w io.Writer) (int64, error) {
+	ret := _m.Called(ctx, query, w)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *ItemQuery, io.Writer) int64); ok {
+		r0 = rf(ctx, query, w)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *ItemQuery, io.Writer) error); ok {
+		r1 = rf(ctx, query, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: ctx, query
 func (_m *FakeAnnotationsRepo) Find(ctx context.Context, query *ItemQuery) ([]*ItemDTO, error) {
 	ret := _m.Called(ctx, query)
@@ -72,6 +94,27 @@ func (_m *FakeAnnotationsRepo) FindTags(ctx context.Context, query *TagsQuery) (
 	return r0, r1
 }
 
+// Import provides a mock function with given fields: ctx, cmd
+func (_m *FakeAnnotationsRepo) Import(ctx context.Context, cmd *ImportCommand) (ImportResult, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 ImportResult
+	if rf, ok := ret.Get(0).(func(context.Context, *ImportCommand) ImportResult); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Get(0).(ImportResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *ImportCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, item
 func (_m *FakeAnnotationsRepo) Save(ctx context.Context, item *Item) error {
 	ret := _m.Called(ctx, item)
@@ -100,6 +143,27 @@ func (_m *FakeAnnotationsRepo) SaveMany(ctx context.Context, items []Item) error
 	return r0
 }
 
+// Stats provides a mock function with given fields: ctx, query
+func (_m *FakeAnnotationsRepo) Stats(ctx context.Context, query *StatsQuery) (StatsResult, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 StatsResult
+	if rf, ok := ret.Get(0).(func(context.Context, *StatsQuery) StatsResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(StatsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *StatsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, item
 func (_m *FakeAnnotationsRepo) Update(ctx context.Context, item *Item) error {
 	ret := _m.Called(ctx, item)
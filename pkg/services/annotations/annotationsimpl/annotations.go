@@ -10,18 +10,23 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/tag"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 type RepositoryImpl struct {
-	db       db.DB
-	authZ    *accesscontrol.AuthService
-	features featuremgmt.FeatureToggles
-	reader   readStore
-	writer   writeStore
+	db               db.DB
+	authZ            *accesscontrol.AuthService
+	accessControl    ac.AccessControl
+	dashboardService dashboards.DashboardService
+	features         featuremgmt.FeatureToggles
+	reader           readStore
+	writer           writeStore
+	exporter         exportStore
 }
 
 func ProvideService(
@@ -31,6 +36,8 @@ func ProvideService(
 	tagService tag.Service,
 	tracer tracing.Tracer,
 	ruleStore *alertingStore.DBstore,
+	accessControl ac.AccessControl,
+	dashboardService dashboards.DashboardService,
 ) *RepositoryImpl {
 	l := log.New("annotations")
 	l.Debug("Initializing annotations service")
@@ -49,11 +56,14 @@ func ProvideService(
 	}
 
 	return &RepositoryImpl{
-		db:       db,
-		features: features,
-		authZ:    accesscontrol.NewAuthService(db, features),
-		reader:   read,
-		writer:   write,
+		db:               db,
+		features:         features,
+		authZ:            accesscontrol.NewAuthService(db, features),
+		accessControl:    accessControl,
+		dashboardService: dashboardService,
+		reader:           read,
+		writer:           write,
+		exporter:         xormStore,
 	}
 }
 
@@ -125,3 +135,35 @@ func (r *RepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteP
 func (r *RepositoryImpl) FindTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
 	return r.reader.GetTags(ctx, *query)
 }
+
+// Stats returns the statistics of all annotations that match the query and that the user can access.
+func (r *RepositoryImpl) Stats(ctx context.Context, query *annotations.StatsQuery) (annotations.StatsResult, error) {
+	// The statistics are not paginated, so they need all dashboards the user can access
+	resources, err := r.authorizeAll(ctx, query.ItemQuery)
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	return r.reader.GetStats(ctx, *query, resources)
+}
+
+// authorizeAll returns the access resources with all dashboards the user can access, instead of a page of them.
+func (r *RepositoryImpl) authorizeAll(ctx context.Context, query annotations.ItemQuery) (*accesscontrol.AccessResources, error) {
+	var resources *accesscontrol.AccessResources
+	for query.Page = 1; ; query.Page++ {
+		page, err := r.authZ.Authorize(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if resources == nil {
+			resources = page
+		} else {
+			for uid, id := range page.Dashboards {
+				resources.Dashboards[uid] = id
+			}
+		}
+		if len(page.Dashboards) == 0 {
+			return resources, nil
+		}
+	}
+}
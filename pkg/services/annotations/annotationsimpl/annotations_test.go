@@ -52,7 +52,7 @@ func TestIntegrationAnnotationListingWithRBAC(t *testing.T) {
 	tagService := tagimpl.ProvideService(sql)
 	ruleStore := alertingStore.SetupStoreForTesting(t, sql)
 
-	repo := ProvideService(sql, cfg, features, tagService, tracing.InitializeTracerForTest(), ruleStore)
+	repo := ProvideService(sql, cfg, features, tagService, tracing.InitializeTracerForTest(), ruleStore, nil, nil)
 
 	dashboard1 := testutil.CreateDashboard(t, sql, cfg, features, dashboards.SaveDashboardCommand{
 		UserID:   1,
@@ -178,6 +178,15 @@ func TestIntegrationAnnotationListingWithRBAC(t *testing.T) {
 			for _, r := range results {
 				assert.Contains(t, tc.expectedAnnotationIds, r.ID)
 			}
+
+			stats, err := repo.Stats(context.Background(), &annotations.StatsQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					SignedInUser: u,
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.expectedAnnotationIds)), stats.Count)
 		})
 	}
 }
@@ -322,7 +331,7 @@ func TestIntegrationAnnotationListingWithInheritedRBAC(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.AnnotationMaximumTagsLength = 60
 			ruleStore := alertingStore.SetupStoreForTesting(t, sql)
-			repo := ProvideService(sql, cfg, tc.features, tagimpl.ProvideService(sql), tracing.InitializeTracerForTest(), ruleStore)
+			repo := ProvideService(sql, cfg, tc.features, tagimpl.ProvideService(sql), tracing.InitializeTracerForTest(), ruleStore, nil, nil)
 
 			usr.Permissions = map[int64]map[string][]string{1: tc.permissions}
 			testutil.SetupRBACPermission(t, sql, role, usr)
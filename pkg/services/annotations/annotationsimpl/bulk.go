@@ -0,0 +1,394 @@
+package annotationsimpl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+const (
+	bulkBatchSize   = 100
+	maxBulkLineSize = 1024 * 1024
+	maxImportErrors = 100
+)
+
+// Import creates the annotations read from cmd.Reader, in batches.
+// Every annotation is checked against the permissions of the user, like annotations created through the HTTP API.
+// Annotations that cannot be read, authorized or saved are skipped and reported in the result.
+func (r *RepositoryImpl) Import(ctx context.Context, cmd *annotations.ImportCommand) (annotations.ImportResult, error) {
+	res := annotations.ImportResult{Errors: []annotations.ImportError{}}
+
+	decoder, err := newBulkDecoder(cmd.Format, cmd.Reader)
+	if err != nil {
+		return res, err
+	}
+
+	importer := &importer{
+		repo:         r,
+		cmd:          cmd,
+		res:          &res,
+		dashboardIDs: make(map[string]int64),
+		allowed:      make(map[string]bool),
+	}
+	for {
+		line, bulkItem, err := decoder.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *lineError
+		if errors.As(err, &lineErr) {
+			importer.fail(line, lineErr.err)
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+
+		item, err := importer.item(ctx, bulkItem)
+		if err != nil {
+			importer.fail(line, err)
+			continue
+		}
+		importer.batch = append(importer.batch, *item)
+		importer.lines = append(importer.lines, line)
+		if len(importer.batch) == bulkBatchSize {
+			importer.save(ctx)
+		}
+	}
+	importer.save(ctx)
+
+	return res, nil
+}
+
+// Export writes the annotations that match the query, and that the user can access, to w as NDJSON.
+// The annotations are read in batches, the limit and page of the query are ignored. It returns the number of exported annotations.
+func (r *RepositoryImpl) Export(ctx context.Context, query *annotations.ItemQuery, w io.Writer) (int64, error) {
+	resources, err := r.authorizeAll(ctx, *query)
+	if err != nil {
+		return 0, err
+	}
+	dashboardUIDs := make(map[int64]string, len(resources.Dashboards))
+	for uid, id := range resources.Dashboards {
+		dashboardUIDs[id] = uid
+	}
+
+	batchQuery := *query
+	batchQuery.Limit = bulkBatchSize
+	enc := json.NewEncoder(w)
+	var count, afterID int64
+	for {
+		items, err := r.exporter.GetBatch(ctx, batchQuery, resources, afterID)
+		if err != nil {
+			return count, err
+		}
+		for _, item := range items {
+			if err := enc.Encode(annotations.BulkItem{
+				DashboardUID: dashboardUIDs[item.DashboardID],
+				PanelID:      item.PanelID,
+				Time:         item.Time,
+				TimeEnd:      item.TimeEnd,
+				Text:         item.Text,
+				Tags:         item.Tags,
+				Data:         item.Data,
+			}); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(items) < bulkBatchSize {
+			return count, nil
+		}
+		afterID = items[len(items)-1].ID
+	}
+}
+
+// importer holds the state of an import, the pending batch and the lookups that are shared by its annotations.
+type importer struct {
+	repo  *RepositoryImpl
+	cmd   *annotations.ImportCommand
+	res   *annotations.ImportResult
+	batch []annotations.Item
+	lines []int64
+	// dashboardIDs caches the IDs of dashboards by UID, 0 if the dashboard does not exist
+	dashboardIDs map[string]int64
+	// allowed caches if annotations can be created by dashboard UID, the empty UID is for organization annotations
+	allowed map[string]bool
+}
+
+func (i *importer) fail(line int64, err error) {
+	i.res.Failed++
+	if len(i.res.Errors) < maxImportErrors {
+		i.res.Errors = append(i.res.Errors, annotations.ImportError{Line: line, Message: err.Error()})
+	}
+}
+
+func (i *importer) item(ctx context.Context, bulkItem *annotations.BulkItem) (*annotations.Item, error) {
+	if bulkItem.Text == "" {
+		return nil, errors.New("text is required")
+	}
+	if bulkItem.Time <= 0 {
+		return nil, errors.New("time is required")
+	}
+
+	var dashboardID int64
+	if uid := bulkItem.DashboardUID; uid != "" {
+		id, ok := i.dashboardIDs[uid]
+		if !ok {
+			dashboard, err := i.repo.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: i.cmd.OrgID})
+			if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+				return nil, err
+			}
+			if dashboard != nil {
+				id = dashboard.ID
+			}
+			i.dashboardIDs[uid] = id
+		}
+		if id == 0 {
+			return nil, fmt.Errorf("dashboard %s not found", uid)
+		}
+		dashboardID = id
+	}
+
+	allowed, ok := i.allowed[bulkItem.DashboardUID]
+	if !ok {
+		var err error
+		allowed, err = i.repo.canCreate(ctx, i.cmd.SignedInUser, bulkItem.DashboardUID)
+		if err != nil {
+			return nil, err
+		}
+		i.allowed[bulkItem.DashboardUID] = allowed
+	}
+	if !allowed {
+		if bulkItem.DashboardUID == "" {
+			return nil, errors.New("not allowed to create organization annotations")
+		}
+		return nil, fmt.Errorf("not allowed to create annotations on dashboard %s", bulkItem.DashboardUID)
+	}
+
+	return &annotations.Item{
+		OrgID:       i.cmd.OrgID,
+		UserID:      i.cmd.UserID,
+		DashboardID: dashboardID,
+		PanelID:     bulkItem.PanelID,
+		Epoch:       bulkItem.Time,
+		EpochEnd:    bulkItem.TimeEnd,
+		Text:        bulkItem.Text,
+		Tags:        bulkItem.Tags,
+		Data:        bulkItem.Data,
+	}, nil
+}
+
+// save saves the pending batch. The batch is saved in a transaction,
+// so that it can be saved one by one if it fails, to only skip the annotations that cannot be saved.
+func (i *importer) save(ctx context.Context) {
+	if len(i.batch) == 0 {
+		return
+	}
+	defer func() {
+		i.batch = i.batch[:0]
+		i.lines = i.lines[:0]
+	}()
+
+	err := i.repo.db.InTransaction(ctx, func(ctx context.Context) error {
+		return i.repo.writer.AddMany(ctx, i.batch)
+	})
+	if err == nil {
+		i.res.Imported += int64(len(i.batch))
+		return
+	}
+
+	for n := range i.batch {
+		if err := i.repo.writer.Add(ctx, &i.batch[n]); err != nil {
+			i.fail(i.lines[n], err)
+			continue
+		}
+		i.res.Imported++
+	}
+}
+
+// canCreate checks if the user can create annotations on the dashboard, or organization annotations if the dashboard UID is empty.
+// These are the same checks as for annotations created through the HTTP API.
+func (r *RepositoryImpl) canCreate(ctx context.Context, user identity.Requester, dashboardUID string) (bool, error) {
+	if dashboardUID == "" {
+		return r.accessControl.Evaluate(ctx, user, ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeOrganization))
+	}
+
+	scope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID)
+	if r.features.IsEnabled(ctx, featuremgmt.FlagAnnotationPermissionUpdate) {
+		return r.accessControl.Evaluate(ctx, user, ac.EvalPermission(ac.ActionAnnotationsCreate, scope))
+	}
+	return r.accessControl.Evaluate(ctx, user, ac.EvalAll(
+		ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeDashboard),
+		ac.EvalPermission(dashboards.ActionDashboardsWrite, scope),
+	))
+}
+
+// bulkDecoder reads the annotations of an import one by one.
+type bulkDecoder interface {
+	// next returns the next annotation and the line it starts at. It returns io.EOF at the end of the input,
+	// and a *lineError if only this annotation cannot be read.
+	next() (int64, *annotations.BulkItem, error)
+}
+
+type lineError struct {
+	err error
+}
+
+func (e *lineError) Error() string {
+	return e.err.Error()
+}
+
+func newBulkDecoder(format string, reader io.Reader) (bulkDecoder, error) {
+	switch format {
+	case annotations.FormatNDJSON:
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBulkLineSize)
+		return &ndjsonDecoder{scanner: scanner}, nil
+	case annotations.FormatCSV:
+		return newCSVDecoder(reader)
+	default:
+		return nil, annotations.ErrBulkFormat.Errorf("unsupported format %q", format)
+	}
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func (d *ndjsonDecoder) next() (int64, *annotations.BulkItem, error) {
+	for d.scanner.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		item := &annotations.BulkItem{}
+		if err := json.Unmarshal(data, item); err != nil {
+			return d.line, nil, &lineError{fmt.Errorf("invalid JSON: %w", err)}
+		}
+		return d.line, item, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return d.line, nil, annotations.ErrBulkRead.Errorf("failed to read line %d: %w", d.line+1, err)
+	}
+	return d.line, nil, io.EOF
+}
+
+type csvDecoder struct {
+	reader *csv.Reader
+	header []string
+}
+
+var csvColumns = map[string]bool{
+	"dashboardUID": true,
+	"panelId":      true,
+	"time":         true,
+	"timeEnd":      true,
+	"text":         true,
+	"tags":         true,
+	"data":         true,
+}
+
+func newCSVDecoder(reader io.Reader) (*csvDecoder, error) {
+	d := &csvDecoder{reader: csv.NewReader(reader)}
+	d.reader.TrimLeadingSpace = true
+
+	header, err := d.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, annotations.ErrBulkRead.Errorf("failed to read CSV header: %w", err)
+	}
+	for _, column := range header {
+		if !csvColumns[column] {
+			return nil, annotations.ErrBulkRead.Errorf("unknown CSV column %q", column)
+		}
+	}
+	d.header = header
+	return d, nil
+}
+
+func (d *csvDecoder) next() (int64, *annotations.BulkItem, error) {
+	if d.header == nil {
+		return 0, nil, io.EOF
+	}
+
+	record, err := d.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return int64(parseErr.StartLine), nil, &lineError{parseErr.Err}
+	}
+	if err != nil {
+		return 0, nil, annotations.ErrBulkRead.Errorf("failed to read CSV: %w", err)
+	}
+	line, _ := d.reader.FieldPos(0)
+
+	item := &annotations.BulkItem{}
+	for n, value := range record {
+		var err error
+		switch column := d.header[n]; column {
+		case "dashboardUID":
+			item.DashboardUID = value
+		case "panelId":
+			if value != "" {
+				item.PanelID, err = strconv.ParseInt(value, 10, 64)
+			}
+		case "time":
+			item.Time, err = parseCSVTime(value)
+		case "timeEnd":
+			item.TimeEnd, err = parseCSVTime(value)
+		case "text":
+			item.Text = value
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					item.Tags = append(item.Tags, tag)
+				}
+			}
+		case "data":
+			if value != "" {
+				item.Data, err = simplejson.NewJson([]byte(value))
+			}
+		}
+		if err != nil {
+			return int64(line), nil, &lineError{fmt.Errorf("invalid %s: %w", d.header[n], err)}
+		}
+	}
+	return int64(line), item, nil
+}
+
+// parseCSVTime parses an epoch datetime in milliseconds, or an RFC3339 datetime.
+func parseCSVTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return epoch, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
@@ -0,0 +1,199 @@
+package annotationsimpl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/testutil"
+	"github.com/grafana/grafana/pkg/services/authz/zanzana"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	alertingStore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationAnnotationBulkImportExport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sql := db.InitTestDB(t)
+
+	cfg := setting.NewCfg()
+	cfg.AnnotationMaximumTagsLength = 60
+	features := featuremgmt.WithFeatures()
+
+	dashboard := testutil.CreateDashboard(t, sql, cfg, features, dashboards.SaveDashboardCommand{
+		UserID: 1,
+		OrgID:  1,
+		Dashboard: simplejson.NewFromAny(map[string]any{
+			"title": "Dashboard 1",
+		}),
+	})
+	dashboardService := &dashboards.FakeDashboardService{}
+	dashboardService.On("GetDashboard", mock.Anything, mock.MatchedBy(func(query *dashboards.GetDashboardQuery) bool {
+		return query.UID == dashboard.UID
+	})).Return(dashboard, nil)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+
+	repo := ProvideService(sql, cfg, features, tagimpl.ProvideService(sql), tracing.InitializeTracerForTest(),
+		alertingStore.SetupStoreForTesting(t, sql), acimpl.ProvideAccessControl(features, zanzana.NewNoopClient()), dashboardService)
+
+	u := &user.SignedInUser{
+		UserID: 1,
+		OrgID:  1,
+	}
+	role := testutil.SetupRBACRole(t, sql, u)
+
+	importAnnotations := func(t *testing.T, format, input string) annotations.ImportResult {
+		t.Helper()
+		res, err := repo.Import(context.Background(), &annotations.ImportCommand{
+			OrgID:        1,
+			UserID:       1,
+			SignedInUser: u,
+			Format:       format,
+			Reader:       strings.NewReader(input),
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("Should import NDJSON and report the lines that fail", func(t *testing.T) {
+		u.Permissions = map[int64]map[string][]string{1: {
+			accesscontrol.ActionAnnotationsCreate: {accesscontrol.ScopeAnnotationsAll},
+			dashboards.ActionDashboardsWrite:      {dashboards.ScopeDashboardsAll},
+		}}
+
+		input := fmt.Sprintf(`{"dashboardUID":%q,"panelId":2,"time":1000,"text":"deploy","tags":["deploy"]}
+{"time":2000,"timeEnd":3000,"text":"outage"}
+
+{"time":3000,"text":
+{"time":4000}
+{"dashboardUID":"missing","time":5000,"text":"lost"}
+`, dashboard.UID)
+		res := importAnnotations(t, annotations.FormatNDJSON, input)
+
+		assert.Equal(t, int64(2), res.Imported)
+		assert.Equal(t, int64(3), res.Failed)
+		require.Len(t, res.Errors, 3)
+		assert.Equal(t, int64(4), res.Errors[0].Line)
+		assert.Equal(t, int64(5), res.Errors[1].Line)
+		assert.Equal(t, "text is required", res.Errors[1].Message)
+		assert.Equal(t, int64(6), res.Errors[2].Line)
+		assert.Equal(t, "dashboard missing not found", res.Errors[2].Message)
+	})
+
+	t.Run("Should import CSV", func(t *testing.T) {
+		input := `time,text,tags,dashboardUID
+2024-01-01T00:00:00Z,from csv,"a, b",
+not a time,bad,,
+`
+		res := importAnnotations(t, annotations.FormatCSV, input)
+
+		assert.Equal(t, int64(1), res.Imported)
+		assert.Equal(t, int64(1), res.Failed)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, int64(3), res.Errors[0].Line)
+	})
+
+	t.Run("Should find imported annotations by tag", func(t *testing.T) {
+		input := `{"time":6000,"text":"tagged 1","tags":["imported","env:prod"]}
+{"time":7000,"text":"tagged 2","tags":["imported"]}
+{"time":8000,"text":"untagged"}
+`
+		res := importAnnotations(t, annotations.FormatNDJSON, input)
+		require.Equal(t, int64(3), res.Imported)
+
+		u.Permissions[1][accesscontrol.ActionAnnotationsRead] = []string{accesscontrol.ScopeAnnotationsAll}
+		testutil.SetupRBACPermission(t, sql, role, u)
+
+		items, err := repo.Find(context.Background(), &annotations.ItemQuery{OrgID: 1, Tags: []string{"imported"}, SignedInUser: u})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.ElementsMatch(t, []string{"tagged 1", "tagged 2"}, []string{items[0].Text, items[1].Text})
+
+		items, err = repo.Find(context.Background(), &annotations.ItemQuery{OrgID: 1, Tags: []string{"env:prod"}, SignedInUser: u})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.ElementsMatch(t, []string{"env:prod", "imported"}, items[0].Tags)
+	})
+
+	t.Run("Should not import annotations the user cannot create", func(t *testing.T) {
+		u.Permissions = map[int64]map[string][]string{1: {
+			accesscontrol.ActionAnnotationsCreate: {accesscontrol.ScopeAnnotationsTypeOrganization},
+		}}
+
+		input := fmt.Sprintf(`{"dashboardUID":%q,"time":1000,"text":"dashboard"}
+{"time":1000,"text":"organization"}
+`, dashboard.UID)
+		res := importAnnotations(t, annotations.FormatNDJSON, input)
+
+		assert.Equal(t, int64(1), res.Imported)
+		assert.Equal(t, int64(1), res.Failed)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, int64(1), res.Errors[0].Line)
+	})
+
+	t.Run("Should fail on an unsupported format", func(t *testing.T) {
+		_, err := repo.Import(context.Background(), &annotations.ImportCommand{OrgID: 1, SignedInUser: u, Format: "xml", Reader: strings.NewReader("")})
+		require.ErrorIs(t, err, annotations.ErrBulkFormat)
+	})
+
+	t.Run("Should export all annotations in batches", func(t *testing.T) {
+		var input strings.Builder
+		for i := 0; i < bulkBatchSize+10; i++ {
+			fmt.Fprintf(&input, `{"time":%d,"text":"batch %d"}`+"\n", 10000+i, i)
+		}
+		res := importAnnotations(t, annotations.FormatNDJSON, input.String())
+		require.Equal(t, int64(bulkBatchSize+10), res.Imported)
+
+		u.Permissions = map[int64]map[string][]string{1: {
+			accesscontrol.ActionAnnotationsRead: {accesscontrol.ScopeAnnotationsAll},
+			dashboards.ActionDashboardsRead:     {dashboards.ScopeDashboardsAll},
+		}}
+		testutil.SetupRBACPermission(t, sql, role, u)
+
+		var out bytes.Buffer
+		count, err := repo.Export(context.Background(), &annotations.ItemQuery{OrgID: 1, SignedInUser: u}, &out)
+		require.NoError(t, err)
+		assert.Equal(t, int64(bulkBatchSize+17), count)
+
+		exported := make(map[string]annotations.BulkItem)
+		scanner := bufio.NewScanner(&out)
+		for scanner.Scan() {
+			var item annotations.BulkItem
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &item))
+			exported[item.Text] = item
+		}
+		require.Len(t, exported, bulkBatchSize+17)
+
+		deploy := exported["deploy"]
+		assert.Equal(t, dashboard.UID, deploy.DashboardUID)
+		assert.Equal(t, int64(2), deploy.PanelID)
+		assert.Equal(t, int64(1000), deploy.Time)
+		assert.Equal(t, []string{"deploy"}, deploy.Tags)
+
+		outage := exported["outage"]
+		assert.Empty(t, outage.DashboardUID)
+		assert.Equal(t, int64(2000), outage.Time)
+		assert.Equal(t, int64(3000), outage.TimeEnd)
+
+		assert.Equal(t, []string{"a", "b"}, exported["from csv"].Tags)
+	})
+}
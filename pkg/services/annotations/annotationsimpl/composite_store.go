@@ -81,6 +81,30 @@ func (c *CompositeStore) GetTags(ctx context.Context, query annotations.TagsQuer
 	return annotations.FindTagsResult{Tags: res}, nil
 }
 
+// GetStats returns statistics from all stores, and combines the results.
+func (c *CompositeStore) GetStats(ctx context.Context, query annotations.StatsQuery, accessResources *accesscontrol.AccessResources) (annotations.StatsResult, error) {
+	resCh := make(chan annotations.StatsResult, len(c.readers))
+
+	err := concurrency.ForEachJob(ctx, len(c.readers), len(c.readers), func(ctx context.Context, i int) (err error) {
+		defer handleJobPanic(c.logger, c.readers[i].Type(), &err)
+
+		res, err := c.readers[i].GetStats(ctx, query, accessResources)
+		resCh <- res
+		return err
+	})
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	close(resCh)
+	builder := annotations.NewStatsBuilder(query)
+	for r := range resCh {
+		builder.AddResult(r)
+	}
+
+	return builder.Result(), nil
+}
+
 // handleJobPanic is a helper function that recovers from a panic in a concurrent job.,
 // It will log the error and set the job error if it is not nil.
 func handleJobPanic(logger log.Logger, storeType string, jobErr *error) {
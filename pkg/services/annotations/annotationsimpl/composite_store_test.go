@@ -127,6 +127,40 @@ func TestCompositeStore(t *testing.T) {
 		require.Equal(t, expected, res.Tags)
 	})
 
+	t.Run("should combine results from GetStats", func(t *testing.T) {
+		r1 := newFakeReader(withItems([]*annotations.ItemDTO{
+			{Time: 1, TimeEnd: 5, DashboardID: 1, Tags: []string{"deploy", "service:api"}},
+			{Time: 12, TimeEnd: 12, DashboardID: 1, Tags: []string{"deploy"}},
+		}))
+		r2 := newFakeReader(withItems([]*annotations.ItemDTO{
+			{Time: 15, TimeEnd: 15, DashboardID: 2},
+		}))
+
+		store := &CompositeStore{
+			log.NewNopLogger(),
+			[]readStore{r1, r2},
+		}
+
+		res, err := store.GetStats(context.Background(), annotations.StatsQuery{Interval: 10}, nil)
+		require.NoError(t, err)
+		require.Equal(t, annotations.StatsResult{
+			Count:   3,
+			Regions: annotations.RegionStats{Count: 1, Duration: 4},
+			Tags: []*annotations.TagsDTO{
+				{Tag: "deploy", Count: 2},
+				{Tag: "service:api", Count: 1},
+			},
+			Dashboards: []*annotations.DashboardStats{
+				{DashboardID: 1, Count: 2},
+				{DashboardID: 2, Count: 1},
+			},
+			Buckets: []*annotations.BucketStats{
+				{Time: 0, Count: 1, Tags: []*annotations.TagsDTO{{Tag: "deploy", Count: 1}, {Tag: "service:api", Count: 1}}},
+				{Time: 10, Count: 2, Tags: []*annotations.TagsDTO{{Tag: "deploy", Count: 1}}},
+			},
+		}, res)
+	})
+
 	// Check if reader is not modifying query since it might cause a race condition in case of composite store
 	t.Run("should not modify query", func(t *testing.T) {
 		getFn1 := func(ctx context.Context, query annotations.ItemQuery, resources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
@@ -199,6 +233,19 @@ func (f *fakeReader) GetTags(ctx context.Context, query annotations.TagsQuery) (
 	return f.tagRes, nil
 }
 
+func (f *fakeReader) GetStats(ctx context.Context, query annotations.StatsQuery, accessResources *accesscontrol.AccessResources) (annotations.StatsResult, error) {
+	items, err := f.Get(ctx, query.ItemQuery, accessResources)
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	builder := annotations.NewStatsBuilder(query)
+	for _, item := range items {
+		builder.AddItem(item)
+	}
+	return builder.Result(), nil
+}
+
 func withWait(wait time.Duration) func(*fakeReader) {
 	return func(f *fakeReader) {
 		f.wait = wait
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/constraints"
@@ -34,6 +35,7 @@ import (
 const (
 	subsystem         = "annotations"
 	defaultQueryRange = 6 * time.Hour // from grafana/pkg/services/ngalert/state/historian/loki.go
+	statsQueryLimit   = 5000          // the maximum page size of the loki client
 )
 
 var (
@@ -81,14 +83,21 @@ func (r *LokiHistorianStore) Type() string {
 }
 
 func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
+	items, _, err := r.find(ctx, query, accessResources)
+	return items, err
+}
+
+// find returns the annotations of the alert state history that match the query, and whether a Loki query
+// returned query.Limit entries, in which case older entries may be missing.
+func (r *LokiHistorianStore) find(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, bool, error) {
 	if query.Type == "annotation" {
-		return make([]*annotations.ItemDTO, 0), nil
+		return make([]*annotations.ItemDTO, 0), false, nil
 	}
 
 	// if the query is filtering on tags, but not on a specific dashboard, we shouldn't query loki
 	// since state history won't have tags for annotations
 	if len(query.Tags) > 0 && query.DashboardID == 0 && query.DashboardUID == "" {
-		return make([]*annotations.ItemDTO, 0), nil
+		return make([]*annotations.ItemDTO, 0), false, nil
 	}
 
 	rule := &ngmodels.AlertRule{}
@@ -97,9 +106,9 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 		rule, err = r.ruleStore.GetRuleByID(ctx, ngmodels.GetAlertRuleByIDQuery{OrgID: query.OrgID, ID: query.AlertID})
 		if err != nil {
 			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-				return make([]*annotations.ItemDTO, 0), ErrLokiStoreNotFound.Errorf("rule with ID %d does not exist", query.AlertID)
+				return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreNotFound.Errorf("rule with ID %d does not exist", query.AlertID)
 			}
-			return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to query rule: %w", err)
+			return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to query rule: %w", err)
 		}
 	}
 
@@ -108,9 +117,9 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 	if err != nil {
 		grafanaErr := errutil.Error{}
 		if errors.As(err, &grafanaErr) {
-			return make([]*annotations.ItemDTO, 0), err
+			return make([]*annotations.ItemDTO, 0), false, err
 		}
-		return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to build loki query: %w", err)
+		return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to build loki query: %w", err)
 	}
 	if len(logQL) > 1 {
 		r.log.FromContext(ctx).Info("Execute query in multiple batches", "batches", logQL, "maxQueryLimit", r.client.MaxQuerySize())
@@ -128,17 +137,26 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 	from := query.From * 1e6
 	to := query.To * 1e6
 	items := make([]*annotations.ItemDTO, 0)
+	truncated := false
 	for _, q := range logQL {
 		res, err := r.client.RangeQuery(ctx, q, from, to, query.Limit)
 		if err != nil {
-			return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
+			return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
 		}
+		entries := 0
 		for _, stream := range res.Data.Result {
+			entries += len(stream.Values)
 			items = append(items, r.annotationsFromStream(stream, *accessResources)...)
 		}
+		if query.Limit > 0 && int64(entries) >= query.Limit {
+			truncated = true
+		}
+	}
+	if query.Text != "" {
+		items = filterByText(items, query.Text)
 	}
 	sort.Sort(annotations.SortedItems(items))
-	return items, err
+	return items, truncated, nil
 }
 
 func (r *LokiHistorianStore) annotationsFromStream(stream historian.Stream, ac accesscontrol.AccessResources) []*annotations.ItemDTO {
@@ -196,8 +214,40 @@ func (r *LokiHistorianStore) GetTags(ctx context.Context, query annotations.Tags
 	return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, nil
 }
 
+// GetStats aggregates the alert state history that matches the query.
+// The statistics are built from at most statsQueryLimit entries of state history per Loki query,
+// the result is marked as truncated if a query hit the limit.
+func (r *LokiHistorianStore) GetStats(ctx context.Context, query annotations.StatsQuery, accessResources *accesscontrol.AccessResources) (annotations.StatsResult, error) {
+	itemQuery := query.ItemQuery
+	itemQuery.Limit = statsQueryLimit
+	items, truncated, err := r.find(ctx, itemQuery, accessResources)
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	builder := annotations.NewStatsBuilder(query)
+	for _, item := range items {
+		builder.AddItem(item)
+	}
+	res := builder.Result()
+	res.Truncated = truncated
+	return res, nil
+}
+
 // util
 
+// filterByText returns the annotations whose text contains the given text, ignoring case.
+func filterByText(items []*annotations.ItemDTO, text string) []*annotations.ItemDTO {
+	text = strings.ToLower(text)
+	filtered := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.Text), text) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
 func hasAccess(entry historian.LokiEntry, resources accesscontrol.AccessResources) bool {
 	orgFilter := resources.CanAccessOrgAnnotations && entry.DashboardUID == ""
 	dashFilter := func() bool {
@@ -305,6 +305,103 @@ func TestIntegrationAlertStateHistoryStore(t *testing.T) {
 			require.NoError(t, err)
 			require.Empty(t, res)
 		})
+
+		t.Run("can filter history by text", func(t *testing.T) {
+			rule1 := ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0])
+			rule1.Title = dashboardRules[dashboard1.UID][0].Title
+			rule2 := ruleMetaFromRule(t, dashboardRules[dashboard1.UID][1])
+			rule2.Title = dashboardRules[dashboard1.UID][1].Title
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(rule1, transitions, map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(rule2, transitions, map[string]string{}, log.NewNopLogger()),
+			}
+
+			query := annotations.ItemQuery{
+				OrgID: 1,
+				From:  start.UnixMilli(),
+				To:    start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				Text:  "test rule 2",
+			}
+			res, err := store.Get(
+				context.Background(),
+				query,
+				&annotation_ac.AccessResources{
+					Dashboards: map[string]int64{
+						dashboard1.UID: dashboard1.ID,
+					},
+					CanAccessDashAnnotations: true,
+				},
+			)
+			require.NoError(t, err)
+			require.Len(t, res, numTransitions)
+			for _, item := range res {
+				require.Contains(t, item.Text, "Test Rule 2")
+			}
+		})
+
+		t.Run("can get statistics of history", func(t *testing.T) {
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][1]), transitions, map[string]string{}, log.NewNopLogger()),
+			}
+
+			query := annotations.StatsQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID: 1,
+					From:  start.UnixMilli(),
+					To:    start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				},
+				Interval: time.Hour.Milliseconds(),
+			}
+			res, err := store.GetStats(
+				context.Background(),
+				query,
+				&annotation_ac.AccessResources{
+					Dashboards: map[string]int64{
+						dashboard1.UID: dashboard1.ID,
+					},
+					CanAccessDashAnnotations: true,
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, int64(2*numTransitions), res.Count)
+			require.Len(t, res.Dashboards, 1)
+			require.Equal(t, dashboard1.ID, res.Dashboards[0].DashboardID)
+			require.Equal(t, int64(2*numTransitions), res.Dashboards[0].Count)
+			require.Len(t, res.Buckets, 1)
+			require.Equal(t, start.UnixMilli(), res.Buckets[0].Time)
+			require.Empty(t, res.Tags)
+			require.False(t, res.Truncated)
+		})
+
+		t.Run("reports when the history is truncated by the limit", func(t *testing.T) {
+			stream := historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger())
+			accessResources := &annotation_ac.AccessResources{
+				Dashboards: map[string]int64{
+					dashboard1.UID: dashboard1.ID,
+				},
+				CanAccessDashAnnotations: true,
+			}
+			query := annotations.ItemQuery{
+				OrgID: 1,
+				From:  start.UnixMilli(),
+				To:    start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				Limit: int64(numTransitions),
+			}
+
+			fakeLokiClient.rangeQueryRes = []historian.Stream{stream}
+			items, truncated, err := store.find(context.Background(), query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, items, numTransitions)
+			require.True(t, truncated)
+
+			query.Limit = int64(numTransitions + 1)
+			fakeLokiClient.rangeQueryRes = []historian.Stream{stream}
+			items, truncated, err = store.find(context.Background(), query, accessResources)
+			require.NoError(t, err)
+			require.Len(t, items, numTransitions)
+			require.False(t, truncated)
+		})
 	})
 
 	t.Run("Testing items from Loki stream", func(t *testing.T) {
@@ -22,6 +22,12 @@ type readStore interface {
 	commonStore
 	Get(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error)
 	GetTags(ctx context.Context, query annotations.TagsQuery) (annotations.FindTagsResult, error)
+	GetStats(ctx context.Context, query annotations.StatsQuery, accessResources *accesscontrol.AccessResources) (annotations.StatsResult, error)
+}
+
+// exportStore reads annotations in batches, for exports that are too large to read at once.
+type exportStore interface {
+	GetBatch(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources, afterID int64) ([]*annotations.ItemDTO, error)
 }
 
 type writeStore interface {
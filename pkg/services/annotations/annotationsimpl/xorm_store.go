@@ -119,11 +119,12 @@ func (r *xormRepositoryImpl) AddMany(ctx context.Context, items []annotations.It
 			return err
 		}
 
-		for i, item := range hasTags {
-			if _, err := sess.Table("annotation").Insert(item); err != nil {
+		for i := range hasTags {
+			// Insert sets the ID of the annotation only if it is passed a pointer
+			itemWithID := &hasTags[i]
+			if _, err := sess.Table("annotation").Insert(itemWithID); err != nil {
 				return err
 			}
-			itemWithID := &hasTags[i]
 			if err := r.ensureTags(ctx, itemWithID.ID, itemWithID.Tags); err != nil {
 				return err
 			}
@@ -250,110 +251,14 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query annotations.ItemQuer
 	params := make([]interface{}, 0)
 	items := make([]*annotations.ItemDTO, 0)
 	err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
-		sql.WriteString(`
-			SELECT
-				annotation.id,
-				annotation.epoch as time,
-				annotation.epoch_end as time_end,
-				annotation.dashboard_id,
-				annotation.panel_id,
-				annotation.new_state,
-				annotation.prev_state,
-				annotation.alert_id,
-				annotation.text,
-				annotation.tags,
-				annotation.data,
-				annotation.created,
-				annotation.updated,
-				usr.email,
-				usr.login,
-				alert.name as alert_name
-			FROM annotation
-			LEFT OUTER JOIN ` + r.db.GetDialect().Quote("user") + ` as usr on usr.id = annotation.user_id
-			LEFT OUTER JOIN alert on alert.id = annotation.alert_id
-			INNER JOIN (
-				SELECT a.id from annotation a
-			`)
-
-		sql.WriteString(`WHERE a.org_id = ?`)
-		params = append(params, query.OrgID)
-
-		if query.AnnotationID != 0 {
-			// fmt.Print("annotation query")
-			sql.WriteString(` AND a.id = ?`)
-			params = append(params, query.AnnotationID)
-		}
-
-		if query.AlertID != 0 {
-			sql.WriteString(` AND a.alert_id = ?`)
-			params = append(params, query.AlertID)
-		}
-
-		if query.DashboardID != 0 {
-			sql.WriteString(` AND a.dashboard_id = ?`)
-			params = append(params, query.DashboardID)
-		}
-
-		if query.PanelID != 0 {
-			sql.WriteString(` AND a.panel_id = ?`)
-			params = append(params, query.PanelID)
-		}
-
-		if query.UserID != 0 {
-			sql.WriteString(` AND a.user_id = ?`)
-			params = append(params, query.UserID)
-		}
+		sql.WriteString(r.selectItemsSQL())
 
-		if query.From > 0 && query.To > 0 {
-			sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
-			params = append(params, query.To, query.From)
-		}
-
-		if query.Type == "alert" {
-			sql.WriteString(` AND a.alert_id > 0`)
-		} else if query.Type == "annotation" {
-			sql.WriteString(` AND a.alert_id = 0`)
-		}
-
-		if len(query.Tags) > 0 {
-			keyValueFilters := []string{}
-
-			tags := tag.ParseTagPairs(query.Tags)
-			for _, tag := range tags {
-				if tag.Value == "" {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
-					params = append(params, tag.Key)
-				} else {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
-					params = append(params, tag.Key, tag.Value)
-				}
-			}
-
-			if len(tags) > 0 {
-				tagsSubQuery := fmt.Sprintf(`
-			SELECT SUM(1) FROM annotation_tag at
-			INNER JOIN tag on tag.id = at.tag_id
-			WHERE at.annotation_id = a.id
-				AND (
-				%s
-				)
-		`, strings.Join(keyValueFilters, " OR "))
-
-				if query.MatchAny {
-					sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
-				} else {
-					sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
-				}
-			}
-		}
-
-		acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+		filter, filterParams, err := r.filterSQL(query, accessResources)
 		if err != nil {
 			return err
 		}
-		if acFilter != "" {
-			sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
-		}
+		sql.WriteString(filter)
+		params = append(params, filterParams...)
 
 		// order of ORDER BY arguments match the order of a sql index for performance
 		orderBy := " ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC"
@@ -373,6 +278,144 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query annotations.ItemQuer
 	return items, err
 }
 
+// selectItemsSQL returns the query of the annotations whose IDs are selected by the subquery that follows it.
+// The subquery must be closed with ") dt on dt.id = annotation.id".
+func (r *xormRepositoryImpl) selectItemsSQL() string {
+	return `
+		SELECT
+			annotation.id,
+			annotation.epoch as time,
+			annotation.epoch_end as time_end,
+			annotation.dashboard_id,
+			annotation.panel_id,
+			annotation.new_state,
+			annotation.prev_state,
+			annotation.alert_id,
+			annotation.text,
+			annotation.tags,
+			annotation.data,
+			annotation.created,
+			annotation.updated,
+			usr.email,
+			usr.login,
+			alert.name as alert_name
+		FROM annotation
+		LEFT OUTER JOIN ` + r.db.GetDialect().Quote("user") + ` as usr on usr.id = annotation.user_id
+		LEFT OUTER JOIN alert on alert.id = annotation.alert_id
+		INNER JOIN (
+			SELECT a.id from annotation a
+		`
+}
+
+// GetBatch returns up to query.Limit annotations that match the query with an ID greater than afterID, in ascending order by ID.
+func (r *xormRepositoryImpl) GetBatch(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources, afterID int64) ([]*annotations.ItemDTO, error) {
+	filter, params, err := r.filterSQL(query, accessResources)
+	if err != nil {
+		return nil, err
+	}
+	params = append(params, afterID)
+	sql := r.selectItemsSQL() + filter + ` AND a.id > ? ORDER BY a.id` + r.db.GetDialect().Limit(query.Limit) + ` ) dt on dt.id = annotation.id ORDER BY annotation.id`
+
+	items := make([]*annotations.ItemDTO, 0)
+	err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(sql, params...).Find(&items)
+	})
+	return items, err
+}
+
+// filterSQL returns the WHERE clause that filters the annotations, aliased as a, that match the query and that the user can access.
+func (r *xormRepositoryImpl) filterSQL(query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) (string, []any, error) {
+	var sql bytes.Buffer
+	params := make([]any, 0)
+
+	sql.WriteString(`WHERE a.org_id = ?`)
+	params = append(params, query.OrgID)
+
+	if query.AnnotationID != 0 {
+		// fmt.Print("annotation query")
+		sql.WriteString(` AND a.id = ?`)
+		params = append(params, query.AnnotationID)
+	}
+
+	if query.AlertID != 0 {
+		sql.WriteString(` AND a.alert_id = ?`)
+		params = append(params, query.AlertID)
+	}
+
+	if query.DashboardID != 0 {
+		sql.WriteString(` AND a.dashboard_id = ?`)
+		params = append(params, query.DashboardID)
+	}
+
+	if query.PanelID != 0 {
+		sql.WriteString(` AND a.panel_id = ?`)
+		params = append(params, query.PanelID)
+	}
+
+	if query.UserID != 0 {
+		sql.WriteString(` AND a.user_id = ?`)
+		params = append(params, query.UserID)
+	}
+
+	if query.From > 0 && query.To > 0 {
+		sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
+		params = append(params, query.To, query.From)
+	}
+
+	if query.Type == "alert" {
+		sql.WriteString(` AND a.alert_id > 0`)
+	} else if query.Type == "annotation" {
+		sql.WriteString(` AND a.alert_id = 0`)
+	}
+
+	if query.Text != "" {
+		sql.WriteString(` AND a.text ` + r.db.GetDialect().LikeStr() + ` ? ESCAPE '` + likeEscapeChar + `'`)
+		params = append(params, "%"+escapeLikePattern(query.Text)+"%")
+	}
+
+	if len(query.Tags) > 0 {
+		keyValueFilters := []string{}
+
+		tags := tag.ParseTagPairs(query.Tags)
+		for _, tag := range tags {
+			if tag.Value == "" {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
+				params = append(params, tag.Key)
+			} else {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
+				params = append(params, tag.Key, tag.Value)
+			}
+		}
+
+		if len(tags) > 0 {
+			tagsSubQuery := fmt.Sprintf(`
+		SELECT SUM(1) FROM annotation_tag at
+		INNER JOIN tag on tag.id = at.tag_id
+		WHERE at.annotation_id = a.id
+			AND (
+			%s
+			)
+	`, strings.Join(keyValueFilters, " OR "))
+
+			if query.MatchAny {
+				sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
+			} else {
+				sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
+			}
+		}
+	}
+
+	acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+	if err != nil {
+		return "", nil, err
+	}
+	if acFilter != "" {
+		sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
+	}
+
+	return sql.String(), params, nil
+}
+
 func (r *xormRepositoryImpl) getAccessControlFilter(user identity.Requester, accessResources *accesscontrol.AccessResources) (string, error) {
 	if accessResources.SkipAccessControlFilter {
 		return "", nil
@@ -486,12 +529,8 @@ func (r *xormRepositoryImpl) GetTags(ctx context.Context, query annotations.Tags
 	}
 	tags := make([]*annotations.TagsDTO, 0)
 	for _, item := range items {
-		tag := item.Key
-		if len(item.Value) > 0 {
-			tag = item.Key + ":" + item.Value
-		}
 		tags = append(tags, &annotations.TagsDTO{
-			Tag:   tag,
+			Tag:   tagString(item),
 			Count: item.Count,
 		})
 	}
@@ -499,6 +538,125 @@ func (r *xormRepositoryImpl) GetTags(ctx context.Context, query annotations.Tags
 	return annotations.FindTagsResult{Tags: tags}, nil
 }
 
+// GetStats aggregates the annotations that match the query in the database.
+func (r *xormRepositoryImpl) GetStats(ctx context.Context, query annotations.StatsQuery, accessResources *accesscontrol.AccessResources) (annotations.StatsResult, error) {
+	filter, params, err := r.filterSQL(query.ItemQuery, accessResources)
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	tagKey := `tag.` + r.db.GetDialect().Quote("key")
+	tagValue := `tag.` + r.db.GetDialect().Quote("value")
+	tagJoin := ` INNER JOIN annotation_tag at ON at.annotation_id = a.id INNER JOIN tag ON tag.id = at.tag_id `
+	// Both values are numbers, so they are safe to inline, which keeps the expression identical in SELECT and GROUP BY
+	bucket := fmt.Sprintf("CASE WHEN a.epoch < %[1]d THEN %[1]d ELSE a.epoch - (a.epoch - %[1]d) %% %[2]d END", query.From, query.Interval)
+	// The durations of regions are clipped to the time range, like in annotations.RegionDuration. The filter only
+	// matches regions that overlap the time range, so the clipped durations are not negative.
+	regionDuration := "a.epoch_end - a.epoch"
+	if query.From > 0 && query.To > 0 {
+		regionDuration = fmt.Sprintf("(CASE WHEN a.epoch_end > %[2]d THEN %[2]d ELSE a.epoch_end END) - (CASE WHEN a.epoch < %[1]d THEN %[1]d ELSE a.epoch END)", query.From, query.To)
+	}
+
+	res := annotations.StatsResult{}
+	err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var totals struct {
+			Count          int64 `xorm:"count"`
+			RegionCount    int64 `xorm:"region_count"`
+			RegionDuration int64 `xorm:"region_duration"`
+		}
+		if _, err := sess.SQL(`
+			SELECT
+				COUNT(*) AS count,
+				COALESCE(SUM(CASE WHEN a.epoch_end > a.epoch THEN 1 ELSE 0 END), 0) AS region_count,
+				COALESCE(SUM(CASE WHEN a.epoch_end > a.epoch THEN `+regionDuration+` ELSE 0 END), 0) AS region_duration
+			FROM annotation a `+filter, params...).Get(&totals); err != nil {
+			return err
+		}
+		res.Count = totals.Count
+		res.Regions = annotations.RegionStats{Count: totals.RegionCount, Duration: totals.RegionDuration}
+		if res.Count == 0 {
+			return nil
+		}
+
+		var dashboards []struct {
+			DashboardID int64 `xorm:"dashboard_id"`
+			Count       int64 `xorm:"count"`
+		}
+		if err := sess.SQL(`SELECT a.dashboard_id, COUNT(*) AS count FROM annotation a `+filter+` GROUP BY a.dashboard_id`, params...).Find(&dashboards); err != nil {
+			return err
+		}
+		for _, dashboard := range dashboards {
+			res.Dashboards = append(res.Dashboards, &annotations.DashboardStats{DashboardID: dashboard.DashboardID, Count: dashboard.Count})
+		}
+
+		var tags []*annotations.Tag
+		if err := sess.SQL(`SELECT `+tagKey+`, `+tagValue+`, COUNT(*) AS count FROM annotation a `+tagJoin+filter+` GROUP BY `+tagKey+`, `+tagValue, params...).Find(&tags); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			res.Tags = append(res.Tags, &annotations.TagsDTO{Tag: tagString(tag), Count: tag.Count})
+		}
+
+		if query.Interval <= 0 {
+			return nil
+		}
+
+		var buckets []struct {
+			Time  int64 `xorm:"bucket"`
+			Count int64 `xorm:"count"`
+		}
+		if err := sess.SQL(`SELECT `+bucket+` AS bucket, COUNT(*) AS count FROM annotation a `+filter+` GROUP BY `+bucket, params...).Find(&buckets); err != nil {
+			return err
+		}
+		bucketStats := make(map[int64]*annotations.BucketStats, len(buckets))
+		for _, b := range buckets {
+			bucketStats[b.Time] = &annotations.BucketStats{Time: b.Time, Count: b.Count}
+			res.Buckets = append(res.Buckets, bucketStats[b.Time])
+		}
+
+		var bucketTags []struct {
+			Time  int64  `xorm:"bucket"`
+			Key   string `xorm:"key"`
+			Value string `xorm:"value"`
+			Count int64  `xorm:"count"`
+		}
+		if err := sess.SQL(`SELECT `+bucket+` AS bucket, `+tagKey+`, `+tagValue+`, COUNT(*) AS count FROM annotation a `+tagJoin+filter+` GROUP BY `+bucket+`, `+tagKey+`, `+tagValue, params...).Find(&bucketTags); err != nil {
+			return err
+		}
+		for _, t := range bucketTags {
+			if b, ok := bucketStats[t.Time]; ok {
+				b.Tags = append(b.Tags, &annotations.TagsDTO{Tag: tagString(&annotations.Tag{Key: t.Key, Value: t.Value}), Count: t.Count})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return annotations.StatsResult{}, err
+	}
+
+	// The builder sorts the statistics
+	builder := annotations.NewStatsBuilder(query)
+	builder.AddResult(res)
+	return builder.Result(), nil
+}
+
+// likeEscapeChar escapes the wildcards of LIKE patterns. It is not a backslash because backslashes are string escapes
+// in MySQL and not in the other databases.
+const likeEscapeChar = "!"
+
+// escapeLikePattern escapes the wildcards in a text so that LIKE matches it literally.
+func escapeLikePattern(text string) string {
+	return strings.NewReplacer(likeEscapeChar, likeEscapeChar+likeEscapeChar, "%", likeEscapeChar+"%", "_", likeEscapeChar+"_").Replace(text)
+}
+
+// tagString formats a tag like it is stored in the tags of an annotation.
+func tagString(tag *annotations.Tag) string {
+	if len(tag.Value) > 0 {
+		return tag.Key + ":" + tag.Value
+	}
+	return tag.Key
+}
+
 func (r *xormRepositoryImpl) validateItem(item *annotations.Item) error {
 	if err := validateTimeRange(item); err != nil {
 		return err
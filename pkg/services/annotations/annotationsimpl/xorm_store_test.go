@@ -330,6 +330,102 @@ func TestIntegrationAnnotations(t *testing.T) {
 			assert.Len(t, items, 1)
 		})
 
+		t.Run("Should find annotations by text, ignoring case", func(t *testing.T) {
+			accRes := &annotation_ac.AccessResources{CanAccessOrgAnnotations: true}
+			items, err := store.Get(context.Background(), annotations.ItemQuery{
+				OrgID:        1,
+				Text:         "ROLL",
+				SignedInUser: testUser,
+			}, accRes)
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			assert.Equal(t, "rollback", items[0].Text)
+		})
+
+		t.Run("Should match wildcards in the text literally", func(t *testing.T) {
+			accRes := &annotation_ac.AccessResources{CanAccessOrgAnnotations: true}
+			items, err := store.Get(context.Background(), annotations.ItemQuery{
+				OrgID:        1,
+				Text:         "%",
+				SignedInUser: testUser,
+			}, accRes)
+			require.NoError(t, err)
+			assert.Empty(t, items)
+
+			items, err = store.Get(context.Background(), annotations.ItemQuery{
+				OrgID:        1,
+				Text:         "r_llback",
+				SignedInUser: testUser,
+			}, accRes)
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		})
+
+		t.Run("Can get statistics of annotations", func(t *testing.T) {
+			accRes := &annotation_ac.AccessResources{
+				Dashboards:               map[string]int64{dashboard.UID: dashboard.ID, dashboard2.UID: dashboard2.ID},
+				CanAccessDashAnnotations: true,
+				CanAccessOrgAnnotations:  true,
+			}
+			res, err := store.GetStats(context.Background(), annotations.StatsQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					From:         1,
+					To:           25,
+					SignedInUser: testUser,
+				},
+				Interval: 10,
+			}, accRes)
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(4), res.Count)
+			assert.Equal(t, annotations.RegionStats{Count: 1, Duration: 1}, res.Regions)
+			assert.Equal(t, []*annotations.TagsDTO{
+				{Tag: "error", Count: 2},
+				{Tag: "outage", Count: 2},
+				{Tag: "server:server-1", Count: 2},
+				{Tag: "type:outage", Count: 2},
+				{Tag: "deploy", Count: 1},
+				{Tag: "rollback", Count: 1},
+			}, res.Tags)
+			require.Len(t, res.Dashboards, 3)
+			assert.Equal(t, &annotations.DashboardStats{DashboardID: 0, Count: 2}, res.Dashboards[0])
+
+			// buckets start at from, so the annotation at 10 is in the first bucket and the others in the second
+			require.Len(t, res.Buckets, 2)
+			assert.Equal(t, int64(1), res.Buckets[0].Time)
+			assert.Equal(t, int64(1), res.Buckets[0].Count)
+			assert.Len(t, res.Buckets[0].Tags, 4)
+			assert.Equal(t, int64(11), res.Buckets[1].Time)
+			assert.Equal(t, int64(3), res.Buckets[1].Count)
+			assert.Len(t, res.Buckets[1].Tags, 6)
+
+			res, err = store.GetStats(context.Background(), annotations.StatsQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					Text:         "deploy",
+					Tags:         []string{"deploy"},
+					SignedInUser: testUser,
+				},
+			}, accRes)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), res.Count)
+			assert.Equal(t, []*annotations.TagsDTO{{Tag: "deploy", Count: 1}}, res.Tags)
+			assert.Empty(t, res.Buckets)
+
+			// the region from 20 to 21 ends after the time range, so only its part in the time range is counted
+			res, err = store.GetStats(context.Background(), annotations.StatsQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					From:         1,
+					To:           20,
+					SignedInUser: testUser,
+				},
+			}, accRes)
+			require.NoError(t, err)
+			assert.Equal(t, annotations.RegionStats{Count: 1, Duration: 0}, res.Regions)
+		})
+
 		t.Run("Can update annotation and remove all tags", func(t *testing.T) {
 			query := annotations.ItemQuery{
 				OrgID:        1,
@@ -662,3 +758,9 @@ func benchmarkFindTags(b *testing.B, numAnnotations int) {
 		require.Equal(b, int64(1), result.Tags[1].Count)
 	}
 }
+
+func TestEscapeLikePattern(t *testing.T) {
+	assert.Equal(t, "deploy", escapeLikePattern("deploy"))
+	assert.Equal(t, "100!% of !_id", escapeLikePattern("100% of _id"))
+	assert.Equal(t, "wow!!", escapeLikePattern("wow!"))
+}
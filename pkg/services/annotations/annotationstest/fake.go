@@ -2,6 +2,8 @@ package annotationstest
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"sync"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -83,6 +85,40 @@ func (repo *fakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.
 	return result, nil
 }
 
+func (repo *fakeAnnotationsRepo) Stats(_ context.Context, query *annotations.StatsQuery) (annotations.StatsResult, error) {
+	repo.mtx.Lock()
+	defer repo.mtx.Unlock()
+
+	builder := annotations.NewStatsBuilder(*query)
+	for _, item := range repo.annotations {
+		builder.AddItem(&annotations.ItemDTO{
+			ID:          item.ID,
+			DashboardID: item.DashboardID,
+			Time:        item.Epoch,
+			TimeEnd:     item.EpochEnd,
+			Tags:        item.Tags,
+		})
+	}
+	return builder.Result(), nil
+}
+
+func (repo *fakeAnnotationsRepo) Import(_ context.Context, cmd *annotations.ImportCommand) (annotations.ImportResult, error) {
+	return annotations.ImportResult{Errors: []annotations.ImportError{}}, nil
+}
+
+func (repo *fakeAnnotationsRepo) Export(_ context.Context, query *annotations.ItemQuery, w io.Writer) (int64, error) {
+	repo.mtx.Lock()
+	defer repo.mtx.Unlock()
+
+	enc := json.NewEncoder(w)
+	for _, item := range repo.annotations {
+		if err := enc.Encode(annotations.BulkItem{PanelID: item.PanelID, Time: item.Epoch, TimeEnd: item.EpochEnd, Text: item.Text, Tags: item.Tags}); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(repo.annotations)), nil
+}
+
 func (repo *fakeAnnotationsRepo) Len() int {
 	repo.mtx.Lock()
 	defer repo.mtx.Unlock()
@@ -0,0 +1,56 @@
+package annotations
+
+import (
+	"io"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	// FormatNDJSON is newline delimited JSON, with one BulkItem per line.
+	FormatNDJSON = "ndjson"
+	// FormatCSV is comma separated values, with a header row naming the BulkItem fields.
+	// Time can be an epoch datetime in milliseconds or RFC3339, and tags are comma separated.
+	FormatCSV = "csv"
+)
+
+var (
+	ErrBulkFormat = errutil.BadRequest("annotations.bulk.format", errutil.WithPublicMessage("Unsupported format, use ndjson or csv."))
+	ErrBulkRead   = errutil.BadRequest("annotations.bulk.read", errutil.WithPublicMessage("Failed to read annotations."))
+)
+
+// BulkItem is an annotation in the import and export formats.
+// Dashboards are referenced by UID, so that annotations can be moved between Grafana instances.
+type BulkItem struct {
+	DashboardUID string           `json:"dashboardUID,omitempty"`
+	PanelID      int64            `json:"panelId,omitempty"`
+	Time         int64            `json:"time"`
+	TimeEnd      int64            `json:"timeEnd,omitempty"`
+	Text         string           `json:"text"`
+	Tags         []string         `json:"tags,omitempty"`
+	Data         *simplejson.Json `json:"data,omitempty"`
+}
+
+// ImportCommand is the command to create annotations in bulk.
+type ImportCommand struct {
+	OrgID        int64
+	UserID       int64
+	SignedInUser identity.Requester
+	Format       string
+	Reader       io.Reader
+}
+
+// ImportResult is the result of a bulk import. Annotations that failed are skipped,
+// and the first errors are reported with the line they were read from.
+type ImportResult struct {
+	Imported int64         `json:"imported"`
+	Failed   int64         `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
+
+type ImportError struct {
+	Line    int64  `json:"line"`
+	Message string `json:"message"`
+}
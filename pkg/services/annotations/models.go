@@ -18,6 +18,7 @@ type ItemQuery struct {
 	Tags         []string `json:"tags"`
 	Type         string   `json:"type"`
 	MatchAny     bool     `json:"matchAny"`
+	Text         string   `json:"text"`
 	SignedInUser identity.Requester
 
 	Limit int64 `json:"limit"`
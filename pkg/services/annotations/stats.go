@@ -0,0 +1,211 @@
+package annotations
+
+import (
+	"sort"
+)
+
+// StatsQuery is the query for annotation statistics.
+// Annotations are filtered like in ItemQuery, Limit and Page are ignored.
+type StatsQuery struct {
+	ItemQuery
+
+	// Interval is the size of the time buckets in milliseconds, no time buckets are returned if it is 0.
+	// Buckets start at From, or at the Unix epoch if From is not set.
+	Interval int64 `json:"interval"`
+}
+
+// StatsResult is the result of an annotation statistics query.
+type StatsResult struct {
+	// Count is the number of annotations.
+	Count int64 `json:"count"`
+	// Regions are the statistics of the region annotations.
+	Regions RegionStats `json:"regions"`
+	// Tags are the number of annotations per tag, most used first.
+	Tags []*TagsDTO `json:"tags"`
+	// Dashboards are the number of annotations per dashboard, most annotated first.
+	// Organization annotations have the dashboard ID 0.
+	Dashboards []*DashboardStats `json:"dashboards"`
+	// Buckets are the number of annotations per time bucket, in ascending order by time.
+	Buckets []*BucketStats `json:"buckets"`
+	// Truncated is true if the statistics were computed from a subset of the annotations,
+	// because a store limits how many annotations it reads.
+	Truncated bool `json:"truncated"`
+}
+
+// RegionStats are the number and the total duration in milliseconds of region annotations.
+// The durations are clipped to the time range of the query if it has one.
+type RegionStats struct {
+	Count    int64 `json:"count"`
+	Duration int64 `json:"duration"`
+}
+
+type DashboardStats struct {
+	DashboardID  int64   `json:"dashboardId"`
+	DashboardUID *string `json:"dashboardUID"`
+	Count        int64   `json:"count"`
+}
+
+// BucketStats is the number of annotations, and the number of annotations per tag,
+// starting in the time bucket that starts at Time.
+type BucketStats struct {
+	Time  int64      `json:"time"`
+	Count int64      `json:"count"`
+	Tags  []*TagsDTO `json:"tags"`
+}
+
+// BucketTime returns the start of the time bucket of an annotation starting at epoch.
+// Annotations starting before from are counted in the first bucket.
+func BucketTime(epoch, from, interval int64) int64 {
+	if epoch < from {
+		return from
+	}
+	return epoch - (epoch-from)%interval
+}
+
+// RegionDuration returns the duration of the part of a region annotation from epoch to epochEnd that is in the time
+// range from from to to. The whole duration is returned if the time range is not set.
+func RegionDuration(epoch, epochEnd, from, to int64) int64 {
+	if from > 0 && to > 0 {
+		epoch = max(epoch, from)
+		epochEnd = min(epochEnd, to)
+	}
+	return max(epochEnd-epoch, 0)
+}
+
+// StatsBuilder aggregates annotations, or the statistics of several stores, into a StatsResult.
+type StatsBuilder struct {
+	from       int64
+	to         int64
+	interval   int64
+	count      int64
+	regions    RegionStats
+	tags       map[string]int64
+	dashboards map[int64]*DashboardStats
+	buckets    map[int64]*bucketCounts
+	truncated  bool
+}
+
+type bucketCounts struct {
+	count int64
+	tags  map[string]int64
+}
+
+func NewStatsBuilder(query StatsQuery) *StatsBuilder {
+	return &StatsBuilder{
+		from:       query.From,
+		to:         query.To,
+		interval:   query.Interval,
+		tags:       make(map[string]int64),
+		dashboards: make(map[int64]*DashboardStats),
+		buckets:    make(map[int64]*bucketCounts),
+	}
+}
+
+// AddItem adds a single annotation to the statistics.
+func (b *StatsBuilder) AddItem(item *ItemDTO) {
+	b.count++
+	if item.TimeEnd > item.Time {
+		b.regions.Count++
+		b.regions.Duration += RegionDuration(item.Time, item.TimeEnd, b.from, b.to)
+	}
+	for _, tag := range item.Tags {
+		b.tags[tag]++
+	}
+	b.addDashboard(item.DashboardID, item.DashboardUID, 1)
+
+	if b.interval > 0 {
+		bucket := b.bucket(BucketTime(item.Time, b.from, b.interval))
+		bucket.count++
+		for _, tag := range item.Tags {
+			bucket.tags[tag]++
+		}
+	}
+}
+
+// AddResult adds the statistics of another store.
+func (b *StatsBuilder) AddResult(res StatsResult) {
+	b.count += res.Count
+	b.regions.Count += res.Regions.Count
+	b.regions.Duration += res.Regions.Duration
+	b.truncated = b.truncated || res.Truncated
+	for _, tag := range res.Tags {
+		b.tags[tag.Tag] += tag.Count
+	}
+	for _, dashboard := range res.Dashboards {
+		b.addDashboard(dashboard.DashboardID, dashboard.DashboardUID, dashboard.Count)
+	}
+	for _, stats := range res.Buckets {
+		bucket := b.bucket(stats.Time)
+		bucket.count += stats.Count
+		for _, tag := range stats.Tags {
+			bucket.tags[tag.Tag] += tag.Count
+		}
+	}
+}
+
+func (b *StatsBuilder) addDashboard(id int64, uid *string, count int64) {
+	dashboard, ok := b.dashboards[id]
+	if !ok {
+		dashboard = &DashboardStats{DashboardID: id}
+		b.dashboards[id] = dashboard
+	}
+	if dashboard.DashboardUID == nil && uid != nil && *uid != "" {
+		dashboard.DashboardUID = uid
+	}
+	dashboard.Count += count
+}
+
+func (b *StatsBuilder) bucket(time int64) *bucketCounts {
+	bucket, ok := b.buckets[time]
+	if !ok {
+		bucket = &bucketCounts{tags: make(map[string]int64)}
+		b.buckets[time] = bucket
+	}
+	return bucket
+}
+
+// Result returns the aggregated statistics.
+func (b *StatsBuilder) Result() StatsResult {
+	res := StatsResult{
+		Count:      b.count,
+		Regions:    b.regions,
+		Tags:       sortedTagCounts(b.tags),
+		Dashboards: make([]*DashboardStats, 0, len(b.dashboards)),
+		Buckets:    make([]*BucketStats, 0, len(b.buckets)),
+		Truncated:  b.truncated,
+	}
+
+	for _, dashboard := range b.dashboards {
+		res.Dashboards = append(res.Dashboards, dashboard)
+	}
+	sort.Slice(res.Dashboards, func(i, j int) bool {
+		if res.Dashboards[i].Count != res.Dashboards[j].Count {
+			return res.Dashboards[i].Count > res.Dashboards[j].Count
+		}
+		return res.Dashboards[i].DashboardID < res.Dashboards[j].DashboardID
+	})
+
+	for time, bucket := range b.buckets {
+		res.Buckets = append(res.Buckets, &BucketStats{Time: time, Count: bucket.count, Tags: sortedTagCounts(bucket.tags)})
+	}
+	sort.Slice(res.Buckets, func(i, j int) bool {
+		return res.Buckets[i].Time < res.Buckets[j].Time
+	})
+
+	return res
+}
+
+// sortedTagCounts returns the tag counts in descending order by count, then in ascending order by tag.
+func sortedTagCounts(counts map[string]int64) []*TagsDTO {
+	tags := make([]*TagsDTO, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, &TagsDTO{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -28,39 +30,44 @@ import (
 )
 
 type CleanUpService struct {
-	log                       log.Logger
-	tracer                    tracing.Tracer
-	store                     db.DB
-	Cfg                       *setting.Cfg
-	ServerLockService         *serverlock.ServerLockService
-	ShortURLService           shorturls.Service
-	QueryHistoryService       queryhistory.Service
-	dashboardVersionService   dashver.Service
-	dashboardSnapshotService  dashboardsnapshots.Service
-	deleteExpiredImageService *image.DeleteExpiredService
-	tempUserService           tempuser.Service
-	annotationCleaner         annotations.Cleaner
-	dashboardService          dashboards.DashboardService
+	log                            log.Logger
+	tracer                         tracing.Tracer
+	store                          db.DB
+	Cfg                            *setting.Cfg
+	ServerLockService              *serverlock.ServerLockService
+	ShortURLService                shorturls.Service
+	QueryHistoryService            queryhistory.Service
+	dashboardVersionService        dashver.Service
+	dashboardSnapshotService       dashboardsnapshots.Service
+	deleteExpiredImageService      *image.DeleteExpiredService
+	deleteExpiredDeliveriesService *notifier.DeleteExpiredDeliveriesService
+	tempUserService                tempuser.Service
+	annotationCleaner              annotations.Cleaner
+	dashboardService               dashboards.DashboardService
+	publicDashboardService         publicdashboards.Service
 }
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService) *CleanUpService {
+	deleteExpiredDeliveriesService *notifier.DeleteExpiredDeliveriesService, tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService,
+	publicDashboardService publicdashboards.Service) *CleanUpService {
 	s := &CleanUpService{
-		Cfg:                       cfg,
-		ServerLockService:         serverLockService,
-		ShortURLService:           shortURLService,
-		QueryHistoryService:       queryHistoryService,
-		store:                     sqlstore,
-		log:                       log.New("cleanup"),
-		dashboardVersionService:   dashboardVersionService,
-		dashboardSnapshotService:  dashSnapSvc,
-		deleteExpiredImageService: deleteExpiredImageService,
-		tempUserService:           tempUserService,
-		tracer:                    tracer,
-		annotationCleaner:         annotationCleaner,
-		dashboardService:          dashboardService,
+		Cfg:                            cfg,
+		ServerLockService:              serverLockService,
+		ShortURLService:                shortURLService,
+		QueryHistoryService:            queryHistoryService,
+		store:                          sqlstore,
+		log:                            log.New("cleanup"),
+		dashboardVersionService:        dashboardVersionService,
+		dashboardSnapshotService:       dashSnapSvc,
+		deleteExpiredImageService:      deleteExpiredImageService,
+		deleteExpiredDeliveriesService: deleteExpiredDeliveriesService,
+		tempUserService:                tempUserService,
+		tracer:                         tracer,
+		annotationCleaner:              annotationCleaner,
+		dashboardService:               dashboardService,
+		publicDashboardService:         publicDashboardService,
 	}
 	return s
 }
@@ -101,12 +108,14 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
 		{"delete expired images", srv.deleteExpiredImages},
+		{"delete expired notification deliveries", srv.deleteExpiredNotificationDeliveries},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
 		{"expire old email verifications", srv.expireOldVerifications},
 		{"cleanup trash dashboards", srv.cleanUpTrashDashboards},
+		{"disable expired public dashboards", srv.disableExpiredPublicDashboards},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -228,6 +237,18 @@ func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredNotificationDeliveries(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
+		return
+	}
+	if rowsAffected, err := srv.deleteExpiredDeliveriesService.DeleteExpired(ctx); err != nil {
+		logger.Error("Failed to delete expired notification deliveries", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired notification deliveries", "rows affected", rowsAffected)
+	}
+}
+
 func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	maxInviteLifetime := srv.Cfg.UserInviteMaxLifetime
@@ -310,3 +331,15 @@ func (srv *CleanUpService) cleanUpTrashDashboards(ctx context.Context) {
 		logger.Debug("Cleaned up deleted dashboards", "dashboards affected", affected)
 	}
 }
+
+func (srv *CleanUpService) disableExpiredPublicDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.PublicDashboardsEnabled {
+		return
+	}
+	if rowsAffected, err := srv.publicDashboardService.DisableExpired(ctx); err != nil {
+		logger.Error("Failed to disable expired public dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Disabled expired public dashboards", "rows affected", rowsAffected)
+	}
+}
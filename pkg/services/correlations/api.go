@@ -27,6 +27,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 			entities.Get("/", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
 			entities.Delete("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.deleteHandler))
 			entities.Patch("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Post("/resolve", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.resolveHandler))
 		})
 	}, middleware.ReqSignedIn)
 }
@@ -240,6 +241,69 @@ type GetCorrelationResponse struct {
 	Body Correlation `json:"body"`
 }
 
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/resolve correlations resolveCorrelation
+//
+// Resolves the target of a correlation for a trace ID or a log line and optionally executes the target query.
+//
+// Responses:
+// 200: resolveCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) resolveHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := ResolveCorrelationCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UID = web.Params(c.Req)[":correlationUID"]
+	cmd.SourceUID = web.Params(c.Req)[":uid"]
+	cmd.OrgId = c.SignedInUser.GetOrgID()
+
+	result, err := s.ResolveCorrelation(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCorrelationNotFound):
+			return response.Error(http.StatusNotFound, "Correlation not found", err)
+		case errors.Is(err, ErrSourceDataSourceDoesNotExists):
+			return response.Error(http.StatusNotFound, "Source data source not found", err)
+		case errors.Is(err, ErrTraceNotFound):
+			return response.Error(http.StatusNotFound, "Trace not found", err)
+		case errors.Is(err, ErrResolveSourceMissing),
+			errors.Is(err, ErrCorrelationFieldNotFound),
+			errors.Is(err, ErrInvalidTransformationRegex),
+			errors.Is(err, ErrCorrelationVariablesUndefined),
+			errors.Is(err, ErrCorrelationNotExecutable),
+			errors.Is(err, ErrTraceSourceNotSupported):
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to resolve correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:parameters resolveCorrelation
+type ResolveCorrelationParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in: body
+	// required:true
+	Body ResolveCorrelationCommand `json:"body"`
+}
+
+// swagger:response resolveCorrelationResponse
+type ResolveCorrelationResponse struct {
+	// in: body
+	Body ResolveCorrelationResponseBody `json:"body"`
+}
+
 // swagger:route GET /datasources/uid/{sourceUID}/correlations correlations getCorrelationsBySourceUID
 //
 // Gets all correlations originating from the given data source.
@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	queryservice "github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -19,6 +21,7 @@ var (
 )
 
 func ProvideService(sqlStore db.DB, routeRegister routing.RouteRegister, ds datasources.DataSourceService, ac accesscontrol.AccessControl, bus bus.Bus, qs quota.Service, cfg *setting.Cfg,
+	queryService queryservice.Service,
 ) (*CorrelationsService, error) {
 	s := &CorrelationsService{
 		SQLStore:          sqlStore,
@@ -27,6 +30,7 @@ func ProvideService(sqlStore db.DB, routeRegister routing.RouteRegister, ds data
 		DataSourceService: ds,
 		AccessControl:     ac,
 		QuotaService:      qs,
+		QueryService:      queryService,
 	}
 
 	s.registerAPIEndpoints()
@@ -64,6 +68,7 @@ type CorrelationsService struct {
 	DataSourceService datasources.DataSourceService
 	AccessControl     accesscontrol.AccessControl
 	QuotaService      quota.Service
+	QueryService      queryservice.Service
 }
 
 func (s CorrelationsService) CreateCorrelation(ctx context.Context, cmd CreateCorrelationCommand) (Correlation, error) {
@@ -103,6 +108,10 @@ func (s CorrelationsService) GetCorrelations(ctx context.Context, cmd GetCorrela
 	return s.getCorrelations(ctx, cmd)
 }
 
+func (s CorrelationsService) ResolveCorrelation(ctx context.Context, user identity.Requester, cmd ResolveCorrelationCommand) (ResolveCorrelationResponseBody, error) {
+	return s.resolveCorrelation(ctx, user, cmd)
+}
+
 func (s CorrelationsService) DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
 	return s.deleteCorrelationsBySourceUID(ctx, cmd)
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
 	"github.com/grafana/grafana/pkg/services/quota"
 )
 
@@ -21,6 +23,13 @@ var (
 	ErrCorrelationsQuotaFailed       = errors.New("error getting correlations quota")
 	ErrCorrelationsQuotaReached      = errors.New("correlations quota reached")
 	ErrInvalidConfigType             = errors.New("correlation contains non default value in config.type")
+	ErrResolveSourceMissing          = errors.New("one of traceId, logLine or fields is required")
+	ErrCorrelationFieldNotFound      = errors.New("source values do not contain the correlation field")
+	ErrInvalidTransformationRegex    = errors.New("invalid regex transformation expression")
+	ErrCorrelationVariablesUndefined = errors.New("correlation target contains undefined variables")
+	ErrCorrelationNotExecutable      = errors.New("only correlations of type query can be executed")
+	ErrTraceSourceNotSupported       = errors.New("trace IDs can only be resolved for Tempo data sources")
+	ErrTraceNotFound                 = errors.New("trace not found")
 )
 
 const (
@@ -290,3 +299,50 @@ type DeleteCorrelationsByTargetUIDCommand struct {
 	TargetUID string
 	OrgId     int64
 }
+
+// ResolveCorrelationCommand is the command for resolving the target of a correlation
+// swagger:model
+type ResolveCorrelationCommand struct {
+	UID       string `json:"-"`
+	SourceUID string `json:"-"`
+	OrgId     int64  `json:"-"`
+	// Trace ID to fetch from the source Tempo data source. The values of the root span are used as source values.
+	// example: 2f4b3c1a9d8e7f60
+	TraceID string `json:"traceId"`
+	// Span ID within the trace to use instead of the root span
+	SpanID string `json:"spanId"`
+	// Log line used as value of the correlation field, for example a Loki log line
+	// example: level=error traceID=2f4b3c1a9d8e7f60 msg="request failed"
+	LogLine string `json:"logLine"`
+	// Additional source values keyed by field name
+	// example: {"job":"app","level":"error"}
+	Fields map[string]string `json:"fields"`
+	// Start of the time range used for the trace lookup and target query
+	// default: now-1h
+	From string `json:"from"`
+	// End of the time range used for the trace lookup and target query
+	// default: now
+	To string `json:"to"`
+	// Execute the interpolated target query through the query service
+	Execute bool `json:"execute"`
+}
+
+func (c ResolveCorrelationCommand) Validate() error {
+	if c.TraceID == "" && c.LogLine == "" && len(c.Fields) == 0 {
+		return ErrResolveSourceMissing
+	}
+	return nil
+}
+
+// ResolveCorrelationResponseBody is the response struct for ResolveCorrelationCommand
+// swagger:model
+type ResolveCorrelationResponseBody struct {
+	Correlation Correlation `json:"correlation"`
+	// Target of the correlation with all variables interpolated
+	// example: {"expr":"{job=\"app\"} |= \"2f4b3c1a9d8e7f60\""}
+	Target map[string]any `json:"target"`
+	// Variables available for interpolation
+	Variables map[string]string `json:"variables"`
+	// Result of the target query, only set when execute is true
+	Result *backend.QueryDataResponse `json:"result,omitempty"`
+}
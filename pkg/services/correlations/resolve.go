@@ -0,0 +1,348 @@
+package correlations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+const (
+	defaultResolveFrom = "now-1h"
+	defaultResolveTo   = "now"
+
+	// valueRawVariable holds the value of the field the correlation is attached to.
+	valueRawVariable = "__value.raw"
+	// traceSourceRefID is the refId used when fetching the source trace from Tempo.
+	traceSourceRefID = "correlation-source"
+)
+
+var (
+	// variableRegex matches ${var}, ${var:format} and $var template variables.
+	variableRegex = regexp.MustCompile(`\$\{([^}:]+)(?::[^}]*)?\}|\$([A-Za-z0-9_]+)`)
+)
+
+// resolveSourceValues builds the set of variables available to a correlation
+// target from the source values of the command. The trace is already expected
+// to be flattened into cmd.Fields when cmd.TraceID is set.
+func resolveSourceValues(correlation Correlation, cmd ResolveCorrelationCommand) (map[string]string, error) {
+	vars := make(map[string]string, len(cmd.Fields)+1)
+	for k, v := range cmd.Fields {
+		vars[k] = v
+	}
+
+	if cmd.LogLine != "" {
+		vars[correlation.Config.Field] = cmd.LogLine
+	}
+
+	value, ok := vars[correlation.Config.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrCorrelationFieldNotFound, correlation.Config.Field)
+	}
+	vars[valueRawVariable] = value
+
+	for _, t := range correlation.Config.Transformations {
+		fieldValue := value
+		if t.Field != "" {
+			fieldValue = vars[t.Field]
+		}
+
+		transformed, err := applyTransformation(t, fieldValue, correlation.Config.Field)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range transformed {
+			vars[k] = v
+		}
+	}
+
+	return vars, nil
+}
+
+// applyTransformation mirrors the transformations applied by the frontend when
+// building correlation links.
+func applyTransformation(t Transformation, fieldValue string, fieldName string) (map[string]string, error) {
+	result := map[string]string{}
+
+	switch t.Type {
+	case "regex":
+		if t.Expression == "" {
+			return result, nil
+		}
+		re, err := regexp.Compile("(?i)" + t.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTransformationRegex, err)
+		}
+		names := re.SubexpNames()
+		for _, match := range re.FindAllStringSubmatch(fieldValue, -1) {
+			named := false
+			for i, name := range names {
+				if name != "" {
+					result[name] = match[i]
+					named = true
+				}
+			}
+			if named {
+				continue
+			}
+
+			key := fieldName
+			if t.MapValue != "" {
+				key = t.MapValue
+			}
+			if len(match) > 1 && match[1] != "" {
+				result[key] = match[1]
+			} else {
+				result[key] = match[0]
+			}
+		}
+	case "logfmt":
+		decoder := logfmt.NewDecoder(strings.NewReader(fieldValue))
+		for decoder.ScanRecord() {
+			for decoder.ScanKeyval() {
+				result[string(decoder.Key())] = string(decoder.Value())
+			}
+		}
+		if err := decoder.Err(); err != nil {
+			return nil, fmt.Errorf("failed to parse logfmt: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// interpolateTarget replaces all template variables in the string values of the
+// target. It returns the names of all variables that could not be resolved.
+func interpolateTarget(target map[string]any, vars map[string]string) (map[string]any, []string) {
+	missing := map[string]struct{}{}
+	interpolated, _ := interpolateValue(target, vars, missing).(map[string]any)
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return interpolated, names
+}
+
+func interpolateValue(value any, vars map[string]string, missing map[string]struct{}) any {
+	switch v := value.(type) {
+	case string:
+		return variableRegex.ReplaceAllStringFunc(v, func(match string) string {
+			groups := variableRegex.FindStringSubmatch(match)
+			name := groups[1]
+			if name == "" {
+				name = groups[2]
+			}
+			if replacement, ok := vars[name]; ok {
+				return replacement
+			}
+			missing[name] = struct{}{}
+			return match
+		})
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = interpolateValue(item, vars, missing)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item, vars, missing)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func (s CorrelationsService) resolveCorrelation(ctx context.Context, user identity.Requester, cmd ResolveCorrelationCommand) (ResolveCorrelationResponseBody, error) {
+	if err := cmd.Validate(); err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+
+	correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{
+		UID:       cmd.UID,
+		SourceUID: cmd.SourceUID,
+		OrgId:     cmd.OrgId,
+	})
+	if err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+
+	if cmd.From == "" {
+		cmd.From = defaultResolveFrom
+	}
+	if cmd.To == "" {
+		cmd.To = defaultResolveTo
+	}
+
+	if cmd.TraceID != "" {
+		fields, err := s.fetchTraceFields(ctx, user, cmd)
+		if err != nil {
+			return ResolveCorrelationResponseBody{}, err
+		}
+		// explicitly provided fields take precedence over the ones from the trace
+		for k, v := range cmd.Fields {
+			fields[k] = v
+		}
+		cmd.Fields = fields
+	}
+
+	vars, err := resolveSourceValues(correlation, cmd)
+	if err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+
+	target, missing := interpolateTarget(correlation.Config.Target, vars)
+	if len(missing) > 0 {
+		return ResolveCorrelationResponseBody{}, fmt.Errorf("%w: %s", ErrCorrelationVariablesUndefined, strings.Join(missing, ", "))
+	}
+
+	result := ResolveCorrelationResponseBody{
+		Correlation: correlation,
+		Target:      target,
+		Variables:   vars,
+	}
+
+	if !cmd.Execute {
+		return result, nil
+	}
+
+	if correlation.Type != query || correlation.TargetUID == nil {
+		return ResolveCorrelationResponseBody{}, ErrCorrelationNotExecutable
+	}
+
+	queryModel := make(map[string]any, len(target)+2)
+	for k, v := range target {
+		queryModel[k] = v
+	}
+	if _, ok := queryModel["refId"]; !ok {
+		queryModel["refId"] = "A"
+	}
+	queryModel["datasource"] = map[string]any{"uid": *correlation.TargetUID}
+
+	resp, err := s.QueryService.QueryData(ctx, user, false, dtos.MetricRequest{
+		From:    cmd.From,
+		To:      cmd.To,
+		Queries: []*simplejson.Json{simplejson.NewFromAny(queryModel)},
+	})
+	if err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+	result.Result = resp
+
+	return result, nil
+}
+
+// fetchTraceFields runs a trace ID query against the source Tempo data source and
+// returns the values of the root span, or of cmd.SpanID when given, keyed by field name.
+func (s CorrelationsService) fetchTraceFields(ctx context.Context, user identity.Requester, cmd ResolveCorrelationCommand) (map[string]string, error) {
+	ds, err := s.DataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{
+		OrgID: cmd.OrgId,
+		UID:   cmd.SourceUID,
+	})
+	if err != nil {
+		return nil, ErrSourceDataSourceDoesNotExists
+	}
+	if ds.Type != datasources.DS_TEMPO {
+		return nil, fmt.Errorf("%w: %q", ErrTraceSourceNotSupported, ds.Type)
+	}
+
+	resp, err := s.QueryService.QueryData(ctx, user, false, dtos.MetricRequest{
+		From: cmd.From,
+		To:   cmd.To,
+		Queries: []*simplejson.Json{simplejson.NewFromAny(map[string]any{
+			"refId":      traceSourceRefID,
+			"queryType":  "traceId",
+			"query":      cmd.TraceID,
+			"datasource": map[string]any{"uid": ds.UID, "type": ds.Type},
+		})},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := resp.Responses[traceSourceRefID]
+	if !ok {
+		return nil, ErrTraceNotFound
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTraceNotFound, res.Error)
+	}
+
+	return traceRowFields(res, cmd.SpanID)
+}
+
+func traceRowFields(res backend.DataResponse, spanID string) (map[string]string, error) {
+	for _, frame := range res.Frames {
+		row := traceRow(frame, spanID)
+		if row < 0 {
+			continue
+		}
+
+		fields := make(map[string]string, len(frame.Fields))
+		for _, field := range frame.Fields {
+			fields[field.Name] = fieldValueString(field, row)
+		}
+		return fields, nil
+	}
+
+	return nil, ErrTraceNotFound
+}
+
+// traceRow returns the row of the span with the given ID, or the first root span
+// when no span ID is given.
+func traceRow(frame *data.Frame, spanID string) int {
+	spanField, _ := frame.FieldByName("spanID")
+	parentField, _ := frame.FieldByName("parentSpanID")
+	if spanField == nil {
+		return -1
+	}
+
+	for i := 0; i < spanField.Len(); i++ {
+		if spanID != "" {
+			if fieldValueString(spanField, i) == spanID {
+				return i
+			}
+			continue
+		}
+		if parentField == nil || fieldValueString(parentField, i) == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func fieldValueString(field *data.Field, row int) string {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return ""
+	}
+
+	switch value := v.(type) {
+	case string:
+		return value
+	case json.RawMessage:
+		return string(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
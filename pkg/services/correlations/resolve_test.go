@@ -0,0 +1,107 @@
+package correlations
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCorrelation(t *testing.T) {
+	t.Run("ResolveCorrelationCommand Validate", func(t *testing.T) {
+		require.ErrorIs(t, ResolveCorrelationCommand{}.Validate(), ErrResolveSourceMissing)
+		require.NoError(t, ResolveCorrelationCommand{TraceID: "abc"}.Validate())
+		require.NoError(t, ResolveCorrelationCommand{LogLine: "msg=hello"}.Validate())
+		require.NoError(t, ResolveCorrelationCommand{Fields: map[string]string{"a": "b"}}.Validate())
+	})
+
+	t.Run("log line is used as value of the correlation field", func(t *testing.T) {
+		correlation := Correlation{
+			Config: CorrelationConfig{
+				Field: "Line",
+				Transformations: Transformations{
+					{Type: "logfmt"},
+					{Type: "regex", Expression: `user=(\w+)`, MapValue: "username"},
+				},
+			},
+		}
+
+		vars, err := resolveSourceValues(correlation, ResolveCorrelationCommand{
+			LogLine: `level=error traceID=abc123 user=alice msg="request failed"`,
+			Fields:  map[string]string{"job": "app"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "app", vars["job"])
+		require.Equal(t, "abc123", vars["traceID"])
+		require.Equal(t, "request failed", vars["msg"])
+		require.Equal(t, "alice", vars["username"])
+		require.Equal(t, `level=error traceID=abc123 user=alice msg="request failed"`, vars["__value.raw"])
+	})
+
+	t.Run("regex with named groups sets a variable per group", func(t *testing.T) {
+		vars, err := applyTransformation(Transformation{Type: "regex", Expression: `(?P<method>GET|POST) (?P<path>\S+)`}, "POST /api/login", "Line")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"method": "POST", "path": "/api/login"}, vars)
+	})
+
+	t.Run("regex without groups maps the match to the field name", func(t *testing.T) {
+		vars, err := applyTransformation(Transformation{Type: "regex", Expression: `[a-f0-9]{8}`}, "trace deadbeef", "traceID")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"traceID": "deadbeef"}, vars)
+	})
+
+	t.Run("invalid regex returns an error", func(t *testing.T) {
+		_, err := applyTransformation(Transformation{Type: "regex", Expression: `(`}, "value", "field")
+		require.ErrorIs(t, err, ErrInvalidTransformationRegex)
+	})
+
+	t.Run("missing correlation field returns an error", func(t *testing.T) {
+		_, err := resolveSourceValues(Correlation{Config: CorrelationConfig{Field: "traceID"}}, ResolveCorrelationCommand{Fields: map[string]string{"job": "app"}})
+		require.ErrorIs(t, err, ErrCorrelationFieldNotFound)
+	})
+
+	t.Run("target is interpolated recursively", func(t *testing.T) {
+		target := map[string]any{
+			"expr":   `{job="${job}"} |= "$traceID"`,
+			"nested": map[string]any{"value": "${__value.raw:raw}"},
+			"list":   []any{"$job", 42},
+		}
+
+		interpolated, missing := interpolateTarget(target, map[string]string{"job": "app", "traceID": "abc", "__value.raw": "raw value"})
+		require.Empty(t, missing)
+		require.Equal(t, map[string]any{
+			"expr":   `{job="app"} |= "abc"`,
+			"nested": map[string]any{"value": "raw value"},
+			"list":   []any{"app", 42},
+		}, interpolated)
+	})
+
+	t.Run("undefined variables are reported", func(t *testing.T) {
+		_, missing := interpolateTarget(map[string]any{"expr": "${b} ${a} $b"}, map[string]string{})
+		require.Equal(t, []string{"a", "b"}, missing)
+	})
+
+	t.Run("trace fields are taken from the root span or the requested span", func(t *testing.T) {
+		frame := data.NewFrame("Trace",
+			data.NewField("traceID", nil, []string{"t1", "t1"}),
+			data.NewField("spanID", nil, []string{"child", "root"}),
+			data.NewField("parentSpanID", nil, []string{"root", ""}),
+			data.NewField("serviceName", nil, []string{"db", "frontend"}),
+			data.NewField("duration", nil, []float64{1.5, 10}),
+		)
+		res := backend.DataResponse{Frames: data.Frames{frame}}
+
+		fields, err := traceRowFields(res, "")
+		require.NoError(t, err)
+		require.Equal(t, "frontend", fields["serviceName"])
+		require.Equal(t, "10", fields["duration"])
+
+		fields, err = traceRowFields(res, "child")
+		require.NoError(t, err)
+		require.Equal(t, "db", fields["serviceName"])
+
+		_, err = traceRowFields(res, "unknown")
+		require.ErrorIs(t, err, ErrTraceNotFound)
+	})
+}
@@ -0,0 +1,130 @@
+package lint
+
+import (
+	"sync"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// Severity is how serious a problem is. Errors break the dashboard for its users,
+// warnings are deviations from best practices.
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Rule checks dashboards for one kind of problem.
+type Rule interface {
+	// Name identifies the rule in the issues, for example panel-datasource-missing.
+	Name() string
+	// Description describes the problems found by the rule.
+	Description() string
+	Severity() Severity
+	// Check returns the problems found in the dashboard JSON model.
+	Check(dashboard *simplejson.Json) []Problem
+}
+
+// Problem is a problem found by a rule.
+type Problem struct {
+	// Path is the path of the element with the problem in the dashboard JSON model, for example panels[2].datasource.
+	Path    string
+	Message string
+}
+
+// Issue is a problem found in a dashboard, with the rule that found it.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Result is the result of linting a dashboard.
+type Result struct {
+	Issues   []Issue `json:"issues"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+}
+
+// NewRule returns a rule that checks dashboards with the check function.
+func NewRule(name, description string, severity Severity, check func(dashboard *simplejson.Json) []Problem) Rule {
+	return &rule{name: name, description: description, severity: severity, check: check}
+}
+
+type rule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(dashboard *simplejson.Json) []Problem
+}
+
+func (r *rule) Name() string {
+	return r.name
+}
+
+func (r *rule) Description() string {
+	return r.description
+}
+
+func (r *rule) Severity() Severity {
+	return r.severity
+}
+
+func (r *rule) Check(dashboard *simplejson.Json) []Problem {
+	return r.check(dashboard)
+}
+
+// Linter checks dashboards with a set of rules.
+// Services can register their own rules, to check dashboards for problems that are specific to them.
+type Linter struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// ProvideService returns a linter with the default rules.
+func ProvideService() *Linter {
+	return New(DefaultRules()...)
+}
+
+func New(rules ...Rule) *Linter {
+	return &Linter{rules: rules}
+}
+
+// Register adds rules to the linter.
+func (l *Linter) Register(rules ...Rule) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rules = append(l.rules, rules...)
+}
+
+// Rules returns the rules of the linter.
+func (l *Linter) Rules() []Rule {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]Rule(nil), l.rules...)
+}
+
+// Lint checks the dashboard JSON model with all rules, and returns the issues in the order of the rules.
+func (l *Linter) Lint(dashboard *simplejson.Json) Result {
+	res := Result{Issues: []Issue{}}
+	for _, rule := range l.Rules() {
+		for _, problem := range rule.Check(dashboard) {
+			res.Issues = append(res.Issues, Issue{
+				Rule:     rule.Name(),
+				Severity: rule.Severity(),
+				Path:     problem.Path,
+				Message:  problem.Message,
+			})
+			if rule.Severity() == SeverityError {
+				res.Errors++
+			} else {
+				res.Warnings++
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestLinter(t *testing.T) {
+	t.Run("Should not find issues in a valid dashboard", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(`{
+			"uid": "valid",
+			"title": "Valid",
+			"links": [
+				{ "type": "link", "title": "Docs", "url": "https://grafana.com/docs" },
+				{ "type": "dashboards", "tags": ["ops"] }
+			],
+			"panels": [
+				{ "type": "text", "title": "Notes" },
+				{ "type": "timeseries", "title": "CPU", "datasource": { "type": "prometheus", "uid": "prom" }, "targets": [{ "expr": "up" }] },
+				{ "type": "stat", "title": "Mixed", "datasource": { "uid": "-- Mixed --" }, "targets": [{ "datasource": { "uid": "prom" } }],
+				  "links": [{ "title": "Details", "url": "/d/details?var-host=${__field.labels.host}" }] }
+			],
+			"templating": { "list": [{ "name": "env" }, { "name": "host" }] }
+		}`))
+		require.NoError(t, err)
+
+		res := ProvideService().Lint(dashboard)
+		assert.Empty(t, res.Issues)
+		assert.Zero(t, res.Errors)
+		assert.Zero(t, res.Warnings)
+	})
+
+	t.Run("Should find the issues of the default rules", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(`{
+			"title": "Broken",
+			"links": [{ "type": "link", "title": "Empty" }],
+			"panels": [
+				{ "type": "graph", "title": "CPU", "targets": [{ "expr": "up" }] },
+				{ "type": "row", "title": "Collapsed", "panels": [
+					{ "type": "stat", "title": "Memory", "datasource": { "uid": "-- Mixed --" }, "targets": [{ "datasource": { "uid": "prom" } }, {}],
+					  "links": [{ "title": "Blank", "url": " " }] }
+				] }
+			],
+			"rows": [{ "panels": [{ "type": "singlestat" }] }],
+			"templating": { "list": [{ "name": "env" }, { "name": "host" }, { "name": "env" }] }
+		}`))
+		require.NoError(t, err)
+
+		res := ProvideService().Lint(dashboard)
+		assert.Equal(t, []Issue{
+			{Rule: "uid-missing", Severity: SeverityWarning, Path: "uid", Message: "Dashboard has no UID"},
+			{Rule: "panel-datasource-missing", Severity: SeverityWarning, Path: "panels[0].datasource", Message: `Panel "CPU" has queries without a data source, the default data source is used`},
+			{Rule: "panel-datasource-missing", Severity: SeverityWarning, Path: "panels[1].panels[0].datasource", Message: `Panel "Memory" has queries without a data source, the default data source is used`},
+			{Rule: "panel-type-deprecated", Severity: SeverityWarning, Path: "panels[0].type", Message: `Panel "CPU" uses the deprecated panel type graph, use timeseries instead`},
+			{Rule: "panel-type-deprecated", Severity: SeverityWarning, Path: "rows[0].panels[0].type", Message: "Panel uses the deprecated panel type singlestat, use stat instead"},
+			{Rule: "variable-name-duplicate", Severity: SeverityError, Path: "templating.list[2].name", Message: "Variable name env is already used by templating.list[0]"},
+			{Rule: "link-url-missing", Severity: SeverityWarning, Path: "links[0].url", Message: `Link "Empty" has no URL`},
+			{Rule: "link-url-missing", Severity: SeverityWarning, Path: "panels[1].panels[0].links[0].url", Message: `Link "Blank" has no URL`},
+		}, res.Issues)
+		assert.Equal(t, 1, res.Errors)
+		assert.Equal(t, 7, res.Warnings)
+	})
+
+	t.Run("Should check dashboards with registered rules", func(t *testing.T) {
+		linter := New()
+		linter.Register(NewRule("title-missing", "Dashboards must have a title.", SeverityError, func(dashboard *simplejson.Json) []Problem {
+			if dashboard.Get("title").MustString() == "" {
+				return []Problem{{Path: "title", Message: "Dashboard has no title"}}
+			}
+			return nil
+		}))
+
+		res := linter.Lint(simplejson.New())
+		assert.Equal(t, []Issue{{Rule: "title-missing", Severity: SeverityError, Path: "title", Message: "Dashboard has no title"}}, res.Issues)
+		assert.Equal(t, 1, res.Errors)
+		require.Len(t, linter.Rules(), 1)
+	})
+}
@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const mixedDatasourceUID = "-- Mixed --"
+
+// deprecatedPanelTypes are the deprecated panel types and the panel types that replace them.
+var deprecatedPanelTypes = map[string]string{
+	"graph":                    "timeseries",
+	"singlestat":               "stat",
+	"grafana-singlestat-panel": "stat",
+	"table-old":                "table",
+	"grafana-piechart-panel":   "piechart",
+	"grafana-worldmap-panel":   "geomap",
+}
+
+// DefaultRules returns the rules that are built into Grafana.
+func DefaultRules() []Rule {
+	return []Rule{
+		NewRule("uid-missing", "Dashboards should have a UID, so that links to them do not change when they are imported again.", SeverityWarning, checkUID),
+		NewRule("panel-datasource-missing", "Panels with queries should select a data source, instead of depending on the default data source.", SeverityWarning, checkPanelDatasources),
+		NewRule("panel-type-deprecated", "Panels should not use deprecated panel types.", SeverityWarning, checkPanelTypes),
+		NewRule("variable-name-duplicate", "Template variables must have unique names.", SeverityError, checkVariableNames),
+		NewRule("link-url-missing", "Dashboard and panel links should have a URL.", SeverityWarning, checkLinks),
+	}
+}
+
+func checkUID(dashboard *simplejson.Json) []Problem {
+	if dashboard.Get("uid").MustString() != "" {
+		return nil
+	}
+	return []Problem{{Path: "uid", Message: "Dashboard has no UID"}}
+}
+
+func checkPanelDatasources(dashboard *simplejson.Json) []Problem {
+	var problems []Problem
+	forEachPanel(dashboard, func(path string, panel *simplejson.Json) {
+		targets := panel.Get("targets")
+		if panel.Get("type").MustString() == "row" || len(targets.MustArray()) == 0 {
+			return
+		}
+
+		uid := datasourceUID(panel.Get("datasource"))
+		if uid != "" && uid != mixedDatasourceUID {
+			return
+		}
+		for i := range targets.MustArray() {
+			if datasourceUID(targets.GetIndex(i).Get("datasource")) == "" {
+				problems = append(problems, Problem{
+					Path:    path + ".datasource",
+					Message: fmt.Sprintf("%s has queries without a data source, the default data source is used", panelName(panel)),
+				})
+				return
+			}
+		}
+	})
+	return problems
+}
+
+func checkPanelTypes(dashboard *simplejson.Json) []Problem {
+	var problems []Problem
+	forEachPanel(dashboard, func(path string, panel *simplejson.Json) {
+		panelType := panel.Get("type").MustString()
+		if replacement, ok := deprecatedPanelTypes[panelType]; ok {
+			problems = append(problems, Problem{
+				Path:    path + ".type",
+				Message: fmt.Sprintf("%s uses the deprecated panel type %s, use %s instead", panelName(panel), panelType, replacement),
+			})
+		}
+	})
+	return problems
+}
+
+func checkVariableNames(dashboard *simplejson.Json) []Problem {
+	var problems []Problem
+	seen := make(map[string]string)
+	variables := dashboard.Get("templating").Get("list")
+	for i := range variables.MustArray() {
+		path := fmt.Sprintf("templating.list[%d]", i)
+		name := variables.GetIndex(i).Get("name").MustString()
+		if name == "" {
+			continue
+		}
+		if first, ok := seen[name]; ok {
+			problems = append(problems, Problem{
+				Path:    path + ".name",
+				Message: fmt.Sprintf("Variable name %s is already used by %s", name, first),
+			})
+			continue
+		}
+		seen[name] = path
+	}
+	return problems
+}
+
+func checkLinks(dashboard *simplejson.Json) []Problem {
+	var problems []Problem
+	checkURLs := func(path string, links *simplejson.Json) {
+		for i := range links.MustArray() {
+			link := links.GetIndex(i)
+			// dashboard links can list dashboards by tag instead of linking to a URL
+			if linkType := link.Get("type").MustString(); linkType != "" && linkType != "link" {
+				continue
+			}
+			if strings.TrimSpace(link.Get("url").MustString()) == "" {
+				problems = append(problems, Problem{
+					Path:    fmt.Sprintf("%s[%d].url", path, i),
+					Message: fmt.Sprintf("Link %q has no URL", link.Get("title").MustString()),
+				})
+			}
+		}
+	}
+
+	checkURLs("links", dashboard.Get("links"))
+	forEachPanel(dashboard, func(path string, panel *simplejson.Json) {
+		checkURLs(path+".links", panel.Get("links"))
+	})
+	return problems
+}
+
+// forEachPanel calls fn with every panel of the dashboard and its path. This includes the panels of collapsed rows,
+// and the panels of the rows of dashboards older than schema version 16.
+func forEachPanel(dashboard *simplejson.Json, fn func(path string, panel *simplejson.Json)) {
+	panels := dashboard.Get("panels")
+	for i := range panels.MustArray() {
+		path := fmt.Sprintf("panels[%d]", i)
+		panel := panels.GetIndex(i)
+		fn(path, panel)
+
+		collapsed := panel.Get("panels")
+		for j := range collapsed.MustArray() {
+			fn(fmt.Sprintf("%s.panels[%d]", path, j), collapsed.GetIndex(j))
+		}
+	}
+
+	rows := dashboard.Get("rows")
+	for i := range rows.MustArray() {
+		rowPanels := rows.GetIndex(i).Get("panels")
+		for j := range rowPanels.MustArray() {
+			fn(fmt.Sprintf("rows[%d].panels[%d]", i, j), rowPanels.GetIndex(j))
+		}
+	}
+}
+
+// datasourceUID returns the UID of a data source reference, or its name for references of dashboards older than schema version 33.
+func datasourceUID(ref *simplejson.Json) string {
+	switch value := ref.Interface().(type) {
+	case string:
+		return value
+	case map[string]any:
+		uid, _ := value["uid"].(string)
+		return uid
+	}
+	return ""
+}
+
+func panelName(panel *simplejson.Json) string {
+	if title := panel.Get("title").MustString(); title != "" {
+		return fmt.Sprintf("Panel %q", title)
+	}
+	return "Panel"
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/grafana/authlib/claims"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
@@ -14,27 +16,46 @@ import (
 
 type DashboardSnapshotStore struct {
 	store db.DB
+	// payloads stores the encrypted dashboards of snapshots. It is nil if they are stored in the database.
+	payloads filestorage.FileStorage
+	log      log.Logger
 }
 
 // DashboardStore implements the Store interface
 var _ dashboardsnapshots.Store = (*DashboardSnapshotStore)(nil)
 
-func ProvideStore(db db.DB, cfg *setting.Cfg) *DashboardSnapshotStore {
+func ProvideStore(db db.DB, cfg *setting.Cfg) (*DashboardSnapshotStore, error) {
 	// nolint:staticcheck
-	return NewStore(db)
+	s := NewStore(db)
+	if cfg.SnapshotStorageURL == "" {
+		return s, nil
+	}
+
+	payloads, err := openPayloadStorage(context.Background(), s.log, cfg.SnapshotStorageURL)
+	if err != nil {
+		return nil, err
+	}
+	s.payloads = payloads
+	return s, nil
 }
 
 func NewStore(db db.DB) *DashboardSnapshotStore {
-	return &DashboardSnapshotStore{store: db}
+	return &DashboardSnapshotStore{store: db, log: log.New("dashboardsnapshots.store")}
 }
 
 // DeleteExpiredSnapshots removes snapshots with old expiry dates.
 // SnapShotRemoveExpired is deprecated and should be removed in the future.
 // Snapshot expiry is decided by the user when they share the snapshot.
 func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+	var payloadPaths []string
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		if err := sess.Table("dashboard_snapshot").Where("expires < ? AND payload_path IS NOT NULL AND payload_path <> ''", now).Cols("payload_path").Find(&payloadPaths); err != nil {
+			return err
+		}
+
 		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ?"
-		expiredResponse, err := sess.Exec(deleteExpiredSQL, time.Now())
+		expiredResponse, err := sess.Exec(deleteExpiredSQL, now)
 		if err != nil {
 			return err
 		}
@@ -42,6 +63,14 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range payloadPaths {
+		d.deletePayload(ctx, path)
+	}
+	return nil
 }
 
 func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
@@ -52,6 +81,11 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			expires = time.Now().Add(time.Second * time.Duration(cmd.Expires))
 		}
 
+		payloadPath, err := d.savePayload(ctx, cmd.OrgID, cmd.DashboardEncrypted)
+		if err != nil {
+			return err
+		}
+
 		snapshot := &dashboardsnapshots.DashboardSnapshot{
 			Name:               cmd.Name,
 			Key:                cmd.Key,
@@ -63,14 +97,25 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			PayloadPath:        payloadPath,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
 		}
-		_, err := sess.Insert(snapshot)
-		result = snapshot
+		if payloadPath != "" {
+			snapshot.DashboardEncrypted = nil
+		}
 
-		return err
+		if _, err := sess.Insert(snapshot); err != nil {
+			if payloadPath != "" {
+				d.deletePayload(ctx, payloadPath)
+			}
+			return err
+		}
+
+		snapshot.DashboardEncrypted = cmd.DashboardEncrypted
+		result = snapshot
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -79,11 +124,24 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 }
 
 func (d *DashboardSnapshotStore) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
-	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+	var payloadPaths []string
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := sess.Table("dashboard_snapshot").Where("delete_key = ? AND payload_path IS NOT NULL AND payload_path <> ''", cmd.DeleteKey).Cols("payload_path").Find(&payloadPaths); err != nil {
+			return err
+		}
+
 		var rawSQL = "DELETE FROM dashboard_snapshot WHERE delete_key=?"
 		_, err := sess.Exec(rawSQL, cmd.DeleteKey)
 		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range payloadPaths {
+		d.deletePayload(ctx, path)
+	}
+	return nil
 }
 
 func (d *DashboardSnapshotStore) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
@@ -104,6 +162,13 @@ func (d *DashboardSnapshotStore) GetDashboardSnapshot(ctx context.Context, query
 	if err != nil {
 		return nil, err
 	}
+
+	if queryResult.PayloadPath != "" {
+		queryResult.DashboardEncrypted, err = d.loadPayload(ctx, queryResult.PayloadPath)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return queryResult, nil
 }
 
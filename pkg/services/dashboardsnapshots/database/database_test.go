@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,8 @@ import (
 	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/secrets"
@@ -31,7 +34,8 @@ func TestIntegrationDashboardSnapshotDBAccess(t *testing.T) {
 	}
 	sqlstore := db.InitTestDB(t)
 	cfg := setting.NewCfg()
-	dashStore := ProvideStore(sqlstore, cfg)
+	dashStore, err := ProvideStore(sqlstore, cfg)
+	require.NoError(t, err)
 
 	origSecret := cfg.SecretKey
 	cfg.SecretKey = "dashboard_snapshot_testing"
@@ -227,3 +231,107 @@ func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key str
 
 	return result
 }
+
+func TestOpenPayloadStorage(t *testing.T) {
+	// The buckets of cloud providers can't be opened without credentials, but their drivers must be registered.
+	for _, url := range []string{"s3://bucket?region=us-east-1", "gs://bucket", "azblob://container"} {
+		t.Run(url, func(t *testing.T) {
+			_, err := openPayloadStorage(context.Background(), log.NewNopLogger(), url)
+			if err != nil {
+				assert.False(t, strings.Contains(err.Error(), "no driver registered"), err.Error())
+			}
+		})
+	}
+
+	t.Run("file", func(t *testing.T) {
+		_, err := openPayloadStorage(context.Background(), log.NewNopLogger(), "file://"+t.TempDir())
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		_, err := openPayloadStorage(context.Background(), log.NewNopLogger(), "ftp://bucket")
+		require.ErrorContains(t, err, "no driver registered")
+	})
+}
+
+func TestIntegrationDashboardSnapshotPayloadStorage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	sqlstore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.SnapshotStorageURL = "file://" + t.TempDir()
+	dashStore, err := ProvideStore(sqlstore, cfg)
+	require.NoError(t, err)
+
+	payloadExists := func(t *testing.T, path string) bool {
+		t.Helper()
+		_, found, err := dashStore.payloads.Get(ctx, path, &filestorage.GetFileOptions{WithContents: false})
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("stores the encrypted dashboard outside the database", func(t *testing.T) {
+		result, err := dashStore.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:                "external",
+			DeleteKey:          "deleteexternal",
+			DashboardEncrypted: []byte("encrypted"),
+			OrgID:              1,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, result.PayloadPath)
+		require.True(t, payloadExists(t, result.PayloadPath))
+
+		stored := dashboardsnapshots.DashboardSnapshot{}
+		err = sqlstore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.ID(result.ID).Get(&stored)
+			return err
+		})
+		require.NoError(t, err)
+		assert.Empty(t, stored.DashboardEncrypted)
+		assert.Equal(t, result.PayloadPath, stored.PayloadPath)
+
+		snapshot, err := dashStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "external"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("encrypted"), snapshot.DashboardEncrypted)
+
+		t.Run("and re-encrypts it", func(t *testing.T) {
+			ok := dashStore.RotatePayloads(ctx, func(_ context.Context, encrypted []byte) ([]byte, error) {
+				return append([]byte("re-"), encrypted...), nil
+			})
+			require.True(t, ok)
+
+			snapshot, err := dashStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "external"})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("re-encrypted"), snapshot.DashboardEncrypted)
+		})
+
+		t.Run("and deletes it with the snapshot", func(t *testing.T) {
+			err := dashStore.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: "deleteexternal"})
+			require.NoError(t, err)
+			assert.False(t, payloadExists(t, result.PayloadPath))
+		})
+	})
+
+	t.Run("deletes the encrypted dashboard of expired snapshots", func(t *testing.T) {
+		expired, err := dashStore.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:                "expired",
+			DeleteKey:          "deleteexpired",
+			DashboardEncrypted: []byte("encrypted"),
+			OrgID:              1,
+		})
+		require.NoError(t, err)
+		require.True(t, payloadExists(t, expired.PayloadPath))
+
+		err = sqlstore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE dashboard_snapshot SET expires = ? WHERE id = ?", time.Now().Add(-time.Hour), expired.ID)
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashStore.DeleteExpiredSnapshots(ctx, &dashboardsnapshots.DeleteExpiredSnapshotsCommand{})
+		require.NoError(t, err)
+		assert.False(t, payloadExists(t, expired.PayloadPath))
+	})
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	payloadFolder   = "snapshots"
+	payloadMimeType = "application/octet-stream"
+)
+
+// openPayloadStorage opens the blob storage of the encrypted dashboards of snapshots, for example
+// file:///var/lib/grafana/snapshots?create_dir=true, s3://bucket?region=us-east-1, gs://bucket or azblob://container.
+func openPayloadStorage(ctx context.Context, logger log.Logger, url string) (filestorage.FileStorage, error) {
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot storage: %w", err)
+	}
+	return filestorage.NewCdkBlobStorage(logger, bucket, "", filestorage.NewAllowAllPathFilter()), nil
+}
+
+// savePayload writes the encrypted dashboard of a snapshot to the payload storage and returns its path.
+// It returns an empty path if the encrypted dashboard is to be stored in the database.
+func (d *DashboardSnapshotStore) savePayload(ctx context.Context, orgID int64, encrypted []byte) (string, error) {
+	if d.payloads == nil || len(encrypted) == 0 {
+		return "", nil
+	}
+
+	path := filestorage.Join(payloadFolder, strconv.FormatInt(orgID, 10), util.GenerateShortUID())
+	if err := d.payloads.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     path,
+		MimeType: payloadMimeType,
+		Contents: encrypted,
+	}); err != nil {
+		return "", fmt.Errorf("failed to save snapshot to storage: %w", err)
+	}
+	return path, nil
+}
+
+func (d *DashboardSnapshotStore) loadPayload(ctx context.Context, path string) ([]byte, error) {
+	if d.payloads == nil {
+		return nil, fmt.Errorf("snapshot is stored in an external storage, but the storage_url of snapshots is not configured")
+	}
+
+	file, found, err := d.payloads.Get(ctx, path, &filestorage.GetFileOptions{WithContents: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot from storage: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("snapshot not found in storage: %s", path)
+	}
+	return file.Contents, nil
+}
+
+// deletePayload deletes the encrypted dashboard of a snapshot from the payload storage.
+// Failures are logged only, since the snapshot itself is already deleted.
+func (d *DashboardSnapshotStore) deletePayload(ctx context.Context, path string) {
+	if d.payloads == nil {
+		d.log.Warn("Cannot delete snapshot from storage, the storage_url of snapshots is not configured", "path", path)
+		return
+	}
+	if err := d.payloads.Delete(ctx, path); err != nil {
+		d.log.Warn("Failed to delete snapshot from storage", "path", path, "error", err)
+	}
+}
+
+// RotatePayloads re-encrypts the dashboards of the snapshots that are stored in the payload storage with the given function.
+// It returns false if any of them failed. The dashboards stored in the database are rotated with the other secrets.
+func (d *DashboardSnapshotStore) RotatePayloads(ctx context.Context, reEncrypt func(ctx context.Context, encrypted []byte) ([]byte, error)) bool {
+	var rows []struct {
+		Id          int64
+		PayloadPath string
+	}
+	if err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_snapshot").Select("id, payload_path").Where("payload_path IS NOT NULL AND payload_path <> ''").Find(&rows)
+	}); err != nil {
+		d.log.Warn("Could not find any snapshot to re-encrypt", "error", err)
+		return false
+	}
+
+	var anyFailure bool
+	for _, row := range rows {
+		if err := d.rotatePayload(ctx, row.PayloadPath, reEncrypt); err != nil {
+			d.log.Warn("Could not re-encrypt snapshot", "id", row.Id, "path", row.PayloadPath, "error", err)
+			anyFailure = true
+		}
+	}
+
+	if anyFailure {
+		d.log.Warn("Snapshots in storage have been re-encrypted with errors", "count", len(rows))
+	} else {
+		d.log.Info("Snapshots in storage have been re-encrypted successfully", "count", len(rows))
+	}
+	return !anyFailure
+}
+
+func (d *DashboardSnapshotStore) rotatePayload(ctx context.Context, path string, reEncrypt func(ctx context.Context, encrypted []byte) ([]byte, error)) error {
+	encrypted, err := d.loadPayload(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	reEncrypted, err := reEncrypt(ctx, encrypted)
+	if err != nil {
+		return err
+	}
+
+	return d.payloads.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     path,
+		MimeType: payloadMimeType,
+		Contents: reEncrypted,
+	})
+}
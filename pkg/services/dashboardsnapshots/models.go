@@ -26,6 +26,9 @@ type DashboardSnapshot struct {
 
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+	// PayloadPath is the path of the encrypted dashboard in the external snapshot storage.
+	// It is empty if the encrypted dashboard is stored in the database.
+	PayloadPath string `xorm:"payload_path"`
 }
 
 // DashboardSnapshotDTO without dashboard map
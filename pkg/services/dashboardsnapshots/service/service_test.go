@@ -36,7 +36,8 @@ func TestMain(m *testing.M) {
 func TestDashboardSnapshotsService(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	cfg := setting.NewCfg()
-	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
+	dsStore, err := dashsnapdb.ProvideStore(sqlStore, cfg)
+	require.NoError(t, err)
 	fakeDashboardService := &dashboards.FakeDashboardService{}
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
 	s := ProvideService(dsStore, secretsService, fakeDashboardService)
@@ -51,7 +52,7 @@ func TestDashboardSnapshotsService(t *testing.T) {
 
 	dashboard := &common.Unstructured{}
 	rawDashboard := []byte(`{"id":123}`)
-	err := json.Unmarshal(rawDashboard, dashboard)
+	err = json.Unmarshal(rawDashboard, dashboard)
 	require.NoError(t, err)
 
 	t.Run("create dashboard snapshot should encrypt the dashboard", func(t *testing.T) {
@@ -95,7 +96,8 @@ func TestDashboardSnapshotsService(t *testing.T) {
 func TestValidateDashboardExists(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	cfg := setting.NewCfg()
-	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
+	dsStore, err := dashsnapdb.ProvideStore(sqlStore, cfg)
+	require.NoError(t, err)
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
 	dashboardStore, err := dashdb.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
 	require.NoError(t, err)
@@ -60,21 +60,28 @@ func (r *RuleService) getRulesReadEvaluator(rules ...*models.AlertRule) accessco
 	return accesscontrol.EvalAll(evals...)
 }
 
-// getRulesQueryEvaluator constructs accesscontrol.Evaluator that checks all permissions to query data sources used by the provided rules
+// getRulesQueryEvaluator constructs accesscontrol.Evaluator that checks all permissions to query data sources used by the provided rules,
+// including the data sources that recording rules write to
 func (r *RuleService) getRulesQueryEvaluator(rules ...*models.AlertRule) accesscontrol.Evaluator {
 	added := make(map[string]struct{}, 2)
 	evals := make([]accesscontrol.Evaluator, 0, 2)
+	add := func(uid string) {
+		if _, ok := added[uid]; ok {
+			return
+		}
+		evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid)))
+		added[uid] = struct{}{}
+	}
 	for _, rule := range rules {
 		for _, query := range rule.Data {
 			if query.QueryType == expr.DatasourceType || query.DatasourceUID == expr.DatasourceUID || query.
 				DatasourceUID == expr.OldDatasourceUID {
 				continue
 			}
-			if _, ok := added[query.DatasourceUID]; ok {
-				continue
-			}
-			evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID)))
-			added[query.DatasourceUID] = struct{}{}
+			add(query.DatasourceUID)
+		}
+		if rule.Record != nil && rule.Record.TargetDatasourceUID != "" {
+			add(rule.Record.TargetDatasourceUID)
 		}
 	}
 	if len(evals) == 1 {
@@ -491,6 +491,23 @@ func TestCheckDatasourcePermissionsForRule(t *testing.T) {
 		require.Error(t, result)
 		require.Len(t, ac.EvaluateRecordings, 1)
 	})
+
+	t.Run("should check the target data source of recording rules", func(t *testing.T) {
+		recording := models.CopyRule(rule)
+		recording.Record = &models.Record{Metric: "metric", From: "A", TargetDatasourceUID: "target"}
+		permissions := map[string][]string{
+			datasources.ActionQuery: scopes,
+		}
+		ac := &recordingAccessControlFake{}
+		svc := NewRuleService(ac)
+
+		err := svc.AuthorizeDatasourceAccessForRule(context.Background(), createUserWithPermissions(permissions), recording)
+		require.Error(t, err)
+
+		permissions[datasources.ActionQuery] = append(scopes, datasources.ScopeProvider.GetResourceScopeUID("target"))
+		err = svc.AuthorizeDatasourceAccessForRule(context.Background(), createUserWithPermissions(permissions), recording)
+		require.NoError(t, err)
+	})
 }
 
 func Test_authorizeAccessToRuleGroup(t *testing.T) {
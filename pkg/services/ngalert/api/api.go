@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -59,6 +60,7 @@ type API struct {
 	ProvenanceStore      provisioning.ProvisioningStore
 	RuleStore            RuleStore
 	AlertingStore        store.AlertingStore
+	SilenceScheduleStore store.SilenceScheduleStore
 	AdminConfigStore     store.AdminConfigurationStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
@@ -110,6 +112,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 				api.RuleStore,
 				ruleAuthzService,
 			),
+			silenceScheduleSvc: notifier.NewSilenceScheduleService(
+				accesscontrol.NewSilenceService(api.AccessControl, api.RuleStore),
+				api.SilenceScheduleStore,
+				api.MultiOrgAlertmanager,
+				logger,
+			),
 			receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
 		},
 	), m)
@@ -117,7 +125,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
 		api.DatasourceCache,
 		NewLotexProm(proxy, logger),
-		&PrometheusSrv{log: logger, manager: api.StateManager, status: api.Scheduler, store: api.RuleStore, authz: ruleAuthzService},
+		&PrometheusSrv{log: logger, manager: api.StateManager, status: api.Scheduler, store: api.RuleStore, authz: ruleAuthzService, acknowledger: api.StateManager, silenceAuthz: accesscontrol.NewSilenceService(api.AccessControl, api.RuleStore)},
 	), m)
 	// Register endpoints for proxying to Cortex Ruler-compatible backends.
 	api.RegisterRulerApiEndpoints(NewForkingRuler(
@@ -125,6 +133,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		NewLotexRuler(proxy, logger),
 		&RulerSrv{
 			conditionValidator: api.ConditionValidator,
+			recordingTargets:   writer.NewTargetValidator(api.DatasourceService),
 			QuotaService:       api.QuotaService,
 			store:              api.RuleStore,
 			provenanceStore:    api.ProvenanceStore,
@@ -146,10 +155,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			ruleTester:      backtesting.NewRuleTester(api.AppUrl, api.FeatureManager, api.Tracer),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
 			folderService:   api.RuleStore,
+			ruleStore:       api.RuleStore,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -168,6 +179,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		recordingTargets:    writer.NewTargetValidator(api.DatasourceService),
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
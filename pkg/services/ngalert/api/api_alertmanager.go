@@ -35,13 +35,14 @@ type receiversAuthz interface {
 }
 
 type AlertmanagerSrv struct {
-	log            log.Logger
-	ac             accesscontrol.AccessControl
-	mam            *notifier.MultiOrgAlertmanager
-	crypto         notifier.Crypto
-	silenceSvc     SilenceService
-	featureManager featuremgmt.FeatureToggles
-	receiverAuthz  receiversAuthz
+	log                log.Logger
+	ac                 accesscontrol.AccessControl
+	mam                *notifier.MultiOrgAlertmanager
+	crypto             notifier.Crypto
+	silenceSvc         SilenceService
+	silenceScheduleSvc SilenceScheduleService
+	featureManager     featuremgmt.FeatureToggles
+	receiverAuthz      receiversAuthz
 }
 
 type UnknownReceiverError struct {
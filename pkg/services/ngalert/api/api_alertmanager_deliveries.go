@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	defaultNotificationDeliveriesLimit = 100
+	maxNotificationDeliveriesLimit     = 1000
+)
+
+func (srv AlertmanagerSrv) RouteGetNotificationDeliveries(c *contextmodel.ReqContext) response.Response {
+	query, err := notificationDeliveriesQueryFromRequest(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	deliveries, err := srv.mam.GetNotificationDeliveries(c.Req.Context(), query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification deliveries")
+	}
+
+	result := make(apimodels.GettableNotificationDeliveries, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, apimodels.GettableNotificationDelivery{
+			Receiver:          d.Receiver,
+			Integration:       d.Integration,
+			IntegrationIndex:  d.IntegrationIndex,
+			GroupKey:          d.GroupKey,
+			AlertFingerprints: d.AlertFingerprints,
+			Firing:            d.Firing,
+			Resolved:          d.Resolved,
+			Status:            string(d.Status),
+			Error:             d.Error,
+			Retry:             d.Retry,
+			Duration:          d.Duration.String(),
+			SentAt:            d.SentAt,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func notificationDeliveriesQueryFromRequest(c *contextmodel.ReqContext) (ngmodels.ListNotificationDeliveriesQuery, error) {
+	query := ngmodels.ListNotificationDeliveriesQuery{
+		OrgID:            c.SignedInUser.GetOrgID(),
+		Receiver:         c.Query("receiver"),
+		Integration:      c.Query("integration"),
+		Status:           ngmodels.NotificationDeliveryStatus(c.Query("status")),
+		AlertFingerprint: c.Query("alert"),
+		Limit:            c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailed:
+	default:
+		return query, fmt.Errorf("invalid status %q, must be one of %q or %q", query.Status, ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailed)
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return query, errors.New("to must not be before from")
+	}
+	if query.Limit < 0 || query.Limit > maxNotificationDeliveriesLimit {
+		return query, fmt.Errorf("limit must be between 0 and %d", maxNotificationDeliveriesLimit)
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultNotificationDeliveriesLimit
+	}
+	return query, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteGetNotificationDeliveries(t *testing.T) {
+	sut := createSut(t)
+
+	request := func(t *testing.T, params map[string]string) *http.Request {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "https://grafana.net", nil)
+		require.NoError(t, err)
+		q := req.URL.Query()
+		for k, v := range params {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+		return req
+	}
+
+	t.Run("assert 200 and empty slice when no deliveries are found", func(t *testing.T) {
+		rc := createRequestCtxInOrg(1)
+		rc.Req = request(t, map[string]string{"receiver": "test", "status": "failed"})
+
+		response := sut.RouteGetNotificationDeliveries(rc)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		var deliveries apimodels.GettableNotificationDeliveries
+		require.NoError(t, json.Unmarshal(response.Body(), &deliveries))
+		require.Empty(t, deliveries)
+	})
+
+	testCases := []struct {
+		name   string
+		params map[string]string
+	}{
+		{name: "invalid status", params: map[string]string{"status": "pending"}},
+		{name: "negative limit", params: map[string]string{"limit": "-1"}},
+		{name: "limit too large", params: map[string]string{"limit": "1001"}},
+		{name: "to before from", params: map[string]string{"from": "200", "to": "100"}},
+	}
+	for _, tc := range testCases {
+		t.Run("assert 400 when "+tc.name, func(t *testing.T) {
+			rc := createRequestCtxInOrg(1)
+			rc.Req = request(t, tc.params)
+
+			response := sut.RouteGetNotificationDeliveries(rc)
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SilenceScheduleService is the service for managing recurring silences in Grafana AM.
+type SilenceScheduleService interface {
+	ListSilenceSchedules(ctx context.Context, user identity.Requester) ([]*models.SilenceSchedule, error)
+	GetSilenceSchedule(ctx context.Context, user identity.Requester, uid string) (*models.SilenceSchedule, error)
+	CreateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (string, error)
+	UpdateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) error
+	DeleteSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error
+	ApproveSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error
+}
+
+func (srv AlertmanagerSrv) RouteGetSilenceSchedules(c *contextmodel.ReqContext) response.Response {
+	schedules, err := srv.silenceScheduleSvc.ListSilenceSchedules(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to list silence schedules", err)
+	}
+	result := make(apimodels.GettableSilenceSchedules, 0, len(schedules))
+	for _, schedule := range schedules {
+		result = append(result, SilenceScheduleToGettable(schedule))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RouteGetSilenceSchedule(c *contextmodel.ReqContext, uid string) response.Response {
+	schedule, err := srv.silenceScheduleSvc.GetSilenceSchedule(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence schedule", err)
+	}
+	return response.JSON(http.StatusOK, SilenceScheduleToGettable(schedule))
+}
+
+func (srv AlertmanagerSrv) RouteCreateSilenceSchedule(c *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule) response.Response {
+	uid, err := srv.silenceScheduleSvc.CreateSilenceSchedule(c.Req.Context(), c.SignedInUser, PostableSilenceScheduleToModel(body))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create silence schedule", err)
+	}
+	return response.JSON(http.StatusCreated, apimodels.PostSilenceScheduleResponse{UID: uid})
+}
+
+func (srv AlertmanagerSrv) RouteUpdateSilenceSchedule(c *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule, uid string) response.Response {
+	schedule := PostableSilenceScheduleToModel(body)
+	schedule.UID = uid
+	if err := srv.silenceScheduleSvc.UpdateSilenceSchedule(c.Req.Context(), c.SignedInUser, schedule); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update silence schedule", err)
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "silence schedule updated"})
+}
+
+func (srv AlertmanagerSrv) RouteDeleteSilenceSchedule(c *contextmodel.ReqContext, uid string) response.Response {
+	if err := srv.silenceScheduleSvc.DeleteSilenceSchedule(c.Req.Context(), c.SignedInUser, uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete silence schedule", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "silence schedule deleted"})
+}
+
+func (srv AlertmanagerSrv) RouteApproveSilenceSchedule(c *contextmodel.ReqContext, uid string) response.Response {
+	if err := srv.silenceScheduleSvc.ApproveSilenceSchedule(c.Req.Context(), c.SignedInUser, uid); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to approve silence schedule", err)
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "silence schedule approved"})
+}
@@ -33,6 +33,9 @@ type PrometheusSrv struct {
 	status  StatusReader
 	store   RuleStore
 	authz   RuleAccessControlService
+
+	acknowledger AlertInstanceAcknowledger
+	silenceAuthz SilenceCreateAuthorizer
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -93,26 +96,30 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 	}
 
 	for _, alertState := range manager.GetAll(opts.OrgID) {
-		startsAt := alertState.StartsAt
-		valString := ""
-
-		if alertState.State == eval.Alerting || alertState.State == eval.Pending {
-			valString = formatValues(alertState)
-		}
+		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, alertFromState(alertState, labelOptions))
+	}
 
-		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, &apimodels.Alert{
-			Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
-			Annotations: apimodels.LabelsFromMap(alertState.Annotations),
+	return alertResponse
+}
 
-			// TODO: or should we make this two fields? Using one field lets the
-			// frontend use the same logic for parsing text on annotations and this.
-			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-			ActiveAt: &startsAt,
-			Value:    valString,
-		})
+func alertFromState(alertState *state.State, labelOptions []ngmodels.LabelOption) *apimodels.Alert {
+	activeAt := alertState.StartsAt
+	valString := ""
+	if alertState.State == eval.Alerting || alertState.State == eval.Pending {
+		valString = formatValues(alertState)
 	}
+	return &apimodels.Alert{
+		Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
+		Annotations: apimodels.LabelsFromMap(alertState.Annotations),
 
-	return alertResponse
+		// TODO: or should we make this two fields? Using one field lets the
+		// frontend use the same logic for parsing text on annotations and this.
+		State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+		ActiveAt:        &activeAt,
+		Value:           valString,
+		Fingerprint:     alertState.CacheID.String(),
+		Acknowledgement: acknowledgementFromState(alertState),
+	}
 }
 
 func formatValues(alertState *state.State) string {
@@ -181,6 +188,8 @@ func getStatesFromQuery(v url.Values) ([]eval.State, error) {
 		// nolint:goconst
 		case "error":
 			states = append(states, eval.Error)
+		case "suppressed":
+			states = append(states, eval.Suppressed)
 		default:
 			return states, fmt.Errorf("unknown state '%s'", s)
 		}
@@ -416,6 +425,8 @@ func filterRules(ruleGroup *apimodels.RuleGroup, withStatesFast map[eval.State]s
 			state = util.Pointer(eval.Alerting)
 		case "pending":
 			state = util.Pointer(eval.Pending)
+		case "suppressed":
+			state = util.Pointer(eval.Suppressed)
 		}
 		if state != nil {
 			if _, ok := withStatesFast[*state]; ok {
@@ -479,31 +490,18 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 		totalsFiltered := make(map[string]int64)
 		for _, alertState := range states {
 			activeAt := alertState.StartsAt
-			valString := ""
-			if alertState.State == eval.Alerting || alertState.State == eval.Pending {
-				valString = formatValues(alertState)
-			}
 			stateKey := strings.ToLower(alertState.State.String())
 			totals[stateKey] += 1
 			// Do not add error twice when execution error state is Error
 			if alertState.Error != nil && rule.ExecErrState != ngmodels.ErrorErrState {
 				totals["error"] += 1
 			}
-			alert := apimodels.Alert{
-				Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
-				Annotations: apimodels.LabelsFromMap(alertState.Annotations),
-
-				// TODO: or should we make this two fields? Using one field lets the
-				// frontend use the same logic for parsing text on annotations and this.
-				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-				ActiveAt: &activeAt,
-				Value:    valString,
-			}
+			alert := *alertFromState(alertState, labelOptions)
 
 			switch alertState.State {
 			case eval.Normal:
 			case eval.Pending:
-				if alertingRule.State == "inactive" {
+				if alertingRule.State == "inactive" || alertingRule.State == "suppressed" {
 					alertingRule.State = "pending"
 				}
 			case eval.Alerting:
@@ -511,6 +509,11 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 					alertingRule.ActiveAt = &activeAt
 				}
 				alertingRule.State = "firing"
+			case eval.Suppressed:
+				// The rule is suppressed if none of its alert instances is firing or pending.
+				if alertingRule.State == "inactive" {
+					alertingRule.State = "suppressed"
+				}
 			case eval.Error:
 			case eval.NoData:
 			}
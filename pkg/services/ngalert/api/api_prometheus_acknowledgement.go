@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertInstanceAcknowledger acknowledges alert instances of Grafana-managed alert rules.
+type AlertInstanceAcknowledger interface {
+	AcknowledgeInstance(ctx context.Context, rule *ngmodels.AlertRule, fingerprint data.Fingerprint, ack ngmodels.AlertInstanceAcknowledgement) (*state.State, error)
+	UnacknowledgeInstance(ctx context.Context, rule *ngmodels.AlertRule, fingerprint data.Fingerprint) (*state.State, error)
+}
+
+// SilenceCreateAuthorizer checks whether a user can create a silence.
+type SilenceCreateAuthorizer interface {
+	AuthorizeCreateSilence(ctx context.Context, user identity.Requester, silence *ngmodels.Silence) error
+}
+
+// RoutePostAlertAcknowledgement acknowledges the alert instance with the given fingerprint on behalf of the signed-in user.
+func (srv PrometheusSrv) RoutePostAlertAcknowledgement(c *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string, fingerprint string) response.Response {
+	fp, err := parseAlertInstanceFingerprint(fingerprint)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	now := timeNow()
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(now) {
+		return ErrResp(http.StatusBadRequest, errors.New("expiresAt must be in the future"), "")
+	}
+
+	rule, err := srv.getAuthorizedRuleByUID(c, ruleUID)
+	if err != nil {
+		return acknowledgementErrorResponse(err, "failed to get rule by UID")
+	}
+	// Suppressing notifications creates a silence for the alert instance, which requires the permission to create
+	// silences for the rules in the folder of the rule.
+	if body.SuppressNotifications {
+		if err := srv.silenceAuthz.AuthorizeCreateSilence(c.Req.Context(), c.SignedInUser, ruleSilence(rule)); err != nil {
+			return acknowledgementErrorResponse(err, "failed to authorize the silence of the acknowledgement")
+		}
+	}
+
+	s, err := srv.acknowledger.AcknowledgeInstance(c.Req.Context(), rule, fp, ngmodels.AlertInstanceAcknowledgement{
+		By:                    c.SignedInUser.GetLogin(),
+		Comment:               body.Comment,
+		At:                    now,
+		ExpiresAt:             body.ExpiresAt,
+		SuppressNotifications: body.SuppressNotifications,
+	})
+	if err != nil {
+		return acknowledgementErrorResponse(err, "failed to acknowledge alert instance")
+	}
+	return response.JSON(http.StatusOK, alertFromState(s, nil))
+}
+
+// RouteDeleteAlertAcknowledgement removes the acknowledgement of the alert instance with the given fingerprint.
+func (srv PrometheusSrv) RouteDeleteAlertAcknowledgement(c *contextmodel.ReqContext, ruleUID string, fingerprint string) response.Response {
+	fp, err := parseAlertInstanceFingerprint(fingerprint)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	rule, err := srv.getAuthorizedRuleByUID(c, ruleUID)
+	if err != nil {
+		return acknowledgementErrorResponse(err, "failed to get rule by UID")
+	}
+
+	s, err := srv.acknowledger.UnacknowledgeInstance(c.Req.Context(), rule, fp)
+	if err != nil {
+		return acknowledgementErrorResponse(err, "failed to remove acknowledgement of alert instance")
+	}
+	return response.JSON(http.StatusOK, alertFromState(s, nil))
+}
+
+// getAuthorizedRuleByUID fetches the rule by UID and checks whether the user is authorized to read it.
+func (srv PrometheusSrv) getAuthorizedRuleByUID(c *contextmodel.ReqContext, ruleUID string) (*ngmodels.AlertRule, error) {
+	rule, err := srv.store.GetAlertRuleByUID(c.Req.Context(), &ngmodels.GetAlertRuleByUIDQuery{
+		UID:   ruleUID,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.authz.AuthorizeAccessInFolder(c.Req.Context(), c.SignedInUser, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ruleSilence returns a silence that matches the alert instances of the rule. It is used to check the permissions to
+// silence the alert instances of the rule.
+func ruleSilence(rule *ngmodels.AlertRule) *ngmodels.Silence {
+	return &ngmodels.Silence{
+		Silence: amv2.Silence{
+			Matchers: amv2.Matchers{{
+				Name:    util.Pointer(alertingModels.RuleUIDLabel),
+				Value:   util.Pointer(rule.UID),
+				IsEqual: util.Pointer(true),
+				IsRegex: util.Pointer(false),
+			}},
+		},
+	}
+}
+
+func parseAlertInstanceFingerprint(fingerprint string) (data.Fingerprint, error) {
+	fp, err := strconv.ParseUint(fingerprint, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid alert instance fingerprint %q", fingerprint)
+	}
+	return data.Fingerprint(fp), nil
+}
+
+func acknowledgementErrorResponse(err error, msg string) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertInstanceNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, ngmodels.ErrAlertInstanceNotFiring) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, msg, err)
+}
+
+func acknowledgementFromState(s *state.State) *apimodels.AlertAcknowledgement {
+	if s.Acknowledgement == nil {
+		return nil
+	}
+	return &apimodels.AlertAcknowledgement{
+		AcknowledgedBy:        s.Acknowledgement.By,
+		AcknowledgedAt:        s.Acknowledgement.At,
+		Comment:               s.Acknowledgement.Comment,
+		ExpiresAt:             s.Acknowledgement.ExpiresAt,
+		SuppressNotifications: s.Acknowledgement.SuppressNotifications,
+	}
+}
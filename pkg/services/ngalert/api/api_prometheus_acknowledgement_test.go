@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/authz/zanzana"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestAlertAcknowledgement(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	gen := models.RuleGen.With(
+		models.RuleGen.WithOrgID(orgID),
+		models.RuleGen.WithNamespaceUID(folder.UID),
+		models.RuleGen.WithFor(0),
+	)
+
+	setup := func(t *testing.T) (PrometheusSrv, *state.Manager, *models.AlertRule, *fakes.FakeSilenceStore) {
+		t.Helper()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		rule := gen.GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+
+		silences := &fakes.FakeSilenceStore{Silences: map[string]*models.Silence{}}
+		clk := clock.NewMock()
+		clk.Set(timeNow())
+		manager := state.NewManager(state.ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clk,
+			Historian:     &state.FakeHistorian{},
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+			Silencer:      silences,
+		}, state.NewNoopPersister())
+
+		ac := acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+		srv := PrometheusSrv{
+			log:          log.NewNopLogger(),
+			manager:      manager,
+			store:        ruleStore,
+			authz:        accesscontrol.NewRuleService(ac),
+			acknowledger: manager,
+			silenceAuthz: accesscontrol.NewSilenceService(ac, ruleStore),
+		}
+		return srv, manager, rule, silences
+	}
+
+	process := func(manager *state.Manager, rule *models.AlertRule, s eval.State, lbls data.Labels) *state.State {
+		transitions := manager.ProcessEvalResults(context.Background(), timeNow(), rule, eval.Results{{
+			Instance:    lbls,
+			State:       s,
+			EvaluatedAt: timeNow(),
+		}}, nil, nil)
+		return transitions[0].State
+	}
+
+	// silencePermissions adds the permissions to create silences for the rules in the folder.
+	silencePermissions := func(permissions map[int64]map[string][]string, folderUID string) map[int64]map[string][]string {
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+		permissions[orgID][ac.ActionAlertingSilencesRead] = append(permissions[orgID][ac.ActionAlertingSilencesRead], scope)
+		permissions[orgID][ac.ActionAlertingSilencesCreate] = append(permissions[orgID][ac.ActionAlertingSilencesCreate], scope)
+		return permissions
+	}
+
+	t.Run("should acknowledge firing alert instance", func(t *testing.T) {
+		srv, manager, rule, silences := setup(t)
+		firing := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+		req := createRequestContextWithPerms(orgID, silencePermissions(createPermissionsForRules([]*models.AlertRule{rule}, orgID), rule.NamespaceUID), nil)
+		req.SignedInUser.Login = "editor"
+		expiresAt := timeNow().Add(time.Hour)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{
+			Comment:               "looking into it",
+			ExpiresAt:             &expiresAt,
+			SuppressNotifications: true,
+		}, rule.UID, firing.CacheID.String())
+
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		var result apimodels.Alert
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, firing.CacheID.String(), result.Fingerprint)
+		require.Equal(t, "Alerting", result.State)
+		require.NotNil(t, result.Acknowledgement)
+		require.Equal(t, "editor", result.Acknowledgement.AcknowledgedBy)
+		require.Equal(t, "looking into it", result.Acknowledgement.Comment)
+		require.True(t, result.Acknowledgement.SuppressNotifications)
+
+		states := manager.GetStatesForRuleUID(orgID, rule.UID)
+		require.Len(t, states, 1)
+		require.NotNil(t, states[0].Acknowledgement)
+		require.Empty(t, states[0].StateReason)
+
+		// the alert instance is still sent to the Alertmanager, where it is silenced
+		require.Len(t, silences.Silences, 1)
+		silence, ok := silences.Silences[states[0].Acknowledgement.SilenceID]
+		require.True(t, ok)
+		require.Equal(t, "editor", *silence.CreatedBy)
+		require.Equal(t, expiresAt, time.Time(*silence.EndsAt))
+		for _, m := range silence.Matchers {
+			require.Equal(t, states[0].Labels[*m.Name], *m.Value)
+			require.True(t, *m.IsEqual)
+			require.False(t, *m.IsRegex)
+		}
+
+		t.Run("should keep acknowledgement while alert instance is firing", func(t *testing.T) {
+			s := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+			require.NotNil(t, s.Acknowledgement)
+			require.Empty(t, s.StateReason)
+			require.Len(t, silences.Silences, 1)
+		})
+
+		t.Run("should remove acknowledgement", func(t *testing.T) {
+			response := srv.RouteDeleteAlertAcknowledgement(req, rule.UID, firing.CacheID.String())
+			require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+			var result apimodels.Alert
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Equal(t, "Alerting", result.State)
+			require.Nil(t, result.Acknowledgement)
+
+			require.Empty(t, silences.Silences)
+
+			s := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+			require.Nil(t, s.Acknowledgement)
+			require.Empty(t, s.StateReason)
+		})
+	})
+
+	t.Run("should clear acknowledgement when alert instance resolves", func(t *testing.T) {
+		srv, manager, rule, silences := setup(t)
+		firing := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+		req := createRequestContextWithPerms(orgID, silencePermissions(createPermissionsForRules([]*models.AlertRule{rule}, orgID), rule.NamespaceUID), nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{SuppressNotifications: true}, rule.UID, firing.CacheID.String())
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		require.Len(t, silences.Silences, 1)
+
+		s := process(manager, rule, eval.Normal, data.Labels{"instance": "a"})
+		require.Nil(t, s.Acknowledgement)
+		require.Empty(t, s.StateReason)
+		require.Empty(t, silences.Silences)
+	})
+
+	t.Run("should return 409 if alert instance is not firing", func(t *testing.T) {
+		srv, manager, rule, _ := setup(t)
+		normal := process(manager, rule, eval.Normal, data.Labels{"instance": "a"})
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, rule.UID, normal.CacheID.String())
+		require.Equalf(t, http.StatusConflict, response.Status(), string(response.Body()))
+	})
+
+	t.Run("should return 404 if alert instance does not exist", func(t *testing.T) {
+		srv, _, rule, _ := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, rule.UID, data.Labels{"instance": "a"}.Fingerprint().String())
+		require.Equalf(t, http.StatusNotFound, response.Status(), string(response.Body()))
+
+		response = srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, "unknown", data.Labels{"instance": "a"}.Fingerprint().String())
+		require.Equalf(t, http.StatusNotFound, response.Status(), string(response.Body()))
+	})
+
+	t.Run("should return 400 if request is invalid", func(t *testing.T) {
+		srv, manager, rule, _ := setup(t)
+		firing := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{rule}, orgID), nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, rule.UID, "not-a-fingerprint")
+		require.Equalf(t, http.StatusBadRequest, response.Status(), string(response.Body()))
+
+		expiresAt := timeNow().Add(-time.Minute)
+		response = srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{ExpiresAt: &expiresAt}, rule.UID, firing.CacheID.String())
+		require.Equalf(t, http.StatusBadRequest, response.Status(), string(response.Body()))
+	})
+
+	t.Run("should return 403 if user cannot silence the alert instance", func(t *testing.T) {
+		srv, manager, rule, silences := setup(t)
+		firing := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+		// the user can create silences only for the rules in another folder
+		req := createRequestContextWithPerms(orgID, silencePermissions(createPermissionsForRules([]*models.AlertRule{rule}, orgID), "other-folder"), nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{SuppressNotifications: true}, rule.UID, firing.CacheID.String())
+		require.Equalf(t, http.StatusForbidden, response.Status(), string(response.Body()))
+		require.Empty(t, silences.Silences)
+		states := manager.GetStatesForRuleUID(orgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Nil(t, states[0].Acknowledgement)
+
+		t.Run("but can acknowledge it without suppressing notifications", func(t *testing.T) {
+			response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, rule.UID, firing.CacheID.String())
+			require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+			require.Empty(t, silences.Silences)
+		})
+	})
+
+	t.Run("should return 403 if user cannot access the rule", func(t *testing.T) {
+		srv, manager, rule, _ := setup(t)
+		firing := process(manager, rule, eval.Alerting, data.Labels{"instance": "a"})
+		req := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil)
+
+		response := srv.RoutePostAlertAcknowledgement(req, apimodels.PostableAlertAcknowledgement{}, rule.UID, firing.CacheID.String())
+		require.Equalf(t, http.StatusForbidden, response.Status(), string(response.Body()))
+	})
+}
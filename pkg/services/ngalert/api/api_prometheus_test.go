@@ -130,7 +130,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": ""
+			"value": "",
+			"fingerprint": "ebb686c9847c7989"
 		}, {
 			"labels": {
 				"alertname": "test_title_1",
@@ -142,7 +143,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": ""
+			"value": "",
+			"fingerprint": "c83a086a64412367"
 		}]
 	}
 }`, string(r.Body()))
@@ -172,7 +174,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Alerting",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": "1.1e+00"
+			"value": "1.1e+00",
+			"fingerprint": "ebb686c9847c7989"
 		}, {
 			"labels": {
 				"alertname": "test_title_1",
@@ -184,7 +187,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Alerting",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": "1.1e+00"
+			"value": "1.1e+00",
+			"fingerprint": "c83a086a64412367"
 		}]
 	}
 }`, string(r.Body()))
@@ -216,7 +220,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": ""
+			"value": "",
+			"fingerprint": "ebb686c9847c7989"
 		}, {
 			"labels": {
 				"__alert_rule_namespace_uid__": "test_namespace_uid",
@@ -230,7 +235,8 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
-			"value": ""
+			"value": "",
+			"fingerprint": "c83a086a64412367"
 		}]
 	}
 }`, string(r.Body()))
@@ -258,6 +264,13 @@ func withAlertingErrorState() forEachState {
 	}
 }
 
+func withSuppressedState() forEachState {
+	return func(s *state.State) *state.State {
+		s.SetSuppressed(ngmodels.StateReasonDependencyFiring, timeNow(), timeNow())
+		return s
+	}
+}
+
 func withErrorState() forEachState {
 	return func(s *state.State) *state.State {
 		s.SetError(errors.New("this is an error"), timeNow(), timeNow().Add(5*time.Minute))
@@ -328,7 +341,8 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
-					"value": ""
+					"value": "",
+					"fingerprint": "ebb686c9847c7989"
 				}],
 				"totals": {
 					"normal": 1
@@ -393,7 +407,8 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
-					"value": ""
+					"value": "",
+					"fingerprint": "ebb686c9847c7989"
 				}],
 				"totals": {
 					"normal": 1
@@ -453,7 +468,8 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
-					"value": ""
+					"value": "",
+					"fingerprint": "ebb686c9847c7989"
 				}],
 				"totals": {
 					"normal": 1
@@ -1216,6 +1232,63 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 		})
 	})
 
+	t.Run("test with suppressed alert instances", func(t *testing.T) {
+		fakeStore, fakeAIM, api := setupAPI(t)
+		rules := gen.With(gen.WithGroupKey(ngmodels.AlertRuleGroupKey{
+			NamespaceUID: "Folder-1",
+			RuleGroup:    "Rule-Group-1",
+			OrgID:        orgID,
+		})).GenerateManyRef(2)
+		ngmodels.AlertRulesBy(ngmodels.AlertRulesByIndex).Sort(rules)
+		fakeStore.PutRule(context.Background(), rules...)
+
+		// all alert instances of the first rule are suppressed, the second rule also has a pending one
+		fakeAIM.GenerateAlertInstances(orgID, rules[0].UID, 2, withSuppressedState())
+		fakeAIM.GenerateAlertInstances(orgID, rules[1].UID, 1, withSuppressedState())
+		fakeAIM.GenerateAlertInstances(orgID, rules[1].UID, 1, withAlertingState(), func(s *state.State) *state.State {
+			s.State = eval.Pending
+			return s
+		})
+
+		getRules := func(t *testing.T, query string) apimodels.RuleResponse {
+			r, err := http.NewRequest("GET", "/api/v1/rules"+query, nil)
+			require.NoError(t, err)
+			c := &contextmodel.ReqContext{
+				Context: &web.Context{Req: r},
+				SignedInUser: &user.SignedInUser{
+					OrgID:       orgID,
+					Permissions: queryPermissions,
+				},
+			}
+			resp := api.RouteGetRuleStatuses(c)
+			require.Equal(t, http.StatusOK, resp.Status())
+			var res apimodels.RuleResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &res))
+			return res
+		}
+
+		t.Run("rules whose alert instances are all suppressed are suppressed", func(t *testing.T) {
+			res := getRules(t, "")
+			require.Equal(t, map[string]int64{"suppressed": 1, "pending": 1}, res.Data.Totals)
+			require.Len(t, res.Data.RuleGroups, 1)
+			rg := res.Data.RuleGroups[0]
+			require.Len(t, rg.Rules, 2)
+			require.Equal(t, "suppressed", rg.Rules[0].State)
+			require.Equal(t, map[string]int64{"suppressed": 2}, rg.Rules[0].Totals)
+			require.Equal(t, "pending", rg.Rules[1].State)
+			require.Equal(t, map[string]int64{"suppressed": 1, "pending": 1}, rg.Rules[1].Totals)
+		})
+
+		t.Run("state filter accepts suppressed", func(t *testing.T) {
+			res := getRules(t, "?state=suppressed")
+			require.Len(t, res.Data.RuleGroups, 1)
+			rg := res.Data.RuleGroups[0]
+			require.Len(t, rg.Rules, 1)
+			require.Equal(t, "suppressed", rg.Rules[0].State)
+			require.Len(t, rg.Rules[0].Alerts, 2)
+		})
+	})
+
 	t.Run("test with matcher on labels", func(t *testing.T) {
 		fakeStore, fakeAIM, api := setupAPI(t)
 		// create two rules in the same Rule Group to keep assertions simple
@@ -30,6 +30,7 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	recordingTargets    RecordingTargetValidator
 	folderSvc           folder.Service
 
 	// XXX: Used to flag recording rules, remove when FT is removed
@@ -347,6 +348,10 @@ func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar de
 		)
 	}
 
+	if err := validateRecordingTargets(c.Req.Context(), srv.recordingTargets, upstreamModel.OrgID, &upstreamModel); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
 	provenance := determineProvenance(c)
 	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), c.SignedInUser, upstreamModel, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
@@ -385,6 +390,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar def
 
 	updated.OrgID = c.SignedInUser.GetOrgID()
 	updated.UID = UID
+	if err := validateRecordingTargets(c.Req.Context(), srv.recordingTargets, updated.OrgID, &updated); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	provenance := determineProvenance(c)
 	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), c.SignedInUser, updated, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
@@ -503,6 +511,13 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	if err != nil {
 		ErrResp(http.StatusBadRequest, err, "")
 	}
+	rules := make([]*alerting_models.AlertRule, 0, len(groupModel.Rules))
+	for i := range groupModel.Rules {
+		rules = append(rules, &groupModel.Rules[i])
+	}
+	if err := validateRecordingTargets(c.Req.Context(), srv.recordingTargets, c.SignedInUser.GetOrgID(), rules...); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	provenance := determineProvenance(c)
 	err = srv.alertRules.ReplaceRuleGroup(c.Req.Context(), c.SignedInUser, groupModel, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
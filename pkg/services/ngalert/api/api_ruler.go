@@ -52,6 +52,7 @@ type RulerSrv struct {
 	log                log.Logger
 	cfg                *setting.UnifiedAlertingSettings
 	conditionValidator ConditionValidator
+	recordingTargets   RecordingTargetValidator
 	authz              RuleAccessControlService
 
 	amConfigStore  AMConfigStore
@@ -349,7 +350,7 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *contextmodel.ReqContext, ruleGro
 		RuleGroup:    ruleGroupConfig.Name,
 	}
 
-	return srv.updateAlertRulesInGroup(c, groupKey, rules)
+	return srv.updateAlertRulesInGroup(c, groupKey, rules, nil)
 }
 
 func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) error {
@@ -365,10 +366,10 @@ func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) er
 }
 
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
-// All operations are performed in a single transaction
+// All operations are performed in a single transaction. restoredFrom maps UIDs of rules that are restored from a previous version to that version.
 //
 //nolint:gocyclo
-func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
+func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals, restoredFrom map[string]int64) response.Response {
 	var finalChanges *store.GroupDelta
 	var dbConfig *ngmodels.AlertConfiguration
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
@@ -397,6 +398,14 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return err
 		}
 
+		if err := validateRecordingTargets(c.Req.Context(), srv.recordingTargets, groupKey.OrgID, changedRecordingTargets(groupChanges)...); err != nil {
+			return err
+		}
+
+		if err := srv.authorizeDependencies(tranCtx, c.SignedInUser, groupChanges); err != nil {
+			return err
+		}
+
 		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
 		if len(newOrUpdatedNotificationSettings) > 0 {
 			dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
@@ -439,8 +448,9 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			for _, update := range finalChanges.Update {
 				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
 				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
+					Existing:     update.Existing,
+					New:          *update.New,
+					RestoredFrom: restoredFrom[update.New.UID],
 				})
 			}
 			err = srv.store.UpdateAlertRules(tranCtx, updates)
@@ -576,6 +586,8 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
 			Metadata:             AlertRuleMetadataFromModelMetadata(r.Metadata),
+			Dependencies:         ApiRuleDependenciesFromModelRuleDependencies(r.Dependencies),
+			InstanceLimit:        r.InstanceLimit,
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -643,6 +655,74 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	return nil
 }
 
+// changedRecordingTargets returns the new rules and the updated rules whose target data source changed.
+func changedRecordingTargets(groupChanges *store.GroupDelta) []*ngmodels.AlertRule {
+	var result []*ngmodels.AlertRule
+	result = append(result, groupChanges.New...)
+	for _, upd := range groupChanges.Update {
+		if upd.Existing.Record == nil || upd.New.Record == nil || upd.Existing.Record.TargetDatasourceUID != upd.New.Record.TargetDatasourceUID {
+			result = append(result, upd.New)
+		}
+	}
+	return result
+}
+
+// authorizeDependencies checks that the rules that new and updated rules depend on exist, and that the user can read them.
+func (srv RulerSrv) authorizeDependencies(ctx context.Context, user identity.Requester, groupChanges *store.GroupDelta) error {
+	uids := make(map[string]struct{})
+	addDependencies := func(rule *ngmodels.AlertRule) {
+		for _, dependency := range rule.Dependencies {
+			if dependency.RuleUID != "" {
+				uids[dependency.RuleUID] = struct{}{}
+			}
+		}
+	}
+	for _, rule := range groupChanges.New {
+		addDependencies(rule)
+	}
+	for _, upd := range groupChanges.Update {
+		addDependencies(upd.New)
+	}
+	// access to the rules of the affected groups is already checked by AuthorizeRuleChanges
+	for _, rules := range groupChanges.AffectedGroups {
+		for _, rule := range rules {
+			delete(uids, rule.UID)
+		}
+	}
+	for _, rule := range groupChanges.New {
+		delete(uids, rule.UID)
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	ruleUIDs := make([]string, 0, len(uids))
+	for uid := range uids {
+		ruleUIDs = append(ruleUIDs, uid)
+	}
+	slices.Sort(ruleUIDs)
+	rules, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:    groupChanges.GroupKey.OrgID,
+		RuleUIDs: ruleUIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get the rules the alert rules depend on: %w", err)
+	}
+	found := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := srv.authz.AuthorizeAccessToRuleGroup(ctx, user, ngmodels.RulesGroup{rule}); err != nil {
+			return err
+		}
+		found[rule.UID] = struct{}{}
+	}
+	for _, uid := range ruleUIDs {
+		if _, ok := found[uid]; !ok {
+			return fmt.Errorf("%w: the alert rule depends on rule with UID '%s' that does not exist", ngmodels.ErrAlertRuleFailedValidation, uid)
+		}
+	}
+	return nil
+}
+
 // shouldValidate returns true if the rule is not paused and there are changes in the rule that are not ignored
 func shouldValidate(delta store.RuleDelta) bool {
 	for _, diff := range delta.Diff {
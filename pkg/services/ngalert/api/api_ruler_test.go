@@ -638,6 +638,79 @@ func TestValidateQueries(t *testing.T) {
 	})
 }
 
+func TestValidateRecordingTargets(t *testing.T) {
+	withTarget := func(uid string) *models.AlertRule {
+		rule := models.RuleGen.With(models.RuleGen.WithAllRecordingRules()).GenerateRef()
+		rule.Record.TargetDatasourceUID = uid
+		return rule
+	}
+	unchanged := withTarget("unchanged")
+	delta := store.GroupDelta{
+		New: []*models.AlertRule{withTarget("new"), withTarget("new"), withTarget("")},
+		Update: []store.RuleDelta{
+			{Existing: unchanged, New: models.CopyRule(unchanged)},
+			{Existing: withTarget("old"), New: withTarget("changed")},
+		},
+	}
+
+	t.Run("should validate the targets of new rules and changed targets once", func(t *testing.T) {
+		validator := &fakeRecordingTargetValidator{}
+		require.NoError(t, validateRecordingTargets(context.Background(), validator, 1, changedRecordingTargets(&delta)...))
+		require.Equal(t, []string{"new", "changed"}, validator.validated)
+	})
+
+	t.Run("should return rule validate error if the target is invalid", func(t *testing.T) {
+		validator := &fakeRecordingTargetValidator{err: errors.New("data source not found")}
+		err := validateRecordingTargets(context.Background(), validator, 1, changedRecordingTargets(&delta)...)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "data source not found")
+	})
+}
+
+type fakeRecordingTargetValidator struct {
+	validated []string
+	err       error
+}
+
+func (f *fakeRecordingTargetValidator) ValidateTarget(_ context.Context, _ int64, dsUID string) error {
+	f.validated = append(f.validated, dsUID)
+	return f.err
+}
+
+func TestAuthorizeDependencies(t *testing.T) {
+	orgID := rand.Int63()
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID))
+	dependency := gen.GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), dependency)
+	svc := createService(ruleStore)
+
+	deltaFor := func(dependencyUID string) *store.GroupDelta {
+		rule := gen.With(gen.WithDependencies(models.RuleDependency{RuleUID: dependencyUID})).GenerateRef()
+		return &store.GroupDelta{
+			GroupKey: rule.GetGroupKey(),
+			New:      []*models.AlertRule{rule},
+		}
+	}
+
+	t.Run("should return validation error if the dependency does not exist", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{dependency}, orgID), nil)
+		err := svc.authorizeDependencies(context.Background(), req.SignedInUser, deltaFor("missing"))
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should return authorization error if the user cannot read the dependency", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil)
+		err := svc.authorizeDependencies(context.Background(), req.SignedInUser, deltaFor(dependency.UID))
+		require.ErrorIs(t, err, accesscontrol.ErrAuthorizationBase)
+	})
+
+	t.Run("should succeed if the user can read the dependency", func(t *testing.T) {
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{dependency}, orgID), nil)
+		require.NoError(t, svc.authorizeDependencies(context.Background(), req.SignedInUser, deltaFor(dependency.UID)))
+	})
+}
+
 func createServiceWithProvenanceStore(store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(store)
 	svc.provenanceStore = provenanceStore
@@ -654,10 +727,11 @@ func createService(store *fakes.RuleStore) *RulerSrv {
 		cfg: &setting.UnifiedAlertingSettings{
 			BaseInterval: 10 * time.Second,
 		},
-		authz:          accesscontrol.NewRuleService(acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())),
-		amConfigStore:  &fakeAMRefresher{},
-		amRefresher:    &fakeAMRefresher{},
-		featureManager: featuremgmt.WithFeatures(featuremgmt.FlagGrafanaManagedRecordingRules),
+		recordingTargets: &fakeRecordingTargetValidator{},
+		authz:            accesscontrol.NewRuleService(acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())),
+		amConfigStore:    &fakeAMRefresher{},
+		amRefresher:      &fakeAMRefresher{},
+		featureManager:   featuremgmt.WithFeatures(featuremgmt.FlagGrafanaManagedRecordingRules),
 	}
 }
 
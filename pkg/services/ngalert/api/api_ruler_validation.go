@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -144,6 +145,19 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		}
 	}
 
+	for _, d := range in.GrafanaManagedAlert.Dependencies {
+		dependency := ModelRuleDependencyFromApiRuleDependency(d)
+		if err := dependency.Validate(); err != nil {
+			return ngmodels.AlertRule{}, fmt.Errorf("%w: invalid dependency: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+		}
+		newRule.Dependencies = append(newRule.Dependencies, dependency)
+	}
+
+	if in.GrafanaManagedAlert.InstanceLimit < 0 {
+		return ngmodels.AlertRule{}, fmt.Errorf("%w: instance_limit cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	newRule.InstanceLimit = in.GrafanaManagedAlert.InstanceLimit
+
 	if in.GrafanaManagedAlert.Metadata != nil {
 		newRule.Metadata.EditorSettings = ngmodels.EditorSettings{
 			SimplifiedQueryAndExpressionsSection: in.GrafanaManagedAlert.Metadata.EditorSettings.SimplifiedQueryAndExpressionsSection,
@@ -188,6 +202,30 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	return newRule, nil
 }
 
+// RecordingTargetValidator validates the data sources that recording rules write to.
+type RecordingTargetValidator interface {
+	ValidateTarget(ctx context.Context, orgID int64, dsUID string) error
+}
+
+// validateRecordingTargets checks that the data sources that the recording rules write to exist and are supported.
+func validateRecordingTargets(ctx context.Context, validator RecordingTargetValidator, orgID int64, rules ...*ngmodels.AlertRule) error {
+	checked := make(map[string]struct{})
+	for _, rule := range rules {
+		if rule.Record == nil || rule.Record.TargetDatasourceUID == "" {
+			continue
+		}
+		uid := rule.Record.TargetDatasourceUID
+		if _, ok := checked[uid]; ok {
+			continue
+		}
+		if err := validator.ValidateTarget(ctx, orgID, uid); err != nil {
+			return fmt.Errorf("%w '%s': %s", ngmodels.ErrAlertRuleFailedValidation, rule.Title, err.Error())
+		}
+		checked[uid] = struct{}{}
+	}
+	return nil
+}
+
 func validateLabels(l map[string]string) error {
 	for key := range l {
 		if _, ok := ngmodels.LabelsUserCannotSpecify[key]; ok {
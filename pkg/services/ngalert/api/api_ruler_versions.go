@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteGetRuleVersions returns the stored versions of the rule with the given UID, the latest version first.
+func (srv RulerSrv) RouteGetRuleVersions(c *contextmodel.ReqContext, ruleUID string) response.Response {
+	ctx := c.Req.Context()
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	versions, err := srv.store.GetAlertRuleVersions(ctx, &ngmodels.GetAlertRuleVersionsQuery{
+		OrgID: rule.OrgID,
+		UID:   rule.UID,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule versions")
+	}
+
+	result := make(apimodels.GettableRuleVersions, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, toGettableRuleVersion(*v))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteGetRuleVersion returns the definition of the rule with the given UID at the given version.
+func (srv RulerSrv) RouteGetRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64) response.Response {
+	ctx := c.Req.Context()
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	v, err := srv.getAuthorizedRuleVersion(ctx, c, rule, version)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule version")
+	}
+
+	provenance, err := srv.provenanceStore.GetProvenance(ctx, &rule, rule.OrgID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule provenance", err)
+	}
+
+	result := toGettableRuleVersion(v)
+	node := toGettableExtendedRuleNode(v.Rule, map[string]ngmodels.Provenance{rule.ResourceID(): provenance})
+	result.Rule = &node
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteDiffRuleVersions returns the fields that differ between two versions of the rule with the given UID.
+func (srv RulerSrv) RouteDiffRuleVersions(c *contextmodel.ReqContext, ruleUID string, cmd apimodels.PostableRuleVersionsDiff) response.Response {
+	ctx := c.Req.Context()
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	base, err := srv.getAuthorizedRuleVersion(ctx, c, rule, cmd.Base)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get base rule version")
+	}
+	next, err := srv.getAuthorizedRuleVersion(ctx, c, rule, cmd.New)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get new rule version")
+	}
+
+	diff := base.Rule.Diff(&next.Rule, store.AlertRuleFieldsToIgnoreInDiff[:]...)
+	result := apimodels.RuleVersionsDiff{
+		Base:  cmd.Base,
+		New:   cmd.New,
+		Diffs: make([]apimodels.RuleVersionFieldDiff, 0, len(diff)),
+	}
+	for _, d := range diff {
+		result.Diffs = append(result.Diffs, apimodels.RuleVersionFieldDiff{
+			Path:  d.Path,
+			Left:  diffValue(d.Left),
+			Right: diffValue(d.Right),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// RouteRestoreRuleVersion saves the definition of a previous version as a new version of the rule.
+// The rule stays in its current folder and group. The change is applied as an update of the rule group,
+// and therefore goes through the same validation, authorization and provenance checks.
+// Provisioned rules cannot be restored.
+func (srv RulerSrv) RouteRestoreRuleVersion(c *contextmodel.ReqContext, ruleUID string, version int64) response.Response {
+	ctx := c.Req.Context()
+
+	rule, err := srv.getAuthorizedRuleByUid(ctx, c, ruleUID)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule by UID")
+	}
+
+	v, err := srv.getAuthorizedRuleVersion(ctx, c, rule, version)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule version")
+	}
+
+	restored := v.Rule
+	restored.Version = rule.Version
+	restored.NamespaceUID = rule.NamespaceUID
+	restored.RuleGroup = rule.RuleGroup
+	restored.RuleGroupIndex = rule.RuleGroupIndex
+	// the interval belongs to the group and must not be changed by restoring a single rule
+	restored.IntervalSeconds = rule.IntervalSeconds
+
+	groupKey := rule.GetGroupKey()
+	groupRules, err := srv.getAuthorizedRuleGroup(ctx, c, groupKey)
+	if err != nil {
+		return ruleVersionErrorResponse(err, "failed to get rule group")
+	}
+
+	submitted := make([]*ngmodels.AlertRuleWithOptionals, 0, len(groupRules))
+	for _, r := range groupRules {
+		next := *r
+		if r.UID == restored.UID {
+			next = restored
+		}
+		submitted = append(submitted, &ngmodels.AlertRuleWithOptionals{AlertRule: next, HasPause: true})
+	}
+
+	return srv.updateAlertRulesInGroup(c, groupKey, submitted, map[string]int64{rule.UID: version})
+}
+
+// getAuthorizedRuleVersion fetches the version of the rule and checks whether the user is authorized to read it
+// in the folder the rule was stored in at that version.
+func (srv RulerSrv) getAuthorizedRuleVersion(ctx context.Context, c *contextmodel.ReqContext, rule ngmodels.AlertRule, version int64) (ngmodels.AlertRuleVersion, error) {
+	v, err := srv.store.GetAlertRuleVersion(ctx, &ngmodels.GetAlertRuleVersionQuery{
+		OrgID:   rule.OrgID,
+		UID:     rule.UID,
+		Version: version,
+	})
+	if err != nil {
+		return ngmodels.AlertRuleVersion{}, err
+	}
+	if v.Rule.NamespaceUID != rule.NamespaceUID {
+		if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, &v.Rule); err != nil {
+			return ngmodels.AlertRuleVersion{}, err
+		}
+	}
+
+	// the version history does not store the fields that are derived from the rule
+	v.Rule.ID = rule.ID
+	if err := v.Rule.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return ngmodels.AlertRuleVersion{}, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err)
+	}
+	return *v, nil
+}
+
+func toGettableRuleVersion(v ngmodels.AlertRuleVersion) apimodels.GettableRuleVersion {
+	result := apimodels.GettableRuleVersion{
+		Version:       v.Rule.Version,
+		ParentVersion: v.ParentVersion,
+		RestoredFrom:  v.RestoredFrom,
+		Created:       v.Created,
+	}
+	if v.CreatedBy != nil {
+		result.CreatedBy = *v.CreatedBy
+	}
+	return result
+}
+
+// diffValue converts a value reported by the diff to a value that can be serialized.
+// Returns nil if the value is missing on one side of the diff.
+func diffValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if !v.CanInterface() {
+		return fmt.Sprintf("%v", v)
+	}
+	return v.Interface()
+}
+
+func ruleVersionErrorResponse(err error, msg string) response.Response {
+	if errors.Is(err, ngmodels.ErrAlertRuleNotFound) || errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, msg)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, msg, err)
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	gen := models.RuleGen.With(
+		models.RuleGen.WithGroupKey(groupKey),
+		models.RuleGen.WithUniqueID(),
+		models.RuleGen.WithSequentialGroupIndex(),
+		models.RuleGen.WithIntervalSeconds(60),
+		models.RuleGen.WithNoNotificationSettings(),
+		models.RuleGen.WithDashboardAndPanel(nil, nil),
+		models.RuleGen.WithAnnotations(nil),
+	)
+
+	setup := func(t *testing.T) (*fakes.RuleStore, []*models.AlertRule) {
+		t.Helper()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+
+		rules := gen.GenerateManyRef(2)
+		rule := rules[0]
+		rule.Version = 3
+		ruleStore.PutRule(context.Background(), rules...)
+
+		author := "user:" + util.GenerateShortUID()
+		versions := make([]*models.AlertRuleVersion, 0, 3)
+		for v := rule.Version; v > 0; v-- {
+			versionRule := models.CopyRule(rule)
+			versionRule.ID = 0
+			versionRule.Version = v
+			versionRule.Title = fmt.Sprintf("%s-v%d", rule.Title, v)
+			versions = append(versions, &models.AlertRuleVersion{
+				Rule:          *versionRule,
+				ParentVersion: v - 1,
+				Created:       time.Unix(v*60, 0).UTC(),
+				CreatedBy:     &author,
+			})
+		}
+		ruleStore.Versions[rule.GetKey()] = versions
+		return ruleStore, rules
+	}
+
+	t.Run("should list versions", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(rules, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersions(req, rule.UID)
+
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		var result apimodels.GettableRuleVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 3)
+		require.EqualValues(t, 3, result[0].Version)
+		require.EqualValues(t, 2, result[0].ParentVersion)
+		require.Equal(t, *ruleStore.Versions[rule.GetKey()][0].CreatedBy, result[0].CreatedBy)
+		require.Nil(t, result[0].Rule)
+	})
+
+	t.Run("should return 404 if rule does not exist", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(rules, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersions(req, "unknown")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 403 if user cannot read the rule", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		req := createRequestContextWithPerms(orgID, map[int64]map[string][]string{}, nil)
+
+		response := createService(ruleStore).RouteGetRuleVersions(req, rule.UID)
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should get a version", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(rules, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersion(req, rule.UID, 2)
+
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		var result apimodels.GettableRuleVersion
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.EqualValues(t, 2, result.Version)
+		require.NotNil(t, result.Rule)
+		require.Equal(t, rule.Title+"-v2", result.Rule.GrafanaManagedAlert.Title)
+		require.Equal(t, rule.ID, result.Rule.GrafanaManagedAlert.ID)
+
+		response = createService(ruleStore).RouteGetRuleVersion(req, rule.UID, 10)
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should calculate diff between versions", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(rules, orgID), nil)
+
+		response := createService(ruleStore).RouteDiffRuleVersions(req, rule.UID, apimodels.PostableRuleVersionsDiff{Base: 1, New: 3})
+
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		var result apimodels.RuleVersionsDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, []apimodels.RuleVersionFieldDiff{
+			{Path: "Title", Left: rule.Title + "-v1", Right: rule.Title + "-v3"},
+		}, result.Diffs)
+	})
+
+	t.Run("should restore a version as a new update", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		perms := createPermissionsForRules(rules, orgID)
+		perms[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)}
+		req := createRequestContextWithPerms(orgID, perms, nil)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+
+		response := svc.RouteRestoreRuleVersion(req, rule.UID, 1)
+
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		updates := map[string]models.UpdateRule{}
+		for _, op := range ruleStore.RecordedOps {
+			if u, ok := op.([]models.UpdateRule); ok {
+				for _, upd := range u {
+					updates[upd.New.UID] = upd
+				}
+			}
+		}
+		require.Contains(t, updates, rule.UID)
+		require.Equal(t, rule.Title+"-v1", updates[rule.UID].New.Title)
+		require.EqualValues(t, 1, updates[rule.UID].RestoredFrom)
+		// other rules in the group are not restored
+		require.Zero(t, updates[rules[1].UID].RestoredFrom)
+		require.Equal(t, rules[1].Title, updates[rules[1].UID].New.Title)
+	})
+
+	t.Run("should not restore provisioned rules", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		provisioningStore := fakes.NewFakeProvisioningStore()
+		require.NoError(t, provisioningStore.SetProvenance(context.Background(), rule, orgID, models.ProvenanceAPI))
+		perms := createPermissionsForRules(rules, orgID)
+		perms[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)}
+		req := createRequestContextWithPerms(orgID, perms, nil)
+		svc := createServiceWithProvenanceStore(ruleStore, provisioningStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+
+		response := svc.RouteRestoreRuleVersion(req, rule.UID, 1)
+
+		require.Equalf(t, http.StatusBadRequest, response.Status(), string(response.Body()))
+	})
+
+	t.Run("should not restore if user cannot update rules", func(t *testing.T) {
+		ruleStore, rules := setup(t)
+		rule := rules[0]
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(rules, orgID), nil)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+
+		response := svc.RouteRestoreRuleVersion(req, rule.UID, 1)
+
+		require.Equalf(t, http.StatusForbidden, response.Status(), string(response.Body()))
+	})
+}
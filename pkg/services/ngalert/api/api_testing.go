@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -39,6 +40,10 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 }
 
+type ruleLister interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -47,10 +52,12 @@ type TestingApiSrv struct {
 	evaluator       eval.EvaluatorFactory
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
+	ruleTester      *backtesting.RuleTester
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       ruleLister
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -286,3 +293,50 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// RouteTestRuleUnitTests runs unit tests of the Grafana-managed alert rules referenced by the tests.
+// The data queries of the rules return the input series of the tests instead of querying the data sources.
+// Only rules that the user can read can be tested.
+func (srv TestingApiSrv) RouteTestRuleUnitTests(c *contextmodel.ReqContext, body apimodels.RuleUnitTests) response.Response {
+	ctx := c.Req.Context()
+
+	query := &ngmodels.ListAlertRulesQuery{OrgID: c.SignedInUser.GetOrgID()}
+	byTitle := false
+	for _, test := range body.Tests {
+		for _, tc := range test.AlertRuleTests {
+			if tc.RuleUID == "" {
+				byTitle = true
+				continue
+			}
+			query.RuleUIDs = append(query.RuleUIDs, tc.RuleUID)
+		}
+	}
+	if byTitle {
+		// rules referenced by title can be anywhere in the organization
+		query.RuleUIDs = nil
+	}
+
+	rules, err := srv.ruleStore.ListAlertRules(ctx, query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	readable := make([]*ngmodels.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+			if errors.Is(err, authz.ErrAuthorizationBase) {
+				continue
+			}
+			return errorToResponse(err)
+		}
+		readable = append(readable, rule)
+	}
+
+	result, err := srv.ruleTester.Run(ctx, c.SignedInUser, readable, body)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to run rule tests")
+	}
+	return response.JSON(http.StatusOK, result)
+}
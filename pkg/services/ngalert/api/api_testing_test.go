@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -391,6 +394,86 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteTestRuleUnitTests(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	f := randFolder()
+	rule := models.RuleGen.With(
+		models.RuleGen.WithOrgID(rc.OrgID),
+		models.RuleGen.WithNamespaceUID(f.UID),
+		models.RuleGen.WithIntervalSeconds(60),
+		models.RuleGen.WithFor(0),
+		models.RuleGen.WithLabels(nil),
+		models.RuleGen.WithAnnotations(nil),
+		models.RuleGen.WithQuery(
+			models.AlertQuery{
+				RefID:             "A",
+				DatasourceUID:     "prometheus",
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(5 * time.Minute)},
+				Model:             json.RawMessage(`{"expr": "up"}`),
+			},
+			models.CreateReduceExpression("B", "A", "last"),
+		),
+		models.RuleGen.WithCondition("B"),
+	).GenerateRef()
+
+	body := definitions.RuleUnitTests{
+		Tests: []definitions.RuleUnitTest{
+			{
+				InputSeries: []definitions.RuleUnitTestSeries{{Series: `up{job="api"}`, Values: "0 1"}},
+				AlertRuleTests: []definitions.AlertRuleUnitTestCase{
+					{
+						EvalTime:       0,
+						RuleUID:        rule.UID,
+						ExpectedAlerts: nil,
+					},
+					{
+						EvalTime:       prommodel.Duration(time.Minute),
+						RuleUID:        rule.UID,
+						ExpectedAlerts: []definitions.RuleUnitTestExpectedAlert{{ExpectedLabels: map[string]string{"job": "api"}}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("should run tests of rules the user can read", func(t *testing.T) {
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+		ruleStore.PutRule(context.Background(), rule)
+		ac := acMock.New().WithPermissions([]ac.Permission{
+			{Action: ac.ActionAlertingRuleRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+		})
+		srv := createTestingApiSrv(t, nil, ac, nil, featuremgmt.WithFeatures(), ruleStore)
+
+		response := srv.RouteTestRuleUnitTests(rc, body)
+
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		var result definitions.RuleUnitTestResults
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Truef(t, result.Success, "%v", result.Tests)
+	})
+
+	t.Run("should not find rules the user cannot read", func(t *testing.T) {
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+		ruleStore.PutRule(context.Background(), rule)
+		srv := createTestingApiSrv(t, nil, acMock.New(), nil, featuremgmt.WithFeatures(), ruleStore)
+
+		response := srv.RouteTestRuleUnitTests(rc, body)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager featuremgmt.FeatureToggles, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -404,5 +487,7 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		tracer:          tracing.InitializeTracerForTest(),
 		featureManager:  featureManager,
 		folderService:   ruleStore,
+		ruleStore:       ruleStore,
+		ruleTester:      backtesting.NewRuleTester(nil, featureManager, tracing.InitializeTracerForTest()),
 	}
 }
@@ -41,11 +41,21 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rules",
 		http.MethodGet + "/api/ruler/grafana/api/v1/export/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
-	case http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}":
+	case http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+		http.MethodGet + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}",
+		http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/calculate-diff":
 		eval = ac.EvalAll(
 			ac.EvalPermission(ac.ActionAlertingRuleRead),
 			ac.EvalPermission(dashboards.ActionFoldersRead),
 		)
+	case http.MethodPost + "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore":
+		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(dashboards.ActionFoldersRead),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+		)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
@@ -92,6 +102,9 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/rule/test/grafana":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/test/grafana/unit":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	// Grafana Rules Testing Paths
 	case http.MethodPost + "/api/v1/rule/backtest":
 		// additional authorization is done in the request handler
@@ -161,6 +174,38 @@ func (api *API) authorize(method, path string) web.Handler {
 			),
 		)
 
+	// Silence schedules for Grafana paths. They are authorized as the silences they create.
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/silence-schedules",
+		http.MethodGet + "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingInstanceRead),
+			ac.EvalPermission(ac.ActionAlertingSilencesRead),
+		)
+	case http.MethodPost + "/api/alertmanager/grafana/api/v1/silence-schedules":
+		eval = ac.EvalAll(
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceRead),
+				ac.EvalPermission(ac.ActionAlertingSilencesRead),
+			),
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceCreate),
+				ac.EvalPermission(ac.ActionAlertingSilencesCreate),
+			),
+		)
+	case http.MethodPut + "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}",
+		http.MethodDelete + "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}",
+		http.MethodPost + "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}/approve":
+		eval = ac.EvalAll(
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceRead),
+				ac.EvalPermission(ac.ActionAlertingSilencesRead),
+			),
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingInstanceUpdate),
+				ac.EvalPermission(ac.ActionAlertingSilencesWrite),
+			),
+		)
+
 	// Alert Instances. Grafana Paths
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/alerts/groups":
 		eval = ac.EvalPermission(ac.ActionAlertingInstanceRead)
@@ -170,6 +215,13 @@ func (api *API) authorize(method, path string) web.Handler {
 	// Grafana Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/alerts":
 		eval = ac.EvalPermission(ac.ActionAlertingInstanceRead)
+	case http.MethodPost + "/api/prometheus/grafana/api/v1/rules/{RuleUID}/instances/{Fingerprint}/acknowledgement",
+		http.MethodDelete + "/api/prometheus/grafana/api/v1/rules/{RuleUID}/instances/{Fingerprint}/acknowledgement":
+		// additional authorization is done in the request handler
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingInstanceRead),
+			ac.EvalPermission(ac.ActionAlertingInstanceUpdate),
+		)
 
 	// Silences. External AM.
 	case http.MethodDelete + "/api/alertmanager/{DatasourceUID}/api/v2/silence/{SilenceId}":
@@ -202,6 +254,8 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodGet + "/api/alertmanager/grafana/config/history":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodGet + "/api/alertmanager/grafana/api/v1/notifications/deliveries":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/status":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/alerts":
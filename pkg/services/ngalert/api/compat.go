@@ -34,6 +34,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		InstanceLimit:        a.InstanceLimit,
 	}, nil
 }
 
@@ -58,6 +59,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		InstanceLimit:        rule.InstanceLimit,
 	}
 }
 
@@ -219,6 +221,9 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	if rule.Labels != nil {
 		result.Labels = &rule.Labels
 	}
+	if rule.InstanceLimit > 0 {
+		result.InstanceLimit = &rule.InstanceLimit
+	}
 	return result, nil
 }
 
@@ -491,10 +496,14 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	if r == nil {
 		return nil
 	}
-	return &definitions.AlertRuleRecordExport{
+	result := &definitions.AlertRuleRecordExport{
 		Metric: r.Metric,
 		From:   r.From,
 	}
+	if r.TargetDatasourceUID != "" {
+		result.TargetDatasourceUID = util.Pointer(r.TargetDatasourceUID)
+	}
+	return result
 }
 
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
@@ -502,8 +511,9 @@ func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 		return nil
 	}
 	return &models.Record{
-		Metric: r.Metric,
-		From:   r.From,
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
 	}
 }
 
@@ -512,11 +522,35 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 		return nil
 	}
 	return &definitions.Record{
-		Metric: r.Metric,
-		From:   r.From,
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
 	}
 }
 
+func ModelRuleDependencyFromApiRuleDependency(d definitions.RuleDependency) models.RuleDependency {
+	return models.RuleDependency{
+		RuleUID: d.RuleUID,
+		Labels:  d.Labels,
+		Equal:   d.Equal,
+	}
+}
+
+func ApiRuleDependenciesFromModelRuleDependencies(dependencies []models.RuleDependency) []definitions.RuleDependency {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, definitions.RuleDependency{
+			RuleUID: d.RuleUID,
+			Labels:  d.Labels,
+			Equal:   d.Equal,
+		})
+	}
+	return result
+}
+
 func GettableGrafanaReceiverFromReceiver(r *models.Integration, provenance models.Provenance) (definitions.GettableGrafanaReceiver, error) {
 	out := definitions.GettableGrafanaReceiver{
 		UID:                   r.UID,
@@ -67,3 +67,30 @@ func SilencePermissionToAPI(p models.SilencePermission) (definitions.SilencePerm
 		return "", fmt.Errorf("unknown permission: %s", p)
 	}
 }
+
+// PostableSilenceScheduleToModel converts a PostableSilenceSchedule to a models.SilenceSchedule.
+func PostableSilenceScheduleToModel(s definitions.PostableSilenceSchedule) models.SilenceSchedule {
+	return models.SilenceSchedule{
+		Matchers:           s.Matchers,
+		Comment:            s.Comment,
+		TimeIntervals:      s.TimeIntervals,
+		RequiresApprovalBy: s.RequiresApprovalBy,
+	}
+}
+
+// SilenceScheduleToGettable converts a models.SilenceSchedule to a GettableSilenceSchedule.
+func SilenceScheduleToGettable(s *models.SilenceSchedule) definitions.GettableSilenceSchedule {
+	return definitions.GettableSilenceSchedule{
+		UID:                s.UID,
+		Matchers:           s.Matchers,
+		Comment:            s.Comment,
+		TimeIntervals:      s.TimeIntervals,
+		RequiresApprovalBy: s.RequiresApprovalBy,
+		State:              string(s.State),
+		CreatedBy:          s.CreatedBy,
+		ApprovedBy:         s.ApprovedBy,
+		ApprovedAt:         s.ApprovedAt,
+		SilenceID:          s.SilenceID,
+		Updated:            s.Updated,
+	}
+}
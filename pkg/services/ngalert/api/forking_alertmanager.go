@@ -163,6 +163,10 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaAlertingConfigHistory(ctx
 	return f.GrafanaSvc.RouteGetAlertingConfigHistory(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationDeliveries(ctx)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostGrafanaAlertingConfigHistoryActivate(ctx *contextmodel.ReqContext, id string) response.Response {
 	return f.GrafanaSvc.RoutePostGrafanaAlertingConfigHistoryActivate(ctx, id)
 }
@@ -175,6 +179,30 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaSilences(ctx *contextmodel
 	return f.GrafanaSvc.RouteGetSilences(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaSilenceSchedules(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetSilenceSchedules(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteGetSilenceSchedule(ctx, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteCreateGrafanaSilenceSchedule(ctx *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule) response.Response {
+	return f.GrafanaSvc.RouteCreateSilenceSchedule(ctx, body)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteUpdateGrafanaSilenceSchedule(ctx *contextmodel.ReqContext, body apimodels.PostableSilenceSchedule, uid string) response.Response {
+	return f.GrafanaSvc.RouteUpdateSilenceSchedule(ctx, body, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteDeleteGrafanaSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteDeleteSilenceSchedule(ctx, uid)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteApproveGrafanaSilenceSchedule(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.GrafanaSvc.RouteApproveSilenceSchedule(ctx, uid)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostGrafanaAlertingConfig(ctx *contextmodel.ReqContext, conf apimodels.PostableUserConfig) response.Response {
 	if !conf.AlertmanagerConfig.ReceiverType().Can(apimodels.GrafanaReceiverType) {
 		return errorToResponse(backendTypeDoesNotMatchPayloadTypeError(apimodels.GrafanaBackend, conf.AlertmanagerConfig.ReceiverType().String()))
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -97,6 +100,30 @@ func (f *RulerApiHandler) handleRouteGetRuleByUID(ctx *contextmodel.ReqContext,
 	return f.GrafanaRuler.RouteGetRuleByUID(ctx, ruleUID)
 }
 
+func (f *RulerApiHandler) handleRouteGetRuleVersionsByUID(ctx *contextmodel.ReqContext, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteGetRuleVersions(ctx, ruleUID)
+}
+
+func (f *RulerApiHandler) handleRouteGetRuleVersion(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version")
+	}
+	return f.GrafanaRuler.RouteGetRuleVersion(ctx, ruleUID, v)
+}
+
+func (f *RulerApiHandler) handleRoutePostRuleVersionsDiff(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleVersionsDiff, ruleUID string) response.Response {
+	return f.GrafanaRuler.RouteDiffRuleVersions(ctx, ruleUID, conf)
+}
+
+func (f *RulerApiHandler) handleRoutePostRestoreRuleVersion(ctx *contextmodel.ReqContext, ruleUID, version string) response.Response {
+	v, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid version")
+	}
+	return f.GrafanaRuler.RouteRestoreRuleVersion(ctx, ruleUID, v)
+}
+
 func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupConfig, namespace string) response.Response {
 	payloadType := conf.Type()
 	if payloadType != apimodels.GrafanaBackend {
@@ -20,11 +20,14 @@ import (
 )
 
 type AlertmanagerApi interface {
+	RouteApproveGrafanaSilenceSchedule(*contextmodel.ReqContext) response.Response
 	RouteCreateGrafanaSilence(*contextmodel.ReqContext) response.Response
+	RouteCreateGrafanaSilenceSchedule(*contextmodel.ReqContext) response.Response
 	RouteCreateSilence(*contextmodel.ReqContext) response.Response
 	RouteDeleteAlertingConfig(*contextmodel.ReqContext) response.Response
 	RouteDeleteGrafanaAlertingConfig(*contextmodel.ReqContext) response.Response
 	RouteDeleteGrafanaSilence(*contextmodel.ReqContext) response.Response
+	RouteDeleteGrafanaSilenceSchedule(*contextmodel.ReqContext) response.Response
 	RouteDeleteSilence(*contextmodel.ReqContext) response.Response
 	RouteGetAMAlertGroups(*contextmodel.ReqContext) response.Response
 	RouteGetAMAlerts(*contextmodel.ReqContext) response.Response
@@ -35,8 +38,11 @@ type AlertmanagerApi interface {
 	RouteGetGrafanaAMStatus(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfig(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfigHistory(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaNotificationDeliveries(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaReceivers(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaSilence(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaSilenceSchedule(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaSilenceSchedules(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaSilences(*contextmodel.ReqContext) response.Response
 	RouteGetSilence(*contextmodel.ReqContext) response.Response
 	RouteGetSilences(*contextmodel.ReqContext) response.Response
@@ -46,8 +52,14 @@ type AlertmanagerApi interface {
 	RoutePostGrafanaAlertingConfigHistoryActivate(*contextmodel.ReqContext) response.Response
 	RoutePostTestGrafanaReceivers(*contextmodel.ReqContext) response.Response
 	RoutePostTestGrafanaTemplates(*contextmodel.ReqContext) response.Response
+	RouteUpdateGrafanaSilenceSchedule(*contextmodel.ReqContext) response.Response
 }
 
+func (f *AlertmanagerApiHandler) RouteApproveGrafanaSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	scheduleUIDParam := web.Params(ctx.Req)[":ScheduleUID"]
+	return f.handleRouteApproveGrafanaSilenceSchedule(ctx, scheduleUIDParam)
+}
 func (f *AlertmanagerApiHandler) RouteCreateGrafanaSilence(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableSilence{}
@@ -56,6 +68,14 @@ func (f *AlertmanagerApiHandler) RouteCreateGrafanaSilence(ctx *contextmodel.Req
 	}
 	return f.handleRouteCreateGrafanaSilence(ctx, conf)
 }
+func (f *AlertmanagerApiHandler) RouteCreateGrafanaSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableSilenceSchedule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteCreateGrafanaSilenceSchedule(ctx, conf)
+}
 func (f *AlertmanagerApiHandler) RouteCreateSilence(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -79,6 +99,11 @@ func (f *AlertmanagerApiHandler) RouteDeleteGrafanaSilence(ctx *contextmodel.Req
 	silenceIdParam := web.Params(ctx.Req)[":SilenceId"]
 	return f.handleRouteDeleteGrafanaSilence(ctx, silenceIdParam)
 }
+func (f *AlertmanagerApiHandler) RouteDeleteGrafanaSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	scheduleUIDParam := web.Params(ctx.Req)[":ScheduleUID"]
+	return f.handleRouteDeleteGrafanaSilenceSchedule(ctx, scheduleUIDParam)
+}
 func (f *AlertmanagerApiHandler) RouteDeleteSilence(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	silenceIdParam := web.Params(ctx.Req)[":SilenceId"]
@@ -120,6 +145,9 @@ func (f *AlertmanagerApiHandler) RouteGetGrafanaAlertingConfig(ctx *contextmodel
 func (f *AlertmanagerApiHandler) RouteGetGrafanaAlertingConfigHistory(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaAlertingConfigHistory(ctx)
 }
+func (f *AlertmanagerApiHandler) RouteGetGrafanaNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaNotificationDeliveries(ctx)
+}
 func (f *AlertmanagerApiHandler) RouteGetGrafanaReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaReceivers(ctx)
 }
@@ -128,6 +156,14 @@ func (f *AlertmanagerApiHandler) RouteGetGrafanaSilence(ctx *contextmodel.ReqCon
 	silenceIdParam := web.Params(ctx.Req)[":SilenceId"]
 	return f.handleRouteGetGrafanaSilence(ctx, silenceIdParam)
 }
+func (f *AlertmanagerApiHandler) RouteGetGrafanaSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	scheduleUIDParam := web.Params(ctx.Req)[":ScheduleUID"]
+	return f.handleRouteGetGrafanaSilenceSchedule(ctx, scheduleUIDParam)
+}
+func (f *AlertmanagerApiHandler) RouteGetGrafanaSilenceSchedules(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaSilenceSchedules(ctx)
+}
 func (f *AlertmanagerApiHandler) RouteGetGrafanaSilences(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaSilences(ctx)
 }
@@ -191,9 +227,31 @@ func (f *AlertmanagerApiHandler) RoutePostTestGrafanaTemplates(ctx *contextmodel
 	}
 	return f.handleRoutePostTestGrafanaTemplates(ctx, conf)
 }
+func (f *AlertmanagerApiHandler) RouteUpdateGrafanaSilenceSchedule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	scheduleUIDParam := web.Params(ctx.Req)[":ScheduleUID"]
+	// Parse Request Body
+	conf := apimodels.PostableSilenceSchedule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteUpdateGrafanaSilenceSchedule(ctx, conf, scheduleUIDParam)
+}
 
 func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}/approve"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}/approve"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}/approve",
+				api.Hooks.Wrap(srv.RouteApproveGrafanaSilenceSchedule),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/grafana/api/v2/silences"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -206,6 +264,18 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/api/v1/silence-schedules"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/api/v1/silence-schedules",
+				api.Hooks.Wrap(srv.RouteCreateGrafanaSilenceSchedule),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/{DatasourceUID}/api/v2/silences"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -254,6 +324,18 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}",
+				api.Hooks.Wrap(srv.RouteDeleteGrafanaSilenceSchedule),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/alertmanager/{DatasourceUID}/api/v2/silence/{SilenceId}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -374,6 +456,18 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications/deliveries"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications/deliveries"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/notifications/deliveries",
+				api.Hooks.Wrap(srv.RouteGetGrafanaNotificationDeliveries),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -398,6 +492,30 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}",
+				api.Hooks.Wrap(srv.RouteGetGrafanaSilenceSchedule),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/silence-schedules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/silence-schedules",
+				api.Hooks.Wrap(srv.RouteGetGrafanaSilenceSchedules),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/api/v2/silences"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -506,5 +624,17 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}",
+				api.Hooks.Wrap(srv.RouteUpdateGrafanaSilenceSchedule),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
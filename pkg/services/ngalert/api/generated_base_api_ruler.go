@@ -29,11 +29,15 @@ type RulerApi interface {
 	RouteGetNamespaceGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetNamespaceRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetRuleByUID(*contextmodel.ReqContext) response.Response
+	RouteGetRuleVersion(*contextmodel.ReqContext) response.Response
+	RouteGetRuleVersionsByUID(*contextmodel.ReqContext) response.Response
 	RouteGetRulegGroupConfig(*contextmodel.ReqContext) response.Response
 	RouteGetRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetRulesForExport(*contextmodel.ReqContext) response.Response
 	RoutePostNameGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
 	RoutePostNameRulesConfig(*contextmodel.ReqContext) response.Response
+	RoutePostRestoreRuleVersion(*contextmodel.ReqContext) response.Response
+	RoutePostRuleVersionsDiff(*contextmodel.ReqContext) response.Response
 	RoutePostRulesGroupForExport(*contextmodel.ReqContext) response.Response
 }
 
@@ -86,6 +90,17 @@ func (f *RulerApiHandler) RouteGetRuleByUID(ctx *contextmodel.ReqContext) respon
 	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
 	return f.handleRouteGetRuleByUID(ctx, ruleUIDParam)
 }
+func (f *RulerApiHandler) RouteGetRuleVersion(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRouteGetRuleVersion(ctx, ruleUIDParam, versionParam)
+}
+func (f *RulerApiHandler) RouteGetRuleVersionsByUID(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	return f.handleRouteGetRuleVersionsByUID(ctx, ruleUIDParam)
+}
 func (f *RulerApiHandler) RouteGetRulegGroupConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -122,6 +137,22 @@ func (f *RulerApiHandler) RoutePostNameRulesConfig(ctx *contextmodel.ReqContext)
 	}
 	return f.handleRoutePostNameRulesConfig(ctx, conf, datasourceUIDParam, namespaceParam)
 }
+func (f *RulerApiHandler) RoutePostRestoreRuleVersion(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	versionParam := web.Params(ctx.Req)[":Version"]
+	return f.handleRoutePostRestoreRuleVersion(ctx, ruleUIDParam, versionParam)
+}
+func (f *RulerApiHandler) RoutePostRuleVersionsDiff(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	// Parse Request Body
+	conf := apimodels.PostableRuleVersionsDiff{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRuleVersionsDiff(ctx, conf, ruleUIDParam)
+}
 func (f *RulerApiHandler) RoutePostRulesGroupForExport(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
@@ -243,6 +274,30 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}",
+				api.Hooks.Wrap(srv.RouteGetRuleVersion),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions",
+				api.Hooks.Wrap(srv.RouteGetRuleVersionsByUID),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}/{Groupname}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -303,6 +358,30 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore",
+				api.Hooks.Wrap(srv.RoutePostRestoreRuleVersion),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/calculate-diff"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/calculate-diff"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/ruler/grafana/api/v1/rule/{RuleUID}/versions/calculate-diff",
+				api.Hooks.Wrap(srv.RoutePostRuleVersionsDiff),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}/export"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
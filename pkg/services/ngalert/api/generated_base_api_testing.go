@@ -24,6 +24,7 @@ type TestingApi interface {
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
+	RouteTestRuleUnitTests(*contextmodel.ReqContext) response.Response
 }
 
 func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) response.Response {
@@ -60,6 +61,14 @@ func (f *TestingApiHandler) RouteTestRuleGrafanaConfig(ctx *contextmodel.ReqCont
 	}
 	return f.handleRouteTestRuleGrafanaConfig(ctx, conf)
 }
+func (f *TestingApiHandler) RouteTestRuleUnitTests(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RuleUnitTests{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteTestRuleUnitTests(ctx, conf)
+}
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -111,5 +120,17 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/test/grafana/unit"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/test/grafana/unit"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/test/grafana/unit",
+				api.Hooks.Wrap(srv.RouteTestRuleUnitTests),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -19,6 +19,8 @@ type RuleStore interface {
 	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) ([]*ngmodels.AlertRule, error)
 	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+	GetAlertRuleVersions(ctx context.Context, query *ngmodels.GetAlertRuleVersionsQuery) ([]*ngmodels.AlertRuleVersion, error)
+	GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) (*ngmodels.AlertRuleVersion, error)
 
 	// InsertAlertRules will insert all alert rules passed into the function
 	// and return the map of uuid to id.
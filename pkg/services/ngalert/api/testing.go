@@ -71,17 +71,19 @@ func (f *fakeAlertInstanceManager) GenerateAlertInstances(orgID int64, alertRule
 			f.states[orgID][alertRuleUID] = []*state.State{}
 		}
 
+		lbls := data.Labels{
+			"__alert_rule_namespace_uid__": "test_namespace_uid",
+			"__alert_rule_uid__":           fmt.Sprintf("test_alert_rule_uid_%v", i),
+			"alertname":                    fmt.Sprintf("test_title_%v", i),
+			"label":                        "test",
+			"instance_label":               "test",
+		}
 		newState := &state.State{
 			AlertRuleUID: alertRuleUID,
 			OrgID:        1,
-			Labels: data.Labels{
-				"__alert_rule_namespace_uid__": "test_namespace_uid",
-				"__alert_rule_uid__":           fmt.Sprintf("test_alert_rule_uid_%v", i),
-				"alertname":                    fmt.Sprintf("test_title_%v", i),
-				"label":                        "test",
-				"instance_label":               "test",
-			},
-			State: eval.Normal,
+			CacheID:      lbls.Fingerprint(),
+			Labels:       lbls,
+			State:        eval.Normal,
 			LatestResult: &state.Evaluation{
 				EvaluationTime:  evaluationTime.Add(1 * time.Minute),
 				EvaluationState: eval.Normal,
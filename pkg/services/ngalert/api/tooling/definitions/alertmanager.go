@@ -58,6 +58,14 @@ import (
 //     Responses:
 //       200: GettableHistoricUserConfigs
 
+// swagger:route GET /alertmanager/grafana/api/v1/notifications/deliveries alertmanager RouteGetGrafanaNotificationDeliveries
+//
+// gets the history of attempts of the Grafana Alertmanager to deliver notifications, the most recent first
+//
+//     Responses:
+//       200: GettableNotificationDeliveries
+//       400: ValidationError
+
 // swagger:route POST /alertmanager/grafana/config/history/{id}/_activate alertmanager RoutePostGrafanaAlertingConfigHistoryActivate
 //
 // revert Alerting configuration to the historical configuration specified by the given id
@@ -287,6 +295,60 @@ type RouteGetGrafanaAlertingConfigHistoryParams struct {
 	Limit int `json:"limit"`
 }
 
+// swagger:parameters RouteGetGrafanaNotificationDeliveries
+type RouteGetGrafanaNotificationDeliveriesParams struct {
+	// Only return deliveries to the contact point with this name.
+	// in:query
+	Receiver string `json:"receiver"`
+	// Only return deliveries to integrations of this type, for example "email".
+	// in:query
+	Integration string `json:"integration"`
+	// Only return deliveries with this status.
+	// in:query
+	// enum: success,failed
+	Status string `json:"status"`
+	// Only return deliveries of notifications that contain the alert with this fingerprint.
+	// in:query
+	Alert string `json:"alert"`
+	// Only return deliveries sent at or after this time, in Unix seconds.
+	// in:query
+	From int64 `json:"from"`
+	// Only return deliveries sent at or before this time, in Unix seconds.
+	// in:query
+	To int64 `json:"to"`
+	// Limit response to n deliveries. Defaults to 100, and cannot be more than 1000.
+	// in:query
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type GettableNotificationDeliveries []GettableNotificationDelivery
+
+// GettableNotificationDelivery is an attempt to deliver a notification to an integration of a contact point.
+type GettableNotificationDelivery struct {
+	// example: on-call
+	Receiver string `json:"receiver"`
+	// example: email
+	Integration      string `json:"integration"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	// The key of the alert group the notification was sent for.
+	GroupKey string `json:"groupKey"`
+	// The fingerprints of the alerts in the notification.
+	AlertFingerprints []string `json:"alertFingerprints"`
+	// The number of firing alerts in the notification.
+	Firing int `json:"firing"`
+	// The number of resolved alerts in the notification.
+	Resolved int `json:"resolved"`
+	// enum: success,failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Whether the Alertmanager retries the delivery after it failed.
+	Retry bool `json:"retry"`
+	// example: 1.5s
+	Duration string    `json:"duration"`
+	SentAt   time.Time `json:"sentAt"`
+}
+
 // swagger:parameters RoutePostTestGrafanaReceivers
 type TestReceiversConfigParams struct {
 	// in:body
@@ -20,6 +20,60 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetRuleVersionsByUID
+//
+// List the stored versions of a rule, the latest version first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersions
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version} ruler RouteGetRuleVersion
+//
+// Get a stored version of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersion
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/versions/calculate-diff ruler RoutePostRuleVersionsDiff
+//
+// Calculate the difference between two stored versions of a rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersionsDiff
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RoutePostRestoreRuleVersion
+//
+// Restore a stored version of a rule. The restored definition is saved as a new version of the rule.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: description: Conflict.
+
 // swagger:route Get /ruler/grafana/api/v1/rules ruler RouteGetGrafanaRulesConfig
 //
 // List rule groups
@@ -224,6 +278,64 @@ type PathGetRuleByUIDParams struct {
 	RuleUID string
 }
 
+// swagger:parameters RouteGetRuleVersionsByUID RoutePostRuleVersionsDiff
+type PathGetRuleVersionsParams struct {
+	// in: path
+	RuleUID string
+}
+
+// swagger:parameters RouteGetRuleVersion RoutePostRestoreRuleVersion
+type PathGetRuleVersionParams struct {
+	// in: path
+	RuleUID string
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RoutePostRuleVersionsDiff
+type PostRuleVersionsDiffParams struct {
+	// in: body
+	Body PostableRuleVersionsDiff
+}
+
+// swagger:model
+type GettableRuleVersions []GettableRuleVersion
+
+// swagger:model
+type GettableRuleVersion struct {
+	Version       int64     `json:"version"`
+	ParentVersion int64     `json:"parentVersion,omitempty"`
+	RestoredFrom  int64     `json:"restoredFrom,omitempty"`
+	Created       time.Time `json:"created"`
+	// UID of the identity that created the version. Empty if the version was created by Grafana.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// The rule definition. Only returned when a single version is requested.
+	Rule *GettableExtendedRuleNode `json:"rule,omitempty"`
+}
+
+// swagger:model
+type PostableRuleVersionsDiff struct {
+	Base int64 `json:"base"`
+	New  int64 `json:"new"`
+}
+
+// swagger:model
+type RuleVersionsDiff struct {
+	Base  int64                  `json:"base"`
+	New   int64                  `json:"new"`
+	Diffs []RuleVersionFieldDiff `json:"diffs"`
+}
+
+// RuleVersionFieldDiff describes a change of a single field of the rule.
+type RuleVersionFieldDiff struct {
+	// Path to the field, for example Data[0].Model or Labels[team]
+	Path string `json:"path"`
+	// Value in the base version. Absent if the field was added.
+	Left any `json:"left,omitempty"`
+	// Value in the new version. Absent if the field was removed.
+	Right any `json:"right,omitempty"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -457,6 +569,18 @@ type AlertRuleMetadata struct {
 	EditorSettings AlertRuleEditorSettings `json:"editor_settings" yaml:"editor_settings"`
 }
 
+// RuleDependency declares that the alert instances of a rule are suppressed while the rule it depends on is firing.
+// swagger:model
+type RuleDependency struct {
+	// UID of the rule this rule depends on. If empty, any rule with firing alert instances that match the labels.
+	RuleUID string `json:"rule_uid,omitempty" yaml:"rule_uid,omitempty"`
+	// Labels the firing alert instances of the dependency must have.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Labels that must have the same value in the firing alert instance of the dependency and the suppressed alert instance.
+	// If empty, all alert instances of the rule are suppressed.
+	Equal []string `json:"equal,omitempty" yaml:"equal,omitempty"`
+}
+
 // swagger:model
 type AlertRuleEditorSettings struct {
 	SimplifiedQueryAndExpressionsSection bool `json:"simplified_query_and_expressions_section" yaml:"simplified_query_and_expressions_section"`
@@ -516,6 +640,10 @@ type Record struct {
 	// required: true
 	// example: A
 	From string `json:"from" yaml:"from"`
+	// UID of the data source to write the recorded metric to. Prometheus, InfluxDB, PostgreSQL and MySQL data sources
+	// are supported. If it is empty, the metric is written to the remote write endpoint of the recording rules settings.
+	// example: influxdb-uid
+	TargetDatasourceUID string `json:"target_datasource_uid,omitempty" yaml:"target_datasource_uid,omitempty"`
 }
 
 // swagger:model
@@ -530,6 +658,8 @@ type PostableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         []RuleDependency               `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	InstanceLimit        int64                          `json:"instance_limit,omitempty" yaml:"instance_limit,omitempty"`
 }
 
 // swagger:model
@@ -552,6 +682,8 @@ type GettableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         []RuleDependency               `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	InstanceLimit        int64                          `json:"instance_limit,omitempty" yaml:"instance_limit,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
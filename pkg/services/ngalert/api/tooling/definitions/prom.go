@@ -42,6 +42,65 @@ import (
 //       200: AlertResponse
 //       404: NotFound
 
+// swagger:route POST /prometheus/grafana/api/v1/rules/{RuleUID}/instances/{Fingerprint}/acknowledgement prometheus RoutePostGrafanaAlertAcknowledgement
+//
+// acknowledges a firing alert instance of a Grafana-managed alert rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: Alert
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: description: Conflict.
+
+// swagger:route DELETE /prometheus/grafana/api/v1/rules/{RuleUID}/instances/{Fingerprint}/acknowledgement prometheus RouteDeleteGrafanaAlertAcknowledgement
+//
+// removes the acknowledgement of an alert instance of a Grafana-managed alert rule
+//
+//     Responses:
+//       200: Alert
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:parameters RoutePostGrafanaAlertAcknowledgement RouteDeleteGrafanaAlertAcknowledgement
+type AlertInstanceParams struct {
+	// in: path
+	RuleUID string
+	// Fingerprint of the alert instance as returned by the rules and alerts APIs.
+	// in: path
+	Fingerprint string
+}
+
+// swagger:parameters RoutePostGrafanaAlertAcknowledgement
+type PostableAlertAcknowledgementParams struct {
+	// in: body
+	Body PostableAlertAcknowledgement
+}
+
+// swagger:model
+type PostableAlertAcknowledgement struct {
+	Comment string `json:"comment,omitempty"`
+	// ExpiresAt is the time when the acknowledgement expires. If it is not set, the acknowledgement lasts until the alert instance resolves.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// SuppressNotifications stops sending notifications for the alert instance while it is acknowledged.
+	SuppressNotifications bool `json:"suppressNotifications,omitempty"`
+}
+
+// swagger:model
+type AlertAcknowledgement struct {
+	// required: true
+	AcknowledgedBy string `json:"acknowledgedBy"`
+	// required: true
+	AcknowledgedAt        time.Time  `json:"acknowledgedAt"`
+	Comment               string     `json:"comment,omitempty"`
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty"`
+	SuppressNotifications bool       `json:"suppressNotifications"`
+}
+
 // swagger:model
 type RuleResponse struct {
 	// in: body
@@ -143,7 +202,7 @@ func (s RuleGroupsSorter) Less(i, j int) bool { return s.by(&s.groups[i], &s.gro
 // adapted from cortex
 // swagger:model
 type AlertingRule struct {
-	// State can be "pending", "firing", "inactive".
+	// State can be "pending", "firing", "inactive", "suppressed".
 	// required: true
 	State string `json:"state,omitempty"`
 	// required: true
@@ -190,6 +249,9 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// Fingerprint identifies the alert instance of a Grafana-managed alert rule.
+	Fingerprint     string                `json:"fingerprint,omitempty"`
+	Acknowledgement *AlertAcknowledgement `json:"acknowledgement,omitempty"`
 }
 
 type StateByImportance int
@@ -170,6 +170,10 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// The maximum number of results the condition of the rule can produce in a single evaluation. It can only lower the global limit of evaluation results. Zero means that only the global limit applies.
+	// minimum: 0
+	// example: 100
+	InstanceLimit int64 `json:"instanceLimit,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -277,6 +281,7 @@ type AlertRuleExport struct {
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	InstanceLimit        *int64                               `json:"instanceLimit,omitempty" yaml:"instanceLimit,omitempty" hcl:"instance_limit"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -310,4 +315,6 @@ type AlertRuleNotificationSettingsExport struct {
 type AlertRuleRecordExport struct {
 	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
 	From   string `json:"from" yaml:"from" hcl:"from"`
+	// TargetDatasourceUID is the UID of the data source to write the recorded metric to.
+	TargetDatasourceUID *string `json:"target_datasource_uid,omitempty" yaml:"target_datasource_uid,omitempty" hcl:"target_datasource_uid,optional"`
 }
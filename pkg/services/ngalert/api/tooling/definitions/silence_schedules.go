@@ -0,0 +1,117 @@
+package definitions
+
+import (
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+// swagger:route GET /alertmanager/grafana/api/v1/silence-schedules alertmanager RouteGetGrafanaSilenceSchedules
+//
+// get silence schedules
+//
+//     Responses:
+//       200: GettableSilenceSchedules
+//       403: ForbiddenError
+
+// swagger:route POST /alertmanager/grafana/api/v1/silence-schedules alertmanager RouteCreateGrafanaSilenceSchedule
+//
+// create a silence schedule
+//
+//     Responses:
+//       201: PostSilenceScheduleResponse
+//       400: ValidationError
+//       403: ForbiddenError
+
+// swagger:route GET /alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID} alertmanager RouteGetGrafanaSilenceSchedule
+//
+// get a silence schedule
+//
+//     Responses:
+//       200: GettableSilenceSchedule
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route PUT /alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID} alertmanager RouteUpdateGrafanaSilenceSchedule
+//
+// update a silence schedule, schedules that require approval must be approved again
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route DELETE /alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID} alertmanager RouteDeleteGrafanaSilenceSchedule
+//
+// delete a silence schedule and expire its silence
+//
+//     Responses:
+//       200: Ack
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route POST /alertmanager/grafana/api/v1/silence-schedules/{ScheduleUID}/approve alertmanager RouteApproveGrafanaSilenceSchedule
+//
+// approve a pending silence schedule
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+//       409: PublicError
+
+// swagger:parameters RouteGetGrafanaSilenceSchedule RouteUpdateGrafanaSilenceSchedule RouteDeleteGrafanaSilenceSchedule RouteApproveGrafanaSilenceSchedule
+type SilenceScheduleUIDParam struct {
+	// in:path
+	// required: true
+	ScheduleUID string
+}
+
+// swagger:parameters RouteCreateGrafanaSilenceSchedule RouteUpdateGrafanaSilenceSchedule
+type PostableSilenceScheduleParams struct {
+	// in:body
+	Body PostableSilenceSchedule
+}
+
+// swagger:model
+type PostableSilenceSchedule struct {
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	Comment  string        `json:"comment,omitempty"`
+	// The time intervals in which the silence is active, in the same format as the time intervals of mute timings.
+	// required: true
+	// items:
+	//   $ref: '#/definitions/TimeIntervalItem'
+	TimeIntervals []timeinterval.TimeInterval `json:"timeIntervals"`
+	// The minimum organization role of the user that must approve the schedule before it becomes active.
+	// enum: Viewer,Editor,Admin
+	RequiresApprovalBy string `json:"requiresApprovalBy,omitempty"`
+}
+
+// swagger:model
+type GettableSilenceSchedules []GettableSilenceSchedule
+
+// swagger:model
+type GettableSilenceSchedule struct {
+	UID                string                      `json:"uid"`
+	Matchers           amv2.Matchers               `json:"matchers"`
+	Comment            string                      `json:"comment,omitempty"`
+	TimeIntervals      []timeinterval.TimeInterval `json:"timeIntervals"`
+	RequiresApprovalBy string                      `json:"requiresApprovalBy,omitempty"`
+	// enum: pending,approved
+	State      string     `json:"state"`
+	CreatedBy  string     `json:"createdBy"`
+	ApprovedBy string     `json:"approvedBy,omitempty"`
+	ApprovedAt *time.Time `json:"approvedAt,omitempty"`
+	// The ID of the last silence created by the schedule.
+	SilenceID string    `json:"silenceId,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+// swagger:model
+type PostSilenceScheduleResponse struct {
+	UID string `json:"uid"`
+}
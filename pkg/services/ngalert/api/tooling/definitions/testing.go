@@ -68,6 +68,20 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/test/grafana/unit testing RouteTestRuleUnitTests
+//
+// Run unit tests of Grafana-managed alert rules against input series
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleUnitTestResults
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -234,3 +248,79 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters RouteTestRuleUnitTests
+type RuleUnitTestsRequest struct {
+	// in:body
+	Body RuleUnitTests
+}
+
+// RuleUnitTests is a set of unit tests of alert rules, in a format similar to the one of promtool.
+// swagger:model
+type RuleUnitTests struct {
+	// Interval between evaluations of the rules. Defaults to the interval of the rule group.
+	EvaluationInterval model.Duration `json:"evaluation_interval,omitempty" yaml:"evaluation_interval,omitempty"`
+	// required: true
+	Tests []RuleUnitTest `json:"tests" yaml:"tests"`
+}
+
+// swagger:model
+type RuleUnitTest struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Interval between the samples of the input series. Defaults to 1m.
+	Interval model.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Series returned by the data queries of the rules, in the Prometheus expanding notation.
+	InputSeries []RuleUnitTestSeries `json:"input_series,omitempty" yaml:"input_series,omitempty"`
+	// Series returned by the data queries of the rules, in CSV format. The header contains the column "time"
+	// followed by a column per series in the Prometheus notation. Every row contains the time of the samples,
+	// relative to the start of the test, and a value per series. An empty value means that the sample is missing.
+	InputCSV string `json:"input_csv,omitempty" yaml:"input_csv,omitempty"`
+	// required: true
+	AlertRuleTests []AlertRuleUnitTestCase `json:"alert_rule_test" yaml:"alert_rule_test"`
+}
+
+// swagger:model
+type RuleUnitTestSeries struct {
+	// example: up{job="api"}
+	Series string `json:"series" yaml:"series"`
+	// example: 1 1 0x5 _ 1+1x3
+	Values string `json:"values" yaml:"values"`
+	// RefID of the data query that returns the series. If empty, the series is returned by all data queries.
+	RefID string `json:"ref_id,omitempty" yaml:"ref_id,omitempty"`
+}
+
+// swagger:model
+type AlertRuleUnitTestCase struct {
+	// Time of the evaluation relative to the start of the test.
+	EvalTime model.Duration `json:"eval_time" yaml:"eval_time"`
+	// UID of the rule to test. Either the UID or the title of the rule must be set.
+	RuleUID string `json:"rule_uid,omitempty" yaml:"rule_uid,omitempty"`
+	// Title of the rule to test.
+	RuleTitle string `json:"rule_title,omitempty" yaml:"rule_title,omitempty"`
+	// Alert instances that are expected to be in a state other than Normal at the evaluation time.
+	ExpectedAlerts []RuleUnitTestExpectedAlert `json:"exp_alerts" yaml:"exp_alerts"`
+}
+
+// swagger:model
+type RuleUnitTestExpectedAlert struct {
+	// Labels of the alert instance, without the labels added by Grafana, such as alertname and grafana_folder.
+	ExpectedLabels map[string]string `json:"exp_labels,omitempty" yaml:"exp_labels,omitempty"`
+	// Annotations of the alert instance after the templates are expanded.
+	ExpectedAnnotations map[string]string `json:"exp_annotations,omitempty" yaml:"exp_annotations,omitempty"`
+	// State of the alert instance. Defaults to Alerting.
+	// enum: Alerting,Pending,NoData,Error
+	ExpectedState string `json:"exp_state,omitempty" yaml:"exp_state,omitempty"`
+}
+
+// swagger:model
+type RuleUnitTestResults struct {
+	Success bool                 `json:"success"`
+	Tests   []RuleUnitTestResult `json:"tests"`
+}
+
+// swagger:model
+type RuleUnitTestResult struct {
+	Name    string   `json:"name"`
+	Success bool     `json:"success"`
+	Errors  []string `json:"errors,omitempty"`
+}
@@ -48,21 +48,26 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 	return &Engine{
 		evalFactory: evalFactory,
 		createStateManager: func() stateManager {
-			cfg := state.ManagerCfg{
-				Metrics:       nil,
-				ExternalURL:   appUrl,
-				InstanceStore: nil,
-				Images:        &NoopImageService{},
-				Clock:         clock.New(),
-				Historian:     nil,
-				Tracer:        tracer,
-				Log:           log.New("ngalert.state.manager"),
-			}
-			return state.NewManager(cfg, state.NewNoopPersister())
+			return newStateManager(appUrl, tracer)
 		},
 	}
 }
 
+// newStateManager creates a state manager that keeps the state of the alert instances only in memory.
+func newStateManager(appUrl *url.URL, tracer tracing.Tracer) *state.Manager {
+	cfg := state.ManagerCfg{
+		Metrics:       nil,
+		ExternalURL:   appUrl,
+		InstanceStore: nil,
+		Images:        &NoopImageService{},
+		Clock:         clock.New(),
+		Historian:     nil,
+		Tracer:        tracer,
+		Log:           log.New("ngalert.state.manager"),
+	}
+	return state.NewManager(cfg, state.NewNoopPersister())
+}
+
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
@@ -0,0 +1,189 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type sample struct {
+	offset time.Duration
+	value  float64
+}
+
+// inputSeries is a series returned by the data queries of a rule under test.
+// The time of the samples is relative to the start of the test.
+type inputSeries struct {
+	refID   string
+	name    string
+	labels  data.Labels
+	samples []sample
+}
+
+// parseInputSeries parses series in the Prometheus expanding notation, e.g. "1+1x3 _ 0x2".
+// Omitted and stale samples are skipped.
+func parseInputSeries(series []apimodels.RuleUnitTestSeries, interval time.Duration) ([]inputSeries, error) {
+	result := make([]inputSeries, 0, len(series))
+	for _, s := range series {
+		lbls, values, err := parser.ParseSeriesDesc(s.Series + " " + s.Values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse series %s: %w", s.Series, err)
+		}
+		in := newInputSeries(s.RefID, lbls)
+		for idx, v := range values {
+			if v.Histogram != nil {
+				return nil, fmt.Errorf("failed to parse series %s: histogram samples are not supported", s.Series)
+			}
+			if v.Omitted || value.IsStaleNaN(v.Value) {
+				continue
+			}
+			in.samples = append(in.samples, sample{offset: time.Duration(idx) * interval, value: v.Value})
+		}
+		result = append(result, in)
+	}
+	return result, nil
+}
+
+// parseInputCSV parses series in CSV format. The first column of the header must be "time", and the other
+// columns are series in the Prometheus notation. The first column of the rows is the time of the samples
+// relative to the start of the test. Empty values are skipped.
+func parseInputCSV(input string) ([]inputSeries, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimSpace(input)))
+	r.TrimLeadingSpace = true
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV input: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	if len(header) < 2 || strings.TrimSpace(header[0]) != "time" {
+		return nil, errors.New("the first column of the CSV input must be 'time' followed by at least one series")
+	}
+	result := make([]inputSeries, 0, len(header)-1)
+	for _, column := range header[1:] {
+		lbls, err := parser.ParseMetric(strings.TrimSpace(column))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse series %s: %w", column, err)
+		}
+		result = append(result, newInputSeries("", lbls))
+	}
+
+	for idx, row := range records[1:] {
+		offset, err := model.ParseDuration(strings.TrimSpace(row[0]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time of row %d: %w", idx+1, err)
+		}
+		for col, raw := range row[1:] {
+			raw = strings.TrimSpace(raw)
+			if raw == "" || raw == "_" {
+				continue
+			}
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse value of series %s in row %d: %w", header[col+1], idx+1, err)
+			}
+			result[col].samples = append(result[col].samples, sample{offset: time.Duration(offset), value: v})
+		}
+	}
+	return result, nil
+}
+
+func newInputSeries(refID string, lbls labels.Labels) inputSeries {
+	s := inputSeries{refID: refID, labels: data.Labels{}}
+	lbls.Range(func(l labels.Label) {
+		if l.Name == labels.MetricName {
+			s.name = l.Value
+			return
+		}
+		s.labels[l.Name] = l.Value
+	})
+	return s
+}
+
+// frame returns the samples of the series within the time range. If instant is true, the frame contains
+// only the latest sample. Returns nil if there are no samples within the time range.
+func (s inputSeries) frame(start time.Time, tr backend.TimeRange, instant bool) *data.Frame {
+	times := make([]time.Time, 0)
+	values := make([]float64, 0)
+	for _, smpl := range s.samples {
+		t := start.Add(smpl.offset)
+		if t.Before(tr.From) || t.After(tr.To) {
+			continue
+		}
+		times = append(times, t)
+		values = append(values, smpl.value)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	if instant {
+		frame := data.NewFrame(s.name, data.NewField(data.TimeSeriesValueFieldName, s.labels, []float64{values[len(values)-1]}))
+		frame.Meta = &data.FrameMeta{Type: data.FrameTypeNumericMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+		return frame
+	}
+	frame := data.NewFrame(s.name,
+		data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+		data.NewField(data.TimeSeriesValueFieldName, s.labels, values),
+	)
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+	return frame
+}
+
+// inputSeriesDataHandler answers the queries to data sources with the input series of a test.
+type inputSeriesDataHandler struct {
+	start  time.Time
+	series []inputSeries
+}
+
+func (h *inputSeriesDataHandler) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		instant := isInstantQuery(q)
+		frames := make(data.Frames, 0, len(h.series))
+		for _, s := range h.series {
+			if s.refID != "" && s.refID != q.RefID {
+				continue
+			}
+			if f := s.frame(h.start, q.TimeRange, instant); f != nil {
+				f.RefID = q.RefID
+				frames = append(frames, f)
+			}
+		}
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: frames}
+	}
+	return resp, nil
+}
+
+// isInstantQuery returns true if the query asks for the latest value of the series, like instant queries of Prometheus and Loki.
+func isInstantQuery(q backend.DataQuery) bool {
+	if q.QueryType == "instant" {
+		return true
+	}
+	query := struct {
+		Instant bool `json:"instant"`
+		Range   bool `json:"range"`
+	}{}
+	if err := json.Unmarshal(q.JSON, &query); err != nil {
+		return false
+	}
+	return query.Instant && !query.Range
+}
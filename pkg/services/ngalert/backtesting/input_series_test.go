@@ -0,0 +1,100 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestParseInputSeries(t *testing.T) {
+	t.Run("should expand values", func(t *testing.T) {
+		series, err := parseInputSeries([]apimodels.RuleUnitTestSeries{
+			{Series: `up{job="api"}`, Values: "1+1x2 _ 0 stale 5", RefID: "A"},
+		}, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, series, 1)
+		require.Equal(t, "A", series[0].refID)
+		require.Equal(t, "up", series[0].name)
+		require.Equal(t, data.Labels{"job": "api"}, series[0].labels)
+		require.Equal(t, []sample{
+			{offset: 0, value: 1},
+			{offset: time.Minute, value: 2},
+			{offset: 2 * time.Minute, value: 3},
+			{offset: 4 * time.Minute, value: 0},
+			{offset: 6 * time.Minute, value: 5},
+		}, series[0].samples)
+	})
+
+	t.Run("should fail if series is invalid", func(t *testing.T) {
+		_, err := parseInputSeries([]apimodels.RuleUnitTestSeries{{Series: `up{job=}`, Values: "1"}}, time.Minute)
+		require.Error(t, err)
+	})
+}
+
+func TestParseInputCSV(t *testing.T) {
+	t.Run("should parse columns as series", func(t *testing.T) {
+		series, err := parseInputCSV(`
+time, up{job="api"}, {job="db"}
+0m, 1, 0
+90s, , 1
+`)
+		require.NoError(t, err)
+		require.Len(t, series, 2)
+		require.Equal(t, "up", series[0].name)
+		require.Equal(t, data.Labels{"job": "api"}, series[0].labels)
+		require.Equal(t, []sample{{offset: 0, value: 1}}, series[0].samples)
+		require.Empty(t, series[1].name)
+		require.Equal(t, []sample{{offset: 0, value: 0}, {offset: 90 * time.Second, value: 1}}, series[1].samples)
+	})
+
+	t.Run("should fail if the first column is not time", func(t *testing.T) {
+		_, err := parseInputCSV("up,down\n1,2")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail if value is not a number", func(t *testing.T) {
+		_, err := parseInputCSV("time,up\n0m,abc")
+		require.Error(t, err)
+	})
+}
+
+func TestInputSeriesDataHandler(t *testing.T) {
+	start := time.Unix(0, 0).UTC()
+	series, err := parseInputSeries([]apimodels.RuleUnitTestSeries{
+		{Series: `up{job="api"}`, Values: "0 1 2 3 4"},
+		{Series: `up{job="db"}`, Values: "5 6", RefID: "B"},
+	}, time.Minute)
+	require.NoError(t, err)
+	h := &inputSeriesDataHandler{start: start, series: series}
+
+	tr := backend.TimeRange{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}
+	resp, err := h.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{RefID: "A", TimeRange: tr, JSON: []byte(`{}`)},
+			{RefID: "C", TimeRange: tr, JSON: []byte(`{"instant": true}`)},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("should return samples within the time range", func(t *testing.T) {
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, data.FrameTypeTimeSeriesMulti, frames[0].Meta.Type)
+		require.Equal(t, 3, frames[0].Rows())
+		require.Equal(t, 1.0, frames[0].Fields[1].At(0))
+		require.Equal(t, 3.0, frames[0].Fields[1].At(2))
+	})
+
+	t.Run("should return the latest sample for instant queries", func(t *testing.T) {
+		frames := resp.Responses["C"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, data.FrameTypeNumericMulti, frames[0].Meta.Type)
+		require.Equal(t, 3.0, frames[0].Fields[0].At(0))
+	})
+}
@@ -0,0 +1,294 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	defaultInputSeriesInterval = time.Minute
+	ruleTestEvaluationTimeout  = 30 * time.Second
+
+	// inputSeriesDatasourceType is the type of the data sources of the rules under test,
+	// all of which return the input series of the test.
+	inputSeriesDatasourceType = "input-series"
+)
+
+// RuleTester runs unit tests of alert rules. The rules are evaluated by the same expression pipeline
+// and state manager as the scheduled rules, but the data queries return the input series of the test.
+type RuleTester struct {
+	appUrl   *url.URL
+	features featuremgmt.FeatureToggles
+	tracer   tracing.Tracer
+}
+
+func NewRuleTester(appUrl *url.URL, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *RuleTester {
+	return &RuleTester{
+		appUrl:   appUrl,
+		features: features,
+		tracer:   tracer,
+	}
+}
+
+// Run runs the tests against the given rules. Failed expectations are reported in the results.
+// Returns ErrInvalidInputData if the tests cannot be run.
+func (t *RuleTester) Run(ctx context.Context, user identity.Requester, rules []*models.AlertRule, tests apimodels.RuleUnitTests) (apimodels.RuleUnitTestResults, error) {
+	result := apimodels.RuleUnitTestResults{
+		Success: true,
+		Tests:   make([]apimodels.RuleUnitTestResult, 0, len(tests.Tests)),
+	}
+	for idx, test := range tests.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", idx+1)
+		}
+		failures, err := t.runTest(ctx, user, rules, time.Duration(tests.EvaluationInterval), test)
+		if err != nil {
+			return apimodels.RuleUnitTestResults{}, fmt.Errorf("%w: %s: %s", ErrInvalidInputData, name, err)
+		}
+		result.Tests = append(result.Tests, apimodels.RuleUnitTestResult{
+			Name:    name,
+			Success: len(failures) == 0,
+			Errors:  failures,
+		})
+		result.Success = result.Success && len(failures) == 0
+	}
+	return result, nil
+}
+
+func (t *RuleTester) runTest(ctx context.Context, user identity.Requester, rules []*models.AlertRule, evaluationInterval time.Duration, test apimodels.RuleUnitTest) ([]string, error) {
+	interval := time.Duration(test.Interval)
+	if interval <= 0 {
+		interval = defaultInputSeriesInterval
+	}
+	series, err := parseInputSeries(test.InputSeries, interval)
+	if err != nil {
+		return nil, err
+	}
+	csvSeries, err := parseInputCSV(test.InputCSV)
+	if err != nil {
+		return nil, err
+	}
+	series = append(series, csvSeries...)
+
+	cases, order, err := groupRuleTestCases(rules, test.AlertRuleTests)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Unix(0, 0).UTC()
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	evalFactory := eval.NewEvaluatorFactory(
+		setting.UnifiedAlertingSettings{EvaluationTimeout: ruleTestEvaluationTimeout},
+		inputSeriesDatasourceCache{},
+		expr.NewServiceWithDataHandler(cfg, &inputSeriesDataHandler{start: start, series: series}, t.features, t.tracer),
+	)
+
+	var failures []string
+	for _, rule := range order {
+		f, err := t.testRule(ctx, user, evalFactory, rule, start, evaluationInterval, cases[rule.UID])
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Title, err)
+		}
+		failures = append(failures, f...)
+	}
+	return failures, nil
+}
+
+// testRule evaluates the rule from the start of the test until the latest evaluation time of the test cases,
+// and compares the alert instances with the expected ones at every evaluation time of the test cases.
+func (t *RuleTester) testRule(ctx context.Context, user identity.Requester, evalFactory eval.EvaluatorFactory, rule *models.AlertRule, start time.Time, evaluationInterval time.Duration, cases []apimodels.AlertRuleUnitTestCase) ([]string, error) {
+	if rule.Type() == models.RuleTypeRecording {
+		return nil, errors.New("recording rules are not supported")
+	}
+	if evaluationInterval <= 0 {
+		evaluationInterval = time.Duration(rule.IntervalSeconds) * time.Second
+	}
+	if evaluationInterval <= 0 {
+		return nil, errors.New("evaluation interval must be greater than zero")
+	}
+
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	stateManager := newStateManager(t.appUrl, t.tracer)
+	evaluator, err := evalFactory.Create(eval.NewContextWithPreviousResults(ruleCtx, user, &schedule.AlertingResultsFromRuleState{
+		Manager: stateManager,
+		Rule:    rule,
+	}), rule.GetEvalCondition().WithSource("unit-test"))
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	last := time.Duration(cases[len(cases)-1].EvalTime)
+	next := 0
+	for offset := time.Duration(0); offset <= last; offset += evaluationInterval {
+		now := start.Add(offset)
+		results, err := evaluator.Evaluate(ruleCtx, now)
+		if err != nil {
+			results = eval.Results{eval.NewResultFromError(err, now, 0)}
+		}
+		stateManager.ProcessEvalResults(ruleCtx, now, rule, results, nil, nil)
+
+		// check the test cases whose evaluation time is before the next evaluation
+		for ; next < len(cases) && time.Duration(cases[next].EvalTime) < offset+evaluationInterval; next++ {
+			if failure := compareAlerts(rule, cases[next], stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)); failure != "" {
+				failures = append(failures, failure)
+			}
+		}
+	}
+	return failures, nil
+}
+
+// groupRuleTestCases resolves the rules of the test cases and groups the test cases by rule UID, sorted by evaluation time.
+// The returned rules are in the order they first appear in the test cases.
+func groupRuleTestCases(rules []*models.AlertRule, cases []apimodels.AlertRuleUnitTestCase) (map[string][]apimodels.AlertRuleUnitTestCase, []*models.AlertRule, error) {
+	if len(cases) == 0 {
+		return nil, nil, errors.New("at least one alert rule test is required")
+	}
+	byRule := make(map[string][]apimodels.AlertRuleUnitTestCase)
+	order := make([]*models.AlertRule, 0)
+	for _, c := range cases {
+		if c.EvalTime < 0 {
+			return nil, nil, fmt.Errorf("evaluation time %s must not be negative", c.EvalTime)
+		}
+		for _, exp := range c.ExpectedAlerts {
+			if exp.ExpectedState == "" {
+				continue
+			}
+			if s, err := eval.ParseStateString(exp.ExpectedState); err != nil || s == eval.Normal {
+				return nil, nil, fmt.Errorf("invalid expected state %q", exp.ExpectedState)
+			}
+		}
+		rule, err := findRule(rules, c.RuleUID, c.RuleTitle)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := byRule[rule.UID]; !ok {
+			order = append(order, rule)
+		}
+		byRule[rule.UID] = append(byRule[rule.UID], c)
+	}
+	for _, c := range byRule {
+		sort.SliceStable(c, func(i, j int) bool {
+			return c[i].EvalTime < c[j].EvalTime
+		})
+	}
+	return byRule, order, nil
+}
+
+func findRule(rules []*models.AlertRule, uid, title string) (*models.AlertRule, error) {
+	if uid == "" && title == "" {
+		return nil, errors.New("either the UID or the title of the rule must be set")
+	}
+	var found *models.AlertRule
+	for _, rule := range rules {
+		if uid != "" && rule.UID != uid || title != "" && rule.Title != title {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("more than one rule has the title %q, use the UID of the rule instead", title)
+		}
+		found = rule
+	}
+	if found == nil {
+		if uid != "" {
+			return nil, fmt.Errorf("rule with UID %q is not found", uid)
+		}
+		return nil, fmt.Errorf("rule with title %q is not found", title)
+	}
+	return found, nil
+}
+
+// compareAlerts compares the alert instances that are not Normal with the expected ones.
+// Returns a description of the difference, or an empty string if they match.
+func compareAlerts(rule *models.AlertRule, c apimodels.AlertRuleUnitTestCase, states []*state.State) string {
+	expected := make([]string, 0, len(c.ExpectedAlerts))
+	for _, exp := range c.ExpectedAlerts {
+		s := eval.Alerting
+		if exp.ExpectedState != "" {
+			s, _ = eval.ParseStateString(exp.ExpectedState)
+		}
+		expected = append(expected, formatAlert(s, exp.ExpectedLabels, exp.ExpectedAnnotations))
+	}
+
+	actual := make([]string, 0, len(states))
+	for _, st := range states {
+		if st.State == eval.Normal {
+			continue
+		}
+		actual = append(actual, formatAlert(st.State, userLabels(st.Labels), userLabels(st.Annotations)))
+	}
+
+	sort.Strings(expected)
+	sort.Strings(actual)
+	if strings.Join(expected, "\n") == strings.Join(actual, "\n") {
+		return ""
+	}
+	return fmt.Sprintf("rule %q, eval_time %s:\n    exp: [%s]\n    got: [%s]",
+		rule.Title, model.Duration(c.EvalTime), strings.Join(expected, ", "), strings.Join(actual, ", "))
+}
+
+func formatAlert(s eval.State, labels, annotations map[string]string) string {
+	return fmt.Sprintf("{state=%s, labels=%s, annotations=%s}", s, formatMap(labels), formatMap(annotations))
+}
+
+func formatMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, m[k]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// userLabels removes the labels and annotations that are added by Grafana and cannot be set by the user.
+func userLabels(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		if k == model.AlertNameLabel || k == models.FolderTitleLabel || strings.HasPrefix(k, "__") && strings.HasSuffix(k, "__") {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// inputSeriesDatasourceCache resolves every data source to one that returns the input series of the test.
+type inputSeriesDatasourceCache struct{}
+
+func (inputSeriesDatasourceCache) GetDatasource(_ context.Context, _ int64, _ identity.Requester, _ bool) (*datasources.DataSource, error) {
+	return nil, datasources.ErrDataSourceNotFound
+}
+
+func (inputSeriesDatasourceCache) GetDatasourceByUID(_ context.Context, uid string, user identity.Requester, _ bool) (*datasources.DataSource, error) {
+	return &datasources.DataSource{
+		OrgID: user.GetOrgID(),
+		UID:   uid,
+		Name:  uid,
+		Type:  inputSeriesDatasourceType,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package backtesting
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRuleTester(t *testing.T) {
+	rule := models.RuleGen.With(
+		models.RuleGen.WithOrgID(1),
+		models.RuleGen.WithTitle("Instance down"),
+		models.RuleGen.WithIntervalSeconds(60),
+		models.RuleGen.WithFor(2*time.Minute),
+		models.RuleGen.WithLabels(map[string]string{"severity": "critical"}),
+		models.RuleGen.WithAnnotations(map[string]string{"summary": "{{ $labels.job }} is down"}),
+		models.RuleGen.WithNoDataExecAs(models.NoData),
+		models.RuleGen.WithQuery(
+			models.AlertQuery{
+				RefID:             "A",
+				DatasourceUID:     "prometheus",
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(5 * time.Minute)},
+				Model:             json.RawMessage(`{"expr": "up"}`),
+			},
+			models.CreateReduceExpression("B", "A", "last"),
+			models.AlertQuery{
+				RefID:         "C",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"type": "math", "expression": "$B < 1"}`),
+			},
+		),
+		models.RuleGen.WithCondition("C"),
+	).GenerateRef()
+
+	tester := NewRuleTester(nil, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+	usr := &user.SignedInUser{OrgID: 1}
+
+	run := func(t *testing.T, cases ...apimodels.AlertRuleUnitTestCase) apimodels.RuleUnitTestResults {
+		t.Helper()
+		result, err := tester.Run(context.Background(), usr, []*models.AlertRule{rule}, apimodels.RuleUnitTests{
+			Tests: []apimodels.RuleUnitTest{
+				{
+					Interval: model.Duration(time.Minute),
+					InputSeries: []apimodels.RuleUnitTestSeries{
+						{Series: `up{job="api"}`, Values: "1 1 0 0 0 0"},
+						{Series: `up{job="db"}`, Values: "1x5"},
+					},
+					AlertRuleTests: cases,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Tests, 1)
+		return result
+	}
+
+	firing := apimodels.RuleUnitTestExpectedAlert{
+		ExpectedLabels:      map[string]string{"job": "api", "severity": "critical"},
+		ExpectedAnnotations: map[string]string{"summary": "api is down"},
+	}
+
+	t.Run("should pass if alerts match", func(t *testing.T) {
+		pending := firing
+		pending.ExpectedState = "Pending"
+		result := run(t,
+			apimodels.AlertRuleUnitTestCase{EvalTime: model.Duration(time.Minute), RuleTitle: rule.Title},
+			apimodels.AlertRuleUnitTestCase{EvalTime: model.Duration(3 * time.Minute), RuleUID: rule.UID, ExpectedAlerts: []apimodels.RuleUnitTestExpectedAlert{pending}},
+			apimodels.AlertRuleUnitTestCase{EvalTime: model.Duration(4 * time.Minute), RuleUID: rule.UID, ExpectedAlerts: []apimodels.RuleUnitTestExpectedAlert{firing}},
+		)
+		require.Truef(t, result.Success, "%v", result.Tests[0].Errors)
+		require.Equal(t, "test 1", result.Tests[0].Name)
+	})
+
+	t.Run("should report mismatched alerts", func(t *testing.T) {
+		result := run(t,
+			apimodels.AlertRuleUnitTestCase{EvalTime: model.Duration(2 * time.Minute), RuleUID: rule.UID, ExpectedAlerts: []apimodels.RuleUnitTestExpectedAlert{firing}},
+		)
+		require.False(t, result.Success)
+		require.Len(t, result.Tests[0].Errors, 1)
+		require.Contains(t, result.Tests[0].Errors[0], "state=Pending")
+	})
+
+	t.Run("should fail if rule is unknown", func(t *testing.T) {
+		_, err := tester.Run(context.Background(), usr, []*models.AlertRule{rule}, apimodels.RuleUnitTests{
+			Tests: []apimodels.RuleUnitTest{{AlertRuleTests: []apimodels.AlertRuleUnitTestCase{{RuleUID: "unknown"}}}},
+		})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail if expected state is invalid", func(t *testing.T) {
+		_, err := tester.Run(context.Background(), usr, []*models.AlertRule{rule}, apimodels.RuleUnitTests{
+			Tests: []apimodels.RuleUnitTest{{AlertRuleTests: []apimodels.AlertRuleUnitTestCase{{
+				RuleUID:        rule.UID,
+				ExpectedAlerts: []apimodels.RuleUnitTestExpectedAlert{{ExpectedState: "Normal"}},
+			}}}},
+		})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
@@ -86,7 +86,7 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		}
 		if conditionResultLength > r.evalResultLimit {
 			logger.FromContext(ctx).Error("Query evaluation returned too many results", "limit", r.evalResultLimit, "actual", conditionResultLength)
-			return nil, fmt.Errorf("query evaluation returned too many results: %d (limit: %d)", conditionResultLength, r.evalResultLimit)
+			return nil, fmt.Errorf("%w: %d (limit: %d)", models.ErrInstanceLimitExceeded, conditionResultLength, r.evalResultLimit)
 		}
 	}
 
@@ -295,14 +295,19 @@ const (
 	// Error is the eval state for an alert rule condition
 	// that evaluated to Error.
 	Error
+
+	// Suppressed is the state of an alert instance that would be
+	// Alerting or Pending but one of the rules it depends on is firing.
+	// Evaluation results never have this state.
+	Suppressed
 )
 
 func (s State) IsValid() bool {
-	return s <= Error
+	return s <= Suppressed
 }
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Suppressed"}[s]
 }
 
 func ParseStateString(repr string) (State, error) {
@@ -317,6 +322,8 @@ func ParseStateString(repr string) (State, error) {
 		return NoData, nil
 	case "error":
 		return Error, nil
+	case "suppressed":
+		return Suppressed, nil
 	default:
 		return -1, fmt.Errorf("invalid state: %s", repr)
 	}
@@ -837,6 +844,15 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	return e.create(condition, req)
 }
 
+// resultLimit returns the limit of results of a condition. The limit of the condition can only lower the global limit.
+// Zero or a negative value means unlimited.
+func resultLimit(global int, condition int64) int {
+	if condition > 0 && (global <= 0 || condition < int64(global)) {
+		return int(condition)
+	}
+	return global
+}
+
 func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
 	pipeline, err := e.expressionService.BuildPipeline(req)
 	if err != nil {
@@ -850,7 +866,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				expressionService: e.expressionService,
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
-				evalResultLimit:   e.evaluationResultLimit,
+				evalResultLimit:   resultLimit(e.evaluationResultLimit, condition.ResultLimit),
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
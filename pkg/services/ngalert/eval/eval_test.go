@@ -1121,6 +1121,7 @@ func TestEvaluateRawLimit(t *testing.T) {
 				if tc.error != "" {
 					require.Error(t, err)
 					require.EqualError(t, err, tc.error)
+					require.ErrorIs(t, err, models.ErrInstanceLimitExceeded)
 				} else {
 					require.NoError(t, err)
 					require.NotNil(t, result)
@@ -1171,6 +1172,26 @@ func TestEvaluateRawLimit(t *testing.T) {
 	})
 }
 
+func TestResultLimit(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		global    int
+		condition int64
+		expected  int
+	}{
+		{desc: "global limit applies if the condition has no limit", global: 10, condition: 0, expected: 10},
+		{desc: "limit of the condition lowers the global limit", global: 10, condition: 5, expected: 5},
+		{desc: "limit of the condition cannot raise the global limit", global: 10, condition: 20, expected: 10},
+		{desc: "limit of the condition applies if there is no global limit", global: -1, condition: 5, expected: 5},
+		{desc: "unlimited if neither has a limit", global: -1, condition: 0, expected: -1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, resultLimit(tc.global, tc.condition))
+		})
+	}
+}
+
 func TestResults_HasNonRetryableErrors(t *testing.T) {
 	tc := []struct {
 		name     string
@@ -0,0 +1,221 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/template"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const templateQueryRefID = "A"
+
+var (
+	ErrTemplateQueryEmpty                = errors.New("query must not be empty")
+	ErrTemplateQueryNoDatasource         = errors.New("query has no data source and the rule does not query any data source")
+	ErrTemplateQueryDatasourceNotQueried = errors.New("query data source is not queried by the rule")
+	ErrTemplateQueryTooManyResults       = errors.New("query returned too many results")
+	ErrTemplateQueryTimeBudgetExceeded   = errors.New("queries exceeded the time budget of the evaluation")
+)
+
+// templateQuery is a query of the query function of templates in JSON format. It has the same format as the queries
+// of the graphLink and tableLink functions.
+type templateQuery struct {
+	Datasource string `json:"datasource"`
+	Expr       string `json:"expr"`
+}
+
+// TemplateQuerier runs the queries of the query function of the annotation and label templates of alert rules.
+//
+// A query is either an expression, such as a PromQL query, that is run against the first data source queried by the
+// rule, or a JSON object with the UID of the data source and the expression, for example
+// {"datasource": "prometheus-uid", "expr": "up"}. Only data sources that the rule queries can be queried, so that the
+// query function does not give access to data sources that the author of the rule could not query. The query is an
+// instant query that inherits the settings of the query of the rule to the same data source.
+type TemplateQuerier struct {
+	evaluator  EvaluatorFactory
+	userFor    func(rule *models.AlertRule) identity.Requester
+	timeout    time.Duration
+	maxResults int
+}
+
+func NewTemplateQuerier(cfg setting.UnifiedAlertingSettings, evaluator EvaluatorFactory, userFor func(rule *models.AlertRule) identity.Requester) *TemplateQuerier {
+	return &TemplateQuerier{
+		evaluator:  evaluator,
+		userFor:    userFor,
+		timeout:    cfg.TemplateQueryTimeout,
+		maxResults: cfg.TemplateQueryMaxResults,
+	}
+}
+
+// QueryFunc returns the query function of the templates of the rule for a single evaluation. Queries run with the
+// identity of the rule. Each query runs once per evaluation and its result is shared by the templates of all alert
+// instances. All queries of the evaluation share the timeout as a total time budget, that starts with the first query.
+func (q *TemplateQuerier) QueryFunc(rule *models.AlertRule) template.QueryFunc {
+	e := &templateQueryEvaluation{
+		querier:   q,
+		rule:      rule,
+		responses: make(map[templateQueryKey]templateQueryResponse),
+	}
+	return e.query
+}
+
+type templateQueryKey struct {
+	query string
+	ts    int64
+}
+
+type templateQueryResponse struct {
+	vector promql.Vector
+	err    error
+}
+
+// templateQueryEvaluation runs the queries of the query function of templates for a single evaluation of a rule.
+type templateQueryEvaluation struct {
+	querier   *TemplateQuerier
+	rule      *models.AlertRule
+	mtx       sync.Mutex
+	deadline  time.Time
+	responses map[templateQueryKey]templateQueryResponse
+}
+
+func (e *templateQueryEvaluation) query(ctx context.Context, query string, ts time.Time) (promql.Vector, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	key := templateQueryKey{query: query, ts: ts.UnixNano()}
+	if resp, ok := e.responses[key]; ok {
+		return resp.vector, resp.err
+	}
+	if e.deadline.IsZero() {
+		e.deadline = time.Now().Add(e.querier.timeout)
+	}
+	if !time.Now().Before(e.deadline) {
+		return nil, fmt.Errorf("%w: the budget is %s", ErrTemplateQueryTimeBudgetExceeded, e.querier.timeout)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, e.deadline)
+	defer cancel()
+	vector, err := e.querier.query(ctx, e.rule, query, ts)
+	e.responses[key] = templateQueryResponse{vector: vector, err: err}
+	return vector, err
+}
+
+func (q *TemplateQuerier) query(ctx context.Context, rule *models.AlertRule, query string, ts time.Time) (promql.Vector, error) {
+	alertQuery, err := templateAlertQuery(rule, query)
+	if err != nil {
+		return nil, err
+	}
+
+	condition := models.Condition{Condition: templateQueryRefID, Data: []models.AlertQuery{alertQuery}}
+	evaluator, err := q.evaluator.Create(NewContext(ctx, q.userFor(rule)), condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	resp, err := evaluator.EvaluateRaw(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	res, ok := resp.Responses[templateQueryRefID]
+	if !ok {
+		return promql.Vector{}, nil
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to run query: %w", res.Error)
+	}
+	return templateQueryResult(res.Frames, ts, q.maxResults)
+}
+
+// templateAlertQuery returns the instant query of the rule for the query of the query function of templates.
+func templateAlertQuery(rule *models.AlertRule, query string) (models.AlertQuery, error) {
+	tq := templateQuery{Expr: query}
+	if strings.HasPrefix(strings.TrimSpace(query), "{") {
+		if err := json.Unmarshal([]byte(query), &tq); err != nil {
+			return models.AlertQuery{}, fmt.Errorf("failed to parse query: %w", err)
+		}
+	}
+	if strings.TrimSpace(tq.Expr) == "" {
+		return models.AlertQuery{}, ErrTemplateQueryEmpty
+	}
+
+	// Use the query of the rule to the same data source as a base so that the query has the same settings.
+	// Other data sources cannot be queried.
+	var base *models.AlertQuery
+	for i := range rule.Data {
+		d := &rule.Data[i]
+		if expr.NodeTypeFromDatasourceUID(d.DatasourceUID) != expr.TypeDatasourceNode {
+			continue
+		}
+		if tq.Datasource == "" || tq.Datasource == d.DatasourceUID {
+			base = d
+			break
+		}
+	}
+	if base == nil && tq.Datasource == "" {
+		return models.AlertQuery{}, ErrTemplateQueryNoDatasource
+	}
+	if base == nil {
+		return models.AlertQuery{}, fmt.Errorf("%w: %s", ErrTemplateQueryDatasourceNotQueried, tq.Datasource)
+	}
+
+	model := make(map[string]any)
+	if err := json.Unmarshal(base.Model, &model); err != nil {
+		return models.AlertQuery{}, fmt.Errorf("failed to unmarshal query model of '%s': %w", base.RefID, err)
+	}
+	result := models.AlertQuery{
+		RefID:             templateQueryRefID,
+		DatasourceUID:     base.DatasourceUID,
+		QueryType:         base.QueryType,
+		RelativeTimeRange: base.RelativeTimeRange,
+	}
+	model["refId"] = templateQueryRefID
+	model["expr"] = tq.Expr
+	model["instant"] = true
+	model["range"] = false
+
+	b, err := json.Marshal(model)
+	if err != nil {
+		return models.AlertQuery{}, err
+	}
+	result.Model = b
+	return result, nil
+}
+
+// templateQueryResult converts the frames of a query to a vector with a sample per numeric field. Fields of time
+// series have the value of the last point in time.
+func templateQueryResult(frames data.Frames, ts time.Time, maxResults int) (promql.Vector, error) {
+	result := make(promql.Vector, 0)
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() || field.Len() == 0 {
+				continue
+			}
+			v, err := field.NullableFloatAt(field.Len() - 1)
+			if err != nil || v == nil {
+				continue
+			}
+			if maxResults > 0 && len(result) >= maxResults {
+				return nil, fmt.Errorf("%w: the limit is %d", ErrTemplateQueryTooManyResults, maxResults)
+			}
+			result = append(result, promql.Sample{
+				Metric: labels.FromMap(field.Labels),
+				T:      timestamp.FromTime(ts),
+				F:      *v,
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type recordingEvaluatorFactory struct {
+	ctx       EvaluationContext
+	condition models.Condition
+	resp      *backend.QueryDataResponse
+	err       error
+	delay     time.Duration
+	calls     int
+}
+
+func (f *recordingEvaluatorFactory) Validate(_ EvaluationContext, _ models.Condition) error {
+	return nil
+}
+
+func (f *recordingEvaluatorFactory) Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error) {
+	f.ctx = ctx
+	f.condition = condition
+	return f, nil
+}
+
+func (f *recordingEvaluatorFactory) EvaluateRaw(ctx context.Context, _ time.Time) (*backend.QueryDataResponse, error) {
+	f.calls++
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return f.resp, f.err
+}
+
+func (f *recordingEvaluatorFactory) Evaluate(_ context.Context, _ time.Time) (Results, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestTemplateQuerier(t *testing.T) {
+	rule := &models.AlertRule{
+		OrgID: 1,
+		Data: []models.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     "prometheus",
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(time.Hour)},
+				Model:             json.RawMessage(`{"refId":"A","expr":"rate(errors[5m])","intervalMs":1000}`),
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"refId":"B","type":"threshold","expression":"A"}`),
+			},
+		},
+	}
+	cfg := setting.UnifiedAlertingSettings{TemplateQueryTimeout: time.Second, TemplateQueryMaxResults: 2}
+	userFor := func(rule *models.AlertRule) identity.Requester {
+		return &user.SignedInUser{OrgID: rule.OrgID, Login: "grafana_scheduler"}
+	}
+	response := func(frames ...*data.Frame) *backend.QueryDataResponse {
+		return &backend.QueryDataResponse{Responses: backend.Responses{templateQueryRefID: backend.DataResponse{Frames: frames}}}
+	}
+	now := time.Now()
+
+	t.Run("should run the query against the data source of the rule", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response(
+			data.NewFrame("", data.NewField("", data.Labels{"pod": "a"}, []float64{3})),
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{now.Add(-time.Minute), now}),
+				data.NewField("", data.Labels{"pod": "b"}, []*float64{nil, util.Pointer(5.0)}),
+			),
+		)}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		vector, err := queryFunc(context.Background(), "topk(5, memory)", now)
+		require.NoError(t, err)
+		require.Len(t, vector, 2)
+		require.Equal(t, "a", vector[0].Metric.Get("pod"))
+		require.Equal(t, 3.0, vector[0].F)
+		require.Equal(t, "b", vector[1].Metric.Get("pod"))
+		require.Equal(t, 5.0, vector[1].F)
+
+		require.Equal(t, "grafana_scheduler", factory.ctx.User.GetLogin())
+		require.Len(t, factory.condition.Data, 1)
+		q := factory.condition.Data[0]
+		require.Equal(t, "prometheus", q.DatasourceUID)
+		require.Equal(t, models.Duration(time.Hour), q.RelativeTimeRange.From)
+		require.JSONEq(t, `{"refId":"A","expr":"topk(5, memory)","intervalMs":1000,"instant":true,"range":false}`, string(q.Model))
+	})
+
+	t.Run("should run JSON queries against their data source", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response()}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		vector, err := queryFunc(context.Background(), `{"datasource":"prometheus","expr":"sum(up)"}`, now)
+		require.NoError(t, err)
+		require.Empty(t, vector)
+		q := factory.condition.Data[0]
+		require.Equal(t, "prometheus", q.DatasourceUID)
+		require.JSONEq(t, `{"refId":"A","expr":"sum(up)","intervalMs":1000,"instant":true,"range":false}`, string(q.Model))
+	})
+
+	t.Run("should fail if the data source is not queried by the rule", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response()}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		_, err := queryFunc(context.Background(), `{"datasource":"loki","expr":"count_over_time({job=\"app\"}[5m])"}`, now)
+		require.ErrorIs(t, err, ErrTemplateQueryDatasourceNotQueried)
+		require.Zero(t, factory.calls)
+	})
+
+	t.Run("should run each query once per evaluation", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response(
+			data.NewFrame("", data.NewField("", data.Labels{"pod": "a"}, []float64{3})),
+		)}
+		querier := NewTemplateQuerier(cfg, factory, userFor)
+		queryFunc := querier.QueryFunc(rule)
+
+		for i := 0; i < 3; i++ {
+			vector, err := queryFunc(context.Background(), "memory", now)
+			require.NoError(t, err)
+			require.Len(t, vector, 1)
+		}
+		require.Equal(t, 1, factory.calls)
+
+		_, err := queryFunc(context.Background(), "cpu", now)
+		require.NoError(t, err)
+		require.Equal(t, 2, factory.calls)
+
+		// The next evaluation runs the query again.
+		_, err = querier.QueryFunc(rule)(context.Background(), "memory", now)
+		require.NoError(t, err)
+		require.Equal(t, 3, factory.calls)
+	})
+
+	t.Run("should fail if the queries of the evaluation exceed the time budget", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response(), delay: 600 * time.Millisecond}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		_, err := queryFunc(context.Background(), "memory", now)
+		require.NoError(t, err)
+		_, err = queryFunc(context.Background(), "cpu", now)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		_, err = queryFunc(context.Background(), "disk", now)
+		require.ErrorIs(t, err, ErrTemplateQueryTimeBudgetExceeded)
+		require.Equal(t, 2, factory.calls)
+	})
+
+	t.Run("should fail if the query returns too many results", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: response(
+			data.NewFrame("", data.NewField("", data.Labels{"pod": "a"}, []float64{1})),
+			data.NewFrame("", data.NewField("", data.Labels{"pod": "b"}, []float64{2})),
+			data.NewFrame("", data.NewField("", data.Labels{"pod": "c"}, []float64{3})),
+		)}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		_, err := queryFunc(context.Background(), "memory", now)
+		require.ErrorIs(t, err, ErrTemplateQueryTooManyResults)
+	})
+
+	t.Run("should fail if the query fails", func(t *testing.T) {
+		factory := &recordingEvaluatorFactory{resp: &backend.QueryDataResponse{Responses: backend.Responses{
+			templateQueryRefID: backend.DataResponse{Error: errors.New("bad query")},
+		}}}
+		queryFunc := NewTemplateQuerier(cfg, factory, userFor).QueryFunc(rule)
+
+		_, err := queryFunc(context.Background(), "memory", now)
+		require.ErrorContains(t, err, "bad query")
+	})
+
+	t.Run("should fail if the query is invalid", func(t *testing.T) {
+		queryFunc := NewTemplateQuerier(cfg, &recordingEvaluatorFactory{}, userFor).QueryFunc(rule)
+
+		_, err := queryFunc(context.Background(), " ", now)
+		require.ErrorIs(t, err, ErrTemplateQueryEmpty)
+		_, err = queryFunc(context.Background(), `{"datasource":`, now)
+		require.Error(t, err)
+
+		noDatasource := NewTemplateQuerier(cfg, &recordingEvaluatorFactory{}, userFor).QueryFunc(&models.AlertRule{OrgID: 1})
+		_, err = noDatasource(context.Background(), "memory", now)
+		require.ErrorIs(t, err, ErrTemplateQueryNoDatasource)
+	})
+}
@@ -8,6 +8,7 @@ import (
 type State struct {
 	StateUpdateDuration   prometheus.Histogram
 	StateFullSyncDuration prometheus.Histogram
+	InstanceLimitExceeded *prometheus.CounterVec
 	r                     prometheus.Registerer
 }
 
@@ -37,5 +38,14 @@ func NewStateMetrics(r prometheus.Registerer) *State {
 				Buckets:   []float64{0.01, 0.1, 1, 2, 5, 10, 60},
 			},
 		),
+		InstanceLimitExceeded: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_instance_limit_exceeded_total",
+				Help:      "The total number of rule evaluations that failed because they produced more alert instances than allowed.",
+			},
+			[]string{"org"},
+		),
 	}
 }
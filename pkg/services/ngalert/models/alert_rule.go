@@ -30,6 +30,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -38,6 +40,9 @@ var (
 	ErrAlertRuleFailedValidation          = errors.New("invalid alert rule")
 	ErrAlertRuleUniqueConstraintViolation = errors.New("rule title under the same organisation and folder should be unique")
 	ErrQuotaReached                       = errors.New("quota has been exceeded")
+	// ErrInstanceLimitExceeded is returned when the condition of an alert rule produces more results, and therefore
+	// alert instances, than allowed.
+	ErrInstanceLimitExceeded = errors.New("query evaluation returned too many results")
 	// ErrNoDashboard is returned when the alert rule does not have a Dashboard UID
 	// in its annotations or the dashboard does not exist.
 	ErrNoDashboard = errors.New("no dashboard")
@@ -164,6 +169,11 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	// StateReasonDependencyFiring is the reason of Suppressed states. It is followed by the UID of the firing rule.
+	StateReasonDependencyFiring = "DependencyFiring"
+	// StateReasonInstanceLimitExceeded is added to the reason of states when the evaluation of the rule produced
+	// more alert instances than allowed.
+	StateReasonInstanceLimitExceeded = "InstanceLimitExceeded"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -270,6 +280,13 @@ type AlertRule struct {
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
 	Metadata             AlertRuleMetadata
+	// Dependencies are the rules this rule depends on. While a dependency is firing, the alert instances of
+	// this rule are suppressed.
+	Dependencies []RuleDependency
+	// InstanceLimit is the maximum number of results the condition of the rule can produce in a single evaluation,
+	// that is, the number of alert instances. It overrides the alerting_rule_evaluation_results quota if it is lower.
+	// Zero means that only the quota applies.
+	InstanceLimit int64
 }
 
 type AlertRuleMetadata struct {
@@ -280,6 +297,52 @@ type EditorSettings struct {
 	SimplifiedQueryAndExpressionsSection bool `json:"simplified_query_and_expressions_section"`
 }
 
+// RuleDependency declares that the alert instances of a rule are suppressed while the rule it depends on is firing.
+// For example, the instances of a "service down" rule can depend on a "datacenter down" rule.
+type RuleDependency struct {
+	// RuleUID is the UID of the rule this rule depends on. If empty, any rule of the organization
+	// with firing alert instances that match Labels.
+	RuleUID string `json:"rule_uid,omitempty"`
+	// Labels the firing alert instances of the dependency must have.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Equal is a list of labels that must have the same value in the firing alert instance of the dependency
+	// and the suppressed alert instance. If empty, all alert instances of the rule are suppressed.
+	Equal []string `json:"equal,omitempty"`
+}
+
+// Validate checks that the dependency selects a rule or alert instances.
+func (d RuleDependency) Validate() error {
+	if d.RuleUID == "" && len(d.Labels) == 0 {
+		return errors.New("either rule UID or labels must be specified")
+	}
+	for name := range d.Labels {
+		if !prommodels.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	for _, name := range d.Equal {
+		if !prommodels.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q in equal", name)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the labels of a firing alert instance satisfy the dependency for an alert instance with the target labels.
+func (d RuleDependency) Matches(source, target map[string]string) bool {
+	for name, value := range d.Labels {
+		if source[name] != value {
+			return false
+		}
+	}
+	for _, name := range d.Equal {
+		if source[name] != target[name] {
+			return false
+		}
+	}
+	return true
+}
+
 // Namespaced describes a class of resources that are stored in a specific namespace.
 type Namespaced interface {
 	GetNamespaceUID() string
@@ -391,9 +454,10 @@ func (alertRule *AlertRule) GetEvalCondition() Condition {
 		}
 	}
 	return Condition{
-		Metadata:  meta,
-		Condition: alertRule.Condition,
-		Data:      alertRule.Data,
+		Metadata:    meta,
+		Condition:   alertRule.Condition,
+		Data:        alertRule.Data,
+		ResultLimit: alertRule.InstanceLimit,
 	}
 }
 
@@ -571,6 +635,10 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.InstanceLimit < 0 {
+		return fmt.Errorf("%w: field `instance_limit` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
 	if len(alertRule.Labels) > 0 {
 		for label := range alertRule.Labels {
 			if _, ok := LabelsUserCannotSpecify[label]; ok {
@@ -579,6 +647,15 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
+	for _, dependency := range alertRule.Dependencies {
+		if dependency.RuleUID == alertRule.UID && alertRule.UID != "" {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if err := dependency.Validate(); err != nil {
+			return errors.Join(ErrAlertRuleFailedValidation, fmt.Errorf("invalid dependency: %w", err))
+		}
+	}
+
 	if len(alertRule.NotificationSettings) > 0 {
 		if len(alertRule.NotificationSettings) != 1 {
 			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
@@ -622,6 +699,8 @@ func clearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.Condition = ""
 	rule.For = 0
 	rule.NotificationSettings = nil
+	rule.Dependencies = nil
+	rule.InstanceLimit = 0
 }
 
 func (alertRule *AlertRule) ResourceType() string {
@@ -719,6 +798,31 @@ type ListNamespaceAlertRulesQuery struct {
 type UpdateRule struct {
 	Existing *AlertRule
 	New      AlertRule
+	// RestoredFrom is the version of the rule the new definition was restored from, if any.
+	RestoredFrom int64
+}
+
+// AlertRuleVersion is a record of the alert rule version history.
+type AlertRuleVersion struct {
+	Rule          AlertRule
+	ParentVersion int64
+	RestoredFrom  int64
+	Created       time.Time
+	// CreatedBy is the UID of the identity that made the change, if known.
+	CreatedBy *string
+}
+
+// GetAlertRuleVersionsQuery is the query for listing versions of an alert rule.
+type GetAlertRuleVersionsQuery struct {
+	OrgID int64
+	UID   string
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a single version of an alert rule.
+type GetAlertRuleVersionQuery struct {
+	OrgID   int64
+	UID     string
+	Version int64
 }
 
 // Condition contains backend expressions and queries and the RefID
@@ -732,6 +836,10 @@ type Condition struct {
 
 	// Data is an array of data source queries and/or server side expressions.
 	Data []AlertQuery `json:"data"`
+
+	// ResultLimit is the maximum number of results of the condition. It can only lower the limit of the evaluator.
+	// Zero means that only the limit of the evaluator applies.
+	ResultLimit int64 `json:"-"`
 }
 
 func (c Condition) withMetadata(key, value string) Condition {
@@ -739,9 +847,10 @@ func (c Condition) withMetadata(key, value string) Condition {
 	maps.Copy(meta, c.Metadata)
 	meta[key] = value
 	return Condition{
-		Metadata:  meta,
-		Condition: c.Condition,
-		Data:      c.Data,
+		Metadata:    meta,
+		Condition:   c.Condition,
+		Data:        c.Data,
+		ResultLimit: c.ResultLimit,
 	}
 }
 
@@ -866,6 +975,9 @@ type Record struct {
 	Metric string
 	// From contains a query RefID, indicating which expression node is the output of the recording rule.
 	From string
+	// TargetDatasourceUID is the UID of the data source to write the recorded metric to. If it is empty, the metric is
+	// written to the remote write endpoint of the recording rules settings.
+	TargetDatasourceUID string `json:",omitempty"`
 }
 
 func (r *Record) Fingerprint() data.Fingerprint {
@@ -880,6 +992,7 @@ func (r *Record) Fingerprint() data.Fingerprint {
 
 	writeString(r.Metric)
 	writeString(r.From)
+	writeString(r.TargetDatasourceUID)
 	return data.Fingerprint(h.Sum64())
 }
 
@@ -884,3 +884,22 @@ func TestTimeRangeYAML(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, yamlRaw, string(serialized))
 }
+
+func TestRuleDependency(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		require.Error(t, RuleDependency{}.Validate())
+		require.Error(t, RuleDependency{Equal: []string{"dc"}}.Validate())
+		require.Error(t, RuleDependency{Labels: map[string]string{"invalid-name": "a"}}.Validate())
+		require.Error(t, RuleDependency{RuleUID: "uid", Equal: []string{"invalid-name"}}.Validate())
+		require.NoError(t, RuleDependency{RuleUID: "uid"}.Validate())
+		require.NoError(t, RuleDependency{Labels: map[string]string{"severity": "critical"}, Equal: []string{"dc"}}.Validate())
+	})
+
+	t.Run("Matches", func(t *testing.T) {
+		dependency := RuleDependency{Labels: map[string]string{"severity": "critical"}, Equal: []string{"dc"}}
+		require.True(t, dependency.Matches(map[string]string{"severity": "critical", "dc": "a"}, map[string]string{"dc": "a"}))
+		require.False(t, dependency.Matches(map[string]string{"severity": "warning", "dc": "a"}, map[string]string{"dc": "a"}))
+		require.False(t, dependency.Matches(map[string]string{"severity": "critical", "dc": "a"}, map[string]string{"dc": "b"}))
+		require.True(t, RuleDependency{RuleUID: "uid"}.Matches(map[string]string{"dc": "a"}, map[string]string{"dc": "b"}))
+	})
+}
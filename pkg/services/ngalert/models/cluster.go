@@ -0,0 +1,9 @@
+package models
+
+// ClusterMembership provides the members of the cluster of Grafana instances in high availability mode.
+type ClusterMembership interface {
+	// LocalMember returns the name of this instance in the cluster.
+	LocalMember() string
+	// Members returns the names of the healthy members of the cluster, including this instance.
+	Members() []string
+}
@@ -1,10 +1,18 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	// ErrAlertInstanceNotFound is returned when an alert instance does not exist.
+	ErrAlertInstanceNotFound = errors.New("could not find alert instance")
+	// ErrAlertInstanceNotFiring is returned when an alert instance cannot be acknowledged because it is not firing.
+	ErrAlertInstanceNotFiring = errors.New("alert instance is not firing")
+)
+
 // AlertInstance represents a single alert instance.
 type AlertInstance struct {
 	AlertInstanceKey  `xorm:"extends"`
@@ -17,6 +25,62 @@ type AlertInstance struct {
 	LastSentAt        *time.Time
 	ResolvedAt        *time.Time
 	ResultFingerprint string
+
+	AckBy                    string
+	AckComment               string
+	AckAt                    *time.Time
+	AckExpiresAt             *time.Time
+	AckSuppressNotifications bool
+	AckSilenceID             string `xorm:"ack_silence_id"`
+}
+
+// Acknowledgement returns the acknowledgement of the alert instance, or nil if it is not acknowledged.
+func (i AlertInstance) Acknowledgement() *AlertInstanceAcknowledgement {
+	if i.AckAt == nil {
+		return nil
+	}
+	return &AlertInstanceAcknowledgement{
+		By:                    i.AckBy,
+		Comment:               i.AckComment,
+		At:                    *i.AckAt,
+		ExpiresAt:             i.AckExpiresAt,
+		SuppressNotifications: i.AckSuppressNotifications,
+		SilenceID:             i.AckSilenceID,
+	}
+}
+
+// SetAcknowledgement sets the acknowledgement fields of the alert instance. A nil acknowledgement clears them.
+func (i *AlertInstance) SetAcknowledgement(ack *AlertInstanceAcknowledgement) {
+	if ack == nil {
+		i.AckBy, i.AckComment, i.AckAt, i.AckExpiresAt, i.AckSuppressNotifications, i.AckSilenceID = "", "", nil, nil, false, ""
+		return
+	}
+	at := ack.At
+	i.AckBy = ack.By
+	i.AckComment = ack.Comment
+	i.AckAt = &at
+	i.AckExpiresAt = ack.ExpiresAt
+	i.AckSuppressNotifications = ack.SuppressNotifications
+	i.AckSilenceID = ack.SilenceID
+}
+
+// AlertInstanceAcknowledgement indicates that somebody is working on a firing alert instance.
+type AlertInstanceAcknowledgement struct {
+	// By is the login of the user who acknowledged the alert instance.
+	By      string
+	Comment string
+	At      time.Time
+	// ExpiresAt is the time when the acknowledgement expires. If it is nil, the acknowledgement lasts until the alert instance resolves.
+	ExpiresAt *time.Time
+	// SuppressNotifications silences the notifications of the alert instance while it is acknowledged.
+	SuppressNotifications bool
+	// SilenceID is the ID of the silence that suppresses the notifications of the alert instance.
+	SilenceID string
+}
+
+// IsExpired returns true if the acknowledgement has expired at the given time.
+func (a AlertInstanceAcknowledgement) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
 }
 
 type AlertInstanceKey struct {
@@ -39,6 +103,8 @@ const (
 	InstanceStateNoData InstanceStateType = "NoData"
 	// InstanceStateError is for an erroring alert.
 	InstanceStateError InstanceStateType = "Error"
+	// InstanceStateSuppressed is for an alert that is suppressed by a firing dependency.
+	InstanceStateSuppressed InstanceStateType = "Suppressed"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -48,7 +114,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNormal ||
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
-		i == InstanceStateError
+		i == InstanceStateError ||
+		i == InstanceStateSuppressed
 }
 
 // ListAlertInstancesQuery is the query list alert Instances.
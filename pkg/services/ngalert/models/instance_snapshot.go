@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// instanceSnapshotVersion is the first byte of encoded snapshots. It allows to change the format of snapshots.
+const instanceSnapshotVersion byte = 1
+
+var ErrInvalidInstanceSnapshot = errors.New("invalid alert instance snapshot")
+
+// instanceSnapshotEntry is the encoded form of an alert instance in a snapshot. The rule of the instance is the rule
+// of the snapshot, and times are stored as Unix timestamps in milliseconds to keep snapshots small.
+type instanceSnapshotEntry struct {
+	Labels                   InstanceLabels    `json:"l"`
+	LabelsHash               string            `json:"h"`
+	CurrentState             InstanceStateType `json:"s"`
+	CurrentReason            string            `json:"r,omitempty"`
+	CurrentStateSince        int64             `json:"ss"`
+	CurrentStateEnd          int64             `json:"se"`
+	LastEvalTime             int64             `json:"le"`
+	LastSentAt               *int64            `json:"ls,omitempty"`
+	ResolvedAt               *int64            `json:"ra,omitempty"`
+	ResultFingerprint        string            `json:"fp,omitempty"`
+	AckBy                    string            `json:"ab,omitempty"`
+	AckComment               string            `json:"ac,omitempty"`
+	AckAt                    *int64            `json:"aa,omitempty"`
+	AckExpiresAt             *int64            `json:"ae,omitempty"`
+	AckSuppressNotifications bool              `json:"as,omitempty"`
+	AckSilenceID             string            `json:"asi,omitempty"`
+}
+
+// EncodeInstanceSnapshot encodes the alert instances of a rule into a compressed snapshot.
+func EncodeInstanceSnapshot(instances []AlertInstance) ([]byte, error) {
+	entries := make([]instanceSnapshotEntry, 0, len(instances))
+	for _, i := range instances {
+		entries = append(entries, instanceSnapshotEntry{
+			Labels:                   i.Labels,
+			LabelsHash:               i.LabelsHash,
+			CurrentState:             i.CurrentState,
+			CurrentReason:            i.CurrentReason,
+			CurrentStateSince:        i.CurrentStateSince.UnixMilli(),
+			CurrentStateEnd:          i.CurrentStateEnd.UnixMilli(),
+			LastEvalTime:             i.LastEvalTime.UnixMilli(),
+			LastSentAt:               timeToUnixMilli(i.LastSentAt),
+			ResolvedAt:               timeToUnixMilli(i.ResolvedAt),
+			ResultFingerprint:        i.ResultFingerprint,
+			AckBy:                    i.AckBy,
+			AckComment:               i.AckComment,
+			AckAt:                    timeToUnixMilli(i.AckAt),
+			AckExpiresAt:             timeToUnixMilli(i.AckExpiresAt),
+			AckSuppressNotifications: i.AckSuppressNotifications,
+			AckSilenceID:             i.AckSilenceID,
+		})
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]byte, 1, 1+snappy.MaxEncodedLen(len(b)))
+	result[0] = instanceSnapshotVersion
+	return append(result, snappy.Encode(nil, b)...), nil
+}
+
+// DecodeInstanceSnapshot decodes a snapshot of the alert instances of the rule.
+func DecodeInstanceSnapshot(orgID int64, ruleUID string, data []byte) ([]*AlertInstance, error) {
+	if len(data) == 0 || data[0] != instanceSnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version", ErrInvalidInstanceSnapshot)
+	}
+	b, err := snappy.Decode(nil, data[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInstanceSnapshot, err)
+	}
+	var entries []instanceSnapshotEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInstanceSnapshot, err)
+	}
+	result := make([]*AlertInstance, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, &AlertInstance{
+			AlertInstanceKey: AlertInstanceKey{
+				RuleOrgID:  orgID,
+				RuleUID:    ruleUID,
+				LabelsHash: e.LabelsHash,
+			},
+			Labels:                   e.Labels,
+			CurrentState:             e.CurrentState,
+			CurrentReason:            e.CurrentReason,
+			CurrentStateSince:        time.UnixMilli(e.CurrentStateSince),
+			CurrentStateEnd:          time.UnixMilli(e.CurrentStateEnd),
+			LastEvalTime:             time.UnixMilli(e.LastEvalTime),
+			LastSentAt:               unixMilliToTime(e.LastSentAt),
+			ResolvedAt:               unixMilliToTime(e.ResolvedAt),
+			ResultFingerprint:        e.ResultFingerprint,
+			AckBy:                    e.AckBy,
+			AckComment:               e.AckComment,
+			AckAt:                    unixMilliToTime(e.AckAt),
+			AckExpiresAt:             unixMilliToTime(e.AckExpiresAt),
+			AckSuppressNotifications: e.AckSuppressNotifications,
+			AckSilenceID:             e.AckSilenceID,
+		})
+	}
+	return result, nil
+}
+
+func timeToUnixMilli(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	ms := t.UnixMilli()
+	return &ms
+}
+
+func unixMilliToTime(ms *int64) *time.Time {
+	if ms == nil {
+		return nil
+	}
+	t := time.UnixMilli(*ms)
+	return &t
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceSnapshot(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	ackExpiresAt := now.Add(time.Hour)
+	instances := []AlertInstance{
+		{
+			AlertInstanceKey:  AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: "hash-1"},
+			Labels:            InstanceLabels{"a": "1"},
+			CurrentState:      InstanceStateFiring,
+			CurrentStateSince: now.Add(-time.Minute),
+			CurrentStateEnd:   now.Add(time.Minute),
+			LastEvalTime:      now,
+			LastSentAt:        &now,
+			ResultFingerprint: "abc",
+		},
+		{
+			AlertInstanceKey:         AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: "hash-2"},
+			Labels:                   InstanceLabels{"a": "2"},
+			CurrentState:             InstanceStateFiring,
+			CurrentStateSince:        now,
+			CurrentStateEnd:          now,
+			LastEvalTime:             now,
+			AckBy:                    "editor",
+			AckComment:               "looking into it",
+			AckAt:                    &now,
+			AckExpiresAt:             &ackExpiresAt,
+			AckSuppressNotifications: true,
+			AckSilenceID:             "silence",
+		},
+	}
+
+	t.Run("should decode encoded instances", func(t *testing.T) {
+		b, err := EncodeInstanceSnapshot(instances)
+		require.NoError(t, err)
+
+		decoded, err := DecodeInstanceSnapshot(1, "rule", b)
+		require.NoError(t, err)
+		require.Len(t, decoded, len(instances))
+		for i := range instances {
+			require.Equal(t, instances[i].AlertInstanceKey, decoded[i].AlertInstanceKey)
+			require.Equal(t, instances[i].Labels, decoded[i].Labels)
+			require.Equal(t, instances[i].CurrentState, decoded[i].CurrentState)
+			require.Equal(t, instances[i].CurrentReason, decoded[i].CurrentReason)
+			require.True(t, instances[i].CurrentStateSince.Equal(decoded[i].CurrentStateSince))
+			require.True(t, instances[i].CurrentStateEnd.Equal(decoded[i].CurrentStateEnd))
+			require.True(t, instances[i].LastEvalTime.Equal(decoded[i].LastEvalTime))
+			require.Equal(t, instances[i].ResultFingerprint, decoded[i].ResultFingerprint)
+			require.Equal(t, instances[i].AckBy, decoded[i].AckBy)
+			require.Equal(t, instances[i].AckSuppressNotifications, decoded[i].AckSuppressNotifications)
+			require.Equal(t, instances[i].AckSilenceID, decoded[i].AckSilenceID)
+		}
+		require.True(t, now.Equal(*decoded[0].LastSentAt))
+		require.Nil(t, decoded[0].AckAt)
+		require.True(t, ackExpiresAt.Equal(*decoded[1].AckExpiresAt))
+	})
+
+	t.Run("should fail on invalid snapshots", func(t *testing.T) {
+		_, err := DecodeInstanceSnapshot(1, "rule", nil)
+		require.ErrorIs(t, err, ErrInvalidInstanceSnapshot)
+		_, err = DecodeInstanceSnapshot(1, "rule", []byte{instanceSnapshotVersion, 1, 2, 3})
+		require.ErrorIs(t, err, ErrInvalidInstanceSnapshot)
+		_, err = DecodeInstanceSnapshot(1, "rule", []byte{42})
+		require.ErrorIs(t, err, ErrInvalidInstanceSnapshot)
+	})
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationDeliveryStatus is the outcome of an attempt to deliver a notification.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery is an attempt of the Grafana Alertmanager to deliver a notification
+// to an integration of a contact point.
+type NotificationDelivery struct {
+	ID    int64
+	OrgID int64
+	// Receiver is the name of the contact point.
+	Receiver string
+	// Integration is the type of the integration, for example "email" or "slack".
+	Integration string
+	// IntegrationIndex is the position of the integration in the contact point.
+	IntegrationIndex int
+	// GroupKey identifies the alert group the notification was sent for.
+	GroupKey string
+	// AlertFingerprints are the fingerprints of the alerts in the notification.
+	AlertFingerprints []string
+	Firing            int
+	Resolved          int
+	Status            NotificationDeliveryStatus
+	Error             string
+	// Retry is true if the delivery failed and the Alertmanager is going to retry it.
+	Retry    bool
+	Duration time.Duration
+	SentAt   time.Time
+}
+
+// ListNotificationDeliveriesQuery is the query for the history of notification deliveries of an organization.
+// Empty fields are not used to filter the deliveries.
+type ListNotificationDeliveriesQuery struct {
+	OrgID            int64
+	Receiver         string
+	Integration      string
+	Status           NotificationDeliveryStatus
+	AlertFingerprint string
+	From             time.Time
+	To               time.Time
+	Limit            int
+}
@@ -536,6 +536,14 @@ func ValidateIntegration(ctx context.Context, integration alertingNotify.Grafana
 		return fmt.Errorf("settings should not be empty")
 	}
 
+	if strings.EqualFold(integration.Type, channels_config.PluginNotifierType) {
+		// The settings of plugin integrations are validated by the plugin when a notification is sent.
+		if _, err := ParsePluginIntegrationSettings(integration.Settings); err != nil {
+			return alertingNotify.IntegrationValidationError{Integration: &integration, Err: err}
+		}
+		return nil
+	}
+
 	_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
 		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
 			Integrations: []*alertingNotify.GrafanaIntegrationConfig{&integration},
@@ -547,6 +555,23 @@ func ValidateIntegration(ctx context.Context, integration alertingNotify.Grafana
 	return nil
 }
 
+// PluginIntegrationSettings are the settings that are common to all integrations of type plugin.
+type PluginIntegrationSettings struct {
+	PluginID string `json:"pluginId"`
+}
+
+// ParsePluginIntegrationSettings parses the settings of an integration of type plugin.
+func ParsePluginIntegrationSettings(settings json.RawMessage) (PluginIntegrationSettings, error) {
+	var result PluginIntegrationSettings
+	if err := json.Unmarshal(settings, &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if result.PluginID == "" {
+		return result, errors.New("could not find plugin ID property in settings")
+	}
+	return result, nil
+}
+
 type EncryptFn = func(string) (string, error)
 type DecryptFn = func(string) (string, error)
 type RedactFn = func(string) string
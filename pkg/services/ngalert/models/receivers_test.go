@@ -110,6 +110,25 @@ func TestIntegration_Validate(t *testing.T) {
 			assert.Errorf(t, invalidIntegration.Validate(Base64Decrypt), "integration should be invalid")
 		})
 	}
+
+	t.Run(channels_config.PluginNotifierType, func(t *testing.T) {
+		pluginConfig, err := IntegrationConfigFromType(channels_config.PluginNotifierType)
+		require.NoError(t, err)
+
+		validIntegration := IntegrationGen(func(c *Integration) {
+			c.Config = pluginConfig
+			c.Settings = map[string]any{"pluginId": "test-app", "project": "OPS"}
+			c.SecureSettings = map[string]string{}
+		})()
+		assert.NoErrorf(t, validIntegration.Validate(Base64Decrypt), "integration should be valid")
+
+		invalidIntegration := IntegrationGen(func(c *Integration) {
+			c.Config = pluginConfig
+			c.Settings = map[string]any{"project": "OPS"}
+			c.SecureSettings = map[string]string{}
+		})()
+		assert.Errorf(t, invalidIntegration.Validate(Base64Decrypt), "integration should be invalid")
+	})
 }
 
 func TestIntegration_WithExistingSecureFields(t *testing.T) {
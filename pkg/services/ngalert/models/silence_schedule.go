@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+var (
+	ErrSilenceScheduleNotFound = errors.New("silence schedule not found")
+	ErrSilenceScheduleInvalid  = errors.New("invalid silence schedule")
+)
+
+// SilenceScheduleState is the approval state of a silence schedule.
+type SilenceScheduleState string
+
+const (
+	// SilenceSchedulePending means that the schedule is waiting for approval and does not silence alerts yet.
+	SilenceSchedulePending SilenceScheduleState = "pending"
+	// SilenceScheduleApproved means that the schedule is active. Schedules that do not require approval are always approved.
+	SilenceScheduleApproved SilenceScheduleState = "approved"
+)
+
+// SilenceSchedule is a template of a silence that recurs. While one of its time intervals is active, the schedule
+// keeps a silence with its matchers in the Alertmanager of the organization.
+type SilenceSchedule struct {
+	ID            int64
+	UID           string
+	OrgID         int64
+	Matchers      amv2.Matchers
+	Comment       string
+	TimeIntervals []timeinterval.TimeInterval
+	CreatedBy     string
+	// CreatedByUID is the UID of the user that created or last updated the schedule.
+	CreatedByUID string
+	// RequiresApprovalBy is the minimum organization role of the user that must approve the schedule before it
+	// becomes active. Empty if the schedule does not require approval.
+	RequiresApprovalBy string
+	State              SilenceScheduleState
+	ApprovedBy         string
+	ApprovedAt         *time.Time
+	// SilenceID is the ID of the last silence created by the schedule.
+	SilenceID string
+	Updated   time.Time
+	// Version is incremented on every update and approval of the schedule. It is used to detect concurrent changes.
+	Version int64
+}
+
+// Validate checks that the schedule has valid matchers and at least one time interval.
+func (s *SilenceSchedule) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrSilenceScheduleInvalid)
+	}
+	for _, m := range s.Matchers {
+		if m == nil {
+			return fmt.Errorf("%w: matcher must not be empty", ErrSilenceScheduleInvalid)
+		}
+		if err := m.Validate(nil); err != nil {
+			return fmt.Errorf("%w: %s", ErrSilenceScheduleInvalid, err)
+		}
+	}
+	if len(s.TimeIntervals) == 0 {
+		return fmt.Errorf("%w: at least one time interval is required", ErrSilenceScheduleInvalid)
+	}
+	return nil
+}
+
+// IsActive returns true if the schedule is approved and one of its time intervals contains the given time.
+func (s *SilenceSchedule) IsActive(t time.Time) bool {
+	if s.State != SilenceScheduleApproved {
+		return false
+	}
+	for _, ti := range s.TimeIntervals {
+		if ti.ContainsTime(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveUntil returns the end of the active window that contains the given time. The window is searched with the
+// resolution of one minute, which is the resolution of time intervals, and is capped to t + limit.
+func (s *SilenceSchedule) ActiveUntil(t time.Time, limit time.Duration) time.Time {
+	end := t.Truncate(time.Minute)
+	horizon := t.Add(limit)
+	for end.Before(horizon) && s.IsActive(end) {
+		end = end.Add(time.Minute)
+	}
+	if end.After(horizon) {
+		return horizon
+	}
+	return end
+}
+
+// Silence returns the silence that the schedule creates for the given window.
+func (s *SilenceSchedule) Silence(startsAt, endsAt time.Time) Silence {
+	comment := s.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("Created by silence schedule %s", s.UID)
+	}
+	start := strfmt.DateTime(startsAt)
+	end := strfmt.DateTime(endsAt)
+	createdBy := s.CreatedBy
+	return Silence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			Matchers:  s.Matchers,
+			StartsAt:  &start,
+			EndsAt:    &end,
+		},
+	}
+}
+
+// ListSilenceSchedulesQuery is the query to list silence schedules. OrgID 0 lists the schedules of all organizations.
+type ListSilenceSchedulesQuery struct {
+	OrgID int64
+}
@@ -499,6 +499,18 @@ func (a *AlertRuleMutators) WithIsPaused(paused bool) AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithDependencies(dependencies ...RuleDependency) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Dependencies = dependencies
+	}
+}
+
+func (a *AlertRuleMutators) WithInstanceLimit(limit int64) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.InstanceLimit = limit
+	}
+}
+
 func (a *AlertRuleMutators) WithRandomRecordingRules() AlertRuleMutator {
 	return func(rule *AlertRule) {
 		if rand.Int63()%2 == 0 {
@@ -614,6 +626,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
 		Record:          r.Record,
+		InstanceLimit:   r.InstanceLimit,
 	}
 
 	if r.DashboardUID != nil {
@@ -662,6 +675,14 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	for _, d := range r.Dependencies {
+		result.Dependencies = append(result.Dependencies, RuleDependency{
+			RuleUID: d.RuleUID,
+			Labels:  maps.Clone(d.Labels),
+			Equal:   slices.Clone(d.Equal),
+		})
+	}
+
 	if len(mutators) > 0 {
 		for _, mutator := range mutators {
 			mutator(&result)
@@ -12,6 +12,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/expr"
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -45,11 +47,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
 )
 
 func ProvideService(
@@ -78,6 +82,9 @@ func ProvideService(
 	ruleStore *store.DBstore,
 	httpClientProvider httpclient.Provider,
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
+	pluginClient plugins.Client,
+	pluginContextProvider *plugincontext.Provider,
+	grafanaDS *grafanads.Service,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                  cfg,
@@ -106,6 +113,11 @@ func ProvideService(
 		store:                ruleStore,
 		httpClientProvider:   httpClientProvider,
 		ResourcePermissions:  resourcePermissions,
+		grafanaDS:            grafanaDS,
+	}
+
+	if pluginClient != nil && pluginContextProvider != nil {
+		ng.PluginNotifications = notifier.NewPluginNotificationClient(pluginClient, pluginContextProvider, pluginsStore)
 	}
 
 	if ng.IsDisabled() {
@@ -140,6 +152,8 @@ type AlertNG struct {
 	RecordingWriter     schedule.RecordingWriter
 	schedule            schedule.ScheduleService
 	stateManager        *state.Manager
+	grafanaDS           *grafanads.Service
+	stateSnapshotStore  *store.RuleStateSnapshotStore
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 	Api                 *api.API
@@ -148,6 +162,8 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
+	silenceScheduler     *notifier.SilenceScheduler
+	PluginNotifications  *notifier.PluginNotificationClient
 	accesscontrol        accesscontrol.AccessControl
 	AccesscontrolService accesscontrol.Service
 	ResourcePermissions  accesscontrol.ReceiverPermissionsService
@@ -309,6 +325,10 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
+	if ng.PluginNotifications != nil {
+		overrides = append(overrides, notifier.WithPluginNotifications(ng.PluginNotifications))
+	}
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(
@@ -330,6 +350,7 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 	ng.MultiOrgAlertmanager = moa
+	ng.silenceScheduler = notifier.NewSilenceScheduler(ng.store, moa, log.New("ngalert.silence-scheduler"))
 
 	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, ng.store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer)
 	if err != nil {
@@ -367,7 +388,7 @@ func (ng *AlertNG) init() error {
 		// Force-disable the feature if the feature toggle is not on - sets us up for feature toggle removal.
 		ng.Cfg.UnifiedAlerting.RecordingRules.Enabled = false
 	}
-	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
+	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.DataSourceService, ng.httpClientProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
 	if err != nil {
 		return fmt.Errorf("failed to initialize recording writer: %w", err)
 	}
@@ -390,6 +411,13 @@ func (ng *AlertNG) init() error {
 		Log:                  log.New("ngalert.scheduler"),
 		RecordingWriter:      ng.RecordingWriter,
 	}
+	if ng.Cfg.UnifiedAlerting.HAShardRuleEvaluation {
+		if membership := ng.MultiOrgAlertmanager.ClusterMembership(); membership != nil {
+			schedCfg.ClusterMembership = membership
+		} else {
+			ng.Log.Warn("Sharding of rule evaluation is enabled but high availability mode is not configured. All rules are evaluated by this instance")
+		}
+	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
 	// If any are set, override the config accordingly.
@@ -410,13 +438,28 @@ func (ng *AlertNG) init() error {
 		ApplyNoDataAndErrorToAllStates: ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingNoDataErrorExecution),
 		MaxStateSaveConcurrency:        ng.Cfg.UnifiedAlerting.MaxStateSaveConcurrency,
 		RulesPerRuleGroupLimit:         ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit,
+		ShardedEvaluation:              schedCfg.ClusterMembership != nil,
 		Tracer:                         ng.tracer,
 		Log:                            log.New("ngalert.state.manager"),
 		ResolvedRetention:              ng.Cfg.UnifiedAlerting.ResolvedAlertRetention,
+		TemplateQuerier: eval.NewTemplateQuerier(ng.Cfg.UnifiedAlerting, evalFactory, func(rule *models.AlertRule) identity.Requester {
+			return schedule.SchedulerUserForRule(rule)
+		}),
+		Silencer: ng.MultiOrgAlertmanager,
 	}
 	logger := log.New("ngalert.state.manager.persist")
 	statePersister := state.NewSyncStatePersisiter(logger, cfg)
-	if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
+	if ng.Cfg.UnifiedAlerting.StateCompressedSnapshots && schedCfg.ClusterMembership != nil {
+		ng.Log.Warn("Compressed state snapshots are not supported when rule evaluation is sharded. The state is saved on every evaluation")
+	} else if ng.Cfg.UnifiedAlerting.StateCompressedSnapshots {
+		ng.stateSnapshotStore = store.NewRuleStateSnapshotStore(ng.store)
+		cfg.InstanceStore = ng.stateSnapshotStore
+		ticker := clock.New().Ticker(ng.Cfg.UnifiedAlerting.StatePeriodicSaveInterval)
+		statePersister = state.NewSnapshotStatePersister(logger, ticker, ng.stateSnapshotStore, cfg)
+	} else if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) && schedCfg.ClusterMembership != nil {
+		// the periodic full sync replaces the state of all rules, including the ones evaluated by other instances.
+		ng.Log.Warn("Periodic saving of alert state is not supported when rule evaluation is sharded. The state is saved on every evaluation")
+	} else if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
 		ticker := clock.New().Ticker(ng.Cfg.UnifiedAlerting.StatePeriodicSaveInterval)
 		statePersister = state.NewAsyncStatePersister(logger, ticker, cfg)
 	}
@@ -429,6 +472,11 @@ func (ng *AlertNG) init() error {
 	}
 
 	ng.stateManager = stateManager
+	if ng.grafanaDS != nil {
+		// alert instance queries of the Grafana data source read the current state from the state manager
+		ng.grafanaDS.SetAlertStateReader(stateManager)
+		ng.grafanaDS.SetStateHistoryReader(history)
+	}
 	ng.schedule = scheduler
 
 	configStore := legacy_storage.NewAlertmanagerConfigStore(ng.store)
@@ -474,6 +522,7 @@ func (ng *AlertNG) init() error {
 		TransactionManager:   ng.store,
 		RuleStore:            ng.store,
 		AlertingStore:        ng.store,
+		SilenceScheduleStore: ng.store,
 		AdminConfigStore:     ng.store,
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
@@ -539,6 +588,9 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.silenceScheduler.Run(subCtx)
+	})
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -550,6 +602,15 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 		// Also note that this runs synchronously to ensure state is loaded
 		// before rule evaluation begins, hence we use ctx and not subCtx.
 		//
+		if ng.stateSnapshotStore != nil {
+			if _, err := ng.stateSnapshotStore.MigrateFromAlertInstances(ctx); err != nil {
+				ng.Log.Error("Failed to migrate alert instances to rule state snapshots", "error", err)
+			}
+		} else if _, err := store.NewRuleStateSnapshotStore(ng.store).MigrateToAlertInstances(ctx); err != nil {
+			// The alert_instance table is not updated while the snapshots are used, therefore the snapshots of a previous
+			// run are newer and replace it.
+			ng.Log.Error("Failed to migrate rule state snapshots to alert instances", "error", err)
+		}
 		ng.stateManager.Warm(ctx, ng.store)
 
 		children.Go(func() error {
@@ -690,12 +751,18 @@ func createRemoteAlertmanager(cfg remote.AlertmanagerConfig, kvstore kvstore.KVS
 	return remote.NewAlertmanager(cfg, notifier.NewFileStore(cfg.OrgID, kvstore), decryptFn, autogenFn, m, tracer)
 }
 
-func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
+func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, dsService datasources.DataSourceService, httpClientProvider httpclient.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
 	logger := log.New("ngalert.writer")
 
+	var defaultWriter writer.Writer = writer.NoopWriter{}
 	if settings.Enabled {
-		return writer.NewPrometheusWriter(settings, httpClientProvider, clock, logger, m)
+		w, err := writer.NewPrometheusWriter(settings, httpClientProvider, clock, logger, m)
+		if err != nil {
+			return nil, err
+		}
+		defaultWriter = w
 	}
 
-	return writer.NoopWriter{}, nil
+	// Rules with a target data source are written to it even if the default target is not configured.
+	return writer.NewDatasourceWriter(defaultWriter, dsService, httpClientProvider, settings, clock, logger, m), nil
 }
@@ -36,6 +36,7 @@ const (
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationDeliveryStore
 	autogenRuleStore
 }
 
@@ -59,6 +60,12 @@ type alertmanager struct {
 	decryptFn alertingNotify.GetDecryptedValueFn
 	orgID     int64
 
+	// pluginNotifications sends the notifications of integrations of type plugin. It is nil if they are not supported.
+	pluginNotifications *PluginNotificationClient
+
+	// deliveryLog saves the history of notification deliveries. It is nil if the history is disabled.
+	deliveryLog *deliveryLog
+
 	withAutogen bool
 }
 
@@ -154,6 +161,9 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		// TODO: Preferably, logic around autogen would be outside of the specific alertmanager implementation so that remote alertmanager will get it for free.
 		withAutogen: withAutogen,
 	}
+	if cfg.UnifiedAlerting.NotificationDeliveryRetention > 0 {
+		am.deliveryLog = newDeliveryLog(store, l)
+	}
 
 	return am, nil
 }
@@ -167,6 +177,9 @@ func (am *alertmanager) Ready() bool {
 
 func (am *alertmanager) StopAndWait() {
 	am.Base.StopAndWait()
+	if am.deliveryLog != nil {
+		am.deliveryLog.stopAndWait()
+	}
 }
 
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
@@ -385,12 +398,17 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	builtin, plugins := splitPluginIntegrations(receiver)
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), builtin, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
 	s := &sender{am.NotificationService}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
+	pluginIntegrations, err := am.buildPluginIntegrations(receiver.Name, plugins, tmpl, img)
+	if err != nil {
+		return nil, err
+	}
 	integrations, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
 		tmpl,
@@ -408,6 +426,10 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
+	integrations = append(integrations, pluginIntegrations...)
+	if am.deliveryLog != nil {
+		integrations = withDeliveryLog(am.orgID, receiver.Name, integrations, am.deliveryLog)
+	}
 	return integrations, nil
 }
 
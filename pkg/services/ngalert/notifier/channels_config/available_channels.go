@@ -1657,6 +1657,23 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        PluginNotifierType,
+			Name:        "Plugin",
+			Description: "Sends notifications through a backend plugin",
+			Heading:     "Plugin settings",
+			Info:        "The other settings of the contact point are described by the plugin",
+			Options: []NotifierOption{
+				{
+					Label:        "Plugin ID",
+					Description:  "The ID of the backend plugin that sends the notifications",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "pluginId",
+					Required:     true,
+				},
+			},
+		},
 	}
 }
 
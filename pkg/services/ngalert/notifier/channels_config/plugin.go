@@ -1,5 +1,9 @@
 package channels_config
 
+// PluginNotifierType is the type of the notifiers that send notifications through a backend plugin. The settings of
+// these notifiers are described by the metadata that the plugin returns.
+const PluginNotifierType = "plugin"
+
 // NotifierPlugin holds meta information about a notifier.
 type NotifierPlugin struct {
 	Type        string           `json:"type"`
@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// deliverySaveTimeout is the maximum time to wait for a notification delivery to be saved.
+	deliverySaveTimeout = 10 * time.Second
+
+	// deliveryLogQueueSize is the number of notification deliveries that can wait to be saved. Deliveries are
+	// dropped when the queue is full so that a slow database does not delay notifications.
+	deliveryLogQueueSize = 1000
+)
+
+type skipDeliveryLogKey struct{}
+
+// withoutDeliveryLog returns a context for notifications that must not be saved to the history of notification
+// deliveries, such as the notifications sent to test receivers.
+func withoutDeliveryLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipDeliveryLogKey{}, true)
+}
+
+func skipDeliveryLog(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipDeliveryLogKey{}).(bool)
+	return skip
+}
+
+// deliveryLog saves notification deliveries in the background, on a best-effort basis.
+type deliveryLog struct {
+	store  store.NotificationDeliveryStore
+	logger log.Logger
+
+	mtx        sync.RWMutex
+	stopped    bool
+	deliveries chan *models.NotificationDelivery
+	done       chan struct{}
+}
+
+func newDeliveryLog(s store.NotificationDeliveryStore, logger log.Logger) *deliveryLog {
+	l := &deliveryLog{
+		store:      s,
+		logger:     logger,
+		deliveries: make(chan *models.NotificationDelivery, deliveryLogQueueSize),
+		done:       make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+func (l *deliveryLog) run() {
+	defer close(l.done)
+	for delivery := range l.deliveries {
+		ctx, cancel := context.WithTimeout(context.Background(), deliverySaveTimeout)
+		if err := l.store.SaveNotificationDelivery(ctx, delivery); err != nil {
+			l.logger.Warn("Failed to save notification delivery", "receiver", delivery.Receiver, "integration", delivery.Integration, "error", err)
+		}
+		cancel()
+	}
+}
+
+// save queues the delivery to be saved. The delivery is dropped if the queue is full or the log is stopped.
+func (l *deliveryLog) save(delivery *models.NotificationDelivery) {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	if l.stopped {
+		return
+	}
+	select {
+	case l.deliveries <- delivery:
+	default:
+		l.logger.Warn("Dropping notification delivery because too many deliveries are waiting to be saved", "receiver", delivery.Receiver, "integration", delivery.Integration)
+	}
+}
+
+// stopAndWait stops accepting deliveries and waits until the queued deliveries are saved.
+func (l *deliveryLog) stopAndWait() {
+	l.mtx.Lock()
+	if !l.stopped {
+		l.stopped = true
+		close(l.deliveries)
+	}
+	l.mtx.Unlock()
+	<-l.done
+}
+
+// withDeliveryLog wraps the integrations of the receiver so that every attempt to deliver a notification is saved
+// to the history of notification deliveries of the organization.
+func withDeliveryLog(orgID int64, receiver string, integrations []*alertingNotify.Integration, dl *deliveryLog) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		n := &deliveryLogNotifier{
+			upstream:    integration,
+			log:         dl,
+			orgID:       orgID,
+			receiver:    receiver,
+			integration: integration.Name(),
+			index:       integration.Index(),
+		}
+		result = append(result, alertingNotify.NewIntegration(n, integration, integration.Name(), integration.Index(), receiver))
+	}
+	return result
+}
+
+// deliveryLogNotifier is a notify.Notifier that saves the outcome of every notification attempt.
+type deliveryLogNotifier struct {
+	upstream    notify.Notifier
+	log         *deliveryLog
+	orgID       int64
+	receiver    string
+	integration string
+	index       int
+}
+
+func (n *deliveryLogNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	if skipDeliveryLog(ctx) {
+		return n.upstream.Notify(ctx, alerts...)
+	}
+	start := time.Now()
+	retry, err := n.upstream.Notify(ctx, alerts...)
+
+	delivery := &models.NotificationDelivery{
+		OrgID:             n.orgID,
+		Receiver:          n.receiver,
+		Integration:       n.integration,
+		IntegrationIndex:  n.index,
+		AlertFingerprints: make([]string, 0, len(alerts)),
+		Status:            models.NotificationDeliverySuccess,
+		Duration:          time.Since(start),
+		SentAt:            start,
+	}
+	if groupKey, ok := notify.GroupKey(ctx); ok {
+		delivery.GroupKey = groupKey
+	}
+	for _, alert := range alerts {
+		delivery.AlertFingerprints = append(delivery.AlertFingerprints, alert.Fingerprint().String())
+		if alert.Resolved() {
+			delivery.Resolved++
+		} else {
+			delivery.Firing++
+		}
+	}
+	sort.Strings(delivery.AlertFingerprints)
+	if err != nil {
+		delivery.Status = models.NotificationDeliveryFailed
+		delivery.Error = err.Error()
+		delivery.Retry = retry
+	}
+	n.log.save(delivery)
+	return retry, err
+}
+
+// DeleteExpiredDeliveriesService is a service to delete the notification deliveries older than the retention period.
+type DeleteExpiredDeliveriesService struct {
+	store     store.NotificationDeliveryStore
+	retention time.Duration
+}
+
+func ProvideDeleteExpiredDeliveriesService(cfg *setting.Cfg, store *store.DBstore) *DeleteExpiredDeliveriesService {
+	return &DeleteExpiredDeliveriesService{store: store, retention: cfg.UnifiedAlerting.NotificationDeliveryRetention}
+}
+
+// DeleteExpired deletes expired notification deliveries. It returns the number of deleted deliveries.
+// If the history of notification deliveries is disabled, all deliveries are deleted.
+func (s *DeleteExpiredDeliveriesService) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.store.DeleteNotificationDeliveriesBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// GetNotificationDeliveries returns the history of notification deliveries of the organization that match the query.
+func (moa *MultiOrgAlertmanager) GetNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]*models.NotificationDelivery, error) {
+	return moa.configStore.ListNotificationDeliveries(ctx, query)
+}
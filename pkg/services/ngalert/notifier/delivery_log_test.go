@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeNotifier struct {
+	retry bool
+	err   error
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	return f.retry, f.err
+}
+
+func TestDeliveryLogNotifier(t *testing.T) {
+	now := time.Now()
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "firing"},
+		StartsAt: now.Add(-time.Minute),
+		EndsAt:   now.Add(time.Hour),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "resolved"},
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(-time.Minute),
+	}}
+
+	newNotifier := func(upstream notify.Notifier, store *fakeConfigStore) (*deliveryLogNotifier, *deliveryLog) {
+		dl := newDeliveryLog(store, log.NewNopLogger())
+		t.Cleanup(dl.stopAndWait)
+		return &deliveryLogNotifier{
+			upstream:    upstream,
+			log:         dl,
+			orgID:       1,
+			receiver:    "test-receiver",
+			integration: "webhook",
+			index:       2,
+		}, dl
+	}
+
+	t.Run("should save successful delivery", func(t *testing.T) {
+		store := NewFakeConfigStore(t, nil)
+		n, dl := newNotifier(&fakeNotifier{}, store)
+
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		retry, err := n.Notify(ctx, firing, resolved)
+		require.NoError(t, err)
+		require.False(t, retry)
+		dl.stopAndWait()
+
+		deliveries, err := store.ListNotificationDeliveries(context.Background(), models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		d := deliveries[0]
+		require.Equal(t, "test-receiver", d.Receiver)
+		require.Equal(t, "webhook", d.Integration)
+		require.Equal(t, 2, d.IntegrationIndex)
+		require.Equal(t, "{}:{alertname=\"test\"}", d.GroupKey)
+		require.ElementsMatch(t, []string{firing.Fingerprint().String(), resolved.Fingerprint().String()}, d.AlertFingerprints)
+		require.Equal(t, 1, d.Firing)
+		require.Equal(t, 1, d.Resolved)
+		require.Equal(t, models.NotificationDeliverySuccess, d.Status)
+		require.Empty(t, d.Error)
+	})
+
+	t.Run("should save failed delivery and pass the error through", func(t *testing.T) {
+		store := NewFakeConfigStore(t, nil)
+		expectedErr := errors.New("connection refused")
+		n, dl := newNotifier(&fakeNotifier{retry: true, err: expectedErr}, store)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		retry, err := n.Notify(ctx, firing)
+		require.ErrorIs(t, err, expectedErr)
+		require.True(t, retry)
+		dl.stopAndWait()
+
+		deliveries, err := store.ListNotificationDeliveries(context.Background(), models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		d := deliveries[0]
+		require.Equal(t, models.NotificationDeliveryFailed, d.Status)
+		require.Equal(t, expectedErr.Error(), d.Error)
+		require.True(t, d.Retry)
+		require.Equal(t, 1, d.Firing)
+		require.Zero(t, d.Resolved)
+	})
+
+	t.Run("should not save notifications to test receivers", func(t *testing.T) {
+		store := NewFakeConfigStore(t, nil)
+		n, dl := newNotifier(&fakeNotifier{}, store)
+
+		_, err := n.Notify(withoutDeliveryLog(context.Background()), firing)
+		require.NoError(t, err)
+		dl.stopAndWait()
+
+		deliveries, err := store.ListNotificationDeliveries(context.Background(), models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("should drop deliveries instead of blocking when the queue is full", func(t *testing.T) {
+		store := NewFakeConfigStore(t, nil)
+		// The deliveries are not consumed, so the queue is always full.
+		dl := &deliveryLog{
+			store:      store,
+			logger:     log.NewNopLogger(),
+			deliveries: make(chan *models.NotificationDelivery),
+		}
+		n := &deliveryLogNotifier{upstream: &fakeNotifier{}, log: dl, orgID: 1}
+
+		_, err := n.Notify(context.Background(), firing)
+		require.NoError(t, err)
+		require.Empty(t, dl.deliveries)
+	})
+
+	t.Run("should drop deliveries after the log is stopped", func(t *testing.T) {
+		store := NewFakeConfigStore(t, nil)
+		n, dl := newNotifier(&fakeNotifier{}, store)
+		dl.stopAndWait()
+
+		_, err := n.Notify(context.Background(), firing)
+		require.NoError(t, err)
+
+		deliveries, err := store.ListNotificationDeliveries(context.Background(), models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+}
@@ -102,6 +102,8 @@ type MultiOrgAlertmanager struct {
 	metrics *metrics.MultiOrgAlertmanager
 	ns      notifications.Service
 
+	pluginNotifications *PluginNotificationClient
+
 	receiverResourcePermissions ac.ReceiverPermissionsService
 }
 
@@ -115,6 +117,13 @@ func WithAlertmanagerOverride(f func(OrgAlertmanagerFactory) OrgAlertmanagerFact
 	}
 }
 
+// WithPluginNotifications enables integrations of type plugin that send notifications through backend plugins.
+func WithPluginNotifications(client *PluginNotificationClient) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.pluginNotifications = client
+	}
+}
+
 func NewMultiOrgAlertmanager(
 	cfg *setting.Cfg,
 	configStore AlertingStore,
@@ -160,7 +169,12 @@ func NewMultiOrgAlertmanager(
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID), l)
 		stateStore := NewFileStore(orgID, kvStore)
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingSimplifiedRouting))
+		am, err := NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingSimplifiedRouting))
+		if err != nil {
+			return nil, err
+		}
+		am.pluginNotifications = moa.pluginNotifications
+		return am, nil
 	}
 
 	for _, opt := range opts {
@@ -406,6 +420,37 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembership returns the members of the cluster the Alertmanagers are part of.
+// It returns nil if high availability mode is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembership() models.ClusterMembership {
+	switch p := moa.peer.(type) {
+	case *redisPeer:
+		return p
+	case *alertingCluster.Peer:
+		return memberlistMembership{peer: p}
+	default:
+		return nil
+	}
+}
+
+// memberlistMembership provides the members of the gossip mesh.
+type memberlistMembership struct {
+	peer *alertingCluster.Peer
+}
+
+func (m memberlistMembership) LocalMember() string {
+	return m.peer.Name()
+}
+
+func (m memberlistMembership) Members() []string {
+	peers := m.peer.Peers()
+	members := make([]string, 0, len(peers))
+	for _, p := range peers {
+		members = append(members, p.Name())
+	}
+	return members
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
@@ -0,0 +1,321 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/alerting/images"
+	"github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+)
+
+const (
+	// pluginNotifierPath is the resource path of a backend plugin that returns the metadata of its notifier.
+	pluginNotifierPath = "alerting/notifier"
+	// pluginNotifyPath is the resource path of a backend plugin that sends notifications.
+	pluginNotifyPath = "alerting/notify"
+)
+
+var (
+	ErrPluginNotifierNotSupported = errors.New("notifications through backend plugins are not supported")
+	// ErrNotNotifierPlugin is returned for plugins that do not declare a notifier with alertingNotifier in plugin.json.
+	ErrNotNotifierPlugin = errors.New("plugin does not implement a notifier")
+)
+
+// PluginContextProvider returns the context of the requests to a plugin.
+type PluginContextProvider interface {
+	Get(ctx context.Context, pluginID string, user identity.Requester, orgID int64) (backend.PluginContext, error)
+}
+
+// PluginStore returns the installed plugins.
+type PluginStore interface {
+	Plugin(ctx context.Context, pluginID string) (pluginstore.Plugin, bool)
+}
+
+// PluginNotificationClient sends notifications to the backend plugins that implement a notifier.
+//
+// A plugin implements a notifier by serving two resources: alerting/notifier responds to GET requests with the
+// metadata of the notifier, in the same format as the metadata of the built-in notifiers, and alerting/notify
+// accepts POST requests with a PluginNotification. Any response with a status code other than 2xx is a failed
+// delivery. Failures with status code 429 or 5xx are retried. Only backend plugins that set alertingNotifier in
+// their plugin.json are called.
+type PluginNotificationClient struct {
+	client   backend.CallResourceHandler
+	contexts PluginContextProvider
+	plugins  PluginStore
+}
+
+func NewPluginNotificationClient(client backend.CallResourceHandler, contexts PluginContextProvider, plugins PluginStore) *PluginNotificationClient {
+	return &PluginNotificationClient{
+		client:   client,
+		contexts: contexts,
+		plugins:  plugins,
+	}
+}
+
+// PluginNotification is the body of the requests sent to the alerting/notify resource of a plugin.
+type PluginNotification struct {
+	*alertingTemplates.ExtendedData
+
+	// The protocol version.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	OrgID    int64  `json:"orgId"`
+	State    string `json:"state"`
+	// The settings of the integration, including the plugin ID.
+	Settings json.RawMessage `json:"settings"`
+	// The decrypted secure settings of the integration.
+	SecureSettings map[string]string `json:"secureSettings,omitempty"`
+}
+
+// PluginNotifierError is returned when a plugin fails to send a notification.
+type PluginNotifierError struct {
+	PluginID   string
+	StatusCode int
+	Message    string
+}
+
+func (e PluginNotifierError) Error() string {
+	return fmt.Sprintf("plugin %s failed to send notification with status code %d: %s", e.PluginID, e.StatusCode, e.Message)
+}
+
+// Retryable returns true if the notification can be sent again.
+func (e PluginNotifierError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// GetNotifier returns the metadata of the notifier implemented by the plugin. The type of the notifier is always plugin.
+func (c *PluginNotificationClient) GetNotifier(ctx context.Context, orgID int64, pluginID string) (*channels_config.NotifierPlugin, error) {
+	status, body, err := c.call(ctx, orgID, pluginID, http.MethodGet, pluginNotifierPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status/100 != 2 {
+		return nil, PluginNotifierError{PluginID: pluginID, StatusCode: status, Message: responseMessage(body)}
+	}
+	var result channels_config.NotifierPlugin
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notifier of plugin %s: %w", pluginID, err)
+	}
+	result.Type = channels_config.PluginNotifierType
+	return &result, nil
+}
+
+// Notify sends the notification to the plugin.
+func (c *PluginNotificationClient) Notify(ctx context.Context, orgID int64, pluginID string, n *PluginNotification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	status, resp, err := c.call(ctx, orgID, pluginID, http.MethodPost, pluginNotifyPath, body)
+	if err != nil {
+		return err
+	}
+	if status/100 != 2 {
+		return PluginNotifierError{PluginID: pluginID, StatusCode: status, Message: responseMessage(resp)}
+	}
+	return nil
+}
+
+func (c *PluginNotificationClient) call(ctx context.Context, orgID int64, pluginID, method, path string, body []byte) (int, []byte, error) {
+	p, ok := c.plugins.Plugin(ctx, pluginID)
+	if !ok {
+		return 0, nil, fmt.Errorf("plugin %s: %w", pluginID, plugins.ErrPluginNotRegistered)
+	}
+	if !p.Backend || !p.AlertingNotifier {
+		return 0, nil, fmt.Errorf("plugin %s: %w", pluginID, ErrNotNotifierPlugin)
+	}
+	pCtx, err := c.contexts.Get(ctx, pluginID, nil, orgID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get context of plugin %s: %w", pluginID, err)
+	}
+	// The context is requested without a user, so the organization is not taken from the user.
+	pCtx.OrgID = orgID
+	req := &backend.CallResourceRequest{
+		PluginContext: pCtx,
+		Path:          path,
+		Method:        method,
+		URL:           path,
+		Headers:       map[string][]string{"Content-Type": {"application/json"}},
+		Body:          body,
+	}
+	var resp *backend.CallResourceResponse
+	err = c.client.CallResource(ctx, req, backend.CallResourceResponseSenderFunc(func(r *backend.CallResourceResponse) error {
+		// Plugins can stream the response, only the first chunk is used.
+		if resp == nil {
+			resp = r
+		}
+		return nil
+	}))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to call plugin %s: %w", pluginID, err)
+	}
+	if resp == nil {
+		return 0, nil, fmt.Errorf("plugin %s did not respond", pluginID)
+	}
+	return resp.Status, resp.Body, nil
+}
+
+// responseMessage returns the message of an error response of a plugin. Plugins usually respond with a JSON object
+// with a message property, otherwise the body is used as is.
+func responseMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Message != "" {
+		return resp.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// pluginNotifier is a notifier that sends notifications through a backend plugin.
+type pluginNotifier struct {
+	*receivers.Base
+	client         *PluginNotificationClient
+	orgID          int64
+	pluginID       string
+	settings       json.RawMessage
+	secureSettings map[string]string
+	tmpl           *alertingTemplates.Template
+	images         images.Provider
+	log            logging.Logger
+}
+
+func (n *pluginNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	_, data := alertingTemplates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	_ = images.WithStoredImages(ctx, n.log, n.images,
+		func(index int, image images.Image) error {
+			if len(image.URL) != 0 {
+				data.Alerts[index].ImageURL = image.URL
+			}
+			return nil
+		},
+		as...)
+
+	msg := &PluginNotification{
+		ExtendedData:   data,
+		Version:        "1",
+		GroupKey:       groupKey.String(),
+		OrgID:          n.orgID,
+		State:          string(receivers.AlertStateOK),
+		Settings:       n.settings,
+		SecureSettings: n.secureSettings,
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(receivers.AlertStateAlerting)
+	}
+
+	if err := n.client.Notify(ctx, n.orgID, n.pluginID, msg); err != nil {
+		var pluginErr PluginNotifierError
+		if errors.As(err, &pluginErr) {
+			return pluginErr.Retryable(), err
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+func (n *pluginNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
+
+// splitPluginIntegrations returns a copy of the receiver without the integrations of type plugin, and the integrations
+// of type plugin.
+func splitPluginIntegrations(receiver *alertingNotify.APIReceiver) (*alertingNotify.APIReceiver, []*alertingNotify.GrafanaIntegrationConfig) {
+	var plugins []*alertingNotify.GrafanaIntegrationConfig
+	builtin := make([]*alertingNotify.GrafanaIntegrationConfig, 0, len(receiver.Integrations))
+	for _, integration := range receiver.Integrations {
+		if strings.EqualFold(integration.Type, channels_config.PluginNotifierType) {
+			plugins = append(plugins, integration)
+			continue
+		}
+		builtin = append(builtin, integration)
+	}
+	if len(plugins) == 0 {
+		return receiver, nil
+	}
+	result := *receiver
+	result.Integrations = builtin
+	return &result, plugins
+}
+
+// buildPluginIntegrations builds the integrations of type plugin of a receiver.
+func (am *alertmanager) buildPluginIntegrations(receiverName string, configs []*alertingNotify.GrafanaIntegrationConfig, tmpl *alertingTemplates.Template, img images.Provider) ([]*alertingNotify.Integration, error) {
+	result := make([]*alertingNotify.Integration, 0, len(configs))
+	for idx, cfg := range configs {
+		if am.pluginNotifications == nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: cfg, Err: ErrPluginNotifierNotSupported}
+		}
+		settings, err := models.ParsePluginIntegrationSettings(cfg.Settings)
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: cfg, Err: err}
+		}
+		meta := receivers.Metadata{
+			UID:                   cfg.UID,
+			Name:                  cfg.Name,
+			Type:                  channels_config.PluginNotifierType,
+			DisableResolveMessage: cfg.DisableResolveMessage,
+		}
+		secureSettings, err := am.decryptPluginSecureSettings(cfg.SecureSettings)
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: cfg, Err: err}
+		}
+		n := &pluginNotifier{
+			Base:           receivers.NewBase(meta),
+			client:         am.pluginNotifications,
+			orgID:          am.orgID,
+			pluginID:       settings.PluginID,
+			settings:       cfg.Settings,
+			secureSettings: secureSettings,
+			tmpl:           tmpl,
+			images:         img,
+			log:            LoggerFactory("ngalert.notifier."+meta.Type, "notifierUID", meta.UID, "pluginId", settings.PluginID),
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, meta.Type, idx, receiverName))
+	}
+	return result, nil
+}
+
+// decryptPluginSecureSettings decrypts the secure settings of an integration of type plugin. Like the secure settings
+// of the built-in integrations, they are encrypted and base64-encoded.
+func (am *alertmanager) decryptPluginSecureSettings(secureSettings map[string]string) (map[string]string, error) {
+	if len(secureSettings) == 0 {
+		return nil, nil
+	}
+	encrypted := make(map[string][]byte, len(secureSettings))
+	for k, v := range secureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secure setting %s: %w", k, err)
+		}
+		encrypted[k] = d
+	}
+	result := make(map[string]string, len(secureSettings))
+	for k := range secureSettings {
+		result[k] = am.decryptFn(context.Background(), encrypted, k, "")
+	}
+	return result, nil
+}
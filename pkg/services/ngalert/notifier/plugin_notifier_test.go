@@ -0,0 +1,234 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakePluginContextProvider struct {
+	plugins map[string]bool
+}
+
+func (f *fakePluginContextProvider) Get(_ context.Context, pluginID string, _ identity.Requester, _ int64) (backend.PluginContext, error) {
+	if !f.plugins[pluginID] {
+		return backend.PluginContext{}, plugins.ErrPluginNotRegistered
+	}
+	// the real provider takes the organization from the user, which is nil
+	return backend.PluginContext{PluginID: pluginID}, nil
+}
+
+type fakePluginStore struct {
+	plugins map[string]pluginstore.Plugin
+}
+
+func newFakePluginStore() *fakePluginStore {
+	return &fakePluginStore{plugins: map[string]pluginstore.Plugin{
+		"test-app":        {JSONData: plugins.JSONData{ID: "test-app", Type: plugins.TypeApp, Backend: true, AlertingNotifier: true}},
+		"test-datasource": {JSONData: plugins.JSONData{ID: "test-datasource", Type: plugins.TypeDataSource, Backend: true}},
+	}}
+}
+
+func (f *fakePluginStore) Plugin(_ context.Context, pluginID string) (pluginstore.Plugin, bool) {
+	p, ok := f.plugins[pluginID]
+	return p, ok
+}
+
+type fakePluginResourceHandler struct {
+	requests []*backend.CallResourceRequest
+	status   int
+	body     []byte
+}
+
+func (f *fakePluginResourceHandler) CallResource(_ context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	f.requests = append(f.requests, req)
+	return sender.Send(&backend.CallResourceResponse{Status: f.status, Body: f.body})
+}
+
+func TestPluginNotificationClient_GetNotifier(t *testing.T) {
+	handler := &fakePluginResourceHandler{
+		status: http.StatusOK,
+		body:   []byte(`{"type":"jira","name":"Jira","options":[{"propertyName":"project","required":true},{"propertyName":"apiToken","secure":true}]}`),
+	}
+	client := NewPluginNotificationClient(handler, &fakePluginContextProvider{plugins: map[string]bool{"test-app": true, "test-datasource": true}}, newFakePluginStore())
+
+	n, err := client.GetNotifier(context.Background(), 1, "test-app")
+	require.NoError(t, err)
+	require.Equal(t, channels_config.PluginNotifierType, n.Type)
+	require.Equal(t, "Jira", n.Name)
+	require.Len(t, n.Options, 2)
+	require.True(t, n.Options[1].Secure)
+
+	require.Len(t, handler.requests, 1)
+	require.Equal(t, http.MethodGet, handler.requests[0].Method)
+	require.Equal(t, pluginNotifierPath, handler.requests[0].Path)
+	require.Equal(t, int64(1), handler.requests[0].PluginContext.OrgID)
+
+	_, err = client.GetNotifier(context.Background(), 1, "missing-app")
+	require.ErrorIs(t, err, plugins.ErrPluginNotRegistered)
+
+	_, err = client.GetNotifier(context.Background(), 1, "test-datasource")
+	require.ErrorIs(t, err, ErrNotNotifierPlugin)
+	require.Len(t, handler.requests, 1)
+}
+
+func TestBuildReceiverIntegrations_Plugin(t *testing.T) {
+	tmpl := alertingTemplates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost/grafana")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	newAM := func(client *PluginNotificationClient) *alertmanager {
+		return &alertmanager{
+			Settings:            &setting.Cfg{},
+			Store:               NewFakeConfigStore(t, nil),
+			decryptFn:           alertingNotify.NoopDecrypt,
+			orgID:               1,
+			logger:              log.NewNopLogger(),
+			pluginNotifications: client,
+		}
+	}
+	receiver := &alertingNotify.APIReceiver{
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{
+					UID:      "webhook-uid",
+					Name:     "test-receiver",
+					Type:     "webhook",
+					Settings: json.RawMessage(`{"url":"http://localhost"}`),
+				},
+				{
+					UID:            "plugin-uid",
+					Name:           "test-receiver",
+					Type:           channels_config.PluginNotifierType,
+					Settings:       json.RawMessage(`{"pluginId":"test-app","project":"OPS"}`),
+					SecureSettings: map[string]string{"apiToken": base64.StdEncoding.EncodeToString([]byte("secret"))},
+				},
+			},
+		},
+	}
+	receiver.Name = "test-receiver"
+
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+		EndsAt:   time.Now().Add(time.Hour),
+	}}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+
+	t.Run("should send notifications to the plugin", func(t *testing.T) {
+		handler := &fakePluginResourceHandler{status: http.StatusOK}
+		am := newAM(NewPluginNotificationClient(handler, &fakePluginContextProvider{plugins: map[string]bool{"test-app": true}}, newFakePluginStore()))
+
+		integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+		require.NoError(t, err)
+		require.Len(t, integrations, 2)
+		require.Equal(t, "webhook", integrations[0].Name())
+		require.Equal(t, channels_config.PluginNotifierType, integrations[1].Name())
+		require.Equal(t, 0, integrations[1].Index())
+
+		_, err = integrations[1].Notify(ctx, firing)
+		require.NoError(t, err)
+
+		require.Len(t, handler.requests, 1)
+		req := handler.requests[0]
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, pluginNotifyPath, req.Path)
+		require.Equal(t, "test-app", req.PluginContext.PluginID)
+		require.Equal(t, int64(1), req.PluginContext.OrgID)
+
+		var body PluginNotification
+		require.NoError(t, json.Unmarshal(req.Body, &body))
+		require.Equal(t, "{}:{alertname=\"test\"}", body.GroupKey)
+		require.Equal(t, int64(1), body.OrgID)
+		require.Equal(t, "alerting", body.State)
+		require.JSONEq(t, `{"pluginId":"test-app","project":"OPS"}`, string(body.Settings))
+		require.Equal(t, map[string]string{"apiToken": "secret"}, body.SecureSettings)
+		require.Len(t, body.Alerts, 1)
+	})
+
+	t.Run("should return plugin errors", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			status        int
+			expectedRetry bool
+		}{
+			{name: "bad request is not retried", status: http.StatusBadRequest, expectedRetry: false},
+			{name: "server error is retried", status: http.StatusInternalServerError, expectedRetry: true},
+			{name: "too many requests is retried", status: http.StatusTooManyRequests, expectedRetry: true},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				handler := &fakePluginResourceHandler{status: tc.status, body: []byte(`{"message":"project OPS not found"}`)}
+				am := newAM(NewPluginNotificationClient(handler, &fakePluginContextProvider{plugins: map[string]bool{"test-app": true}}, newFakePluginStore()))
+
+				integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+				require.NoError(t, err)
+
+				retry, err := integrations[1].Notify(ctx, firing)
+				require.Equal(t, tc.expectedRetry, retry)
+				var pluginErr PluginNotifierError
+				require.ErrorAs(t, err, &pluginErr)
+				require.Equal(t, tc.status, pluginErr.StatusCode)
+				require.Equal(t, "project OPS not found", pluginErr.Message)
+			})
+		}
+	})
+
+	t.Run("should fail if plugin ID is missing", func(t *testing.T) {
+		am := newAM(NewPluginNotificationClient(&fakePluginResourceHandler{}, &fakePluginContextProvider{}, newFakePluginStore()))
+		invalid := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{UID: "plugin-uid", Type: channels_config.PluginNotifierType, Settings: json.RawMessage(`{}`)},
+				},
+			},
+		}
+		_, err := am.buildReceiverIntegrations(invalid, tmpl)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("should fail if secure settings are not base64-encoded", func(t *testing.T) {
+		am := newAM(NewPluginNotificationClient(&fakePluginResourceHandler{}, &fakePluginContextProvider{}, newFakePluginStore()))
+		invalid := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{
+						UID:            "plugin-uid",
+						Type:           channels_config.PluginNotifierType,
+						Settings:       json.RawMessage(`{"pluginId":"test-app"}`),
+						SecureSettings: map[string]string{"apiToken": "not base64"},
+					},
+				},
+			},
+		}
+		_, err := am.buildReceiverIntegrations(invalid, tmpl)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("should fail if plugin notifications are not supported", func(t *testing.T) {
+		am := newAM(nil)
+		_, err := am.buildReceiverIntegrations(receiver, tmpl)
+		require.ErrorIs(t, err, ErrPluginNotifierNotSupported)
+	})
+}
@@ -410,6 +410,11 @@ func (p *redisPeer) Members() []string {
 	return p.members
 }
 
+// LocalMember returns the name of this peer as it appears in the list of members.
+func (p *redisPeer) LocalMember() string {
+	return p.withPrefix(p.name)
+}
+
 func (p *redisPeer) WaitReady(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,231 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+var (
+	ErrSilenceScheduleNotFound   = errutil.NotFound("alerting.notifications.silence-schedules.notFound")
+	ErrSilenceScheduleBadRequest = errutil.BadRequest("alerting.notifications.silence-schedules.badRequest")
+	ErrSilenceScheduleForbidden  = errutil.Forbidden("alerting.notifications.silence-schedules.forbidden")
+	ErrSilenceScheduleConflict   = errutil.Conflict("alerting.notifications.silence-schedules.conflict")
+)
+
+// SilenceScheduleService is the authenticated service for managing recurring silences.
+type SilenceScheduleService struct {
+	authz    SilenceAccessControlService
+	store    store.SilenceScheduleStore
+	silences SilenceStore
+	log      log.Logger
+	now      func() time.Time
+}
+
+func NewSilenceScheduleService(authz SilenceAccessControlService, store store.SilenceScheduleStore, silences SilenceStore, log log.Logger) *SilenceScheduleService {
+	return &SilenceScheduleService{
+		authz:    authz,
+		store:    store,
+		silences: silences,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// ListSilenceSchedules returns the schedules of the user's organization that the user can read.
+func (s *SilenceScheduleService) ListSilenceSchedules(ctx context.Context, user identity.Requester) ([]*models.SilenceSchedule, error) {
+	schedules, err := s.store.ListSilenceSchedules(ctx, models.ListSilenceSchedulesQuery{OrgID: user.GetOrgID()})
+	if err != nil {
+		return nil, err
+	}
+
+	// Schedules are authorized as the silences they create.
+	bySilence := make(map[*models.Silence]*models.SilenceSchedule, len(schedules))
+	silences := make([]*models.Silence, 0, len(schedules))
+	for _, schedule := range schedules {
+		silence := accessSilence(schedule.Matchers)
+		bySilence[silence] = schedule
+		silences = append(silences, silence)
+	}
+	allowed, err := s.authz.FilterByAccess(ctx, user, silences...)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.SilenceSchedule, 0, len(allowed))
+	for _, silence := range allowed {
+		result = append(result, bySilence[silence])
+	}
+	return result, nil
+}
+
+// GetSilenceSchedule returns the schedule by its UID.
+func (s *SilenceScheduleService) GetSilenceSchedule(ctx context.Context, user identity.Requester, uid string) (*models.SilenceSchedule, error) {
+	schedule, err := s.store.GetSilenceSchedule(ctx, user.GetOrgID(), uid)
+	if err != nil {
+		if errors.Is(err, models.ErrSilenceScheduleNotFound) {
+			return nil, WithPublicError(ErrSilenceScheduleNotFound.Errorf("silence schedule %s not found", uid))
+		}
+		return nil, err
+	}
+	if err := s.authz.AuthorizeReadSilence(ctx, user, accessSilence(schedule.Matchers)); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CreateSilenceSchedule creates a new schedule. If the schedule requires approval, it does not silence alerts until
+// it is approved by another user.
+func (s *SilenceScheduleService) CreateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) (string, error) {
+	if err := validateSilenceSchedule(schedule); err != nil {
+		return "", err
+	}
+	if err := s.authz.AuthorizeCreateSilence(ctx, user, accessSilence(schedule.Matchers)); err != nil {
+		return "", err
+	}
+
+	schedule.ID = 0
+	schedule.UID = ""
+	schedule.OrgID = user.GetOrgID()
+	schedule.SilenceID = ""
+	setScheduleAuthor(&schedule, user)
+	if err := s.store.InsertSilenceSchedule(ctx, &schedule); err != nil {
+		return "", err
+	}
+	return schedule.UID, nil
+}
+
+// UpdateSilenceSchedule replaces an existing schedule. The silence created by the previous version of the schedule
+// is expired. If the schedule requires approval, it must be approved again.
+func (s *SilenceScheduleService) UpdateSilenceSchedule(ctx context.Context, user identity.Requester, schedule models.SilenceSchedule) error {
+	if err := validateSilenceSchedule(schedule); err != nil {
+		return err
+	}
+	existing, err := s.GetSilenceSchedule(ctx, user, schedule.UID)
+	if err != nil {
+		return err
+	}
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, accessSilence(existing.Matchers)); err != nil {
+		return err
+	}
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, accessSilence(schedule.Matchers)); err != nil {
+		return err
+	}
+
+	s.expireSilence(ctx, existing)
+	schedule.ID = existing.ID
+	schedule.OrgID = existing.OrgID
+	schedule.Version = existing.Version
+	schedule.SilenceID = ""
+	setScheduleAuthor(&schedule, user)
+	return s.store.UpdateSilenceSchedule(ctx, &schedule)
+}
+
+// DeleteSilenceSchedule deletes the schedule and expires the silence it created.
+func (s *SilenceScheduleService) DeleteSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error {
+	existing, err := s.GetSilenceSchedule(ctx, user, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, accessSilence(existing.Matchers)); err != nil {
+		return err
+	}
+
+	s.expireSilence(ctx, existing)
+	return s.store.DeleteSilenceSchedule(ctx, existing.OrgID, existing.UID)
+}
+
+// ApproveSilenceSchedule approves a pending schedule. The user must have the role required by the schedule, must be
+// allowed to create and update the silences of the schedule, and must not be the user that created or last updated it.
+// The approval fails if the schedule is changed while it is approved,
+// so that the user only approves the version of the schedule they have checked.
+func (s *SilenceScheduleService) ApproveSilenceSchedule(ctx context.Context, user identity.Requester, uid string) error {
+	schedule, err := s.GetSilenceSchedule(ctx, user, uid)
+	if err != nil {
+		return err
+	}
+	if schedule.State == models.SilenceScheduleApproved {
+		return WithPublicError(ErrSilenceScheduleBadRequest.Errorf("silence schedule %s is already approved", uid))
+	}
+	if schedule.CreatedByUID == user.GetUID() {
+		return WithPublicError(ErrSilenceScheduleForbidden.Errorf("silence schedule %s must be approved by another user", uid))
+	}
+	if !user.GetOrgRole().Includes(identity.RoleType(schedule.RequiresApprovalBy)) {
+		return WithPublicError(ErrSilenceScheduleForbidden.Errorf("silence schedule %s must be approved by a user with role %s", uid, schedule.RequiresApprovalBy))
+	}
+	// The approved schedule creates and updates silences, so the approver must be allowed to do it.
+	if err := s.authz.AuthorizeCreateSilence(ctx, user, accessSilence(schedule.Matchers)); err != nil {
+		return err
+	}
+	if err := s.authz.AuthorizeUpdateSilence(ctx, user, accessSilence(schedule.Matchers)); err != nil {
+		return err
+	}
+
+	approved, err := s.store.ApproveSilenceSchedule(ctx, schedule.OrgID, schedule.UID, schedule.Version, user.GetLogin(), s.now())
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return WithPublicError(ErrSilenceScheduleConflict.Errorf("silence schedule %s was changed while it was approved, check it and try again", uid))
+	}
+	return nil
+}
+
+// expireSilence expires the silence created by the schedule, if any. Failures are logged because the silence
+// expires on its own at the end of the time interval.
+func (s *SilenceScheduleService) expireSilence(ctx context.Context, schedule *models.SilenceSchedule) {
+	if schedule.SilenceID == "" {
+		return
+	}
+	silence, err := s.silences.GetSilence(ctx, schedule.OrgID, schedule.SilenceID)
+	if err != nil {
+		if !errors.Is(err, ErrSilenceNotFound) {
+			s.log.Warn("Failed to get silence of silence schedule", "schedule", schedule.UID, "silenceID", schedule.SilenceID, "error", err)
+		}
+		return
+	}
+	if silence.Status == nil || silence.Status.State == nil || *silence.Status.State == amv2.SilenceStatusStateExpired {
+		return
+	}
+	if err := s.silences.DeleteSilence(ctx, schedule.OrgID, schedule.SilenceID); err != nil {
+		s.log.Warn("Failed to expire silence of silence schedule", "schedule", schedule.UID, "silenceID", schedule.SilenceID, "error", err)
+	}
+}
+
+func validateSilenceSchedule(schedule models.SilenceSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return WithPublicError(ErrSilenceScheduleBadRequest.Errorf("%s", err))
+	}
+	if schedule.RequiresApprovalBy != "" {
+		role := identity.RoleType(schedule.RequiresApprovalBy)
+		if !role.IsValid() || role == identity.RoleNone {
+			return WithPublicError(ErrSilenceScheduleBadRequest.Errorf("invalid approval role %q, must be one of %s, %s or %s", schedule.RequiresApprovalBy, identity.RoleViewer, identity.RoleEditor, identity.RoleAdmin))
+		}
+	}
+	return nil
+}
+
+// setScheduleAuthor records the user as the author of the schedule and resets its approval.
+func setScheduleAuthor(schedule *models.SilenceSchedule, user identity.Requester) {
+	schedule.CreatedBy = user.GetLogin()
+	schedule.CreatedByUID = user.GetUID()
+	schedule.ApprovedBy = ""
+	schedule.ApprovedAt = nil
+	schedule.State = models.SilenceScheduleApproved
+	if schedule.RequiresApprovalBy != "" {
+		schedule.State = models.SilenceSchedulePending
+	}
+}
+
+// accessSilence returns a silence with the given matchers. It is used to authorize access to silence schedules
+// with the same rules as silences.
+func accessSilence(matchers amv2.Matchers) *models.Silence {
+	return &models.Silence{Silence: amv2.Silence{Matchers: matchers}}
+}
@@ -0,0 +1,420 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	alertingModels "github.com/grafana/alerting/models"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/authz/zanzana"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceScheduler(t *testing.T) {
+	// Saturday 22:00 - 23:30 UTC.
+	intervals := mustTimeIntervals(t, `[{"times":[{"start_time":"22:00","end_time":"23:30"}],"weekdays":["saturday"]}]`)
+	saturday := time.Date(2024, time.June, 1, 22, 10, 0, 0, time.UTC)
+
+	newSchedule := func(state models.SilenceScheduleState) *models.SilenceSchedule {
+		return &models.SilenceSchedule{
+			UID:           "schedule",
+			OrgID:         1,
+			Matchers:      amv2.Matchers{{Name: util.Pointer("service"), Value: util.Pointer("db"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+			TimeIntervals: intervals,
+			CreatedBy:     "editor",
+			State:         state,
+		}
+	}
+
+	t.Run("should create a silence until the end of the active interval", func(t *testing.T) {
+		schedules := newFakeSilenceScheduleStore(newSchedule(models.SilenceScheduleApproved))
+		silences := newFakeSilenceStore()
+		scheduler := NewSilenceScheduler(schedules, silences, log.NewNopLogger())
+
+		scheduler.Reconcile(context.Background(), saturday)
+
+		require.Len(t, silences.silences, 1)
+		schedule := schedules.schedules["schedule"]
+		silence := silences.silences[schedule.SilenceID]
+		require.NotNil(t, silence)
+		require.Equal(t, time.Date(2024, time.June, 1, 23, 30, 0, 0, time.UTC), time.Time(*silence.EndsAt))
+		require.Equal(t, schedule.Matchers, silence.Matchers)
+
+		// The next reconciliation keeps the silence.
+		scheduler.Reconcile(context.Background(), saturday.Add(time.Minute))
+		require.Len(t, silences.silences, 1)
+	})
+
+	t.Run("should not create a silence outside of the interval", func(t *testing.T) {
+		schedules := newFakeSilenceScheduleStore(newSchedule(models.SilenceScheduleApproved))
+		silences := newFakeSilenceStore()
+		scheduler := NewSilenceScheduler(schedules, silences, log.NewNopLogger())
+
+		scheduler.Reconcile(context.Background(), saturday.Add(2*time.Hour))
+		require.Empty(t, silences.silences)
+	})
+
+	t.Run("should not create a silence for a pending schedule", func(t *testing.T) {
+		schedules := newFakeSilenceScheduleStore(newSchedule(models.SilenceSchedulePending))
+		silences := newFakeSilenceStore()
+		scheduler := NewSilenceScheduler(schedules, silences, log.NewNopLogger())
+
+		scheduler.Reconcile(context.Background(), saturday)
+		require.Empty(t, silences.silences)
+	})
+
+	t.Run("should extend the silence of a long interval", func(t *testing.T) {
+		schedule := newSchedule(models.SilenceScheduleApproved)
+		schedule.TimeIntervals = mustTimeIntervals(t, `[{"weekdays":["saturday","sunday"]}]`)
+		schedules := newFakeSilenceScheduleStore(schedule)
+		silences := newFakeSilenceStore()
+		scheduler := NewSilenceScheduler(schedules, silences, log.NewNopLogger())
+
+		start := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+		scheduler.Reconcile(context.Background(), start)
+		id := schedules.schedules["schedule"].SilenceID
+		require.Equal(t, start.Add(silenceScheduleLookahead), time.Time(*silences.silences[id].EndsAt))
+
+		now := start.Add(silenceScheduleLookahead - time.Hour)
+		silences.now = now
+		scheduler.Reconcile(context.Background(), now)
+		require.Equal(t, id, schedules.schedules["schedule"].SilenceID)
+		require.Equal(t, now.Add(silenceScheduleLookahead), time.Time(*silences.silences[id].EndsAt))
+	})
+
+	t.Run("should expire the new silence if the schedule changed concurrently", func(t *testing.T) {
+		schedules := newFakeSilenceScheduleStore(newSchedule(models.SilenceScheduleApproved))
+		schedules.concurrentSilenceID = "other"
+		silences := newFakeSilenceStore()
+		scheduler := NewSilenceScheduler(schedules, silences, log.NewNopLogger())
+
+		scheduler.Reconcile(context.Background(), saturday)
+		require.Len(t, silences.silences, 1)
+		for _, s := range silences.silences {
+			require.Equal(t, amv2.SilenceStatusStateExpired, *s.Status.State)
+		}
+	})
+}
+
+func TestSilenceScheduleService(t *testing.T) {
+	intervals := mustTimeIntervals(t, `[{"weekdays":["saturday"]}]`)
+	editor := &user.SignedInUser{UserUID: "editor", Login: "editor", OrgID: 1, OrgRole: identity.RoleEditor}
+	otherEditor := &user.SignedInUser{UserUID: "other-editor", Login: "other-editor", OrgID: 1, OrgRole: identity.RoleEditor}
+	admin := &user.SignedInUser{UserUID: "admin", Login: "admin", OrgID: 1, OrgRole: identity.RoleAdmin}
+
+	newService := func() (*SilenceScheduleService, *fakeSilenceScheduleStore) {
+		store := newFakeSilenceScheduleStore()
+		return NewSilenceScheduleService(&fakes.FakeSilenceService{}, store, newFakeSilenceStore(), log.NewNopLogger()), store
+	}
+	schedule := models.SilenceSchedule{
+		Matchers:           amv2.Matchers{{Name: util.Pointer("service"), Value: util.Pointer("db"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}},
+		TimeIntervals:      intervals,
+		RequiresApprovalBy: string(identity.RoleAdmin),
+	}
+
+	t.Run("schedule without approval is approved on creation", func(t *testing.T) {
+		svc, store := newService()
+		s := schedule
+		s.RequiresApprovalBy = ""
+		uid, err := svc.CreateSilenceSchedule(context.Background(), editor, s)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceScheduleApproved, store.schedules[uid].State)
+		require.Equal(t, "editor", store.schedules[uid].CreatedBy)
+	})
+
+	t.Run("schedule with approval must be approved by another user with the role", func(t *testing.T) {
+		svc, store := newService()
+		uid, err := svc.CreateSilenceSchedule(context.Background(), editor, schedule)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceSchedulePending, store.schedules[uid].State)
+
+		err = svc.ApproveSilenceSchedule(context.Background(), otherEditor, uid)
+		require.ErrorIs(t, err, ErrSilenceScheduleForbidden)
+
+		err = svc.ApproveSilenceSchedule(context.Background(), admin, uid)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceScheduleApproved, store.schedules[uid].State)
+		require.Equal(t, "admin", store.schedules[uid].ApprovedBy)
+
+		err = svc.ApproveSilenceSchedule(context.Background(), admin, uid)
+		require.ErrorIs(t, err, ErrSilenceScheduleBadRequest)
+	})
+
+	t.Run("approval fails if the schedule changed concurrently", func(t *testing.T) {
+		svc, store := newService()
+		uid, err := svc.CreateSilenceSchedule(context.Background(), editor, schedule)
+		require.NoError(t, err)
+
+		// the approver reads the schedule before it is updated
+		read := *store.schedules[uid]
+		s := schedule
+		s.UID = uid
+		s.Comment = "updated"
+		require.NoError(t, svc.UpdateSilenceSchedule(context.Background(), otherEditor, s))
+
+		approved, err := store.ApproveSilenceSchedule(context.Background(), read.OrgID, uid, read.Version, "admin", time.Now())
+		require.NoError(t, err)
+		require.False(t, approved)
+		require.Equal(t, models.SilenceSchedulePending, store.schedules[uid].State)
+	})
+
+	t.Run("author cannot approve their own schedule", func(t *testing.T) {
+		svc, _ := newService()
+		uid, err := svc.CreateSilenceSchedule(context.Background(), admin, schedule)
+		require.NoError(t, err)
+
+		err = svc.ApproveSilenceSchedule(context.Background(), admin, uid)
+		require.ErrorIs(t, err, ErrSilenceScheduleForbidden)
+	})
+
+	t.Run("update requires a new approval", func(t *testing.T) {
+		svc, store := newService()
+		uid, err := svc.CreateSilenceSchedule(context.Background(), editor, schedule)
+		require.NoError(t, err)
+		require.NoError(t, svc.ApproveSilenceSchedule(context.Background(), admin, uid))
+
+		s := schedule
+		s.UID = uid
+		s.Comment = "updated"
+		require.NoError(t, svc.UpdateSilenceSchedule(context.Background(), otherEditor, s))
+		require.Equal(t, models.SilenceSchedulePending, store.schedules[uid].State)
+		require.Empty(t, store.schedules[uid].ApprovedBy)
+		require.Equal(t, "other-editor", store.schedules[uid].CreatedBy)
+	})
+
+	t.Run("invalid schedules are rejected", func(t *testing.T) {
+		svc, _ := newService()
+		s := schedule
+		s.RequiresApprovalBy = "Owner"
+		_, err := svc.CreateSilenceSchedule(context.Background(), editor, s)
+		require.ErrorIs(t, err, ErrSilenceScheduleBadRequest)
+
+		s = schedule
+		s.TimeIntervals = nil
+		_, err = svc.CreateSilenceSchedule(context.Background(), editor, s)
+		require.ErrorIs(t, err, ErrSilenceScheduleBadRequest)
+	})
+
+	t.Run("get returns not found", func(t *testing.T) {
+		svc, _ := newService()
+		_, err := svc.GetSilenceSchedule(context.Background(), editor, "missing")
+		require.ErrorIs(t, err, ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("approver must be allowed to silence the rule of the schedule", func(t *testing.T) {
+		// The schedule silences a rule in folder-b.
+		ruleSchedule := schedule
+		ruleSchedule.RequiresApprovalBy = string(identity.RoleEditor)
+		ruleSchedule.Matchers = amv2.Matchers{{Name: util.Pointer(alertingModels.RuleUIDLabel), Value: util.Pointer("rule-b"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)}}
+		folderPermissions := func(read []string, write []string) map[int64]map[string][]string {
+			permissions := map[string][]string{}
+			for _, folderUID := range read {
+				scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+				permissions[accesscontrol.ActionAlertingSilencesRead] = append(permissions[accesscontrol.ActionAlertingSilencesRead], scope)
+			}
+			for _, folderUID := range write {
+				scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+				permissions[accesscontrol.ActionAlertingSilencesCreate] = append(permissions[accesscontrol.ActionAlertingSilencesCreate], scope)
+				permissions[accesscontrol.ActionAlertingSilencesWrite] = append(permissions[accesscontrol.ActionAlertingSilencesWrite], scope)
+			}
+			return map[int64]map[string][]string{1: permissions}
+		}
+		author := &user.SignedInUser{UserUID: "author", Login: "author", OrgID: 1, OrgRole: identity.RoleEditor,
+			Permissions: folderPermissions([]string{"folder-b"}, []string{"folder-b"})}
+		// The approver can read the silences of both folders but only silence the rules in folder-a.
+		otherFolderEditor := &user.SignedInUser{UserUID: "other-folder-editor", Login: "other-folder-editor", OrgID: 1, OrgRole: identity.RoleEditor,
+			Permissions: folderPermissions([]string{"folder-a", "folder-b"}, []string{"folder-a"})}
+		folderEditor := &user.SignedInUser{UserUID: "folder-editor", Login: "folder-editor", OrgID: 1, OrgRole: identity.RoleEditor,
+			Permissions: folderPermissions([]string{"folder-b"}, []string{"folder-b"})}
+
+		store := newFakeSilenceScheduleStore()
+		authz := ac.NewSilenceService(
+			acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient()),
+			fakeRuleNamespaceStore{"rule-b": "folder-b"},
+		)
+		svc := NewSilenceScheduleService(authz, store, newFakeSilenceStore(), log.NewNopLogger())
+		uid, err := svc.CreateSilenceSchedule(context.Background(), author, ruleSchedule)
+		require.NoError(t, err)
+
+		err = svc.ApproveSilenceSchedule(context.Background(), otherFolderEditor, uid)
+		require.ErrorIs(t, err, ac.ErrAuthorizationBase)
+		require.Equal(t, models.SilenceSchedulePending, store.schedules[uid].State)
+
+		require.NoError(t, svc.ApproveSilenceSchedule(context.Background(), folderEditor, uid))
+		require.Equal(t, models.SilenceScheduleApproved, store.schedules[uid].State)
+	})
+}
+
+// fakeRuleNamespaceStore maps the UIDs of rules to the UIDs of their folders.
+type fakeRuleNamespaceStore map[string]string
+
+func (f fakeRuleNamespaceStore) GetNamespacesByRuleUID(_ context.Context, _ int64, uids ...string) (map[string]string, error) {
+	result := make(map[string]string, len(uids))
+	for _, uid := range uids {
+		if ns, ok := f[uid]; ok {
+			result[uid] = ns
+		}
+	}
+	return result, nil
+}
+
+func mustTimeIntervals(t *testing.T, s string) []timeinterval.TimeInterval {
+	t.Helper()
+	var intervals []timeinterval.TimeInterval
+	require.NoError(t, json.Unmarshal([]byte(s), &intervals))
+	return intervals
+}
+
+type fakeSilenceScheduleStore struct {
+	schedules map[string]*models.SilenceSchedule
+	// concurrentSilenceID simulates a change of the schedule by another replica.
+	concurrentSilenceID string
+}
+
+func newFakeSilenceScheduleStore(schedules ...*models.SilenceSchedule) *fakeSilenceScheduleStore {
+	f := &fakeSilenceScheduleStore{schedules: map[string]*models.SilenceSchedule{}}
+	for _, s := range schedules {
+		f.schedules[s.UID] = s
+	}
+	return f
+}
+
+func (f *fakeSilenceScheduleStore) ListSilenceSchedules(_ context.Context, q models.ListSilenceSchedulesQuery) ([]*models.SilenceSchedule, error) {
+	var result []*models.SilenceSchedule
+	for _, s := range f.schedules {
+		if q.OrgID == 0 || s.OrgID == q.OrgID {
+			c := *s
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceScheduleStore) GetSilenceSchedule(_ context.Context, orgID int64, uid string) (*models.SilenceSchedule, error) {
+	s, ok := f.schedules[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrSilenceScheduleNotFound
+	}
+	c := *s
+	return &c, nil
+}
+
+func (f *fakeSilenceScheduleStore) InsertSilenceSchedule(_ context.Context, s *models.SilenceSchedule) error {
+	s.UID = fmt.Sprintf("schedule-%d", len(f.schedules)+1)
+	c := *s
+	f.schedules[s.UID] = &c
+	return nil
+}
+
+func (f *fakeSilenceScheduleStore) UpdateSilenceSchedule(_ context.Context, s *models.SilenceSchedule) error {
+	existing, ok := f.schedules[s.UID]
+	if !ok || existing.Version != s.Version {
+		return models.ErrSilenceScheduleNotFound
+	}
+	s.Version++
+	c := *s
+	f.schedules[s.UID] = &c
+	return nil
+}
+
+func (f *fakeSilenceScheduleStore) ApproveSilenceSchedule(_ context.Context, _ int64, uid string, version int64, approvedBy string, approvedAt time.Time) (bool, error) {
+	s, ok := f.schedules[uid]
+	if !ok || s.Version != version || s.State != models.SilenceSchedulePending {
+		return false, nil
+	}
+	s.State = models.SilenceScheduleApproved
+	s.ApprovedBy = approvedBy
+	s.ApprovedAt = &approvedAt
+	s.Version++
+	return true, nil
+}
+
+func (f *fakeSilenceScheduleStore) DeleteSilenceSchedule(_ context.Context, _ int64, uid string) error {
+	delete(f.schedules, uid)
+	return nil
+}
+
+func (f *fakeSilenceScheduleStore) SetSilenceScheduleSilenceID(_ context.Context, _ int64, uid string, oldID, newID string) (bool, error) {
+	s, ok := f.schedules[uid]
+	if !ok {
+		return false, nil
+	}
+	if f.concurrentSilenceID != "" {
+		s.SilenceID = f.concurrentSilenceID
+	}
+	if s.SilenceID != oldID {
+		return false, nil
+	}
+	s.SilenceID = newID
+	return true, nil
+}
+
+// fakeSilenceStore keeps silences in memory. Silences are active until they end at the time now.
+type fakeSilenceStore struct {
+	silences map[string]*models.Silence
+	now      time.Time
+}
+
+func newFakeSilenceStore() *fakeSilenceStore {
+	return &fakeSilenceStore{silences: map[string]*models.Silence{}}
+}
+
+func (f *fakeSilenceStore) ListSilences(_ context.Context, _ int64, _ []string) ([]*models.Silence, error) {
+	result := make([]*models.Silence, 0, len(f.silences))
+	for _, s := range f.silences {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceStore) GetSilence(_ context.Context, _ int64, id string) (*models.Silence, error) {
+	s, ok := f.silences[id]
+	if !ok {
+		return nil, WithPublicError(ErrSilenceNotFound.Errorf("silence %s not found", id))
+	}
+	c := *s
+	return &c, nil
+}
+
+func (f *fakeSilenceStore) CreateSilence(_ context.Context, _ int64, ps models.Silence) (string, error) {
+	id := ps.ID
+	if id == nil || *id == "" {
+		id = util.Pointer(fmt.Sprintf("silence-%d", len(f.silences)+1))
+	}
+	ps.ID = id
+	ps.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateActive)}
+	f.silences[*id] = &ps
+	return *id, nil
+}
+
+func (f *fakeSilenceStore) UpdateSilence(ctx context.Context, orgID int64, ps models.Silence) (string, error) {
+	return f.CreateSilence(ctx, orgID, ps)
+}
+
+func (f *fakeSilenceStore) DeleteSilence(_ context.Context, _ int64, id string) error {
+	s, ok := f.silences[id]
+	if !ok {
+		return WithPublicError(ErrSilenceNotFound.Errorf("silence %s not found", id))
+	}
+	s.EndsAt = util.Pointer(strfmt.DateTime(f.now))
+	s.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateExpired)}
+	return nil
+}
@@ -0,0 +1,130 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// silenceSchedulerInterval is how often the silence schedules are reconciled. It matches the resolution of time intervals.
+	silenceSchedulerInterval = time.Minute
+	// silenceScheduleLookahead is the maximum duration of a silence created by a schedule. Silences of longer time
+	// intervals are extended while the interval is active.
+	silenceScheduleLookahead = 24 * time.Hour
+)
+
+// SilenceScheduler creates silences for the silence schedules whose time intervals are active.
+type SilenceScheduler struct {
+	store    store.SilenceScheduleStore
+	silences SilenceStore
+	logger   log.Logger
+}
+
+func NewSilenceScheduler(store store.SilenceScheduleStore, silences SilenceStore, logger log.Logger) *SilenceScheduler {
+	return &SilenceScheduler{
+		store:    store,
+		silences: silences,
+		logger:   logger,
+	}
+}
+
+// Run reconciles the silence schedules every minute until the context is cancelled.
+func (s *SilenceScheduler) Run(ctx context.Context) error {
+	s.logger.Info("Starting silence scheduler")
+	ticker := time.NewTicker(silenceSchedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping silence scheduler")
+			return nil
+		case now := <-ticker.C:
+			s.Reconcile(ctx, now)
+		}
+	}
+}
+
+// Reconcile makes sure that every active silence schedule has a silence that lasts until the end of its time interval.
+func (s *SilenceScheduler) Reconcile(ctx context.Context, now time.Time) {
+	schedules, err := s.store.ListSilenceSchedules(ctx, models.ListSilenceSchedulesQuery{})
+	if err != nil {
+		s.logger.Error("Failed to list silence schedules", "error", err)
+		return
+	}
+	for _, schedule := range schedules {
+		if err := s.reconcile(ctx, schedule, now); err != nil {
+			s.logger.Error("Failed to reconcile silence schedule", "org", schedule.OrgID, "schedule", schedule.UID, "error", err)
+		}
+	}
+}
+
+func (s *SilenceScheduler) reconcile(ctx context.Context, schedule *models.SilenceSchedule, now time.Time) error {
+	if !schedule.IsActive(now) {
+		// The silence created for the previous interval expires on its own.
+		return nil
+	}
+	end := schedule.ActiveUntil(now, silenceScheduleLookahead)
+
+	current, err := s.activeSilence(ctx, schedule)
+	if err != nil {
+		return err
+	}
+
+	var silenceID string
+	if current != nil {
+		currentEnd := time.Time(*current.EndsAt)
+		if !currentEnd.Before(end) || currentEnd.Sub(now) > silenceScheduleLookahead/2 {
+			return nil
+		}
+		silence := schedule.Silence(time.Time(*current.StartsAt), end)
+		silence.ID = current.ID
+		if silenceID, err = s.silences.UpdateSilence(ctx, schedule.OrgID, silence); err != nil {
+			return err
+		}
+		s.logger.Debug("Extended silence of silence schedule", "org", schedule.OrgID, "schedule", schedule.UID, "silenceID", silenceID, "endsAt", end)
+	} else {
+		if silenceID, err = s.silences.CreateSilence(ctx, schedule.OrgID, schedule.Silence(now, end)); err != nil {
+			return err
+		}
+		s.logger.Info("Created silence for silence schedule", "org", schedule.OrgID, "schedule", schedule.UID, "silenceID", silenceID, "endsAt", end)
+	}
+
+	if silenceID == schedule.SilenceID {
+		return nil
+	}
+	ok, err := s.store.SetSilenceScheduleSilenceID(ctx, schedule.OrgID, schedule.UID, schedule.SilenceID, silenceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// The schedule was updated or reconciled by another replica in the meantime.
+		s.logger.Debug("Silence schedule changed concurrently, expiring the new silence", "org", schedule.OrgID, "schedule", schedule.UID, "silenceID", silenceID)
+		return s.silences.DeleteSilence(ctx, schedule.OrgID, silenceID)
+	}
+	return nil
+}
+
+// activeSilence returns the silence created by the schedule if it is still active, or nil.
+func (s *SilenceScheduler) activeSilence(ctx context.Context, schedule *models.SilenceSchedule) (*models.Silence, error) {
+	if schedule.SilenceID == "" {
+		return nil, nil
+	}
+	silence, err := s.silences.GetSilence(ctx, schedule.OrgID, schedule.SilenceID)
+	if err != nil {
+		if errors.Is(err, ErrSilenceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if silence.Status == nil || silence.Status.State == nil || *silence.Status.State != amv2.SilenceStatusStateActive {
+		return nil, nil
+	}
+	return silence, nil
+}
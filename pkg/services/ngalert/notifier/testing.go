@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +31,9 @@ type fakeConfigStore struct {
 
 	// notificationSettings stores notification settings by orgID.
 	notificationSettings map[int64]map[models.AlertRuleKey][]models.NotificationSettings
+
+	deliveriesMtx sync.Mutex
+	deliveries    []*models.NotificationDelivery
 }
 
 func (f *fakeConfigStore) ListNotificationSettings(ctx context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error) {
@@ -57,6 +62,47 @@ func (f *fakeConfigStore) ListNotificationSettings(ctx context.Context, q models
 	return settings, nil
 }
 
+func (f *fakeConfigStore) SaveNotificationDelivery(_ context.Context, delivery *models.NotificationDelivery) error {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	delivery.ID = int64(len(f.deliveries) + 1)
+	f.deliveries = append(f.deliveries, delivery)
+	return nil
+}
+
+func (f *fakeConfigStore) ListNotificationDeliveries(_ context.Context, q models.ListNotificationDeliveriesQuery) ([]*models.NotificationDelivery, error) {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	result := make([]*models.NotificationDelivery, 0)
+	for i := len(f.deliveries) - 1; i >= 0; i-- {
+		d := f.deliveries[i]
+		if d.OrgID != q.OrgID ||
+			(q.Receiver != "" && d.Receiver != q.Receiver) ||
+			(q.Integration != "" && d.Integration != q.Integration) ||
+			(q.Status != "" && d.Status != q.Status) ||
+			(q.AlertFingerprint != "" && !slices.Contains(d.AlertFingerprints, q.AlertFingerprint)) ||
+			(!q.From.IsZero() && d.SentAt.Before(q.From)) ||
+			(!q.To.IsZero() && d.SentAt.After(q.To)) {
+			continue
+		}
+		result = append(result, d)
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeConfigStore) DeleteNotificationDeliveriesBefore(_ context.Context, before time.Time) (int64, error) {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	n := len(f.deliveries)
+	f.deliveries = slices.DeleteFunc(f.deliveries, func(d *models.NotificationDelivery) bool {
+		return d.SentAt.Before(before)
+	})
+	return int64(n - len(f.deliveries)), nil
+}
+
 // Saves the image or returns an error.
 func (f *fakeConfigStore) SaveImage(ctx context.Context, img *models.Image) error {
 	return alertingImages.ErrImageNotFound
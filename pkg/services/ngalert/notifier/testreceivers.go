@@ -35,7 +35,8 @@ func (am *alertmanager) TestReceivers(ctx context.Context, c apimodels.TestRecei
 		alert = &alertingNotify.TestReceiversConfigAlertParams{Annotations: c.Alert.Annotations, Labels: c.Alert.Labels}
 	}
 
-	return am.Base.TestReceivers(ctx, alertingNotify.TestReceiversConfigBodyParams{
+	// Test notifications are not part of the history of notification deliveries.
+	return am.Base.TestReceivers(withoutDeliveryLog(ctx), alertingNotify.TestReceiversConfigBodyParams{
 		Alert:     alert,
 		Receivers: receivers,
 	})
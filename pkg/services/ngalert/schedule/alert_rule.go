@@ -15,6 +15,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -355,6 +357,10 @@ func (a *alertRule) Run() error {
 				states := a.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key), a.key, ngmodels.StateReasonRuleDeleted)
 				a.expireAndSend(grafanaCtx, states)
 			}
+			// the rule is now evaluated by another instance that loads the state from the database.
+			if errors.Is(grafanaCtx.Err(), errRuleNotOwned) {
+				a.stateManager.ForgetRule(a.key)
+			}
 			a.logger.Debug("Stopping alert rule routine")
 			return nil
 		}
@@ -371,7 +377,25 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 
 	start := a.clock.Now()
 
-	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
+	sender := func(ctx context.Context, statesToSend state.StateTransitions) {
+		start := a.clock.Now()
+		alerts := a.send(ctx, logger, statesToSend)
+		span.AddEvent("results sent", trace.WithAttributes(
+			attribute.Int64("alerts_sent", int64(len(alerts.PostableAlerts))),
+		))
+		sendDuration.Observe(a.clock.Now().Sub(start).Seconds())
+	}
+
+	// There is no need to query the data sources if all alert instances of the rule are suppressed by a firing dependency.
+	if reason, ok := a.stateManager.RuleSuppressedBy(ctx, e.scheduledAt, e.rule); ok {
+		logger.Debug("Skip evaluation because the rule is suppressed by a firing dependency", "reason", reason)
+		span.AddEvent("rule suppressed", trace.WithAttributes(attribute.String("reason", reason)))
+		_ = a.stateManager.ProcessSuppressedRule(ctx, e.scheduledAt, e.rule, reason, sender)
+		processDuration.Observe(a.clock.Now().Sub(start).Seconds())
+		return nil
+	}
+
+	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserForRule(e.rule), a.newLoadedMetricsReader(e.rule))
 	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
 	var results eval.Results
 	var dur time.Duration
@@ -444,14 +468,7 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		e.rule,
 		results,
 		state.GetRuleExtraLabels(logger, e.rule, e.folderTitle, !a.disableGrafanaFolder),
-		func(ctx context.Context, statesToSend state.StateTransitions) {
-			start := a.clock.Now()
-			alerts := a.send(ctx, logger, statesToSend)
-			span.AddEvent("results sent", trace.WithAttributes(
-				attribute.Int64("alerts_sent", int64(len(alerts.PostableAlerts))),
-			))
-			sendDuration.Observe(a.clock.Now().Sub(start).Seconds())
-		},
+		sender,
 	)
 	processDuration.Observe(a.clock.Now().Sub(start).Seconds())
 
@@ -508,6 +525,21 @@ func (a *alertRule) stopApplied() {
 	a.stopAppliedHook(a.key)
 }
 
+// SchedulerUserForRule returns the scheduler user with additional permissions to read the
+// alert rules, dashboards and dashboard annotations in the folder of the rule. This allows
+// rules to query the state of other rules in the same folder through the Grafana data source,
+// without reading anything outside of the folder of the rule.
+func SchedulerUserForRule(rule *ngmodels.AlertRule) *user.SignedInUser {
+	u := SchedulerUserFor(rule.OrgID)
+	folderScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID)
+	permissions := u.Permissions[rule.OrgID]
+	permissions[accesscontrol.ActionAlertingRuleRead] = []string{folderScope}
+	permissions[dashboards.ActionFoldersRead] = []string{folderScope}
+	permissions[dashboards.ActionDashboardsRead] = []string{folderScope}
+	permissions[accesscontrol.ActionAnnotationsRead] = []string{accesscontrol.ScopeAnnotationsTypeDashboard}
+	return u
+}
+
 func SchedulerUserFor(orgID int64) *user.SignedInUser {
 	return &user.SignedInUser{
 		UserID:           -1,
@@ -530,12 +530,12 @@ func withQueryForHealth(health string) models.AlertRuleMutator {
 	}
 }
 
-func setupWriter(t *testing.T, target *writer.TestRemoteWriteTarget, reg prometheus.Registerer) *writer.PrometheusWriter {
+func setupWriter(t *testing.T, target *writer.TestRemoteWriteTarget, reg prometheus.Registerer) *writer.DatasourceWriter {
 	provider := testClientProvider{}
 	m := metrics.NewNGAlert(reg)
 	wr, err := writer.NewPrometheusWriter(target.ClientSettings(), provider, clock.NewMock(), log.NewNopLogger(), m.GetRemoteWriterMetrics())
 	require.NoError(t, err)
-	return wr
+	return writer.NewDatasourceWriter(wr, nil, nil, target.ClientSettings(), clock.NewMock(), log.NewNopLogger(), m.GetRemoteWriterMetrics())
 }
 
 type testClientProvider struct{}
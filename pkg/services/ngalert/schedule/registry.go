@@ -18,6 +18,7 @@ import (
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	errRuleNotOwned  = errors.New("rule is evaluated by another instance")
 )
 
 type ruleFactory interface {
@@ -311,6 +312,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(setting.Fingerprint()))
 		writeBytes(tmp)
 	}
+	writeInt(rule.InstanceLimit)
 
 	// fields that do not affect the state.
 	// TODO consider removing fields below from the fingerprint
@@ -331,6 +333,21 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, dependency := range rule.Dependencies {
+		writeString(dependency.RuleUID)
+		names := make([]string, 0, len(dependency.Labels))
+		for name := range dependency.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			writeString(name)
+			writeString(dependency.Labels[name])
+		}
+		for _, name := range dependency.Equal {
+			writeString(name)
+		}
+	}
 
 	return fingerprint(sum.Sum64())
 }
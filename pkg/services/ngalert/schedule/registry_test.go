@@ -209,6 +209,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: false,
 				},
 			},
+			Dependencies: []models.RuleDependency{
+				{RuleUID: "dependency", Labels: map[string]string{"key": "value"}, Equal: []string{"label"}},
+			},
+			InstanceLimit: 10,
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -253,6 +257,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: true,
 				},
 			},
+			Dependencies: []models.RuleDependency{
+				{Labels: map[string]string{"key": "value2"}},
+			},
+			InstanceLimit: 20,
 		}
 
 		excludedFields := map[string]struct{}{
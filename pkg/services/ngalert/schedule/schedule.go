@@ -49,7 +49,9 @@ type RulesStore interface {
 }
 
 type RecordingWriter interface {
-	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	// WriteDatasource writes the result of a recording rule to the data source with the given UID, or to the
+	// default target if the UID is empty.
+	WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
 }
 
 type schedule struct {
@@ -100,6 +102,9 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// sharder decides which rules are evaluated by this instance. If nil, all rules are evaluated.
+	sharder *ruleSharder
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -119,6 +124,9 @@ type SchedulerCfg struct {
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	// ClusterMembership enables sharding of rule evaluation between the members of the cluster in high
+	// availability mode. Every instance evaluates only the rules assigned to it by a consistent hash ring.
+	ClusterMembership ngmodels.ClusterMembership
 }
 
 // NewScheduler returns a new scheduler.
@@ -149,6 +157,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		tracer:                cfg.Tracer,
 		recordingWriter:       cfg.RecordingWriter,
 	}
+	if cfg.ClusterMembership != nil {
+		sch.sharder = newRuleSharder(cfg.ClusterMembership)
+	}
 
 	return &sch
 }
@@ -248,6 +259,18 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	// this is the new current state. rulesDiff contains the previously existing rules that were different between this state and the previous state.
 	alertRules, folderTitles := sch.schedulableAlertRules.all()
 
+	// In sharded mode, the states of rules whose ownership moved to this instance are loaded from the database because
+	// they were evaluated by another instance. This is not needed on startup because the whole state cache is warmed.
+	sharded := sch.sharder != nil
+	membershipChanged, handOver := false, false
+	if sharded {
+		handOver = sch.sharder.ring != nil
+		membershipChanged = sch.sharder.sync()
+		if membershipChanged {
+			sch.log.Info("Cluster membership changed, rebalancing alert rules", "members", sch.sharder.members)
+		}
+	}
+
 	// registeredDefinitions is a map used for finding deleted alert rules
 	// initially it is assigned to all known alert rules from the previous cycle
 	// each alert rule found also in this cycle is removed
@@ -277,9 +300,20 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
 	)
+	notOwned := make([]ngmodels.AlertRuleKey, 0)
 	for _, item := range alertRules {
-		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		key := item.GetKey()
+		if sharded && !sch.sharder.owns(key) {
+			// The rule is evaluated by another instance. If it was evaluated by this instance, its routine is stopped below.
+			if _, ok := registeredDefinitions[key]; ok {
+				notOwned = append(notOwned, key)
+				delete(registeredDefinitions, key)
+			} else if membershipChanged {
+				sch.stateManager.ForgetRule(key)
+			}
+			continue
+		}
+		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
 		// enforce minimum evaluation interval
@@ -299,7 +333,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			warm := sharded && handOver
 			dispatcherGroup.Go(func() error {
+				if warm {
+					sch.stateManager.WarmRule(ctx, item)
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -387,6 +425,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// stop routines of the rules that are now evaluated by other instances. The state is kept in the database for the new owner.
+	for _, key := range notOwned {
+		if ruleRoutine, ok := sch.registry.del(key); ok {
+			ruleRoutine.Stop(errRuleNotOwned)
+		}
+	}
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
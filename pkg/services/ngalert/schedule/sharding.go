@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// tokensPerMember is the number of virtual nodes of every member in the hash ring.
+// More tokens spread the rules more evenly between members at the cost of a larger ring.
+const tokensPerMember = 128
+
+// hashRing is a consistent hash ring that assigns alert rules to the members of the cluster.
+// When a member joins or leaves the cluster, only the rules of that member are reassigned.
+type hashRing struct {
+	tokens []uint32
+	owners []string
+}
+
+func newHashRing(members []string) *hashRing {
+	type token struct {
+		value uint32
+		owner string
+	}
+	tokens := make([]token, 0, len(members)*tokensPerMember)
+	for _, member := range members {
+		for i := 0; i < tokensPerMember; i++ {
+			tokens = append(tokens, token{value: hashString(member + "-" + strconv.Itoa(i)), owner: member})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].value == tokens[j].value {
+			return tokens[i].owner < tokens[j].owner
+		}
+		return tokens[i].value < tokens[j].value
+	})
+
+	r := &hashRing{
+		tokens: make([]uint32, len(tokens)),
+		owners: make([]string, len(tokens)),
+	}
+	for i, t := range tokens {
+		r.tokens[i] = t.value
+		r.owners[i] = t.owner
+	}
+	return r
+}
+
+// owner returns the member that owns the rule, that is the owner of the first token after the hash of the rule key.
+func (r *hashRing) owner(key ngmodels.AlertRuleKey) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hashString(strconv.FormatInt(key.OrgID, 10) + "/" + key.UID)
+	idx := sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i] >= h })
+	if idx == len(r.tokens) {
+		idx = 0
+	}
+	return r.owners[idx]
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// ruleSharder decides which alert rules are evaluated by this instance when the evaluation is sharded between
+// the members of the cluster.
+type ruleSharder struct {
+	membership ngmodels.ClusterMembership
+	local      string
+	members    []string
+	ring       *hashRing
+}
+
+func newRuleSharder(membership ngmodels.ClusterMembership) *ruleSharder {
+	return &ruleSharder{membership: membership}
+}
+
+// sync updates the hash ring with the current members of the cluster. It returns true if the membership changed
+// since the last call, and therefore the ownership of rules could have changed.
+// If the local instance is not a member of the cluster, for example when the cluster has not settled yet or
+// the instance lost connection to its peers, it owns all rules. Evaluating a rule twice is preferred over not
+// evaluating it at all, because the notifications are deduplicated by the clustered Alertmanager.
+func (s *ruleSharder) sync() bool {
+	local := s.membership.LocalMember()
+	members := slices.Clone(s.membership.Members())
+	sort.Strings(members)
+	members = slices.Compact(members)
+	if !slices.Contains(members, local) {
+		members = nil
+	}
+
+	if s.ring != nil && local == s.local && slices.Equal(members, s.members) {
+		return false
+	}
+	s.local = local
+	s.members = members
+	s.ring = newHashRing(members)
+	return true
+}
+
+// owns returns true if the rule is evaluated by this instance.
+func (s *ruleSharder) owns(key ngmodels.AlertRuleKey) bool {
+	if s.ring == nil || len(s.members) == 0 {
+		return true
+	}
+	return s.ring.owner(key) == s.local
+}
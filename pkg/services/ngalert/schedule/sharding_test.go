@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeClusterMembership struct {
+	local   string
+	members []string
+}
+
+func (f *fakeClusterMembership) LocalMember() string { return f.local }
+func (f *fakeClusterMembership) Members() []string   { return f.members }
+
+func TestHashRing(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, models.GenerateRuleKey(1))
+	}
+
+	ring := newHashRing([]string{"a", "b", "c"})
+	owners := make(map[models.AlertRuleKey]string, len(keys))
+	counts := make(map[string]int)
+	for _, key := range keys {
+		owner := ring.owner(key)
+		require.Equal(t, owner, ring.owner(key), "owner of a rule must be stable")
+		owners[key] = owner
+		counts[owner]++
+	}
+	require.Len(t, counts, 3)
+	for member, count := range counts {
+		require.Greaterf(t, count, 200, "member %s owns too few rules", member)
+	}
+
+	t.Run("should move rules only to the new member", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c", "d"})
+		moved := 0
+		for _, key := range keys {
+			owner := ring.owner(key)
+			if owner != owners[key] {
+				require.Equal(t, "d", owner)
+				moved++
+			}
+		}
+		require.Positive(t, moved)
+	})
+
+	t.Run("should move only rules of the member that left", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "c"})
+		for _, key := range keys {
+			if owners[key] != "b" {
+				require.Equal(t, owners[key], ring.owner(key))
+			}
+		}
+	})
+}
+
+func TestRuleSharder(t *testing.T) {
+	membership := &fakeClusterMembership{local: "a", members: []string{"b", "a"}}
+	sharder := newRuleSharder(membership)
+
+	require.True(t, sharder.sync(), "first sync should report a change")
+	require.False(t, sharder.sync())
+	require.Equal(t, []string{"a", "b"}, sharder.members)
+
+	membership.members = []string{"a", "b", "c"}
+	require.True(t, sharder.sync())
+
+	t.Run("should own all rules if local instance is not a member", func(t *testing.T) {
+		membership.members = []string{"b", "c"}
+		require.True(t, sharder.sync())
+		for i := 0; i < 100; i++ {
+			require.True(t, sharder.owns(models.GenerateRuleKey(1)))
+		}
+	})
+}
+
+func TestProcessTickSharded(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	membership := &fakeClusterMembership{local: "a", members: []string{"a", "b"}}
+	sch.sharder = newRuleSharder(membership)
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second), withQueryForState(t, eval.Normal)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	ownedBy := func(member string) map[models.AlertRuleKey]struct{} {
+		ring := newHashRing([]string{"a", "b"})
+		result := make(map[models.AlertRuleKey]struct{})
+		for _, rule := range rules {
+			if ring.owner(rule.GetKey()) == member {
+				result[rule.GetKey()] = struct{}{}
+			}
+		}
+		return result
+	}
+	owned := ownedBy("a")
+	require.NotEmpty(t, owned)
+	require.Less(t, len(owned), len(rules))
+
+	tick := time.Time{}.Add(time.Second)
+	scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, len(owned))
+	for _, item := range scheduled {
+		require.Contains(t, owned, item.rule.GetKey())
+	}
+
+	t.Run("should take over rules of the member that left", func(t *testing.T) {
+		membership.members = []string{"a"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, len(rules))
+		require.Empty(t, stopped)
+	})
+
+	t.Run("should stop rules that are owned by the member that joined", func(t *testing.T) {
+		membership.members = []string{"a", "b"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, len(owned))
+		require.Empty(t, stopped, "rules owned by other members must not be deleted")
+		for _, rule := range rules {
+			_, isOwned := owned[rule.GetKey()]
+			require.Equal(t, isOwned, sch.registry.exists(rule.GetKey()))
+		}
+		all, _ := sch.Rules()
+		require.Len(t, all, len(rules))
+	})
+}
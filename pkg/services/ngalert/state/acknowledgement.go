@@ -0,0 +1,229 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// acknowledgementSilenceMaxDuration is the duration of the silence of an acknowledgement that does not expire. The
+// silence is deleted earlier when the alert instance resolves or the acknowledgement is removed.
+const acknowledgementSilenceMaxDuration = 365 * 24 * time.Hour
+
+var errSilencesUnavailable = errors.New("silences are not available")
+
+type acknowledgementKey struct {
+	orgID   int64
+	ruleUID string
+	cacheID data.Fingerprint
+}
+
+// AcknowledgeInstance acknowledges the alert instance of the rule with the given fingerprint. Only alert instances
+// that are not Normal or Pending can be acknowledged. The acknowledgement lasts until it expires or the alert instance
+// resolves. It is persisted with the state and recorded in the state history. If the acknowledgement suppresses
+// notifications, a silence that matches the alert instance is created in the Alertmanager, so that the alert
+// instance is still sent to the Alertmanager and does not resolve there.
+// When the evaluation of rules is sharded and the rule is evaluated by another instance, the acknowledgement is saved
+// in the instance store, from where the instance that evaluates the rule takes it over.
+func (st *Manager) AcknowledgeInstance(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint, ack ngModels.AlertInstanceAcknowledgement) (*State, error) {
+	return st.setAcknowledgement(ctx, rule, fingerprint, &ack)
+}
+
+// UnacknowledgeInstance removes the acknowledgement of the alert instance of the rule with the given fingerprint.
+func (st *Manager) UnacknowledgeInstance(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint) (*State, error) {
+	return st.setAcknowledgement(ctx, rule, fingerprint, nil)
+}
+
+func (st *Manager) setAcknowledgement(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint, ack *ngModels.AlertInstanceAcknowledgement) (*State, error) {
+	ctx, span := st.tracer.Start(ctx, "set alert instance acknowledgement", trace.WithAttributes(
+		attribute.String("rule_uid", rule.UID),
+		attribute.Int64("org_id", rule.OrgID),
+		attribute.String("fingerprint", fingerprint.String()),
+		attribute.Bool("acknowledged", ack != nil)))
+	defer span.End()
+
+	remote := st.isRemoteRule(rule.GetKey())
+	var current *State
+	if remote {
+		var err error
+		current, err = st.getStoredState(ctx, rule, fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the alert instance: %w", err)
+		}
+	} else {
+		current = st.cache.get(rule.OrgID, rule.UID, fingerprint)
+	}
+	if current == nil {
+		return nil, ngModels.ErrAlertInstanceNotFound
+	}
+	if ack != nil && !canAcknowledge(current.State) {
+		return nil, ngModels.ErrAlertInstanceNotFiring
+	}
+	logger := st.log.FromContext(ctx).New("rule_uid", rule.UID, "org_id", rule.OrgID, "instance", current.Labels)
+
+	if ack != nil && ack.SuppressNotifications {
+		silenceID, err := st.createAcknowledgementSilence(ctx, current, *ack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to silence the notifications of the alert instance: %w", err)
+		}
+		ack.SilenceID = silenceID
+	}
+
+	next := *current
+	next.Acknowledgement = ack
+	if remote {
+		st.deleteAcknowledgementSilence(ctx, logger, rule.OrgID, current.Acknowledgement)
+		st.storedMtx.Lock()
+		delete(st.storedStates, rule.OrgID)
+		st.storedMtx.Unlock()
+	} else {
+		// The state can be updated by the evaluation of the rule at the same time. Therefore, the acknowledgement is
+		// applied to a copy of the state, and it is also kept until the next evaluation of the rule applies it to the
+		// state it works with. This way, the acknowledgement is not lost if the evaluation overwrites the copy in the
+		// cache.
+		key := acknowledgementKey{orgID: rule.OrgID, ruleUID: rule.UID, cacheID: fingerprint}
+		st.ackMtx.Lock()
+		previous, ok := st.acknowledgements[key]
+		if !ok {
+			previous = current.Acknowledgement
+		}
+		st.acknowledgements[key] = ack
+		st.ackMtx.Unlock()
+		st.deleteAcknowledgementSilence(ctx, logger, rule.OrgID, previous)
+		st.cache.set(&next)
+	}
+
+	transitions := StateTransitions{{
+		State:                  &next,
+		PreviousState:          current.State,
+		PreviousStateReason:    current.StateReason,
+		AcknowledgementChanged: true,
+	}}
+	logger.Debug("Alert instance acknowledgement changed", "acknowledged", ack != nil)
+
+	st.persister.Sync(ctx, span, transitions)
+	if st.historian != nil {
+		st.historian.Record(ctx, history_model.NewRuleMeta(rule, logger), transitions)
+	}
+	return &next, nil
+}
+
+// updateAcknowledgement applies the acknowledgement set since the last evaluation to the state, and removes it if it
+// has expired or the alert instance resolved. It returns true if the acknowledgement was removed.
+func (st *Manager) updateAcknowledgement(ctx context.Context, logger log.Logger, s *State, now time.Time) bool {
+	if ack, ok := st.takeAcknowledgement(s); ok {
+		s.Acknowledgement = ack
+	}
+	if s.Acknowledgement == nil || (canAcknowledge(s.State) && !s.Acknowledgement.IsExpired(now)) {
+		return false
+	}
+	st.deleteAcknowledgementSilence(ctx, logger, s.OrgID, s.Acknowledgement)
+	s.Acknowledgement = nil
+	return true
+}
+
+// takeAcknowledgement returns the acknowledgement set for the state since the last evaluation, if any.
+func (st *Manager) takeAcknowledgement(s *State) (*ngModels.AlertInstanceAcknowledgement, bool) {
+	st.ackMtx.Lock()
+	defer st.ackMtx.Unlock()
+	key := acknowledgementKey{orgID: s.OrgID, ruleUID: s.AlertRuleUID, cacheID: s.CacheID}
+	ack, ok := st.acknowledgements[key]
+	if ok {
+		delete(st.acknowledgements, key)
+	}
+	return ack, ok
+}
+
+// forgetAcknowledgements drops the acknowledgements of the rule that have not been applied yet, and returns them.
+func (st *Manager) forgetAcknowledgements(ruleKey ngModels.AlertRuleKey) []*ngModels.AlertInstanceAcknowledgement {
+	st.ackMtx.Lock()
+	defer st.ackMtx.Unlock()
+	var result []*ngModels.AlertInstanceAcknowledgement
+	for key, ack := range st.acknowledgements {
+		if key.orgID == ruleKey.OrgID && key.ruleUID == ruleKey.UID {
+			delete(st.acknowledgements, key)
+			if ack != nil {
+				result = append(result, ack)
+			}
+		}
+	}
+	return result
+}
+
+// createAcknowledgementSilence creates a silence that matches the alert instance of the state until the
+// acknowledgement expires, and returns its ID.
+func (st *Manager) createAcknowledgementSilence(ctx context.Context, s *State, ack ngModels.AlertInstanceAcknowledgement) (string, error) {
+	if st.silencer == nil {
+		return "", errSilencesUnavailable
+	}
+
+	matchers := make(amv2.Matchers, 0, len(s.Labels))
+	for name, value := range s.Labels {
+		// The alert name is replaced in the alerts of NoData and Error states.
+		if name == model.AlertNameLabel {
+			continue
+		}
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    util.Pointer(name),
+			Value:   util.Pointer(value),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		})
+	}
+	sort.Slice(matchers, func(i, j int) bool {
+		return *matchers[i].Name < *matchers[j].Name
+	})
+
+	endsAt := ack.At.Add(acknowledgementSilenceMaxDuration)
+	if ack.ExpiresAt != nil {
+		endsAt = *ack.ExpiresAt
+	}
+	createdBy := ack.By
+	if createdBy == "" {
+		createdBy = "grafana"
+	}
+	comment := fmt.Sprintf("Alert instance acknowledged by %s", createdBy)
+	if ack.Comment != "" {
+		comment = fmt.Sprintf("%s: %s", comment, ack.Comment)
+	}
+
+	return st.silencer.CreateSilence(ctx, s.OrgID, ngModels.Silence{
+		Silence: amv2.Silence{
+			Comment:   util.Pointer(comment),
+			CreatedBy: util.Pointer(createdBy),
+			StartsAt:  util.Pointer(strfmt.DateTime(ack.At)),
+			EndsAt:    util.Pointer(strfmt.DateTime(endsAt)),
+			Matchers:  matchers,
+		},
+	})
+}
+
+// deleteAcknowledgementSilence deletes the silence of the acknowledgement, if it has one that has not expired yet.
+func (st *Manager) deleteAcknowledgementSilence(ctx context.Context, logger log.Logger, orgID int64, ack *ngModels.AlertInstanceAcknowledgement) {
+	if ack == nil || ack.SilenceID == "" || st.silencer == nil || ack.IsExpired(st.clock.Now()) {
+		return
+	}
+	if err := st.silencer.DeleteSilence(ctx, orgID, ack.SilenceID); err != nil {
+		logger.Warn("Failed to delete the silence of the alert instance acknowledgement", "silenceID", ack.SilenceID, "error", err)
+	}
+}
+
+// canAcknowledge returns true if an alert instance in the given state can be acknowledged.
+func canAcknowledge(s eval.State) bool {
+	return s != eval.Normal && s != eval.Pending
+}
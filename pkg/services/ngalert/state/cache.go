@@ -52,6 +52,7 @@ func (c *cache) RegisterMetrics(r prometheus.Registerer) {
 	r.MustRegister(newAlertCountByState(eval.Pending))
 	r.MustRegister(newAlertCountByState(eval.Error))
 	r.MustRegister(newAlertCountByState(eval.NoData))
+	r.MustRegister(newAlertCountByState(eval.Suppressed))
 }
 
 func (c *cache) countAlertsBy(state eval.State) float64 {
@@ -71,11 +72,11 @@ func (c *cache) countAlertsBy(state eval.State) float64 {
 	return count
 }
 
-func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
+func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL, queryFunc template.QueryFunc) *State {
 	// Calculation of state ID involves label and annotation expansion, which may be resource intensive operations, and doing it in the context guarded by mtxStates may create a lot of contention.
 	// Instead of just calculating ID we create an entire state - a candidate. If rule states already hold a state with this ID, this candidate will be discarded and the existing one will be returned.
 	// Otherwise, this candidate will be added to the rule states and returned.
-	stateCandidate := calculateState(ctx, log, alertRule, result, extraLabels, externalURL)
+	stateCandidate := calculateState(ctx, log, alertRule, result, extraLabels, externalURL, queryFunc)
 
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -123,7 +124,7 @@ func (rs *ruleStates) getOrAdd(stateCandidate State, log log.Logger) *State {
 	return state
 }
 
-func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) State {
+func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL, queryFunc template.QueryFunc) State {
 	var reserved []string
 	resultLabels := result.Instance
 	if len(resultLabels) > 0 {
@@ -157,8 +158,8 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 
 	// For now, do nothing with these errors as they are already logged in expand.
 	// In the future, we want to show these errors to the user somehow.
-	labels, _ := expand(ctx, log, alertRule.Title, alertRule.Labels, templateData, externalURL, result.EvaluatedAt)
-	annotations, _ := expand(ctx, log, alertRule.Title, alertRule.Annotations, templateData, externalURL, result.EvaluatedAt)
+	labels, _ := expand(ctx, log, alertRule.Title, alertRule.Labels, templateData, externalURL, result.EvaluatedAt, queryFunc)
+	annotations, _ := expand(ctx, log, alertRule.Title, alertRule.Annotations, templateData, externalURL, result.EvaluatedAt, queryFunc)
 
 	lbs := make(data.Labels, len(extraLabels)+len(labels)+len(resultLabels))
 	dupes := make(data.Labels)
@@ -215,13 +216,13 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 // If a template cannot be expanded due to an error in the template the original template is
 // maintained and an error is added to the multierror. All errors in the multierror are
 // template.ExpandError errors.
-func expand(ctx context.Context, log log.Logger, name string, original map[string]string, data template.Data, externalURL *url.URL, evaluatedAt time.Time) (map[string]string, error) {
+func expand(ctx context.Context, log log.Logger, name string, original map[string]string, data template.Data, externalURL *url.URL, evaluatedAt time.Time, queryFunc template.QueryFunc) (map[string]string, error) {
 	var (
 		errs     error
 		expanded = make(map[string]string, len(original))
 	)
 	for k, v := range original {
-		result, err := template.Expand(ctx, name, v, data, externalURL, evaluatedAt)
+		result, err := template.Expand(ctx, name, v, data, externalURL, evaluatedAt, queryFunc)
 		if err != nil {
 			log.Error("Error in expanding template", "error", err)
 			errs = errors.Join(errs, err)
@@ -261,6 +262,16 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+// setRuleStates replaces all states of the rule.
+func (c *cache) setRuleStates(ruleKey ngModels.AlertRuleKey, rs *ruleStates) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[ruleKey.OrgID]; !ok {
+		c.states[ruleKey.OrgID] = make(map[string]*ruleStates)
+	}
+	c.states[ruleKey.OrgID][ruleKey.UID] = rs
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -353,32 +364,50 @@ func (c *cache) asInstances(skipNormalState bool) []ngModels.AlertInstance {
 	defer c.mtxStates.RUnlock()
 	for _, orgStates := range c.states {
 		for _, v1 := range orgStates {
-			for _, v2 := range v1.states {
-				if skipNormalState && IsNormalStateWithNoReason(v2) {
-					continue
-				}
-				key, err := v2.GetAlertInstanceKey()
-				if err != nil {
-					continue
-				}
-				states = append(states, ngModels.AlertInstance{
-					AlertInstanceKey:  key,
-					Labels:            ngModels.InstanceLabels(v2.Labels),
-					CurrentState:      ngModels.InstanceStateType(v2.State.String()),
-					CurrentReason:     v2.StateReason,
-					LastEvalTime:      v2.LastEvaluationTime,
-					CurrentStateSince: v2.StartsAt,
-					CurrentStateEnd:   v2.EndsAt,
-					ResolvedAt:        v2.ResolvedAt,
-					LastSentAt:        v2.LastSentAt,
-					ResultFingerprint: v2.ResultFingerprint.String(),
-				})
-			}
+			states = v1.appendInstances(states, skipNormalState)
 		}
 	}
 	return states
 }
 
+// ruleInstances returns the alert instances of the rule.
+func (c *cache) ruleInstances(ruleKey ngModels.AlertRuleKey, skipNormalState bool) []ngModels.AlertInstance {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	rs, ok := c.states[ruleKey.OrgID][ruleKey.UID]
+	if !ok {
+		return nil
+	}
+	return rs.appendInstances(make([]ngModels.AlertInstance, 0, len(rs.states)), skipNormalState)
+}
+
+func (rs *ruleStates) appendInstances(instances []ngModels.AlertInstance, skipNormalState bool) []ngModels.AlertInstance {
+	for _, v2 := range rs.states {
+		if skipNormalState && IsNormalStateWithNoReason(v2) {
+			continue
+		}
+		key, err := v2.GetAlertInstanceKey()
+		if err != nil {
+			continue
+		}
+		instance := ngModels.AlertInstance{
+			AlertInstanceKey:  key,
+			Labels:            ngModels.InstanceLabels(v2.Labels),
+			CurrentState:      ngModels.InstanceStateType(v2.State.String()),
+			CurrentReason:     v2.StateReason,
+			LastEvalTime:      v2.LastEvaluationTime,
+			CurrentStateSince: v2.StartsAt,
+			CurrentStateEnd:   v2.EndsAt,
+			ResolvedAt:        v2.ResolvedAt,
+			LastSentAt:        v2.LastSentAt,
+			ResultFingerprint: v2.ResultFingerprint.String(),
+		}
+		instance.SetAcknowledgement(v2.Acknowledgement)
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
 // if duplicate labels exist, keep the value from the first set
 func mergeLabels(a, b data.Labels) data.Labels {
 	newLbs := make(data.Labels, len(a)+len(b))
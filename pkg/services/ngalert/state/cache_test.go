@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -28,7 +29,7 @@ func Test_expand(t *testing.T) {
 	// If the expand function forgets to use ErrorOrNil() then the error returned will
 	// be non-nil even if no errors have been added to the multierror.
 	t.Run("err is nil if there are no errors", func(t *testing.T) {
-		result, err := expand(ctx, logger, "test", map[string]string{}, template.Data{}, nil, time.Now())
+		result, err := expand(ctx, logger, "test", map[string]string{}, template.Data{}, nil, time.Now(), nil)
 		require.NoError(t, err)
 		require.Len(t, result, 0)
 	})
@@ -37,7 +38,7 @@ func Test_expand(t *testing.T) {
 		original := map[string]string{"Summary": `Instance {{ $labels.instance }} has been down for more than 5 minutes`}
 		expected := map[string]string{"Summary": "Instance host1 has been down for more than 5 minutes"}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NoError(t, err)
 		require.Equal(t, expected, results)
 	})
@@ -47,7 +48,7 @@ func Test_expand(t *testing.T) {
 			"Summary": `Instance {{ $labels. }} has been down for more than 5 minutes`,
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, original, results)
 
@@ -63,7 +64,7 @@ func Test_expand(t *testing.T) {
 			"Description": "The instance has been down for {{ $value minutes, please check the instance is online",
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, original, results)
 
@@ -100,7 +101,7 @@ func Test_expand(t *testing.T) {
 			"Description": "The instance has been down for {{ $value minutes, please check the instance is online",
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, expected, results)
 
@@ -136,7 +137,7 @@ func Test_getOrCreate(t *testing.T) {
 		result := eval.Result{
 			Instance: models.GenerateAlertLabels(5, "result-"),
 		}
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -151,6 +152,18 @@ func Test_getOrCreate(t *testing.T) {
 			assert.Equal(t, expected, state.Labels[key])
 		}
 	})
+	t.Run("should run queries in labels and annotations", func(t *testing.T) {
+		rule := generateRule()
+		rule.Labels = map[string]string{"pods": `{{ query "up" | first | value }}`}
+		rule.Annotations = map[string]string{"pods": `{{ query "up" | first | value }}`}
+		queryFunc := func(context.Context, string, time.Time) (promql.Vector, error) {
+			return promql.Vector{{F: 3}}, nil
+		}
+
+		state := c.getOrCreate(context.Background(), l, rule, eval.Result{}, nil, url, queryFunc)
+		require.Equal(t, "3", state.Labels["pods"])
+		require.Equal(t, "3", state.Annotations["pods"])
+	})
 	t.Run("extra labels should take precedence over rule and result labels", func(t *testing.T) {
 		rule := generateRule()
 
@@ -164,7 +177,7 @@ func Test_getOrCreate(t *testing.T) {
 			result.Instance[key] = "result-" + util.GenerateShortUID()
 		}
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -180,7 +193,7 @@ func Test_getOrCreate(t *testing.T) {
 		for key := range rule.Labels {
 			result.Instance[key] = "result-" + util.GenerateShortUID()
 		}
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range rule.Labels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -202,7 +215,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule.Labels = labelTemplates
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			assert.Equal(t, expected, state.Labels["rule-"+key])
 		}
@@ -229,7 +242,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule.Annotations = annotationTemplates
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			assert.Equal(t, expected, state.Annotations["rule-"+key])
 		}
@@ -260,7 +273,7 @@ func Test_getOrCreate(t *testing.T) {
 
 		rule := generateRule()
 
-		state := c.getOrCreate(context.Background(), l, rule, result, nil, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, nil, url, nil)
 
 		for key := range models.LabelsUserCannotSpecify {
 			assert.NotContains(t, state.Labels, key)
@@ -282,7 +295,7 @@ func Test_getOrCreate(t *testing.T) {
 			result.Instance["label1_user"] = uuid.NewString()
 			result.Instance["label4_user"] = uuid.NewString()
 
-			state = c.getOrCreate(context.Background(), l, rule, result, nil, url)
+			state = c.getOrCreate(context.Background(), l, rule, result, nil, url, nil)
 			assert.NotContains(t, state.Labels, "__label1__")
 			assert.Contains(t, state.Labels, "label1")
 			assert.Equal(t, state.Labels["label1"], result.Instance["label1"])
@@ -0,0 +1,90 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// dependencyStates contains the firing alert instances of every dependency of a rule.
+type dependencyStates struct {
+	dependencies []ngModels.RuleDependency
+	firing       [][]*State
+}
+
+// getDependencyStates returns the firing alert instances of the dependencies of the rule. When the evaluation of rules
+// is sharded, the dependencies can be evaluated by other instances, therefore their states are read from the instance
+// store. The states of an organization are read once per evaluation tick, and shared by all rules evaluated at the
+// tick. Otherwise, the states in the cache are used.
+func (st *Manager) getDependencyStates(ctx context.Context, evaluatedAt time.Time, rule *ngModels.AlertRule) dependencyStates {
+	result := dependencyStates{
+		dependencies: rule.Dependencies,
+		firing:       make([][]*State, len(rule.Dependencies)),
+	}
+	if len(rule.Dependencies) == 0 {
+		return result
+	}
+	var stored map[string][]*State
+	if st.shardedEvaluation {
+		stored = st.readStoredStates(ctx, rule.OrgID, evaluatedAt)
+	}
+	for i, dependency := range rule.Dependencies {
+		var candidates []*State
+		if st.shardedEvaluation && dependency.RuleUID != "" {
+			candidates = stored[dependency.RuleUID]
+		} else if st.shardedEvaluation {
+			for _, states := range stored {
+				candidates = append(candidates, states...)
+			}
+		} else if dependency.RuleUID != "" {
+			candidates = st.cache.getStatesForRuleUID(rule.OrgID, dependency.RuleUID, true)
+		} else {
+			candidates = st.cache.getAll(rule.OrgID, true)
+		}
+		for _, s := range candidates {
+			if s.State != eval.Alerting || s.AlertRuleUID == rule.UID {
+				continue
+			}
+			result.firing[i] = append(result.firing[i], s)
+		}
+	}
+	return result
+}
+
+// suppressedBy returns the reason why the alert instance with the given labels is suppressed, if any of the
+// dependencies has a firing alert instance that matches it.
+func (d dependencyStates) suppressedBy(labels data.Labels) (string, bool) {
+	for i, dependency := range d.dependencies {
+		for _, s := range d.firing[i] {
+			if dependency.Matches(s.Labels, labels) {
+				return dependencyFiringReason(s.AlertRuleUID), true
+			}
+		}
+	}
+	return "", false
+}
+
+// ruleSuppressedBy returns the reason why all alert instances of the rule are suppressed, if any of the dependencies
+// that do not compare labels with the alert instances is firing.
+func (d dependencyStates) ruleSuppressedBy() (string, bool) {
+	for i, dependency := range d.dependencies {
+		if len(dependency.Equal) > 0 {
+			continue
+		}
+		for _, s := range d.firing[i] {
+			if dependency.Matches(s.Labels, nil) {
+				return dependencyFiringReason(s.AlertRuleUID), true
+			}
+		}
+	}
+	return "", false
+}
+
+func dependencyFiringReason(ruleUID string) string {
+	return fmt.Sprintf("%s: %s", ngModels.StateReasonDependencyFiring, ruleUID)
+}
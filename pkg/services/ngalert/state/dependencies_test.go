@@ -0,0 +1,205 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestRuleDependencies(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+
+	newManager := func() *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clk,
+			Historian:     &state.FakeHistorian{},
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+		}
+		return state.NewManager(cfg, state.NewNoopPersister())
+	}
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithFor(0), models.RuleGen.WithNoDataExecAs(models.NoData))
+	source := gen.GenerateRef()
+
+	resultsFor := func(s eval.State, instances ...data.Labels) eval.Results {
+		results := make(eval.Results, 0, len(instances))
+		for _, lbls := range instances {
+			results = append(results, eval.Result{Instance: lbls, State: s, EvaluatedAt: clk.Now()})
+		}
+		return results
+	}
+
+	statesByLabel := func(states []*state.State, name string) map[string]*state.State {
+		result := make(map[string]*state.State, len(states))
+		for _, s := range states {
+			result[s.Labels[name]] = s
+		}
+		return result
+	}
+
+	t.Run("should suppress only instances that match the firing instances of the dependency", func(t *testing.T) {
+		st := newManager()
+		dependent := gen.With(gen.WithDependencies(models.RuleDependency{RuleUID: source.UID, Equal: []string{"dc"}})).GenerateRef()
+
+		_ = st.ProcessEvalResults(ctx, clk.Now(), source, resultsFor(eval.Alerting, data.Labels{"dc": "a"}), nil, nil)
+		_ = st.ProcessEvalResults(ctx, clk.Now(), source, resultsFor(eval.Normal, data.Labels{"dc": "b"}), nil, nil)
+
+		_, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed, "dependency with equal labels must not suppress the whole rule")
+
+		var sent state.StateTransitions
+		sender := func(_ context.Context, transitions state.StateTransitions) {
+			sent = append(sent, transitions...)
+		}
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}, data.Labels{"dc": "b"}), nil, sender)
+		require.Len(t, transitions, 2)
+
+		states := statesByLabel(st.GetStatesForRuleUID(dependent.OrgID, dependent.UID), "dc")
+		require.Equal(t, eval.Suppressed, states["a"].State)
+		require.Equal(t, models.StateReasonDependencyFiring+": "+source.UID, states["a"].StateReason)
+		require.Equal(t, eval.Alerting, states["b"].State)
+
+		for _, s := range sent {
+			require.NotEqual(t, eval.Suppressed, s.State.State, "suppressed instances must not be sent")
+		}
+
+		t.Run("should restore the instance when the dependency resolves", func(t *testing.T) {
+			clk.Add(time.Minute)
+			_ = st.ProcessEvalResults(ctx, clk.Now(), source, resultsFor(eval.Normal, data.Labels{"dc": "a"}), nil, nil)
+			_ = st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}, data.Labels{"dc": "b"}), nil, nil)
+
+			states := statesByLabel(st.GetStatesForRuleUID(dependent.OrgID, dependent.UID), "dc")
+			require.Equal(t, eval.Alerting, states["a"].State)
+			require.Empty(t, states["a"].StateReason)
+		})
+	})
+
+	t.Run("should suppress the whole rule when a dependency without equal labels is firing", func(t *testing.T) {
+		st := newManager()
+		dependent := gen.With(gen.WithDependencies(models.RuleDependency{Labels: map[string]string{"severity": "critical"}})).GenerateRef()
+
+		_ = st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}), nil, nil)
+
+		_, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed)
+
+		_ = st.ProcessEvalResults(ctx, clk.Now(), source, resultsFor(eval.Alerting, data.Labels{"severity": "warning"}), nil, nil)
+		_, suppressed = st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed, "firing instance does not match the labels of the dependency")
+
+		_ = st.ProcessEvalResults(ctx, clk.Now(), source, resultsFor(eval.Alerting, data.Labels{"severity": "critical"}), nil, nil)
+		reason, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.True(t, suppressed)
+		require.Equal(t, models.StateReasonDependencyFiring+": "+source.UID, reason)
+
+		clk.Add(time.Minute)
+		var sent state.StateTransitions
+		sender := func(_ context.Context, transitions state.StateTransitions) {
+			sent = append(sent, transitions...)
+		}
+		transitions := st.ProcessSuppressedRule(ctx, clk.Now(), dependent, reason, sender)
+		require.Len(t, transitions, 1)
+		require.Equal(t, eval.Alerting, transitions[0].PreviousState)
+		require.Equal(t, eval.Suppressed, transitions[0].State.State)
+		require.Equal(t, reason, transitions[0].StateReason)
+
+		// the firing alert is resolved in the Alertmanager
+		require.Len(t, sent, 1)
+		require.NotNil(t, sent[0].ResolvedAt)
+		require.Equal(t, clk.Now(), *sent[0].ResolvedAt)
+		require.Equal(t, clk.Now(), sent[0].EndsAt)
+	})
+
+	t.Run("should read the states of dependencies from the instance store when evaluation is sharded", func(t *testing.T) {
+		instanceStore := &firingInstanceStore{}
+		cfg := state.ManagerCfg{
+			Metrics:           metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:     instanceStore,
+			Images:            &state.NotAvailableImageService{},
+			Clock:             clk,
+			Historian:         &state.FakeHistorian{},
+			Tracer:            tracing.InitializeTracerForTest(),
+			Log:               log.New("ngalert.state.manager"),
+			ShardedEvaluation: true,
+		}
+		st := state.NewManager(cfg, state.NewNoopPersister())
+		dependent := gen.With(gen.WithDependencies(models.RuleDependency{RuleUID: source.UID})).GenerateRef()
+
+		_, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed)
+
+		// the dependency is evaluated by another instance that saved its state
+		instanceStore.instances = append(instanceStore.instances, &models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: source.OrgID, RuleUID: source.UID, LabelsHash: "hash"},
+			Labels:           models.InstanceLabels{"dc": "a"},
+			CurrentState:     models.InstanceStateFiring,
+		})
+		_, suppressed = st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed, "the states are read once per evaluation tick")
+		require.Equal(t, 1, instanceStore.queries)
+
+		clk.Add(10 * time.Second)
+		reason, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.True(t, suppressed)
+		require.Equal(t, models.StateReasonDependencyFiring+": "+source.UID, reason)
+
+		// the evaluation and other rules evaluated at the same tick use the same states
+		other := gen.With(gen.WithDependencies(models.RuleDependency{Labels: map[string]string{"dc": "a"}})).GenerateRef()
+		_ = st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}), nil, nil)
+		_ = st.ProcessEvalResults(ctx, clk.Now(), other, resultsFor(eval.Alerting, data.Labels{"dc": "b"}), nil, nil)
+		require.Equal(t, 2, instanceStore.queries)
+		for _, s := range st.GetStatesForRuleUID(other.OrgID, other.UID) {
+			require.Equal(t, eval.Suppressed, s.State)
+		}
+	})
+
+	t.Run("should not depend on itself", func(t *testing.T) {
+		st := newManager()
+		dependent := gen.With(gen.WithDependencies(models.RuleDependency{Labels: map[string]string{"dc": "a"}})).GenerateRef()
+
+		_ = st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}), nil, nil)
+		_ = st.ProcessEvalResults(ctx, clk.Now(), dependent, resultsFor(eval.Alerting, data.Labels{"dc": "a"}), nil, nil)
+
+		_, suppressed := st.RuleSuppressedBy(ctx, clk.Now(), dependent)
+		require.False(t, suppressed)
+		for _, s := range st.GetStatesForRuleUID(dependent.OrgID, dependent.UID) {
+			require.Equal(t, eval.Alerting, s.State)
+		}
+	})
+}
+
+// firingInstanceStore is an instance store that returns the instances of the rule in the query.
+type firingInstanceStore struct {
+	state.FakeInstanceStore
+	instances []*models.AlertInstance
+	queries   int
+}
+
+func (f *firingInstanceStore) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	f.queries++
+	var result []*models.AlertInstance
+	for _, instance := range f.instances {
+		if instance.RuleOrgID == q.RuleOrgID && (q.RuleUID == "" || instance.RuleUID == q.RuleUID) {
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
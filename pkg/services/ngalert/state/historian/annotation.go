@@ -140,6 +140,7 @@ func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQue
 		OrgID:        query.OrgID,
 		From:         query.From.UnixMilli(),
 		To:           query.To.UnixMilli(),
+		Limit:        int64(query.Limit),
 		SignedInUser: query.SignedInUser,
 	}
 	items, err := h.store.Find(ctx, &q)
@@ -181,7 +182,7 @@ func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQue
 			logger.Error("Annotation service gave an annotation with unparseable data, skipping", "id", item.ID, "err", err)
 			continue
 		}
-		times = append(times, time.Unix(item.Time, 0))
+		times = append(times, time.UnixMilli(item.Time))
 		texts = append(texts, item.Text)
 		prevStates = append(prevStates, item.PrevState)
 		nextStates = append(nextStates, item.NewState)
@@ -252,6 +253,11 @@ func BuildAnnotationTextAndData(rule history_model.RuleMeta, currentState *state
 		value = strings.Join(values, ", ")
 	}
 
+	if ack := currentState.Acknowledgement; ack != nil {
+		jsonData.Set("acknowledgedBy", ack.By)
+		jsonData.Set("acknowledgementComment", ack.Comment)
+	}
+
 	labels := removePrivateLabels(currentState.Labels)
 	return fmt.Sprintf("%s {%s} - %s", rule.Title, labels.String(), value), jsonData
 }
@@ -79,22 +79,25 @@ func TestAnnotationHistorian(t *testing.T) {
 	})
 
 	t.Run("annotation queries send expected item query", func(t *testing.T) {
-		store := &interceptingAnnotationStore{}
-		anns := createTestAnnotationSutWithStore(t, store)
 		now := time.Now().UTC()
+		store := &interceptingAnnotationStore{items: []*annotations.ItemDTO{{Time: now.UnixMilli()}}}
+		anns := createTestAnnotationSutWithStore(t, store)
 
 		q := models.HistoryQuery{
 			RuleUID: "my-rule",
 			OrgID:   1,
 			From:    now.Add(-10 * time.Second),
 			To:      now,
+			Limit:   50,
 		}
-		_, err := anns.Query(context.Background(), q)
+		frame, err := anns.Query(context.Background(), q)
 
 		require.NoError(t, err)
 		query := store.lastQuery
 		require.Equal(t, now.UnixMilli(), query.To)
 		require.Equal(t, now.Add(-10*time.Second).UnixMilli(), query.From)
+		require.Equal(t, int64(50), query.Limit)
+		require.Equal(t, time.UnixMilli(now.UnixMilli()), frame.Fields[0].At(0))
 	})
 
 	t.Run("writing state transitions as annotations succeeds", func(t *testing.T) {
@@ -276,11 +279,15 @@ func assertValidJSON(t *testing.T, j *simplejson.Json) string {
 
 type interceptingAnnotationStore struct {
 	lastQuery *annotations.ItemQuery
+	items     []*annotations.ItemDTO
 }
 
 func (i *interceptingAnnotationStore) Find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
 	i.lastQuery = query
-	return []*annotations.ItemDTO{}, nil
+	if i.items == nil {
+		return []*annotations.ItemDTO{}, nil
+	}
+	return i.items, nil
 }
 
 func (i *interceptingAnnotationStore) Save(ctx context.Context, panel *PanelKey, annotations []annotations.Item, orgID int64, logger log.Logger) error {
@@ -301,6 +301,10 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 		if state.State.State == eval.Error {
 			entry.Error = state.Error.Error()
 		}
+		if ack := state.Acknowledgement; ack != nil {
+			entry.AcknowledgedBy = ack.By
+			entry.AcknowledgementComment = ack.Comment
+		}
 
 		jsn, err := json.Marshal(entry)
 		if err != nil {
@@ -346,6 +350,9 @@ type LokiEntry struct {
 	// InstanceLabels is exactly the set of labels associated with the alert instance in Alertmanager.
 	// These should not be conflated with labels associated with log streams.
 	InstanceLabels map[string]string `json:"labels"`
+	// AcknowledgedBy and AcknowledgementComment are set when the alert instance is acknowledged.
+	AcknowledgedBy         string `json:"acknowledgedBy,omitempty"`
+	AcknowledgementComment string `json:"acknowledgementComment,omitempty"`
 }
 
 func valuesAsDataBlob(state *state.State) *simplejson.Json {
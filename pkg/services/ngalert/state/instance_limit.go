@@ -0,0 +1,39 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// recordInstanceLimitExceeded counts the evaluations of the rule that failed because the condition produced more
+// results, and therefore alert instances, than the rule is allowed to have. The limit is enforced by the evaluator.
+func (st *Manager) recordInstanceLimitExceeded(rule *ngModels.AlertRule, results eval.Results, logger log.Logger) {
+	for _, r := range results {
+		if r.State != eval.Error || !errors.Is(r.Error, ngModels.ErrInstanceLimitExceeded) {
+			continue
+		}
+		logger.Warn("Evaluation produced more alert instances than allowed", "error", r.Error)
+		if st.metrics != nil {
+			st.metrics.InstanceLimitExceeded.WithLabelValues(fmt.Sprint(rule.OrgID)).Inc()
+		}
+		return
+	}
+}
+
+// withInstanceLimitExceededReason adds the reason that explains that the evaluation failed because of the instance limit,
+// unless the reason already has it because the state did not change since the previous evaluation.
+func withInstanceLimitExceededReason(reason string) string {
+	if reason == "" {
+		return ngModels.StateReasonInstanceLimitExceeded
+	}
+	if slices.Contains(strings.Split(reason, ", "), ngModels.StateReasonInstanceLimitExceeded) {
+		return reason
+	}
+	return ngModels.ConcatReasons(reason, ngModels.StateReasonInstanceLimitExceeded)
+}
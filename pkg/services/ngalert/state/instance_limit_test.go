@@ -0,0 +1,102 @@
+package state_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestInstanceLimit(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+
+	newManager := func() (*state.Manager, *metrics.State) {
+		m := metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics()
+		cfg := state.ManagerCfg{
+			Metrics:       m,
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clk,
+			Historian:     &state.FakeHistorian{},
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+		}
+		return state.NewManager(cfg, state.NewNoopPersister()), m
+	}
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithFor(0), models.RuleGen.WithErrorExecAs(models.ErrorErrState))
+
+	// limitExceeded returns the result of an evaluation whose condition produced more results than the limit.
+	limitExceeded := func() eval.Results {
+		err := fmt.Errorf("%w: %d (limit: %d)", models.ErrInstanceLimitExceeded, 10, 5)
+		return eval.Results{eval.NewResultFromError(err, clk.Now(), time.Second)}
+	}
+
+	t.Run("should add the reason and count the evaluation if the limit is exceeded", func(t *testing.T) {
+		st, m := newManager()
+		rule := gen.With(gen.WithInstanceLimit(5)).GenerateRef()
+
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, limitExceeded(), nil, nil)
+		require.Len(t, transitions, 1)
+		s := transitions[0].State
+		require.Equal(t, eval.Error, s.State)
+		require.Equal(t, models.StateReasonInstanceLimitExceeded, s.StateReason)
+		require.ErrorIs(t, s.Error, models.ErrInstanceLimitExceeded)
+		require.Equal(t, float64(1), testutil.ToFloat64(m.InstanceLimitExceeded.WithLabelValues("1")))
+
+		t.Run("should keep the reason while the limit is exceeded", func(t *testing.T) {
+			clk.Add(time.Minute)
+			transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, limitExceeded(), nil, nil)
+			require.Len(t, transitions, 1)
+			require.Equal(t, models.StateReasonInstanceLimitExceeded, transitions[0].State.StateReason)
+			require.Equal(t, float64(2), testutil.ToFloat64(m.InstanceLimitExceeded.WithLabelValues("1")))
+		})
+	})
+
+	t.Run("should add the reason to the state the error is mapped to", func(t *testing.T) {
+		st, _ := newManager()
+		rule := gen.With(gen.WithErrorExecAs(models.AlertingErrState)).GenerateRef()
+
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, limitExceeded(), nil, nil)
+		require.Len(t, transitions, 1)
+		require.Equal(t, eval.Alerting, transitions[0].State.State)
+		require.Equal(t, models.ConcatReasons(models.StateReasonError, models.StateReasonInstanceLimitExceeded), transitions[0].State.StateReason)
+	})
+
+	t.Run("should not count other errors", func(t *testing.T) {
+		st, m := newManager()
+		rule := gen.GenerateRef()
+
+		results := eval.Results{eval.NewResultFromError(fmt.Errorf("failed to query data"), clk.Now(), time.Second)}
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil, nil)
+		require.Len(t, transitions, 1)
+		require.NotContains(t, transitions[0].State.StateReason, models.StateReasonInstanceLimitExceeded)
+		require.Zero(t, testutil.ToFloat64(m.InstanceLimitExceeded.WithLabelValues("1")))
+	})
+
+	t.Run("should not count evaluations within the limit", func(t *testing.T) {
+		st, m := newManager()
+		rule := gen.With(gen.WithInstanceLimit(5)).GenerateRef()
+
+		results := eval.Results{{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: clk.Now()}}
+		transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil, nil)
+		require.Len(t, transitions, 1)
+		require.Empty(t, transitions[0].State.StateReason)
+		require.Zero(t, testutil.ToFloat64(m.InstanceLimitExceeded.WithLabelValues("1")))
+	})
+}
@@ -2,9 +2,11 @@ package state
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -18,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 )
 
 var (
@@ -52,12 +55,23 @@ type Manager struct {
 	images        ImageCapturer
 	historian     Historian
 	externalURL   *url.URL
+	querier       TemplateQuerier
+	silencer      Silencer
 
 	doNotSaveNormalState           bool
 	applyNoDataAndErrorToAllStates bool
 	rulesPerRuleGroupLimit         int64
+	shardedEvaluation              bool
 
 	persister StatePersister
+
+	ackMtx           sync.Mutex
+	acknowledgements map[acknowledgementKey]*ngModels.AlertInstanceAcknowledgement
+
+	remoteMtx    sync.RWMutex
+	remoteRules  map[ngModels.AlertRuleKey]struct{}
+	storedMtx    sync.Mutex
+	storedStates map[int64]storedStates
 }
 
 type ManagerCfg struct {
@@ -67,6 +81,11 @@ type ManagerCfg struct {
 	Images        ImageCapturer
 	Clock         clock.Clock
 	Historian     Historian
+	// TemplateQuerier runs the queries of the query function of templates. The query function is a no-op if it is nil.
+	TemplateQuerier TemplateQuerier
+	// Silencer creates the silences of acknowledgements that suppress notifications. Such acknowledgements are
+	// rejected if it is nil.
+	Silencer Silencer
 	// DoNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods
 	DoNotSaveNormalState bool
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -75,6 +94,10 @@ type ManagerCfg struct {
 	// to all states when corresponding execution in the rule definition is set to either `Alerting` or `OK`
 	ApplyNoDataAndErrorToAllStates bool
 	RulesPerRuleGroupLimit         int64
+	// ShardedEvaluation is true if the rules are evaluated by different instances in high availability mode.
+	// The states of the dependencies of rules, and of the rules evaluated by other instances, are then read from the
+	// instance store.
+	ShardedEvaluation bool
 
 	DisableExecution bool
 
@@ -104,11 +127,17 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		historian:                      cfg.Historian,
 		clock:                          cfg.Clock,
 		externalURL:                    cfg.ExternalURL,
+		querier:                        cfg.TemplateQuerier,
+		silencer:                       cfg.Silencer,
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		rulesPerRuleGroupLimit:         cfg.RulesPerRuleGroupLimit,
+		shardedEvaluation:              cfg.ShardedEvaluation,
 		persister:                      statePersister,
 		tracer:                         cfg.Tracer,
+		acknowledgements:               make(map[acknowledgementKey]*ngModels.AlertInstanceAcknowledgement),
+		remoteRules:                    make(map[ngModels.AlertRuleKey]struct{}),
+		storedStates:                   make(map[int64]storedStates),
 	}
 
 	if m.applyNoDataAndErrorToAllStates {
@@ -187,44 +216,13 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
 			rulesStates, ok := orgStates[entry.RuleUID]
 			if !ok {
 				rulesStates = &ruleStates{states: make(map[data.Fingerprint]*State)}
 				orgStates[entry.RuleUID] = rulesStates
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			state := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[state.CacheID] = state
 			statesCount++
 		}
 	}
@@ -233,6 +231,79 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// WarmRule replaces the states of the rule in the cache with the ones stored in the instance store.
+// It is used when the rule was evaluated by another instance, for example after the ownership of rules
+// is handed over between instances in high availability mode.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		st.log.Error("Unable to fetch previous state of the rule", append(rule.GetKey().LogContext(), "error", err)...)
+		return
+	}
+	rs := &ruleStates{states: make(map[data.Fingerprint]*State, len(alertInstances))}
+	for _, entry := range alertInstances {
+		state := st.stateFromInstance(entry, rule)
+		rs.states[state.CacheID] = state
+	}
+	st.cache.setRuleStates(rule.GetKey(), rs)
+	st.setRemoteRule(rule.GetKey(), false)
+	st.log.Debug("State cache of the rule has been initialized", append(rule.GetKey().LogContext(), "states", len(rs.states))...)
+}
+
+// ForgetRule removes the states of the rule from the cache because the rule is evaluated by another instance. Unlike
+// DeleteStateByRuleUID, the states are kept in the instance store and no state transitions are produced. Until the
+// rule is warmed again, its states are read from the instance store.
+func (st *Manager) ForgetRule(ruleKey ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+	st.setRemoteRule(ruleKey, true)
+}
+
+// stateFromInstance converts the alert instance to a state. The annotations of the rule are used if it is not nil.
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	// nil safety.
+	var annotations map[string]string
+	if rule != nil {
+		annotations = rule.Annotations
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	lbs := map[string]string(entry.Labels)
+	cacheID := entry.Labels.Fingerprint()
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+		Acknowledgement:      entry.Acknowledgement(),
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -245,6 +316,21 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 	logger.Debug("Resetting state of the rule")
 
 	states := st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+	st.setRemoteRule(ruleKey, false)
+	acknowledgements := st.forgetAcknowledgements(ruleKey)
+	for _, s := range states {
+		if s.Acknowledgement != nil {
+			acknowledgements = append(acknowledgements, s.Acknowledgement)
+		}
+	}
+	silenceIDs := make(map[string]struct{}, len(acknowledgements))
+	for _, ack := range acknowledgements {
+		if _, ok := silenceIDs[ack.SilenceID]; ok {
+			continue
+		}
+		silenceIDs[ack.SilenceID] = struct{}{}
+		st.deleteAcknowledgementSilence(ctx, logger, ruleKey.OrgID, ack)
+	}
 
 	if len(states) == 0 {
 		return nil
@@ -260,6 +346,7 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 			startsAt = now
 		}
 		s.SetNormal(reason, startsAt, now)
+		s.Acknowledgement = nil
 		// Set Resolved property so the scheduler knows to send a postable alert
 		// to Alertmanager.
 		if oldState == eval.Alerting || oldState == eval.Error || oldState == eval.NoData {
@@ -330,7 +417,9 @@ func (st *Manager) ProcessEvalResults(
 
 	logger := st.log.FromContext(ctx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
-	states := st.setNextStateForRule(ctx, alertRule, results, extraLabels, logger)
+	st.applyStoredAcknowledgements(ctx, alertRule)
+	st.recordInstanceLimitExceeded(alertRule, results, logger)
+	states := st.setNextStateForRule(ctx, evaluatedAt, alertRule, results, extraLabels, logger)
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
 	span.AddEvent("results processed", trace.WithAttributes(
@@ -374,7 +463,8 @@ func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Ti
 	return result
 }
 
-func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
+func (st *Manager) setNextStateForRule(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
+	dependencies := st.getDependencyStates(ctx, evaluatedAt, alertRule)
 	if st.applyNoDataAndErrorToAllStates && results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK || alertRule.NoDataState == ngModels.KeepLast) { // If it is no data, check the mapping and switch all results to the new state
 		// aggregate UID of datasources that returned NoData into one and provide as auxiliary info via annotationa. See: https://github.com/grafana/grafana/issues/88184
 		var refIds strings.Builder
@@ -398,7 +488,7 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 				}
 			}
 		}
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], dependencies, logger)
 		if len(transitions) > 0 {
 			for _, t := range transitions {
 				if t.State.Annotations == nil {
@@ -412,32 +502,36 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 	}
 	if st.applyNoDataAndErrorToAllStates && results.IsError() && (alertRule.ExecErrState == ngModels.AlertingErrState || alertRule.ExecErrState == ngModels.OkErrState || alertRule.ExecErrState == ngModels.KeepLastErrState) {
 		// TODO squash all errors into one, and provide as annotation
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], dependencies, logger)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
 	}
+	var queryFunc template.QueryFunc
+	if st.querier != nil {
+		queryFunc = st.querier.QueryFunc(alertRule)
+	}
 	transitions := make([]StateTransition, 0, len(results))
 	for _, result := range results {
-		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
-		s := st.setNextState(ctx, alertRule, currentState, result, logger)
+		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL, queryFunc)
+		s := st.setNextState(ctx, alertRule, currentState, result, dependencies, logger)
 		transitions = append(transitions, s)
 	}
 	return transitions
 }
 
-func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger) []StateTransition {
+func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, dependencies dependencyStates, logger log.Logger) []StateTransition {
 	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID, false)
 	transitions := make([]StateTransition, 0, len(currentStates))
 	for _, currentState := range currentStates {
-		t := st.setNextState(ctx, alertRule, currentState, result, logger)
+		t := st.setNextState(ctx, alertRule, currentState, result, dependencies, logger)
 		transitions = append(transitions, t)
 	}
 	return transitions
 }
 
 // Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, logger log.Logger) StateTransition {
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, dependencies dependencyStates, logger log.Logger) StateTransition {
 	start := st.clock.Now()
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
@@ -452,6 +546,7 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	currentState.LastEvaluationString = result.EvaluationString
 	oldState := currentState.State
 	oldReason := currentState.StateReason
+	oldStartsAt := currentState.StartsAt
 
 	// Add the instance to the log context to help correlate log lines for a state
 	logger = logger.New("instance", result.Instance)
@@ -502,6 +597,23 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		result.State != eval.Alerting {
 		currentState.StateReason = resultStateReason(result, alertRule)
 	}
+	if result.State == eval.Error && errors.Is(result.Error, ngModels.ErrInstanceLimitExceeded) {
+		currentState.StateReason = withInstanceLimitExceededReason(currentState.StateReason)
+	}
+
+	// Alert instances that would fire are suppressed while a rule they depend on is firing.
+	if currentState.State == eval.Alerting || currentState.State == eval.Pending {
+		if reason, ok := dependencies.suppressedBy(currentState.Labels); ok {
+			startsAt := result.EvaluatedAt
+			if oldState == eval.Suppressed {
+				startsAt = oldStartsAt
+			}
+			logger.Debug("Alert instance is suppressed by a firing dependency", "reason", reason)
+			currentState.SetSuppressed(reason, startsAt, result.EvaluatedAt)
+		}
+	}
+
+	acknowledgementRemoved := st.updateAcknowledgement(ctx, logger, currentState, result.EvaluatedAt)
 
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
@@ -509,7 +621,10 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	if oldState == eval.Alerting && currentState.State == eval.Normal {
 		currentState.ResolvedAt = &result.EvaluatedAt
 		newlyResolved = true
-	} else if currentState.State != eval.Normal && currentState.State != eval.Pending { // Retain the last resolved time for Normal->Normal and Normal->Pending.
+	} else if oldState == eval.Alerting && currentState.State == eval.Suppressed {
+		// Suppressed alerts are resolved in the Alertmanager.
+		currentState.ResolvedAt = &result.EvaluatedAt
+	} else if currentState.State != eval.Normal && currentState.State != eval.Pending && currentState.State != eval.Suppressed { // Retain the last resolved time for Normal->Normal, Normal->Pending and Suppressed->Suppressed.
 		currentState.ResolvedAt = nil
 	}
 
@@ -528,9 +643,10 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	st.cache.set(currentState)
 
 	nextState := StateTransition{
-		State:               currentState,
-		PreviousState:       oldState,
-		PreviousStateReason: oldReason,
+		State:                  currentState,
+		PreviousState:          oldState,
+		PreviousStateReason:    oldReason,
+		AcknowledgementChanged: acknowledgementRemoved,
 	}
 
 	if st.metrics != nil {
@@ -540,6 +656,75 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	return nextState
 }
 
+// RuleSuppressedBy returns the reason why all alert instances of the rule are suppressed, if a dependency of the rule
+// that does not compare labels with the alert instances is firing. Such rule does not need to be evaluated.
+func (st *Manager) RuleSuppressedBy(ctx context.Context, evaluatedAt time.Time, rule *ngModels.AlertRule) (string, bool) {
+	if len(rule.Dependencies) == 0 {
+		return "", false
+	}
+	return st.getDependencyStates(ctx, evaluatedAt, rule).ruleSuppressedBy()
+}
+
+// ProcessSuppressedRule updates the states of a rule that is not evaluated because it is suppressed by a firing
+// dependency. All alert instances that are not Normal become Suppressed with the given reason, and the ones that
+// were firing are sent to the Alertmanager as resolved.
+func (st *Manager) ProcessSuppressedRule(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, reason string, send Sender) StateTransitions {
+	ctx, span := st.tracer.Start(ctx, "suppressed alert rule state calculation", trace.WithAttributes(
+		attribute.String("rule_uid", alertRule.UID),
+		attribute.Int64("org_id", alertRule.OrgID),
+		attribute.Int64("rule_version", alertRule.Version),
+		attribute.String("reason", reason)))
+	defer span.End()
+
+	logger := st.log.FromContext(ctx)
+	st.applyStoredAcknowledgements(ctx, alertRule)
+	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID, false)
+	transitions := make(StateTransitions, 0, len(currentStates))
+	for _, currentState := range currentStates {
+		oldState := currentState.State
+		oldReason := currentState.StateReason
+		currentState.LastEvaluationTime = evaluatedAt
+		switch currentState.State {
+		case eval.Normal:
+		case eval.Suppressed:
+			currentState.SetSuppressed(reason, currentState.StartsAt, evaluatedAt)
+		default:
+			currentState.SetSuppressed(reason, evaluatedAt, evaluatedAt)
+			if oldState == eval.Alerting {
+				// Suppressed alerts are resolved in the Alertmanager.
+				currentState.ResolvedAt = &evaluatedAt
+			} else {
+				currentState.ResolvedAt = nil
+			}
+		}
+		acknowledgementRemoved := st.updateAcknowledgement(ctx, logger, currentState, evaluatedAt)
+		st.cache.set(currentState)
+		transitions = append(transitions, StateTransition{
+			State:                  currentState,
+			PreviousState:          oldState,
+			PreviousStateReason:    oldReason,
+			AcknowledgementChanged: acknowledgementRemoved,
+		})
+	}
+	logger.Debug("Alert rule is suppressed by a firing dependency", "reason", reason, "states", len(transitions))
+
+	// Like in ProcessEvalResults, LastSentAt must be updated before the states are persisted.
+	var statesToSend StateTransitions
+	if send != nil {
+		statesToSend = st.updateLastSentAt(transitions, evaluatedAt)
+	}
+
+	st.persister.Sync(ctx, span, transitions)
+	if st.historian != nil {
+		st.historian.Record(ctx, history_model.NewRuleMeta(alertRule, logger), transitions)
+	}
+
+	if send != nil {
+		send(ctx, statesToSend)
+	}
+	return transitions
+}
+
 func resultStateReason(result eval.Result, rule *ngModels.AlertRule) string {
 	if rule.ExecErrState == ngModels.KeepLastErrState || rule.NoDataState == ngModels.KeepLast {
 		return ngModels.ConcatReasons(result.State.String(), ngModels.StateReasonKeepLast)
@@ -548,11 +733,22 @@ func resultStateReason(result eval.Result, rule *ngModels.AlertRule) string {
 	return result.State.String()
 }
 
+// GetAll returns the states of all rules of the organization. When the evaluation of rules is sharded, the states of
+// the rules evaluated by other instances are read from the instance store.
 func (st *Manager) GetAll(orgID int64) []*State {
 	allStates := st.cache.getAll(orgID, st.doNotSaveNormalState)
+	if st.hasRemoteRules(orgID) {
+		allStates = append(allStates, st.getRemoteStates(context.Background(), orgID, "", st.doNotSaveNormalState)...)
+	}
 	return allStates
 }
+
+// GetStatesForRuleUID returns the states of the rule. When the evaluation of rules is sharded and the rule is
+// evaluated by another instance, the states are read from the instance store.
 func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
+	if st.isRemoteRule(ngModels.AlertRuleKey{OrgID: orgID, UID: alertRuleUID}) {
+		return st.getRemoteStates(context.Background(), orgID, alertRuleUID, st.doNotSaveNormalState)
+	}
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID, st.doNotSaveNormalState)
 }
 
@@ -579,6 +775,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.NoData
 	case ngModels.InstanceStatePending:
 		return eval.Pending
+	case ngModels.InstanceStateSuppressed:
+		return eval.Suppressed
 	default:
 		return eval.Error
 	}
@@ -601,6 +799,7 @@ func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Lo
 		s.StateReason = ngModels.StateReasonMissingSeries
 		s.EndsAt = evaluatedAt
 		s.LastEvaluationTime = evaluatedAt
+		st.updateAcknowledgement(ctx, logger, s, evaluatedAt)
 
 		if oldState == eval.Alerting {
 			s.ResolvedAt = &evaluatedAt
@@ -243,6 +243,24 @@ func TestWarmStateCache(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("rule states are forgotten and warmed again", func(t *testing.T) {
+		st.ForgetRule(rule.GetKey())
+		for _, entry := range expectedEntries {
+			require.Nil(t, st.Get(entry.OrgID, entry.AlertRuleUID, entry.CacheID))
+		}
+		// the states of the rule evaluated by another instance are read from the instance store.
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), len(expectedEntries))
+
+		st.WarmRule(ctx, rule)
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), len(expectedEntries))
+		for _, entry := range expectedEntries {
+			cacheEntry := st.Get(entry.OrgID, entry.AlertRuleUID, entry.CacheID)
+			if diff := cmp.Diff(entry, cacheEntry, cmpopts.IgnoreFields(state.State{}, "LatestResult")); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		}
+	})
 }
 
 func TestDashboardAnnotations(t *testing.T) {
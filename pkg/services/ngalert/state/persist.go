@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 )
 
 // InstanceStore represents the ability to fetch and write alert instances.
@@ -36,3 +37,15 @@ type Historian interface {
 type ImageCapturer interface {
 	NewImage(ctx context.Context, r *models.AlertRule) (*models.Image, error)
 }
+
+// Silencer creates and deletes silences in the Alertmanager of an organization.
+type Silencer interface {
+	CreateSilence(ctx context.Context, orgID int64, ps models.Silence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, silenceID string) error
+}
+
+// TemplateQuerier runs the queries of the query function of the annotation and label templates of alert rules.
+type TemplateQuerier interface {
+	// QueryFunc returns the query function of the templates of the rule for a single evaluation.
+	QueryFunc(rule *models.AlertRule) template.QueryFunc
+}
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	benchPersisterRules            = 1_000
+	benchPersisterInstancesPerRule = 200
+)
+
+// encodingSnapshotStore encodes the snapshots like the database store does, but does not save them.
+type encodingSnapshotStore struct {
+	FakeInstanceStore
+	snapshots map[models.AlertRuleKey][]byte
+}
+
+func (s *encodingSnapshotStore) SaveRuleStates(_ context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	b, err := models.EncodeInstanceSnapshot(instances)
+	if err != nil {
+		return err
+	}
+	s.snapshots[key] = b
+	return nil
+}
+
+func makeBenchPersisterCache() *cache {
+	c := newCache()
+	now := time.Now()
+	for r := 0; r < benchPersisterRules; r++ {
+		uid := fmt.Sprintf("rule-%d", r)
+		for i := 0; i < benchPersisterInstancesPerRule; i++ {
+			labels := data.Labels{"__alert_rule_uid__": uid, "instance": fmt.Sprintf("instance-%d", i), "job": "node"}
+			c.set(&State{
+				OrgID:              1,
+				AlertRuleUID:       uid,
+				CacheID:            labels.Fingerprint(),
+				Labels:             labels,
+				State:              eval.Alerting,
+				StartsAt:           now,
+				EndsAt:             now.Add(time.Minute),
+				LastEvaluationTime: now,
+			})
+		}
+	}
+	return c
+}
+
+func BenchmarkAsyncStatePersister_FullSync(b *testing.B) {
+	c := makeBenchPersisterCache()
+	persister := NewAsyncStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), ManagerCfg{
+		InstanceStore: &FakeInstanceStore{},
+	}).(*AsyncStatePersister)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = persister.fullSync(context.Background(), c)
+	}
+}
+
+func BenchmarkSnapshotStatePersister_Snapshot(b *testing.B) {
+	c := makeBenchPersisterCache()
+	for _, changedRules := range []int{10, benchPersisterRules} {
+		b.Run(fmt.Sprintf("%d changed rules", changedRules), func(b *testing.B) {
+			store := &encodingSnapshotStore{snapshots: make(map[models.AlertRuleKey][]byte)}
+			persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{}).(*SnapshotStatePersister)
+			transitions := make(StateTransitions, 0, changedRules)
+			for r := 0; r < changedRules; r++ {
+				transitions = append(transitions, StateTransition{State: &State{OrgID: 1, AlertRuleUID: fmt.Sprintf("rule-%d", r)}})
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				persister.Sync(context.Background(), nil, transitions)
+				persister.snapshot(context.Background(), c)
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeInstanceSnapshots(b *testing.B) {
+	c := makeBenchPersisterCache()
+	store := &encodingSnapshotStore{snapshots: make(map[models.AlertRuleKey][]byte)}
+	persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{}).(*SnapshotStatePersister)
+	for r := 0; r < benchPersisterRules; r++ {
+		persister.Sync(context.Background(), nil, StateTransitions{{State: &State{OrgID: 1, AlertRuleUID: fmt.Sprintf("rule-%d", r)}}})
+	}
+	persister.snapshot(context.Background(), c)
+	var size int
+	for _, s := range store.snapshots {
+		size += len(s)
+	}
+	b.ReportMetric(float64(size), "snapshot-bytes")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for key, s := range store.snapshots {
+			if _, err := models.DecodeInstanceSnapshot(key.OrgID, key.UID, s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleStateSnapshotStore is an instance store that saves all alert instances of a rule at once.
+type RuleStateSnapshotStore interface {
+	InstanceStore
+	// SaveRuleStates replaces the alert instances of the rule. The alert instances of the rule are deleted if there
+	// are none.
+	SaveRuleStates(ctx context.Context, key ngModels.AlertRuleKey, instances []ngModels.AlertInstance) error
+}
+
+// SnapshotStatePersister periodically saves a snapshot of the alert instances of the rules whose state changed
+// since the last snapshot. Unlike AsyncStatePersister, it does not rewrite the alert instances of all rules.
+type SnapshotStatePersister struct {
+	log log.Logger
+	// doNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods.
+	doNotSaveNormalState bool
+	store                RuleStateSnapshotStore
+	ticker               *clock.Ticker
+	metrics              *metrics.State
+
+	mtx sync.Mutex
+	// changed is the set of rules whose state changed since the last snapshot.
+	changed map[ngModels.AlertRuleKey]struct{}
+}
+
+func NewSnapshotStatePersister(log log.Logger, ticker *clock.Ticker, store RuleStateSnapshotStore, cfg ManagerCfg) StatePersister {
+	return &SnapshotStatePersister{
+		log:                  log,
+		doNotSaveNormalState: cfg.DoNotSaveNormalState,
+		store:                store,
+		ticker:               ticker,
+		metrics:              cfg.Metrics,
+		changed:              make(map[ngModels.AlertRuleKey]struct{}),
+	}
+}
+
+func (a *SnapshotStatePersister) Async(ctx context.Context, cache *cache) {
+	for {
+		select {
+		case <-a.ticker.C:
+			a.snapshot(ctx, cache)
+		case <-ctx.Done():
+			a.log.Info("Scheduler is shutting down, saving a final state snapshot.")
+			a.snapshot(context.Background(), cache)
+			a.ticker.Stop()
+			a.log.Info("State snapshot worker is shut down.")
+			return
+		}
+	}
+}
+
+// Sync marks the rules of the state transitions as changed. Their state is saved by the next snapshot.
+func (a *SnapshotStatePersister) Sync(_ context.Context, _ trace.Span, states StateTransitions) {
+	if len(states) == 0 {
+		return
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for _, s := range states {
+		a.changed[ngModels.AlertRuleKey{OrgID: s.OrgID, UID: s.AlertRuleUID}] = struct{}{}
+	}
+}
+
+func (a *SnapshotStatePersister) snapshot(ctx context.Context, cache *cache) {
+	a.mtx.Lock()
+	changed := a.changed
+	a.changed = make(map[ngModels.AlertRuleKey]struct{}, len(changed))
+	a.mtx.Unlock()
+	if len(changed) == 0 {
+		return
+	}
+
+	startTime := time.Now()
+	a.log.Debug("State snapshot start", "rules", len(changed))
+	var instances, failed int
+	for key := range changed {
+		ruleInstances := cache.ruleInstances(key, a.doNotSaveNormalState)
+		if err := a.store.SaveRuleStates(ctx, key, ruleInstances); err != nil {
+			a.log.Error("Failed to save state snapshot of the rule", append(key.LogContext(), "error", err)...)
+			failed++
+			// Save the state of the rule with the next snapshot, unless the rule changes again in the meantime.
+			a.mtx.Lock()
+			a.changed[key] = struct{}{}
+			a.mtx.Unlock()
+			continue
+		}
+		instances += len(ruleInstances)
+	}
+	a.log.Debug("State snapshot done", "duration", time.Since(startTime), "rules", len(changed), "failed", failed, "instances", instances)
+	if a.metrics != nil {
+		a.metrics.StateFullSyncDuration.Observe(time.Since(startTime).Seconds())
+	}
+}
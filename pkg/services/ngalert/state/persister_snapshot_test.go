@@ -0,0 +1,136 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeRuleStateSnapshotStore struct {
+	FakeInstanceStore
+	mtx   sync.Mutex
+	saved map[models.AlertRuleKey][]models.AlertInstance
+	err   error
+}
+
+func (f *fakeRuleStateSnapshotStore) SaveRuleStates(_ context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	if f.saved == nil {
+		f.saved = make(map[models.AlertRuleKey][]models.AlertInstance)
+	}
+	f.saved[key] = instances
+	return nil
+}
+
+func (f *fakeRuleStateSnapshotStore) Saved() map[models.AlertRuleKey][]models.AlertInstance {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make(map[models.AlertRuleKey][]models.AlertInstance, len(f.saved))
+	for k, v := range f.saved {
+		result[k] = v
+	}
+	return result
+}
+
+func TestSnapshotStatePersister(t *testing.T) {
+	rule1 := models.AlertRuleKey{OrgID: 1, UID: "rule-1"}
+	rule2 := models.AlertRuleKey{OrgID: 1, UID: "rule-2"}
+
+	newCacheWithStates := func() *cache {
+		c := newCache()
+		c.set(&State{OrgID: rule1.OrgID, AlertRuleUID: rule1.UID, CacheID: data.Fingerprint(1), State: eval.Alerting, Labels: data.Labels{"a": "1"}})
+		c.set(&State{OrgID: rule1.OrgID, AlertRuleUID: rule1.UID, CacheID: data.Fingerprint(2), State: eval.Normal, Labels: data.Labels{"a": "2"}})
+		c.set(&State{OrgID: rule2.OrgID, AlertRuleUID: rule2.UID, CacheID: data.Fingerprint(3), State: eval.Alerting, Labels: data.Labels{"a": "3"}})
+		return c
+	}
+
+	t.Run("It should save only the rules whose state changed on tick", func(t *testing.T) {
+		mockClock := clock.NewMock()
+		store := &fakeRuleStateSnapshotStore{}
+		persister := NewSnapshotStatePersister(log.NewNopLogger(), mockClock.Ticker(time.Second), store, ManagerCfg{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cache := newCacheWithStates()
+		go persister.Async(ctx, cache)
+
+		persister.Sync(ctx, nil, StateTransitions{{State: &State{OrgID: rule1.OrgID, AlertRuleUID: rule1.UID}}})
+		mockClock.Add(time.Second)
+
+		require.Eventually(t, func() bool {
+			return len(store.Saved()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Len(t, store.Saved()[rule1], 2)
+	})
+
+	t.Run("It should skip normal states if configured", func(t *testing.T) {
+		store := &fakeRuleStateSnapshotStore{}
+		persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{DoNotSaveNormalState: true}).(*SnapshotStatePersister)
+
+		persister.Sync(context.Background(), nil, StateTransitions{{State: &State{OrgID: rule1.OrgID, AlertRuleUID: rule1.UID}}})
+		persister.snapshot(context.Background(), newCacheWithStates())
+
+		saved := store.Saved()[rule1]
+		require.Len(t, saved, 1)
+		require.Equal(t, models.InstanceStateFiring, saved[0].CurrentState)
+	})
+
+	t.Run("It should save the state of deleted rules as empty", func(t *testing.T) {
+		store := &fakeRuleStateSnapshotStore{}
+		persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{}).(*SnapshotStatePersister)
+
+		deleted := models.AlertRuleKey{OrgID: 1, UID: "deleted"}
+		persister.Sync(context.Background(), nil, StateTransitions{{State: &State{OrgID: deleted.OrgID, AlertRuleUID: deleted.UID}}})
+		persister.snapshot(context.Background(), newCacheWithStates())
+
+		saved, ok := store.Saved()[deleted]
+		require.True(t, ok)
+		require.Empty(t, saved)
+	})
+
+	t.Run("It should retry failed rules with the next snapshot", func(t *testing.T) {
+		store := &fakeRuleStateSnapshotStore{err: errors.New("failed")}
+		persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{}).(*SnapshotStatePersister)
+		cache := newCacheWithStates()
+
+		persister.Sync(context.Background(), nil, StateTransitions{{State: &State{OrgID: rule2.OrgID, AlertRuleUID: rule2.UID}}})
+		persister.snapshot(context.Background(), cache)
+		require.Empty(t, store.Saved())
+
+		store.mtx.Lock()
+		store.err = nil
+		store.mtx.Unlock()
+		persister.snapshot(context.Background(), cache)
+		require.Len(t, store.Saved()[rule2], 1)
+	})
+
+	t.Run("It should save on context done", func(t *testing.T) {
+		store := &fakeRuleStateSnapshotStore{}
+		persister := NewSnapshotStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Second), store, ManagerCfg{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		persister.Sync(ctx, nil, StateTransitions{{State: &State{OrgID: rule2.OrgID, AlertRuleUID: rule2.UID}}})
+		done := make(chan struct{})
+		go func() {
+			persister.Async(ctx, newCacheWithStates())
+			close(done)
+		}()
+		cancel()
+		<-done
+		require.Len(t, store.Saved()[rule2], 1)
+	})
+}
@@ -106,6 +106,7 @@ func (a *SyncStatePersister) saveAlertStates(ctx context.Context, states ...Stat
 			LastSentAt:        s.LastSentAt,
 			ResultFingerprint: s.ResultFingerprint.String(),
 		}
+		instance.SetAcknowledgement(s.Acknowledgement)
 
 		err = a.store.SaveAlertInstance(ctx, instance)
 		if err != nil {
@@ -0,0 +1,180 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// storedStatesMaxAge is how long the states read from the instance store are reused to serve the states of the rules
+// evaluated by other instances. It limits the number of queries when the states of many rules are requested at once,
+// for example by the rules API. The states of dependencies are read again at every evaluation tick instead.
+const storedStatesMaxAge = 5 * time.Second
+
+// storedStates are the states of the rules of an organization as read from the instance store.
+type storedStates struct {
+	readAt time.Time
+	rules  map[string][]*State
+}
+
+// setRemoteRule records whether the rule is evaluated by another instance when the evaluation of rules is sharded.
+// The states of such rules are not in the cache, and they are read from the instance store instead.
+func (st *Manager) setRemoteRule(key ngModels.AlertRuleKey, remote bool) {
+	st.remoteMtx.Lock()
+	defer st.remoteMtx.Unlock()
+	if remote {
+		st.remoteRules[key] = struct{}{}
+		return
+	}
+	delete(st.remoteRules, key)
+}
+
+// isRemoteRule returns true if the rule is evaluated by another instance.
+func (st *Manager) isRemoteRule(key ngModels.AlertRuleKey) bool {
+	st.remoteMtx.RLock()
+	defer st.remoteMtx.RUnlock()
+	_, ok := st.remoteRules[key]
+	return ok
+}
+
+// hasRemoteRules returns true if any rule of the organization is evaluated by another instance.
+func (st *Manager) hasRemoteRules(orgID int64) bool {
+	st.remoteMtx.RLock()
+	defer st.remoteMtx.RUnlock()
+	for key := range st.remoteRules {
+		if key.OrgID == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+// getRemoteStates returns the states of the rule with the given UID if it is evaluated by another instance, or the
+// states of all such rules of the organization if the UID is empty.
+func (st *Manager) getRemoteStates(ctx context.Context, orgID int64, ruleUID string, skipNormalState bool) []*State {
+	var result []*State
+	for uid, states := range st.readStoredStates(ctx, orgID, st.clock.Now().Add(-storedStatesMaxAge)) {
+		if ruleUID != "" && uid != ruleUID {
+			continue
+		}
+		if !st.isRemoteRule(ngModels.AlertRuleKey{OrgID: orgID, UID: uid}) {
+			continue
+		}
+		for _, s := range states {
+			if skipNormalState && IsNormalStateWithNoReason(s) {
+				continue
+			}
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// readStoredStates returns the states of the rules of the organization from the instance store. The states are
+// read again only if they were read before the given time. The annotations of the states are not set because they
+// are not stored.
+func (st *Manager) readStoredStates(ctx context.Context, orgID int64, readSince time.Time) map[string][]*State {
+	if st.instanceStore == nil {
+		return nil
+	}
+	st.storedMtx.Lock()
+	defer st.storedMtx.Unlock()
+	if cached, ok := st.storedStates[orgID]; ok && !cached.readAt.Before(readSince) {
+		return cached.rules
+	}
+	now := st.clock.Now()
+
+	instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{RuleOrgID: orgID})
+	if err != nil {
+		st.log.FromContext(ctx).Error("Failed to fetch the states of the rules from the instance store", "orgID", orgID, "error", err)
+		return nil
+	}
+	rules := make(map[string][]*State)
+	for _, instance := range instances {
+		rules[instance.RuleUID] = append(rules[instance.RuleUID], st.stateFromInstance(instance, nil))
+	}
+	st.storedStates[orgID] = storedStates{readAt: now, rules: rules}
+	return rules
+}
+
+// getStoredState returns the state with the given fingerprint of the rule from the instance store, or nil if it does
+// not exist.
+func (st *Manager) getStoredState(ctx context.Context, rule *ngModels.AlertRule, fingerprint data.Fingerprint) (*State, error) {
+	if st.instanceStore == nil {
+		return nil, nil
+	}
+	instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		if instance.Labels.Fingerprint() == fingerprint {
+			return st.stateFromInstance(instance, rule), nil
+		}
+	}
+	return nil, nil
+}
+
+// applyStoredAcknowledgements takes over the acknowledgements of the alert instances of the rule that were changed by
+// other instances when the evaluation of rules is sharded. Other instances save them in the instance store because
+// they do not evaluate the rule. The instance store is only read if the rule has alert instances that can be
+// acknowledged or are acknowledged.
+func (st *Manager) applyStoredAcknowledgements(ctx context.Context, rule *ngModels.AlertRule) {
+	if !st.shardedEvaluation || st.instanceStore == nil {
+		return
+	}
+	current := make(map[data.Fingerprint]*State)
+	for _, s := range st.cache.getStatesForRuleUID(rule.OrgID, rule.UID, false) {
+		if canAcknowledge(s.State) || s.Acknowledgement != nil {
+			current[s.CacheID] = s
+		}
+	}
+	if len(current) == 0 {
+		return
+	}
+
+	instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		st.log.FromContext(ctx).Error("Failed to fetch the acknowledgements of the alert instances", append(rule.GetKey().LogContext(), "error", err)...)
+		return
+	}
+	st.ackMtx.Lock()
+	defer st.ackMtx.Unlock()
+	for _, instance := range instances {
+		s, ok := current[instance.Labels.Fingerprint()]
+		if !ok {
+			continue
+		}
+		stored := instance.Acknowledgement()
+		if equalAcknowledgements(s.Acknowledgement, stored) {
+			continue
+		}
+		key := acknowledgementKey{orgID: rule.OrgID, ruleUID: rule.UID, cacheID: s.CacheID}
+		// An acknowledgement set by this instance since the last evaluation takes precedence.
+		if _, ok := st.acknowledgements[key]; !ok {
+			st.acknowledgements[key] = stored
+		}
+	}
+}
+
+// equalAcknowledgements returns true if both acknowledgements are nil or equal. The times are compared with the
+// precision of the instance store.
+func equalAcknowledgements(a, b *ngModels.AlertInstanceAcknowledgement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.ExpiresAt == nil) != (b.ExpiresAt == nil) || (a.ExpiresAt != nil && a.ExpiresAt.Unix() != b.ExpiresAt.Unix()) {
+		return false
+	}
+	return a.By == b.By && a.Comment == b.Comment && a.At.Unix() == b.At.Unix() &&
+		a.SuppressNotifications == b.SuppressNotifications && a.SilenceID == b.SilenceID
+}
@@ -0,0 +1,106 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestShardedEvaluation(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+	clk := clock.NewMock()
+	clk.Set(time.Now().Truncate(time.Second))
+
+	// owner evaluates the rule, and other is another instance of the cluster that shares the instance store.
+	newManager := func() *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:           metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:     dbstore,
+			Images:            &state.NotAvailableImageService{},
+			Clock:             clk,
+			Historian:         &state.FakeHistorian{},
+			ShardedEvaluation: true,
+			Tracer:            tracing.InitializeTracerForTest(),
+			Log:               log.New("ngalert.state.manager"),
+		}
+		return state.NewManager(cfg, state.NewSyncStatePersisiter(log.New("ngalert.state.manager.persist"), cfg))
+	}
+	owner := newManager()
+	other := newManager()
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, 1)
+	instance := data.Labels{"instance": "a"}
+	evaluate := func() {
+		clk.Add(time.Minute)
+		_ = owner.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{{Instance: instance, State: eval.Alerting, EvaluatedAt: clk.Now()}}, nil, nil)
+	}
+	evaluate()
+	owned := owner.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, owned, 1)
+	fingerprint := owned[0].CacheID
+
+	t.Run("should read the states of rules evaluated by other instances from the instance store", func(t *testing.T) {
+		require.Empty(t, other.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		other.ForgetRule(rule.GetKey())
+
+		states := other.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Equal(t, fingerprint, states[0].CacheID)
+		require.Equal(t, owned[0].Labels, states[0].Labels)
+
+		all := other.GetAll(rule.OrgID)
+		require.Len(t, all, 1)
+		require.Equal(t, fingerprint, all[0].CacheID)
+	})
+
+	t.Run("should hand over acknowledgements to the instance that evaluates the rule", func(t *testing.T) {
+		ack := models.AlertInstanceAcknowledgement{By: "editor", Comment: "on it", At: clk.Now()}
+		acknowledged, err := other.AcknowledgeInstance(ctx, rule, fingerprint, ack)
+		require.NoError(t, err)
+		require.Equal(t, "editor", acknowledged.Acknowledgement.By)
+
+		states := other.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.NotNil(t, states[0].Acknowledgement)
+
+		evaluate()
+		states = owner.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.NotNil(t, states[0].Acknowledgement)
+		require.Equal(t, "editor", states[0].Acknowledgement.By)
+		require.Equal(t, "on it", states[0].Acknowledgement.Comment)
+
+		_, err = other.UnacknowledgeInstance(ctx, rule, fingerprint)
+		require.NoError(t, err)
+		evaluate()
+		states = owner.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Nil(t, states[0].Acknowledgement)
+	})
+
+	t.Run("should not find alert instances that do not exist", func(t *testing.T) {
+		_, err := other.AcknowledgeInstance(ctx, rule, data.Labels{"instance": "b"}.Fingerprint(), models.AlertInstanceAcknowledgement{At: clk.Now()})
+		require.ErrorIs(t, err, models.ErrAlertInstanceNotFound)
+	})
+
+	t.Run("should use the cache again when the rule is warmed", func(t *testing.T) {
+		other.WarmRule(ctx, rule)
+		require.NotNil(t, other.Get(rule.OrgID, rule.UID, fingerprint))
+		require.Len(t, other.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+	})
+}
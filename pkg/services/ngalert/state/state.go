@@ -73,6 +73,10 @@ type State struct {
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
 	EvaluationDuration   time.Duration
+
+	// Acknowledgement is set when a user acknowledges the alert instance. It is cleared when the acknowledgement
+	// expires or the alert instance resolves.
+	Acknowledgement *models.AlertInstanceAcknowledgement
 }
 
 func (a *State) GetRuleKey() models.AlertRuleKey {
@@ -127,6 +131,16 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.Error = err
 }
 
+// SetSuppressed sets the state to Suppressed. It changes both the start and end time. Suppressed alerts are not
+// active in the Alertmanager, so the end time is the time of the evaluation.
+func (a *State) SetSuppressed(reason string, startsAt, endsAt time.Time) {
+	a.State = eval.Suppressed
+	a.StateReason = reason
+	a.StartsAt = startsAt
+	a.EndsAt = endsAt
+	a.Error = nil
+}
+
 // SetNormal sets the state to Normal. It changes both the start and end time.
 func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.State = eval.Normal
@@ -212,6 +226,8 @@ type StateTransition struct {
 	*State
 	PreviousState       eval.State
 	PreviousStateReason string
+	// AcknowledgementChanged is true if the alert instance was acknowledged, or its acknowledgement was removed.
+	AcknowledgementChanged bool
 }
 
 func (c StateTransition) Formatted() string {
@@ -223,7 +239,7 @@ func (c StateTransition) PreviousFormatted() string {
 }
 
 func (c StateTransition) Changed() bool {
-	return c.PreviousState != c.State.State || c.PreviousStateReason != c.State.StateReason
+	return c.PreviousState != c.State.State || c.PreviousStateReason != c.State.StateReason || c.AcknowledgementChanged
 }
 
 type StateTransitions []StateTransition
@@ -451,6 +467,10 @@ func resultKeepLast(state *State, rule *models.AlertRule, result eval.Result, lo
 		} else {
 			logger.Debug("Ignoring set next state to pending")
 		}
+	case eval.Suppressed:
+		// the condition was true when the alert instance was suppressed
+		logger.Debug("Execution keep last state is Suppressed", "handler", "resultAlerting")
+		resultAlerting(state, rule, result, logger, reason)
 	case eval.Normal:
 		logger.Debug("Execution keep last state is Normal", "handler", "resultNormal")
 		resultNormal(state, rule, result, logger, reason)
@@ -477,9 +497,9 @@ func (a *State) NeedsSending(resendDelay time.Duration, resolvedRetention time.D
 		return true
 	}
 
-	// For normal states, we should only be sending if this is a resolved notification or a re-send of the resolved
-	// notification within the resolvedRetention period.
-	if a.State == eval.Normal && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
+	// For normal and suppressed states, we should only be sending if this is a resolved notification or a re-send of
+	// the resolved notification within the resolvedRetention period.
+	if (a.State == eval.Normal || a.State == eval.Suppressed) && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
 		return false
 	}
 
@@ -95,7 +95,11 @@ func (e ExpandError) Error() string {
 	return fmt.Sprintf("failed to expand template '%s': %s", e.Tmpl, e.Err)
 }
 
-func Expand(ctx context.Context, name, tmpl string, data Data, externalURL *url.URL, evaluatedAt time.Time) (string, error) {
+// QueryFunc runs the query of the query function of templates at the given time. The query function is a no-op if
+// it is nil.
+type QueryFunc = template.QueryFunc
+
+func Expand(ctx context.Context, name, tmpl string, data Data, externalURL *url.URL, evaluatedAt time.Time, queryFunc QueryFunc) (string, error) {
 	if !strings.Contains(tmpl, "{{") { // If it is not a template, skip expanding it.
 		return tmpl, nil
 	}
@@ -104,9 +108,10 @@ func Expand(ctx context.Context, name, tmpl string, data Data, externalURL *url.
 	name = "__alert_" + name
 	// add variables for the labels and values to the beginning of the template
 	tmpl = "{{- $labels := .Labels -}}{{- $values := .Values -}}{{- $value := .Value -}}" + tmpl
-	// ctx and queryFunc are no-ops as `query()` is not supported in Grafana
-	queryFunc := func(context.Context, string, time.Time) (promql.Vector, error) {
-		return nil, nil
+	if queryFunc == nil {
+		queryFunc = func(context.Context, string, time.Time) (promql.Vector, error) {
+			return nil, nil
+		}
 	}
 	tm := model.Time(timestamp.FromTime(evaluatedAt))
 	// Use missingkey=invalid so missing data shows <no value> instead of the type's default value
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -436,7 +439,7 @@ func TestExpandTemplate(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			v, err := Expand(context.Background(), "test", c.text, NewData(c.labels, c.alertInstance), externalURL, c.alertInstance.EvaluatedAt)
+			v, err := Expand(context.Background(), "test", c.text, NewData(c.labels, c.alertInstance), externalURL, c.alertInstance.EvaluatedAt, nil)
 			if c.expectedError != nil {
 				require.NotNil(t, err)
 				require.EqualError(t, c.expectedError, err.Error())
@@ -447,3 +450,36 @@ func TestExpandTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandTemplateWithQuery(t *testing.T) {
+	queries := make([]string, 0)
+	queryFunc := func(_ context.Context, q string, _ time.Time) (promql.Vector, error) {
+		queries = append(queries, q)
+		if q == "fail" {
+			return nil, errors.New("query failed")
+		}
+		return promql.Vector{
+			{Metric: labels.FromStrings("pod", "b"), F: 2},
+			{Metric: labels.FromStrings("pod", "a"), F: 1},
+		}, nil
+	}
+	data := NewData(map[string]string{"node": "node-1"}, eval.Result{})
+
+	t.Run("query results can be iterated", func(t *testing.T) {
+		v, err := Expand(context.Background(), "test", `{{ range query (printf "topk(2, memory{node=%q})" $labels.node) | sortByLabel "pod" }}{{ .Labels.pod }}={{ .Value }} {{ end }}`, data, nil, time.Now(), queryFunc)
+		require.NoError(t, err)
+		require.Equal(t, "a=1 b=2 ", v)
+		require.Equal(t, `topk(2, memory{node="node-1"})`, queries[len(queries)-1])
+	})
+
+	t.Run("query results can be used with the helper functions", func(t *testing.T) {
+		v, err := Expand(context.Background(), "test", `{{ query "memory" | first | label "pod" }}`, data, nil, time.Now(), queryFunc)
+		require.NoError(t, err)
+		require.Equal(t, "b", v)
+	})
+
+	t.Run("failed queries return an error", func(t *testing.T) {
+		_, err := Expand(context.Background(), "test", `{{ query "fail" }}`, data, nil, time.Now(), queryFunc)
+		require.ErrorContains(t, err, "query failed")
+	})
+}
@@ -0,0 +1,71 @@
+package state_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
+)
+
+// countingQuerier counts the query functions it returns, one per evaluation.
+type countingQuerier struct {
+	evaluations int
+}
+
+func (q *countingQuerier) QueryFunc(_ *models.AlertRule) template.QueryFunc {
+	q.evaluations++
+	return func(context.Context, string, time.Time) (promql.Vector, error) {
+		return promql.Vector{{F: 3}}, nil
+	}
+}
+
+func TestTemplateQueryInLabels(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+
+	querier := &countingQuerier{}
+	cfg := state.ManagerCfg{
+		Metrics:         metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:   &state.FakeInstanceStore{},
+		Images:          &state.NotAvailableImageService{},
+		Clock:           clk,
+		Historian:       &state.FakeHistorian{},
+		Tracer:          tracing.InitializeTracerForTest(),
+		Log:             log.New("ngalert.state.manager"),
+		TemplateQuerier: querier,
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithFor(0))
+	rule := gen.With(
+		gen.WithLabels(map[string]string{"replicas": `{{ query "count(up)" | first | value }}`}),
+		gen.WithAnnotations(map[string]string{"summary": `{{ $labels.pod }} of {{ query "count(up)" | first | value }}`}),
+	).GenerateRef()
+	results := eval.Results{
+		{Instance: data.Labels{"pod": "a"}, State: eval.Alerting, EvaluatedAt: clk.Now()},
+		{Instance: data.Labels{"pod": "b"}, State: eval.Alerting, EvaluatedAt: clk.Now()},
+	}
+
+	transitions := st.ProcessEvalResults(ctx, clk.Now(), rule, results, nil, nil)
+	require.Len(t, transitions, 2)
+	for _, tr := range transitions {
+		require.Equal(t, "3", tr.State.Labels["replicas"])
+		require.Equal(t, tr.State.Labels["pod"]+" of 3", tr.State.Annotations["summary"])
+	}
+	// The labels and annotations of all alert instances share the query function of the evaluation.
+	require.Equal(t, 1, querier.evaluations)
+}
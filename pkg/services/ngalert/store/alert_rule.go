@@ -58,6 +58,12 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("Deleted alert instances", "count", rows)
+
+		rows, err = sess.Table(ruleStateSnapshot{}).Where("org_id = ?", orgID).In("rule_uid", ruleUID).Delete(ruleStateSnapshot{})
+		if err != nil {
+			return err
+		}
+		logger.Debug("Deleted rule state snapshots", "count", rows)
 		return nil
 	})
 }
@@ -222,6 +228,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 		}
 
 		if len(ruleVersions) > 0 {
+			setAlertRuleVersionsCreatedBy(ctx, ruleVersions)
 			if _, err := sess.Insert(&ruleVersions); err != nil {
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
@@ -268,9 +275,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 			v := alertRuleToAlertRuleVersion(converted)
 			v.Version++
 			v.ParentVersion = r.Existing.Version
+			v.RestoredFrom = r.RestoredFrom
 			ruleVersions = append(ruleVersions, v)
 		}
 		if len(ruleVersions) > 0 {
+			setAlertRuleVersionsCreatedBy(ctx, ruleVersions)
 			if _, err := sess.Insert(&ruleVersions); err != nil {
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
@@ -288,6 +297,62 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 	})
 }
 
+// setAlertRuleVersionsCreatedBy records the identity from the context as the author of the versions.
+// Changes made by background processes have no identity and keep the author empty.
+func setAlertRuleVersionsCreatedBy(ctx context.Context, versions []alertRuleVersion) {
+	user, err := identity.GetRequester(ctx)
+	if err != nil {
+		return
+	}
+	uid := user.GetUID()
+	for i := range versions {
+		versions[i].CreatedBy = &uid
+	}
+}
+
+// GetAlertRuleVersions returns the stored versions of the alert rule, the latest version first.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, query *ngmodels.GetAlertRuleVersionsQuery) (result []*ngmodels.AlertRuleVersion, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var versions []alertRuleVersion
+		if err := sess.Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.UID).Desc("version").Find(&versions); err != nil {
+			return err
+		}
+		result = make([]*ngmodels.AlertRuleVersion, 0, len(versions))
+		for _, v := range versions {
+			converted, err := alertRuleVersionToModelsAlertRuleVersion(v, st.Logger)
+			if err != nil {
+				st.Logger.Error("Invalid alert rule version found in DB store, ignoring it", "func", "GetAlertRuleVersions", "rule_uid", v.RuleUID, "version", v.Version, "error", err)
+				continue
+			}
+			result = append(result, &converted)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleVersion returns a single stored version of the alert rule.
+// Returns ngmodels.ErrAlertRuleVersionNotFound if the version does not exist or was pruned.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) (result *ngmodels.AlertRuleVersion, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		v := alertRuleVersion{}
+		has, err := sess.Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.UID, query.Version).Desc("id").Get(&v)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		converted, err := alertRuleVersionToModelsAlertRuleVersion(v, st.Logger)
+		if err != nil {
+			return fmt.Errorf("failed to convert alert rule version: %w", err)
+		}
+		result = &converted
+		return nil
+	})
+	return result, err
+}
+
 func (st DBstore) deleteOldAlertRuleVersions(ctx context.Context, ruleUID string, orgID int64, limit int) (int64, error) {
 	if limit < 0 {
 		return 0, fmt.Errorf("failed to delete old alert rule versions: limit is set to '%d' but needs to be > 0", limit)
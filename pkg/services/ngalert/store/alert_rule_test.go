@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/db"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -1601,3 +1602,66 @@ func TestIntegration_AlertRuleVersionsCleanup(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestIntegration_GetAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{
+		BaseInterval: time.Duration(rand.Int63n(100)+1) * time.Second,
+	}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		Cfg:           cfg.UnifiedAlerting,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        &logtest.Fake{},
+	}
+	gen := models.RuleGen
+	gen = gen.With(gen.WithIntervalMatching(store.Cfg.BaseInterval), gen.WithUniqueOrgID())
+
+	rule := gen.GenerateRef()
+	rule.UID = ""
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+	require.NoError(t, err)
+	rule, err = store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: ids[0].UID})
+	require.NoError(t, err)
+
+	editor := &user.SignedInUser{UserUID: "editor", UserID: 1, OrgID: rule.OrgID}
+	updated := models.CopyRule(rule)
+	updated.Title = util.GenerateShortUID()
+	err = store.UpdateAlertRules(identity.WithRequester(context.Background(), editor), []models.UpdateRule{{
+		Existing:     rule,
+		New:          *updated,
+		RestoredFrom: 1,
+	}})
+	require.NoError(t, err)
+
+	t.Run("should return versions with the latest first", func(t *testing.T) {
+		versions, err := store.GetAlertRuleVersions(context.Background(), &models.GetAlertRuleVersionsQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+
+		require.Equal(t, rule.Version+1, versions[0].Rule.Version)
+		require.Equal(t, updated.Title, versions[0].Rule.Title)
+		require.Equal(t, rule.Version, versions[0].ParentVersion)
+		require.EqualValues(t, 1, versions[0].RestoredFrom)
+		require.NotNil(t, versions[0].CreatedBy)
+		require.Equal(t, editor.GetUID(), *versions[0].CreatedBy)
+
+		require.Equal(t, rule.Version, versions[1].Rule.Version)
+		require.Equal(t, rule.Title, versions[1].Rule.Title)
+		require.Nil(t, versions[1].CreatedBy)
+	})
+
+	t.Run("should return a single version", func(t *testing.T) {
+		v, err := store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, UID: rule.UID, Version: rule.Version})
+		require.NoError(t, err)
+		require.Equal(t, rule.Title, v.Rule.Title)
+		require.Equal(t, rule.Data, v.Rule.Data)
+
+		_, err = store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{OrgID: rule.OrgID, UID: rule.UID, Version: rule.Version + 10})
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+	})
+}
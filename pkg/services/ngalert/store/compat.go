@@ -33,6 +33,7 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		RuleGroupIndex:  ar.RuleGroupIndex,
 		For:             ar.For,
 		IsPaused:        ar.IsPaused,
+		InstanceLimit:   ar.InstanceLimit,
 	}
 
 	if ar.NoDataState != "" {
@@ -88,6 +89,13 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		}
 	}
 
+	if ar.Dependencies != "" {
+		err = json.Unmarshal([]byte(ar.Dependencies), &result.Dependencies)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -118,6 +126,7 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		ExecErrState:    ar.ExecErrState.String(),
 		For:             ar.For,
 		IsPaused:        ar.IsPaused,
+		InstanceLimit:   ar.InstanceLimit,
 	}
 
 	// Serialize complex types to JSON strings
@@ -165,6 +174,14 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 	}
 	result.Metadata = string(metadata)
 
+	if len(ar.Dependencies) > 0 {
+		dependencies, err := json.Marshal(ar.Dependencies)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		result.Dependencies = string(dependencies)
+	}
+
 	return result, nil
 }
 
@@ -192,5 +209,44 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
 		Metadata:             rule.Metadata,
+		Dependencies:         rule.Dependencies,
+		InstanceLimit:        rule.InstanceLimit,
 	}
 }
+
+func alertRuleVersionToModelsAlertRuleVersion(v alertRuleVersion, l log.Logger) (models.AlertRuleVersion, error) {
+	rule, err := alertRuleToModelsAlertRule(alertRule{
+		OrgID:                v.RuleOrgID,
+		Title:                v.Title,
+		Condition:            v.Condition,
+		Data:                 v.Data,
+		Updated:              v.Created,
+		IntervalSeconds:      v.IntervalSeconds,
+		Version:              v.Version,
+		UID:                  v.RuleUID,
+		NamespaceUID:         v.RuleNamespaceUID,
+		RuleGroup:            v.RuleGroup,
+		RuleGroupIndex:       v.RuleGroupIndex,
+		Record:               v.Record,
+		NoDataState:          v.NoDataState,
+		ExecErrState:         v.ExecErrState,
+		For:                  v.For,
+		Annotations:          v.Annotations,
+		Labels:               v.Labels,
+		IsPaused:             v.IsPaused,
+		NotificationSettings: v.NotificationSettings,
+		Metadata:             v.Metadata,
+		Dependencies:         v.Dependencies,
+		InstanceLimit:        v.InstanceLimit,
+	}, l)
+	if err != nil {
+		return models.AlertRuleVersion{}, err
+	}
+	return models.AlertRuleVersion{
+		Rule:          rule,
+		ParentVersion: v.ParentVersion,
+		RestoredFrom:  v.RestoredFrom,
+		Created:       v.Created,
+		CreatedBy:     v.CreatedBy,
+	}, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -69,12 +70,18 @@ func (st DBstore) SaveAlertInstance(ctx context.Context, alertInstance models.Al
 			nullableTimeToUnix(alertInstance.ResolvedAt),
 			nullableTimeToUnix(alertInstance.LastSentAt),
 			alertInstance.ResultFingerprint,
+			alertInstance.AckBy,
+			alertInstance.AckComment,
+			nullableTimeToUnix(alertInstance.AckAt),
+			nullableTimeToUnix(alertInstance.AckExpiresAt),
+			alertInstance.AckSuppressNotifications,
+			alertInstance.AckSilenceID,
 		)
 
 		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
 			"alert_instance",
 			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "result_fingerprint"})
+			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "result_fingerprint", "ack_by", "ack_comment", "ack_at", "ack_expires_at", "ack_suppress_notifications", "ack_silence_id"})
 		_, err = sess.SQL(upsertSQL, params...).Query()
 		if err != nil {
 			return err
@@ -222,34 +229,8 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 		if _, err := sess.Exec("DELETE FROM alert_instance"); err != nil {
 			return fmt.Errorf("failed to delete alert_instance table: %w", err)
 		}
-		for _, alertInstance := range instances {
-			if err := models.ValidateAlertInstance(alertInstance); err != nil {
-				st.Logger.Warn("Failed to validate alert instance, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
-				continue
-			}
-			labelTupleJSON, err := alertInstance.Labels.StringKey()
-			if err != nil {
-				st.Logger.Warn("Failed to generate alert instance labels key, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
-				continue
-			}
-
-			_, err = sess.Exec(
-				"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)",
-				alertInstance.RuleOrgID,
-				alertInstance.RuleUID,
-				labelTupleJSON,
-				alertInstance.LabelsHash,
-				alertInstance.CurrentState,
-				alertInstance.CurrentReason,
-				alertInstance.CurrentStateSince.Unix(),
-				alertInstance.CurrentStateEnd.Unix(),
-				alertInstance.LastEvalTime.Unix(),
-				nullableTimeToUnix(alertInstance.ResolvedAt),
-				nullableTimeToUnix(alertInstance.LastSentAt),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to insert into alert_instance table: %w", err)
-			}
+		if err := insertAlertInstances(sess, st.Logger, instances); err != nil {
+			return err
 		}
 		if err := sess.Commit(); err != nil {
 			return fmt.Errorf("failed to commit alert_instance table: %w", err)
@@ -258,6 +239,46 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 	})
 }
 
+// insertAlertInstances inserts the alert instances into the alert_instance table. Invalid alert instances are skipped.
+func insertAlertInstances(sess *sqlstore.DBSession, logger log.Logger, instances []models.AlertInstance) error {
+	for _, alertInstance := range instances {
+		if err := models.ValidateAlertInstance(alertInstance); err != nil {
+			logger.Warn("Failed to validate alert instance, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
+			continue
+		}
+		labelTupleJSON, err := alertInstance.Labels.StringKey()
+		if err != nil {
+			logger.Warn("Failed to generate alert instance labels key, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
+			continue
+		}
+
+		_, err = sess.Exec(
+			"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at, ack_by, ack_comment, ack_at, ack_expires_at, ack_suppress_notifications, ack_silence_id) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			alertInstance.RuleOrgID,
+			alertInstance.RuleUID,
+			labelTupleJSON,
+			alertInstance.LabelsHash,
+			alertInstance.CurrentState,
+			alertInstance.CurrentReason,
+			alertInstance.CurrentStateSince.Unix(),
+			alertInstance.CurrentStateEnd.Unix(),
+			alertInstance.LastEvalTime.Unix(),
+			nullableTimeToUnix(alertInstance.ResolvedAt),
+			nullableTimeToUnix(alertInstance.LastSentAt),
+			alertInstance.AckBy,
+			alertInstance.AckComment,
+			nullableTimeToUnix(alertInstance.AckAt),
+			nullableTimeToUnix(alertInstance.AckExpiresAt),
+			alertInstance.AckSuppressNotifications,
+			alertInstance.AckSilenceID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert into alert_instance table: %w", err)
+		}
+	}
+	return nil
+}
+
 // nullableTimeToUnix converts a nullable time.Time to nil, if it is nil, otherwise it converts the time.Time to a unix timestamp.
 func nullableTimeToUnix(t *time.Time) *int64 {
 	if t == nil {
@@ -116,6 +116,53 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, instance.CurrentReason, alerts[0].CurrentReason)
 	})
 
+	t.Run("can save and read acknowledgement of alert instance", func(t *testing.T) {
+		rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+		labels := models.InstanceLabels{"test": "acknowledged"}
+		_, hash, _ := labels.StringAndHash()
+		instance := models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  rule.OrgID,
+				RuleUID:    rule.UID,
+				LabelsHash: hash,
+			},
+			CurrentState: models.InstanceStateFiring,
+			Labels:       labels,
+		}
+		expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+		ack := &models.AlertInstanceAcknowledgement{
+			By:                    "editor",
+			Comment:               "looking into it",
+			At:                    time.Unix(time.Now().Unix(), 0),
+			ExpiresAt:             &expiresAt,
+			SuppressNotifications: true,
+			SilenceID:             "silence",
+		}
+		instance.SetAcknowledgement(ack)
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+
+		alerts, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		actual := alerts[0].Acknowledgement()
+		require.NotNil(t, actual)
+		require.Equal(t, ack.By, actual.By)
+		require.Equal(t, ack.Comment, actual.Comment)
+		require.True(t, ack.At.Equal(actual.At))
+		require.True(t, ack.ExpiresAt.Equal(*actual.ExpiresAt))
+		require.True(t, actual.SuppressNotifications)
+		require.Equal(t, ack.SilenceID, actual.SilenceID)
+
+		instance.SetAcknowledgement(nil)
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+		alerts, err = dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Nil(t, alerts[0].Acknowledgement())
+
+		require.NoError(t, dbstore.DeleteAlertInstances(ctx, instance.AlertInstanceKey))
+	})
+
 	t.Run("can save and read new alert instance with no labels", func(t *testing.T) {
 		labels := models.InstanceLabels{}
 		_, hash, _ := labels.StringAndHash()
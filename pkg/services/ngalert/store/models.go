@@ -27,6 +27,8 @@ type alertRule struct {
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
 	Metadata             string `xorm:"metadata"`
+	Dependencies         string `xorm:"dependencies"`
+	InstanceLimit        int64  `xorm:"instance_limit"`
 }
 
 func (a alertRule) TableName() string {
@@ -59,8 +61,11 @@ type alertRuleVersion struct {
 	Annotations          string
 	Labels               string
 	IsPaused             bool
-	NotificationSettings string `xorm:"notification_settings"`
-	Metadata             string `xorm:"metadata"`
+	NotificationSettings string  `xorm:"notification_settings"`
+	Metadata             string  `xorm:"metadata"`
+	CreatedBy            *string `xorm:"created_by"`
+	Dependencies         string  `xorm:"dependencies"`
+	InstanceLimit        int64   `xorm:"instance_limit"`
 }
 
 func (a alertRuleVersion) TableName() string {
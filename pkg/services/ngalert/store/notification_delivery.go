@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationDeliveryStore stores the history of notification deliveries of the Grafana Alertmanager.
+type NotificationDeliveryStore interface {
+	// SaveNotificationDelivery saves the delivery and sets its ID.
+	SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	// ListNotificationDeliveries returns the deliveries that match the query, the most recent first.
+	ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]*models.NotificationDelivery, error)
+	// DeleteNotificationDeliveriesBefore deletes the deliveries sent before the given time. It returns the number of deleted deliveries.
+	DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// notificationDelivery represents a record in alert_notification_delivery table
+type notificationDelivery struct {
+	ID                int64  `xorm:"pk autoincr 'id'"`
+	OrgID             int64  `xorm:"org_id"`
+	Receiver          string `xorm:"receiver"`
+	Integration       string `xorm:"integration"`
+	IntegrationIndex  int    `xorm:"integration_index"`
+	GroupKey          string `xorm:"group_key"`
+	AlertFingerprints string `xorm:"alert_fingerprints"`
+	Firing            int    `xorm:"firing"`
+	Resolved          int    `xorm:"resolved"`
+	Status            string `xorm:"status"`
+	Error             string `xorm:"error"`
+	Retry             bool   `xorm:"retry"`
+	DurationMs        int64  `xorm:"duration_ms"`
+	SentAt            int64  `xorm:"sent_at"`
+}
+
+func (d notificationDelivery) TableName() string {
+	return "alert_notification_delivery"
+}
+
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := notificationDelivery{
+			OrgID:             delivery.OrgID,
+			Receiver:          delivery.Receiver,
+			Integration:       delivery.Integration,
+			IntegrationIndex:  delivery.IntegrationIndex,
+			GroupKey:          delivery.GroupKey,
+			AlertFingerprints: strings.Join(delivery.AlertFingerprints, ","),
+			Firing:            delivery.Firing,
+			Resolved:          delivery.Resolved,
+			Status:            string(delivery.Status),
+			Error:             delivery.Error,
+			Retry:             delivery.Retry,
+			DurationMs:        delivery.Duration.Milliseconds(),
+			SentAt:            delivery.SentAt.UnixMilli(),
+		}
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to save notification delivery: %w", err)
+		}
+		delivery.ID = row.ID
+		return nil
+	})
+}
+
+func (st DBstore) ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]*models.NotificationDelivery, error) {
+	var result []*models.NotificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(notificationDelivery{}).Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			q = q.And("integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", string(query.Status))
+		}
+		if query.AlertFingerprint != "" {
+			// The fingerprints are stored as a comma-separated list, so the fingerprint must be the whole list, or
+			// be delimited by commas.
+			fp := escapeLikePattern(query.AlertFingerprint)
+			q = q.And("(alert_fingerprints = ? OR alert_fingerprints LIKE ? ESCAPE '!' OR alert_fingerprints LIKE ? ESCAPE '!' OR alert_fingerprints LIKE ? ESCAPE '!')",
+				query.AlertFingerprint, fp+",%", "%,"+fp, "%,"+fp+",%")
+		}
+		if !query.From.IsZero() {
+			q = q.And("sent_at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			q = q.And("sent_at <= ?", query.To.UnixMilli())
+		}
+		q = q.Desc("sent_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+
+		var rows []notificationDelivery
+		if err := q.Find(&rows); err != nil {
+			return fmt.Errorf("failed to list notification deliveries: %w", err)
+		}
+		result = make([]*models.NotificationDelivery, 0, len(rows))
+		for _, row := range rows {
+			result = append(result, notificationDeliveryToModel(row))
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("sent_at < ?", before.UnixMilli()).Delete(&notificationDelivery{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification deliveries: %w", err)
+		}
+		n = rows
+		return nil
+	})
+	return n, err
+}
+
+// escapeLikePattern escapes the wildcards of a LIKE pattern using '!' as the escape character.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+func notificationDeliveryToModel(row notificationDelivery) *models.NotificationDelivery {
+	var fingerprints []string
+	if row.AlertFingerprints != "" {
+		fingerprints = strings.Split(row.AlertFingerprints, ",")
+	}
+	return &models.NotificationDelivery{
+		ID:                row.ID,
+		OrgID:             row.OrgID,
+		Receiver:          row.Receiver,
+		Integration:       row.Integration,
+		IntegrationIndex:  row.IntegrationIndex,
+		GroupKey:          row.GroupKey,
+		AlertFingerprints: fingerprints,
+		Firing:            row.Firing,
+		Resolved:          row.Resolved,
+		Status:            models.NotificationDeliveryStatus(row.Status),
+		Error:             row.Error,
+		Retry:             row.Retry,
+		Duration:          time.Duration(row.DurationMs) * time.Millisecond,
+		SentAt:            time.UnixMilli(row.SentAt).UTC(),
+	}
+}
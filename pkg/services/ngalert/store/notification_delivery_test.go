@@ -0,0 +1,143 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	deliveries := []*models.NotificationDelivery{
+		{
+			OrgID:             1,
+			Receiver:          "on-call",
+			Integration:       "pagerduty",
+			GroupKey:          "{}:{alertname=\"test\"}",
+			AlertFingerprints: []string{"aaaa", "bbbb"},
+			Firing:            2,
+			Status:            models.NotificationDeliverySuccess,
+			Duration:          150 * time.Millisecond,
+			SentAt:            now.Add(-2 * time.Hour),
+		},
+		{
+			OrgID:             1,
+			Receiver:          "on-call",
+			Integration:       "email",
+			IntegrationIndex:  1,
+			AlertFingerprints: []string{"aaaa"},
+			Resolved:          1,
+			Status:            models.NotificationDeliveryFailed,
+			Error:             "connection refused",
+			Retry:             true,
+			Duration:          time.Second,
+			SentAt:            now.Add(-time.Hour),
+		},
+		{
+			OrgID:             2,
+			Receiver:          "team",
+			Integration:       "slack",
+			AlertFingerprints: []string{"aaaabbbb"},
+			Status:            models.NotificationDeliverySuccess,
+			SentAt:            now,
+		},
+	}
+	for _, d := range deliveries {
+		require.NoError(t, dbstore.SaveNotificationDelivery(ctx, d))
+		require.NotZero(t, d.ID)
+	}
+
+	t.Run("should list deliveries of org with the most recent first", func(t *testing.T) {
+		result, err := dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*models.NotificationDelivery{deliveries[1], deliveries[0]}, result)
+	})
+
+	t.Run("should filter deliveries", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    models.ListNotificationDeliveriesQuery
+			expected []*models.NotificationDelivery
+		}{
+			{
+				name:     "by integration",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Integration: "pagerduty"},
+				expected: []*models.NotificationDelivery{deliveries[0]},
+			},
+			{
+				name:     "by status",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Status: models.NotificationDeliveryFailed},
+				expected: []*models.NotificationDelivery{deliveries[1]},
+			},
+			{
+				name:     "by alert fingerprint",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, AlertFingerprint: "bbbb"},
+				expected: []*models.NotificationDelivery{deliveries[0]},
+			},
+			{
+				name:     "by exact alert fingerprint",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 2, AlertFingerprint: "aaaa"},
+				expected: []*models.NotificationDelivery{},
+			},
+			{
+				name:     "by alert fingerprint that is the whole list or the first of the list",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, AlertFingerprint: "aaaa"},
+				expected: []*models.NotificationDelivery{deliveries[1], deliveries[0]},
+			},
+			{
+				name:     "by alert fingerprint with limit",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, AlertFingerprint: "bbbb", Limit: 1},
+				expected: []*models.NotificationDelivery{deliveries[0]},
+			},
+			{
+				name:     "by alert fingerprint with wildcards",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 2, AlertFingerprint: "aaaa%"},
+				expected: []*models.NotificationDelivery{},
+			},
+			{
+				name:     "by alert fingerprint with single character wildcards",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, AlertFingerprint: "b_bb"},
+				expected: []*models.NotificationDelivery{},
+			},
+			{
+				name:     "by time range",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, From: now.Add(-90 * time.Minute), To: now},
+				expected: []*models.NotificationDelivery{deliveries[1]},
+			},
+			{
+				name:     "by limit",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Limit: 1},
+				expected: []*models.NotificationDelivery{deliveries[1]},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				result, err := dbstore.ListNotificationDeliveries(ctx, tc.query)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, result)
+			})
+		}
+	})
+
+	t.Run("should delete deliveries sent before the given time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationDeliveriesBefore(ctx, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		result, err := dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+}
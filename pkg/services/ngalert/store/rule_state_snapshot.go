@@ -0,0 +1,298 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleStateSnapshot is a row of the alert_rule_state table. It holds a compressed snapshot of the alert instances of a rule.
+type ruleStateSnapshot struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	RuleUID   string    `xorm:"rule_uid"`
+	Data      []byte    `xorm:"data"`
+	UpdatedAt time.Time `xorm:"updated_at"`
+}
+
+func (ruleStateSnapshot) TableName() string {
+	return "alert_rule_state"
+}
+
+// RuleStateSnapshotStore stores the alert instances of each rule as a single compressed snapshot instead of a row per
+// alert instance. It is an alternative to the instance store of DBstore that is much faster to write and read when
+// there are many alert instances, but saving a single alert instance must rewrite the snapshot of its rule. The
+// alert_instance table is not updated while the snapshots are used, therefore all alert instances must be read through it.
+type RuleStateSnapshotStore struct {
+	SQLStore       db.DB
+	FeatureToggles featuremgmt.FeatureToggles
+	Logger         log.Logger
+}
+
+func NewRuleStateSnapshotStore(st *DBstore) *RuleStateSnapshotStore {
+	return &RuleStateSnapshotStore{
+		SQLStore:       st.SQLStore,
+		FeatureToggles: st.FeatureToggles,
+		Logger:         st.Logger,
+	}
+}
+
+// FetchOrgIds returns the IDs of the organizations that have alert instances.
+func (st *RuleStateSnapshotStore) FetchOrgIds(ctx context.Context) ([]int64, error) {
+	orgIDs := []int64{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL("SELECT DISTINCT org_id FROM alert_rule_state").Find(&orgIDs)
+	})
+	return orgIDs, err
+}
+
+// ListAlertInstances returns the alert instances of the organization, or of a single rule if the query has a rule UID.
+func (st *RuleStateSnapshotStore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var rows []ruleStateSnapshot
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", cmd.RuleOrgID)
+		if cmd.RuleUID != "" {
+			q = q.And("rule_uid = ?", cmd.RuleUID)
+		}
+		return q.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	skipNormal := st.FeatureToggles.IsEnabled(ctx, featuremgmt.FlagAlertingNoNormalState)
+	result := make([]*models.AlertInstance, 0)
+	for _, row := range rows {
+		instances, err := models.DecodeInstanceSnapshot(row.OrgID, row.RuleUID, row.Data)
+		if err != nil {
+			st.Logger.Warn("Failed to decode alert instances of rule, skipping", "org", row.OrgID, "rule_uid", row.RuleUID, "error", err)
+			continue
+		}
+		for _, instance := range instances {
+			if skipNormal && instance.CurrentState == models.InstanceStateNormal && instance.CurrentReason == "" {
+				continue
+			}
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
+
+// SaveRuleStates replaces the snapshot of the rule with the given alert instances. The snapshot is deleted if there
+// are no alert instances.
+func (st *RuleStateSnapshotStore) SaveRuleStates(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return saveRuleStateSnapshot(sess, key, instances)
+	})
+}
+
+// SaveAlertInstance adds or replaces the alert instance in the snapshot of its rule.
+func (st *RuleStateSnapshotStore) SaveAlertInstance(ctx context.Context, instance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(instance); err != nil {
+		return err
+	}
+	key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		instances, err := getRuleStateSnapshot(sess, key)
+		if err != nil {
+			return err
+		}
+		updated := make([]models.AlertInstance, 0, len(instances)+1)
+		for _, i := range instances {
+			if i.LabelsHash != instance.LabelsHash {
+				updated = append(updated, *i)
+			}
+		}
+		updated = append(updated, instance)
+		return saveRuleStateSnapshot(sess, key, updated)
+	})
+}
+
+// DeleteAlertInstances removes the alert instances from the snapshots of their rules.
+func (st *RuleStateSnapshotStore) DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	byRule := make(map[models.AlertRuleKey]map[string]struct{})
+	for _, k := range keys {
+		ruleKey := models.AlertRuleKey{OrgID: k.RuleOrgID, UID: k.RuleUID}
+		if byRule[ruleKey] == nil {
+			byRule[ruleKey] = make(map[string]struct{})
+		}
+		byRule[ruleKey][k.LabelsHash] = struct{}{}
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for ruleKey, hashes := range byRule {
+			instances, err := getRuleStateSnapshot(sess, ruleKey)
+			if err != nil {
+				return err
+			}
+			updated := make([]models.AlertInstance, 0, len(instances))
+			for _, i := range instances {
+				if _, ok := hashes[i.LabelsHash]; !ok {
+					updated = append(updated, *i)
+				}
+			}
+			if len(updated) == len(instances) {
+				continue
+			}
+			if err := saveRuleStateSnapshot(sess, ruleKey, updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteAlertInstancesByRule deletes the snapshot of the rule.
+func (st *RuleStateSnapshotStore) DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", key.OrgID, key.UID)
+		return err
+	})
+}
+
+// FullSync replaces the snapshots of all rules with the given alert instances.
+func (st *RuleStateSnapshotStore) FullSync(ctx context.Context, instances []models.AlertInstance) error {
+	byRule := make(map[models.AlertRuleKey][]models.AlertInstance)
+	for _, instance := range instances {
+		if err := models.ValidateAlertInstance(instance); err != nil {
+			st.Logger.Warn("Failed to validate alert instance, skipping", "err", err, "rule_uid", instance.RuleUID)
+			continue
+		}
+		key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+		byRule[key] = append(byRule[key], instance)
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_rule_state"); err != nil {
+			return fmt.Errorf("failed to delete alert_rule_state table: %w", err)
+		}
+		for key, ruleInstances := range byRule {
+			if err := saveRuleStateSnapshot(sess, key, ruleInstances); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateFromAlertInstances copies the alert instances of the alert_instance table to the snapshots if there are no
+// snapshots yet. The alert_instance table is left as is, and is not updated while the snapshots are used. Snapshots
+// are deleted by MigrateToAlertInstances when they are disabled, therefore existing snapshots are always newer than
+// the alert_instance table. It returns the number of migrated alert instances.
+func (st *RuleStateSnapshotStore) MigrateFromAlertInstances(ctx context.Context) (int, error) {
+	var migrated int
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		count, err := sess.Table("alert_rule_state").Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		var instances []*models.AlertInstance
+		if err := sess.SQL("SELECT * FROM alert_instance").Find(&instances); err != nil {
+			return err
+		}
+		byRule := make(map[models.AlertRuleKey][]models.AlertInstance)
+		for _, instance := range instances {
+			key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+			byRule[key] = append(byRule[key], *instance)
+		}
+		for key, ruleInstances := range byRule {
+			if err := saveRuleStateSnapshot(sess, key, ruleInstances); err != nil {
+				return err
+			}
+		}
+		migrated = len(instances)
+		return nil
+	})
+	if err == nil && migrated > 0 {
+		st.Logger.Info("Migrated alert instances to rule state snapshots", "instances", migrated)
+	}
+	return migrated, err
+}
+
+// MigrateToAlertInstances replaces the alert instances of the alert_instance table with the alert instances of the
+// snapshots, and deletes the snapshots. It is used when the snapshots are disabled, because the alert_instance table
+// is not updated while they are used. It does nothing if there are no snapshots. It returns the number of migrated
+// alert instances.
+func (st *RuleStateSnapshotStore) MigrateToAlertInstances(ctx context.Context) (int, error) {
+	var migrated int
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var rows []ruleStateSnapshot
+		if err := sess.Find(&rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		var instances []models.AlertInstance
+		for _, row := range rows {
+			ruleInstances, err := models.DecodeInstanceSnapshot(row.OrgID, row.RuleUID, row.Data)
+			if err != nil {
+				st.Logger.Warn("Failed to decode alert instances of rule, skipping", "org", row.OrgID, "rule_uid", row.RuleUID, "error", err)
+				continue
+			}
+			for _, instance := range ruleInstances {
+				instances = append(instances, *instance)
+			}
+		}
+		if _, err := sess.Exec("DELETE FROM alert_instance"); err != nil {
+			return fmt.Errorf("failed to delete alert_instance table: %w", err)
+		}
+		if err := insertAlertInstances(sess, st.Logger, instances); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("DELETE FROM alert_rule_state"); err != nil {
+			return fmt.Errorf("failed to delete alert_rule_state table: %w", err)
+		}
+		migrated = len(instances)
+		return nil
+	})
+	if err == nil && migrated > 0 {
+		st.Logger.Info("Migrated rule state snapshots to alert instances", "instances", migrated)
+	}
+	return migrated, err
+}
+
+func getRuleStateSnapshot(sess *db.Session, key models.AlertRuleKey) ([]*models.AlertInstance, error) {
+	var row ruleStateSnapshot
+	found, err := sess.Where("org_id = ? AND rule_uid = ?", key.OrgID, key.UID).Get(&row)
+	if err != nil || !found {
+		return nil, err
+	}
+	return models.DecodeInstanceSnapshot(row.OrgID, row.RuleUID, row.Data)
+}
+
+func saveRuleStateSnapshot(sess *db.Session, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	if _, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", key.OrgID, key.UID); err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+	// Sort the instances to produce the same snapshot for the same states.
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].LabelsHash < instances[j].LabelsHash
+	})
+	data, err := models.EncodeInstanceSnapshot(instances)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert instances of rule %s: %w", key.UID, err)
+	}
+	_, err = sess.Insert(&ruleStateSnapshot{
+		OrgID:     key.OrgID,
+		RuleUID:   key.UID,
+		Data:      data,
+		UpdatedAt: time.Now(),
+	})
+	return err
+}
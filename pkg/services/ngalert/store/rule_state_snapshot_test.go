@@ -0,0 +1,130 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationRuleStateSnapshotStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	snapshots := store.NewRuleStateSnapshotStore(dbstore)
+
+	const orgID int64 = 1
+	ruleA := models.AlertRuleKey{OrgID: orgID, UID: "a"}
+	ruleB := models.AlertRuleKey{OrgID: orgID, UID: "b"}
+
+	t.Run("should migrate alert instances if there are no snapshots", func(t *testing.T) {
+		require.NoError(t, dbstore.FullSync(ctx, []models.AlertInstance{
+			generateTestAlertInstance(orgID, ruleA.UID),
+			generateTestAlertInstance(orgID, ruleB.UID),
+		}))
+
+		migrated, err := snapshots.MigrateFromAlertInstances(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, migrated)
+
+		res, err := snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		orgIDs, err := snapshots.FetchOrgIds(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{orgID}, orgIDs)
+
+		// The alert instances are migrated only once.
+		migrated, err = snapshots.MigrateFromAlertInstances(ctx)
+		require.NoError(t, err)
+		require.Zero(t, migrated)
+	})
+
+	t.Run("should replace the alert instances of a rule", func(t *testing.T) {
+		instance := generateTestAlertInstance(orgID, ruleA.UID)
+		other := generateTestAlertInstance(orgID, ruleA.UID)
+		other.LabelsHash = "other"
+		require.NoError(t, snapshots.SaveRuleStates(ctx, ruleA, []models.AlertInstance{instance, other}))
+
+		res, err := snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleA.UID})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		require.NoError(t, snapshots.SaveRuleStates(ctx, ruleA, nil))
+		res, err = snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleA.UID})
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+
+	t.Run("should save and delete single alert instances", func(t *testing.T) {
+		instance := generateTestAlertInstance(orgID, ruleB.UID)
+		instance.CurrentState = models.InstanceStatePending
+		require.NoError(t, snapshots.SaveAlertInstance(ctx, instance))
+
+		res, err := snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleB.UID})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, models.InstanceStatePending, res[0].CurrentState)
+
+		require.NoError(t, snapshots.DeleteAlertInstances(ctx, instance.AlertInstanceKey))
+		res, err = snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleB.UID})
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+
+	t.Run("should do a full sync", func(t *testing.T) {
+		require.NoError(t, snapshots.FullSync(ctx, []models.AlertInstance{
+			generateTestAlertInstance(orgID, ruleA.UID),
+			generateTestAlertInstance(orgID, ruleB.UID),
+		}))
+		require.NoError(t, snapshots.DeleteAlertInstancesByRule(ctx, ruleA))
+
+		res, err := snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, ruleB.UID, res[0].RuleUID)
+	})
+
+	t.Run("should migrate snapshots back to alert instances and delete them", func(t *testing.T) {
+		require.NoError(t, dbstore.FullSync(ctx, []models.AlertInstance{
+			generateTestAlertInstance(orgID, ruleA.UID),
+		}))
+		require.NoError(t, snapshots.FullSync(ctx, []models.AlertInstance{
+			generateTestAlertInstance(orgID, ruleB.UID),
+		}))
+
+		migrated, err := snapshots.MigrateToAlertInstances(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, migrated)
+
+		// The snapshots replace the stale alert instances.
+		res, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, ruleB.UID, res[0].RuleUID)
+
+		res, err = snapshots.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		require.NoError(t, err)
+		require.Empty(t, res)
+
+		// Without snapshots, there is nothing to migrate and the alert instances are kept.
+		migrated, err = snapshots.MigrateToAlertInstances(ctx)
+		require.NoError(t, err)
+		require.Zero(t, migrated)
+		res, err = dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		// The snapshots are migrated again from the alert instances when they are enabled again.
+		migrated, err = snapshots.MigrateFromAlertInstances(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, migrated)
+	})
+}
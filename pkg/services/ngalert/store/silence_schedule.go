@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SilenceScheduleStore stores recurring silences.
+type SilenceScheduleStore interface {
+	ListSilenceSchedules(ctx context.Context, query models.ListSilenceSchedulesQuery) ([]*models.SilenceSchedule, error)
+	// GetSilenceSchedule returns models.ErrSilenceScheduleNotFound if the schedule does not exist.
+	GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*models.SilenceSchedule, error)
+	// InsertSilenceSchedule saves a new schedule. It generates the UID if it is empty and sets the ID.
+	InsertSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error
+	// UpdateSilenceSchedule replaces the schedule with the same organization, UID and version, and increments its
+	// version. It returns models.ErrSilenceScheduleNotFound if there is no such schedule.
+	UpdateSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error
+	// ApproveSilenceSchedule approves a pending schedule if its version is still equal to version. It returns false
+	// if the schedule was changed or approved concurrently.
+	ApproveSilenceSchedule(ctx context.Context, orgID int64, uid string, version int64, approvedBy string, approvedAt time.Time) (bool, error)
+	DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error
+	// SetSilenceScheduleSilenceID replaces the ID of the silence created by the schedule if it is still equal to
+	// oldID. It returns false if the ID was changed concurrently.
+	SetSilenceScheduleSilenceID(ctx context.Context, orgID int64, uid string, oldID, newID string) (bool, error)
+}
+
+// silenceSchedule represents a record in alert_silence_schedule table
+type silenceSchedule struct {
+	ID                 int64      `xorm:"pk autoincr 'id'"`
+	OrgID              int64      `xorm:"org_id"`
+	UID                string     `xorm:"uid"`
+	Matchers           string     `xorm:"matchers"`
+	Comment            string     `xorm:"comment"`
+	TimeIntervals      string     `xorm:"time_intervals"`
+	CreatedBy          string     `xorm:"created_by"`
+	CreatedByUID       string     `xorm:"created_by_uid"`
+	RequiresApprovalBy string     `xorm:"requires_approval_by"`
+	State              string     `xorm:"state"`
+	ApprovedBy         string     `xorm:"approved_by"`
+	ApprovedAt         *time.Time `xorm:"approved_at"`
+	SilenceID          string     `xorm:"silence_id"`
+	Updated            time.Time  `xorm:"updated"`
+	Version            int64      `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+}
+
+func (s silenceSchedule) TableName() string {
+	return "alert_silence_schedule"
+}
+
+func (st DBstore) ListSilenceSchedules(ctx context.Context, query models.ListSilenceSchedulesQuery) ([]*models.SilenceSchedule, error) {
+	var result []*models.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(silenceSchedule{})
+		if query.OrgID > 0 {
+			q = q.Where("org_id = ?", query.OrgID)
+		}
+		var rows []silenceSchedule
+		if err := q.Asc("org_id", "id").Find(&rows); err != nil {
+			return fmt.Errorf("failed to list silence schedules: %w", err)
+		}
+		result = make([]*models.SilenceSchedule, 0, len(rows))
+		for _, row := range rows {
+			schedule, err := silenceScheduleToModel(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, schedule)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (st DBstore) GetSilenceSchedule(ctx context.Context, orgID int64, uid string) (*models.SilenceSchedule, error) {
+	var result *models.SilenceSchedule
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var row silenceSchedule
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return fmt.Errorf("failed to get silence schedule: %w", err)
+		}
+		if !has {
+			return models.ErrSilenceScheduleNotFound
+		}
+		result, err = silenceScheduleToModel(row)
+		return err
+	})
+	return result, err
+}
+
+func (st DBstore) InsertSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error {
+	if schedule.UID == "" {
+		schedule.UID = util.GenerateShortUID()
+	}
+	schedule.Updated = TimeNow()
+	row, err := silenceScheduleFromModel(schedule)
+	if err != nil {
+		return err
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to insert silence schedule: %w", err)
+		}
+		schedule.ID = row.ID
+		schedule.Version = row.Version
+		return nil
+	})
+}
+
+func (st DBstore) UpdateSilenceSchedule(ctx context.Context, schedule *models.SilenceSchedule) error {
+	schedule.Updated = TimeNow()
+	row, err := silenceScheduleFromModel(schedule)
+	if err != nil {
+		return err
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		n, err := sess.Where("org_id = ? AND uid = ?", schedule.OrgID, schedule.UID).
+			Cols("matchers", "comment", "time_intervals", "created_by", "created_by_uid", "requires_approval_by", "state", "approved_by", "approved_at", "silence_id", "updated").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to update silence schedule: %w", err)
+		}
+		if n == 0 {
+			return models.ErrSilenceScheduleNotFound
+		}
+		schedule.Version = row.Version
+		return nil
+	})
+}
+
+func (st DBstore) ApproveSilenceSchedule(ctx context.Context, orgID int64, uid string, version int64, approvedBy string, approvedAt time.Time) (bool, error) {
+	var updated bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := silenceSchedule{
+			State:      string(models.SilenceScheduleApproved),
+			ApprovedBy: approvedBy,
+			ApprovedAt: &approvedAt,
+			Updated:    TimeNow(),
+			Version:    version,
+		}
+		n, err := sess.Where("org_id = ? AND uid = ? AND state = ?", orgID, uid, string(models.SilenceSchedulePending)).
+			Cols("state", "approved_by", "approved_at", "updated").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to approve silence schedule: %w", err)
+		}
+		updated = n > 0
+		return nil
+	})
+	return updated, err
+}
+
+func (st DBstore) DeleteSilenceSchedule(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&silenceSchedule{}); err != nil {
+			return fmt.Errorf("failed to delete silence schedule: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) SetSilenceScheduleSilenceID(ctx context.Context, orgID int64, uid string, oldID, newID string) (bool, error) {
+	var updated bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		// The silence does not change the schedule, so the version is not incremented.
+		n, err := sess.Table(silenceSchedule{}).Where("org_id = ? AND uid = ? AND silence_id = ?", orgID, uid, oldID).
+			Update(map[string]any{"silence_id": newID})
+		if err != nil {
+			return fmt.Errorf("failed to update silence of silence schedule: %w", err)
+		}
+		updated = n > 0
+		return nil
+	})
+	return updated, err
+}
+
+func silenceScheduleFromModel(s *models.SilenceSchedule) (silenceSchedule, error) {
+	matchers, err := json.Marshal(s.Matchers)
+	if err != nil {
+		return silenceSchedule{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	intervals, err := json.Marshal(s.TimeIntervals)
+	if err != nil {
+		return silenceSchedule{}, fmt.Errorf("failed to marshal time intervals: %w", err)
+	}
+	return silenceSchedule{
+		ID:                 s.ID,
+		OrgID:              s.OrgID,
+		UID:                s.UID,
+		Matchers:           string(matchers),
+		Comment:            s.Comment,
+		TimeIntervals:      string(intervals),
+		CreatedBy:          s.CreatedBy,
+		CreatedByUID:       s.CreatedByUID,
+		RequiresApprovalBy: s.RequiresApprovalBy,
+		State:              string(s.State),
+		ApprovedBy:         s.ApprovedBy,
+		ApprovedAt:         s.ApprovedAt,
+		SilenceID:          s.SilenceID,
+		Updated:            s.Updated,
+		Version:            s.Version,
+	}, nil
+}
+
+func silenceScheduleToModel(row silenceSchedule) (*models.SilenceSchedule, error) {
+	var matchers amv2.Matchers
+	if err := json.Unmarshal([]byte(row.Matchers), &matchers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal matchers of silence schedule %s: %w", row.UID, err)
+	}
+	var intervals []timeinterval.TimeInterval
+	if err := json.Unmarshal([]byte(row.TimeIntervals), &intervals); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal time intervals of silence schedule %s: %w", row.UID, err)
+	}
+	return &models.SilenceSchedule{
+		ID:                 row.ID,
+		UID:                row.UID,
+		OrgID:              row.OrgID,
+		Matchers:           matchers,
+		Comment:            row.Comment,
+		TimeIntervals:      intervals,
+		CreatedBy:          row.CreatedBy,
+		CreatedByUID:       row.CreatedByUID,
+		RequiresApprovalBy: row.RequiresApprovalBy,
+		State:              models.SilenceScheduleState(row.State),
+		ApprovedBy:         row.ApprovedBy,
+		ApprovedAt:         row.ApprovedAt,
+		SilenceID:          row.SilenceID,
+		Updated:            row.Updated,
+		Version:            row.Version,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegrationSilenceSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	var intervals []timeinterval.TimeInterval
+	require.NoError(t, json.Unmarshal([]byte(`[{"times":[{"start_time":"22:00","end_time":"23:30"}],"weekdays":["saturday"],"location":"Europe/Berlin"}]`), &intervals))
+
+	schedule := &models.SilenceSchedule{
+		OrgID: 1,
+		Matchers: amv2.Matchers{
+			{Name: util.Pointer("service"), Value: util.Pointer("db"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)},
+		},
+		Comment:            "weekly maintenance",
+		TimeIntervals:      intervals,
+		CreatedBy:          "editor",
+		CreatedByUID:       "editor-uid",
+		RequiresApprovalBy: "Admin",
+		State:              models.SilenceSchedulePending,
+	}
+	require.NoError(t, dbstore.InsertSilenceSchedule(ctx, schedule))
+	require.NotEmpty(t, schedule.UID)
+	require.NotZero(t, schedule.ID)
+
+	other := &models.SilenceSchedule{
+		OrgID:         2,
+		Matchers:      schedule.Matchers,
+		TimeIntervals: intervals,
+		State:         models.SilenceScheduleApproved,
+	}
+	require.NoError(t, dbstore.InsertSilenceSchedule(ctx, other))
+
+	t.Run("get returns the saved schedule", func(t *testing.T) {
+		got, err := dbstore.GetSilenceSchedule(ctx, 1, schedule.UID)
+		require.NoError(t, err)
+		require.Equal(t, schedule.Comment, got.Comment)
+		require.Equal(t, schedule.Matchers, got.Matchers)
+		require.Equal(t, "Europe/Berlin", got.TimeIntervals[0].Location.String())
+		require.Equal(t, schedule.TimeIntervals[0].Times, got.TimeIntervals[0].Times)
+		require.Equal(t, models.SilenceSchedulePending, got.State)
+		require.Nil(t, got.ApprovedAt)
+
+		_, err = dbstore.GetSilenceSchedule(ctx, 2, schedule.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("list filters by organization", func(t *testing.T) {
+		result, err := dbstore.ListSilenceSchedules(ctx, models.ListSilenceSchedulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, schedule.UID, result[0].UID)
+
+		result, err = dbstore.ListSilenceSchedules(ctx, models.ListSilenceSchedulesQuery{})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("update replaces the schedule", func(t *testing.T) {
+		approvedAt := time.Now().UTC().Truncate(time.Second)
+		schedule.State = models.SilenceScheduleApproved
+		schedule.ApprovedBy = "admin"
+		schedule.ApprovedAt = &approvedAt
+		require.NoError(t, dbstore.UpdateSilenceSchedule(ctx, schedule))
+
+		got, err := dbstore.GetSilenceSchedule(ctx, 1, schedule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceScheduleApproved, got.State)
+		require.Equal(t, "admin", got.ApprovedBy)
+		require.NotNil(t, got.ApprovedAt)
+		require.Equal(t, approvedAt.Unix(), got.ApprovedAt.Unix())
+
+		err = dbstore.UpdateSilenceSchedule(ctx, &models.SilenceSchedule{OrgID: 1, UID: "missing"})
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+	})
+
+	t.Run("approve updates only a pending schedule with the same version", func(t *testing.T) {
+		pending := &models.SilenceSchedule{
+			OrgID:              1,
+			Matchers:           schedule.Matchers,
+			Comment:            "pending",
+			TimeIntervals:      intervals,
+			RequiresApprovalBy: "Admin",
+			State:              models.SilenceSchedulePending,
+		}
+		require.NoError(t, dbstore.InsertSilenceSchedule(ctx, pending))
+		read, err := dbstore.GetSilenceSchedule(ctx, 1, pending.UID)
+		require.NoError(t, err)
+
+		pending.Comment = "changed"
+		require.NoError(t, dbstore.UpdateSilenceSchedule(ctx, pending))
+		approvedAt := time.Now().UTC().Truncate(time.Second)
+		ok, err := dbstore.ApproveSilenceSchedule(ctx, 1, pending.UID, read.Version, "admin", approvedAt)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		read, err = dbstore.GetSilenceSchedule(ctx, 1, pending.UID)
+		require.NoError(t, err)
+		ok, err = dbstore.ApproveSilenceSchedule(ctx, 1, pending.UID, read.Version, "admin", approvedAt)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := dbstore.GetSilenceSchedule(ctx, 1, pending.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceScheduleApproved, got.State)
+		require.Equal(t, "admin", got.ApprovedBy)
+		require.Equal(t, "changed", got.Comment)
+		require.Equal(t, approvedAt.Unix(), got.ApprovedAt.Unix())
+
+		ok, err = dbstore.ApproveSilenceSchedule(ctx, 1, pending.UID, got.Version, "admin", approvedAt)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, dbstore.DeleteSilenceSchedule(ctx, 1, pending.UID))
+	})
+
+	t.Run("silence ID is updated only if it did not change", func(t *testing.T) {
+		ok, err := dbstore.SetSilenceScheduleSilenceID(ctx, 1, schedule.UID, "", "silence-1")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = dbstore.SetSilenceScheduleSilenceID(ctx, 1, schedule.UID, "", "silence-2")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err := dbstore.GetSilenceSchedule(ctx, 1, schedule.UID)
+		require.NoError(t, err)
+		require.Equal(t, "silence-1", got.SilenceID)
+	})
+
+	t.Run("delete removes the schedule", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSilenceSchedule(ctx, 1, schedule.UID))
+		_, err := dbstore.GetSilenceSchedule(ctx, 1, schedule.UID)
+		require.ErrorIs(t, err, models.ErrSilenceScheduleNotFound)
+	})
+}
@@ -25,6 +25,8 @@ type RuleStore struct {
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
+	// Versions holds the version history of rules, the latest version first
+	Versions map[models.AlertRuleKey][]*models.AlertRuleVersion
 }
 
 type GenericRecordedQuery struct {
@@ -39,7 +41,8 @@ func NewRuleStore(t *testing.T) *RuleStore {
 		Hook: func(any) error {
 			return nil
 		},
-		Folders: map[int64][]*folder.Folder{},
+		Folders:  map[int64][]*folder.Folder{},
+		Versions: map[models.AlertRuleKey][]*models.AlertRuleVersion{},
 	}
 }
 
@@ -144,6 +147,31 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil, models.ErrAlertRuleNotFound
 }
 
+func (f *RuleStore) GetAlertRuleVersions(_ context.Context, q *models.GetAlertRuleVersionsQuery) ([]*models.AlertRuleVersion, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return nil, err
+	}
+	return f.Versions[models.AlertRuleKey{OrgID: q.OrgID, UID: q.UID}], nil
+}
+
+func (f *RuleStore) GetAlertRuleVersion(_ context.Context, q *models.GetAlertRuleVersionQuery) (*models.AlertRuleVersion, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return nil, err
+	}
+	for _, v := range f.Versions[models.AlertRuleKey{OrgID: q.OrgID, UID: q.UID}] {
+		if v.Rule.Version == q.Version {
+			return v, nil
+		}
+	}
+	return nil, models.ErrAlertRuleVersionNotFound
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -0,0 +1,377 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-sql-driver/mysql"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	infrahttp "github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// DefaultSQLTable is the table that recording rules write to when the target is a SQL data source that does not set
+// the table in the recordingRulesTable field of its JSON data.
+const DefaultSQLTable = "grafana_recorded_metrics"
+
+var ErrUnsupportedTarget = errors.New("data source type is not supported as a target of recording rules")
+
+// Writer writes the result of a recording rule to a single target.
+type Writer interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+}
+
+// DatasourceWriter writes the results of recording rules to the data source that the rule targets. Rules without
+// a target are written by the default writer, which is configured in the recording_rules section of the settings.
+//
+// The supported targets are Prometheus compatible data sources with remote write, InfluxDB, PostgreSQL and MySQL.
+// Writers of data sources are cached until the data source is updated, and closed when the writes that use them
+// complete.
+type DatasourceWriter struct {
+	defaultWriter      Writer
+	datasources        datasources.DataSourceService
+	httpClientProvider infrahttp.Provider
+	settings           setting.RecordingRuleSettings
+	clock              clock.Clock
+	logger             log.Logger
+	metrics            *metrics.RemoteWriter
+
+	mtx     sync.Mutex
+	writers map[datasourceWriterKey]*cachedWriter
+}
+
+type datasourceWriterKey struct {
+	orgID int64
+	uid   string
+}
+
+// cachedWriter is a writer of a data source with the number of writes that use it. The writer is closed when it is
+// evicted from the cache and no write uses it anymore.
+type cachedWriter struct {
+	version int
+	writer  Writer
+	refs    int
+	evicted bool
+}
+
+func NewDatasourceWriter(
+	defaultWriter Writer,
+	datasources datasources.DataSourceService,
+	httpClientProvider infrahttp.Provider,
+	settings setting.RecordingRuleSettings,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) *DatasourceWriter {
+	return &DatasourceWriter{
+		defaultWriter:      defaultWriter,
+		datasources:        datasources,
+		httpClientProvider: httpClientProvider,
+		settings:           settings,
+		clock:              clock,
+		logger:             l,
+		metrics:            metrics,
+		writers:            make(map[datasourceWriterKey]*cachedWriter),
+	}
+}
+
+// Write writes the given frames with the default writer.
+func (w *DatasourceWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	return w.defaultWriter.Write(ctx, name, t, frames, orgID, extraLabels)
+}
+
+// WriteDatasource writes the given frames to the data source with the given UID, or with the default writer if the
+// UID is empty.
+func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	if dsUID == "" {
+		return w.Write(ctx, name, t, frames, orgID, extraLabels)
+	}
+	target, err := w.acquire(ctx, orgID, dsUID)
+	if err != nil {
+		return errors.Join(ErrWriteFailure, err)
+	}
+	defer w.release(target)
+	return target.writer.Write(ctx, name, t, frames, orgID, extraLabels)
+}
+
+// TargetValidator validates the data sources that recording rules write to when the rules are saved.
+type TargetValidator struct {
+	datasources datasources.DataSourceService
+}
+
+func NewTargetValidator(datasources datasources.DataSourceService) *TargetValidator {
+	return &TargetValidator{datasources: datasources}
+}
+
+// ValidateTarget checks that the data source with the given UID exists and is supported as a target of recording
+// rules.
+func (v *TargetValidator) ValidateTarget(ctx context.Context, orgID int64, dsUID string) error {
+	ds, err := v.datasources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: dsUID, OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to get target data source %s: %w", dsUID, err)
+	}
+	switch ds.Type {
+	case datasources.DS_PROMETHEUS, datasources.DS_INFLUXDB:
+		return nil
+	case datasources.DS_POSTGRES, "postgres", datasources.DS_MYSQL:
+		if table := sqlTable(ds); !sqlTableRegexp.MatchString(table) {
+			return fmt.Errorf("invalid table name of target data source %s: %q", dsUID, table)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedTarget, ds.Type)
+	}
+}
+
+// acquire returns the writer of the data source, and counts the write that uses it until it is released.
+func (w *DatasourceWriter) acquire(ctx context.Context, orgID int64, uid string) (*cachedWriter, error) {
+	ds, err := w.datasources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target data source %s: %w", uid, err)
+	}
+
+	key := datasourceWriterKey{orgID: orgID, uid: uid}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if cached, ok := w.writers[key]; ok {
+		if cached.version == ds.Version {
+			cached.refs++
+			return cached, nil
+		}
+		delete(w.writers, key)
+		cached.evicted = true
+		if cached.refs == 0 {
+			closeWriter(cached.writer)
+		}
+	}
+
+	writer, err := w.newWriter(ctx, ds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create writer for target data source %s: %w", uid, err)
+	}
+	cached := &cachedWriter{version: ds.Version, writer: writer, refs: 1}
+	w.writers[key] = cached
+	return cached, nil
+}
+
+// release ends a write that uses the writer, and closes the writer if it was evicted and no other write uses it.
+func (w *DatasourceWriter) release(cached *cachedWriter) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	cached.refs--
+	if cached.evicted && cached.refs == 0 {
+		closeWriter(cached.writer)
+	}
+}
+
+func (w *DatasourceWriter) newWriter(ctx context.Context, ds *datasources.DataSource) (Writer, error) {
+	logger := w.logger.New("datasource_uid", ds.UID, "datasource_type", ds.Type)
+	switch ds.Type {
+	case datasources.DS_PROMETHEUS:
+		client, err := w.httpClient(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		u, err := url.JoinPath(ds.URL, RemoteWriteEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		settings := setting.RecordingRuleSettings{URL: u, Timeout: w.settings.Timeout}
+		return NewPrometheusWriter(settings, staticClientProvider{client: client}, w.clock, logger, w.metrics)
+	case datasources.DS_INFLUXDB:
+		client, err := w.httpClient(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		settings, err := w.influxDBSettings(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		return NewInfluxDBWriter(settings, client, w.clock, logger, w.metrics)
+	case datasources.DS_POSTGRES, "postgres", datasources.DS_MYSQL:
+		settings, err := w.sqlSettings(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLWriter(settings, w.clock, logger, w.metrics)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTarget, ds.Type)
+	}
+}
+
+func (w *DatasourceWriter) httpClient(ctx context.Context, ds *datasources.DataSource) (*http.Client, error) {
+	rt, err := w.datasources.GetHTTPTransport(ctx, ds, w.httpClientProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+func (w *DatasourceWriter) influxDBSettings(ctx context.Context, ds *datasources.DataSource) (InfluxDBSettings, error) {
+	settings := InfluxDBSettings{URL: ds.URL, Database: ds.Database, User: ds.User, Timeout: w.settings.Timeout}
+	if ds.JsonData != nil {
+		if db := ds.JsonData.Get("dbName").MustString(); db != "" {
+			settings.Database = db
+		}
+		// Data sources with Flux or SQL as query language use the API of InfluxDB 2.x.
+		if version := ds.JsonData.Get("version").MustString(); version == "Flux" || version == "SQL" {
+			if bucket := ds.JsonData.Get("defaultBucket").MustString(); bucket != "" {
+				settings.Database = bucket
+			}
+			settings.Organization = ds.JsonData.Get("organization").MustString()
+			token, _, err := w.datasources.DecryptedValue(ctx, ds, "token")
+			if err != nil {
+				return InfluxDBSettings{}, err
+			}
+			settings.Token = token
+			return settings, nil
+		}
+	}
+	if settings.User != "" {
+		password, err := w.datasources.DecryptedPassword(ctx, ds)
+		if err != nil {
+			return InfluxDBSettings{}, err
+		}
+		settings.Password = password
+	}
+	return settings, nil
+}
+
+// sqlSettings returns the settings of the SQL writer of the data source. The connection and TLS settings are read
+// from the JSON data and the secure JSON data of the data source like the PostgreSQL and MySQL data sources do.
+func (w *DatasourceWriter) sqlSettings(ctx context.Context, ds *datasources.DataSource) (SQLSettings, error) {
+	secure, err := w.datasources.DecryptedValues(ctx, ds)
+	if err != nil {
+		return SQLSettings{}, err
+	}
+	jsonData := ds.JsonData
+	if jsonData == nil {
+		jsonData = simplejson.New()
+	}
+	database := ds.Database
+	if db := jsonData.Get("database").MustString(); db != "" {
+		database = db
+	}
+
+	settings := SQLSettings{Table: sqlTable(ds), Timeout: w.settings.Timeout}
+	if ds.Type == datasources.DS_MYSQL {
+		cfg := mysql.NewConfig()
+		cfg.User = ds.User
+		cfg.Passwd = secure["password"]
+		cfg.Net = "tcp"
+		cfg.Addr = ds.URL
+		if strings.HasPrefix(ds.URL, "/") {
+			cfg.Net = "unix"
+		}
+		cfg.DBName = database
+		cfg.ParseTime = true
+		cfg.AllowNativePasswords = true
+		cfg.AllowCleartextPasswords = jsonData.Get("allowCleartextPasswords").MustBool(false)
+
+		tlsConfig, err := sdkhttpclient.GetTLSConfig(sdkhttpclient.Options{TLS: mysqlTLSOptions(jsonData, secure)})
+		if err != nil {
+			return SQLSettings{}, err
+		}
+		if tlsConfig.RootCAs != nil || len(tlsConfig.Certificates) > 0 || tlsConfig.InsecureSkipVerify {
+			// The driver refers to custom TLS configurations by name in the DSN.
+			name := fmt.Sprintf("ngalert-recording-ds%d", ds.ID)
+			if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+				return SQLSettings{}, err
+			}
+			cfg.TLSConfig = name
+		}
+		settings.Dialect = SQLDialectMySQL
+		settings.DSN = cfg.FormatDSN()
+		return settings, nil
+	}
+
+	host, port, err := net.SplitHostPort(ds.URL)
+	if err != nil {
+		host, port = ds.URL, "5432"
+	}
+	params := url.Values{"sslmode": []string{jsonData.Get("sslmode").MustString("verify-full")}}
+	if params.Get("sslmode") != "disable" {
+		if jsonData.Get("tlsConfigurationMethod").MustString() == "file-content" {
+			// The certificates are stored in the secure JSON data, and passed to the driver in the DSN.
+			params.Set("sslinline", "true")
+			setNotEmpty(params, "sslrootcert", secure["tlsCACert"])
+			setNotEmpty(params, "sslcert", secure["tlsClientCert"])
+			setNotEmpty(params, "sslkey", secure["tlsClientKey"])
+		} else {
+			setNotEmpty(params, "sslrootcert", jsonData.Get("sslRootCertFile").MustString())
+			setNotEmpty(params, "sslcert", jsonData.Get("sslCertFile").MustString())
+			setNotEmpty(params, "sslkey", jsonData.Get("sslKeyFile").MustString())
+		}
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(ds.User, secure["password"]),
+		Host:     net.JoinHostPort(host, port),
+		Path:     database,
+		RawQuery: params.Encode(),
+	}
+	settings.Dialect = SQLDialectPostgres
+	settings.DSN = dsn.String()
+	return settings, nil
+}
+
+// mysqlTLSOptions returns the TLS options of a MySQL data source.
+func mysqlTLSOptions(jsonData *simplejson.Json, secure map[string]string) *sdkhttpclient.TLSOptions {
+	opts := &sdkhttpclient.TLSOptions{
+		InsecureSkipVerify: jsonData.Get("tlsSkipVerify").MustBool(false),
+		ServerName:         jsonData.Get("serverName").MustString(),
+	}
+	if jsonData.Get("tlsAuthWithCACert").MustBool(false) {
+		opts.CACertificate = secure["tlsCACert"]
+	}
+	if jsonData.Get("tlsAuth").MustBool(false) {
+		opts.ClientCertificate = secure["tlsClientCert"]
+		opts.ClientKey = secure["tlsClientKey"]
+	}
+	return opts
+}
+
+// sqlTable returns the table of a SQL data source that recording rules write to.
+func sqlTable(ds *datasources.DataSource) string {
+	if ds.JsonData != nil {
+		if table := ds.JsonData.Get("recordingRulesTable").MustString(); table != "" {
+			return table
+		}
+	}
+	return DefaultSQLTable
+}
+
+func setNotEmpty(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
+
+func closeWriter(w Writer) {
+	if c, ok := w.(interface{ Close() error }); ok {
+		_ = c.Close()
+	}
+}
+
+// staticClientProvider provides the HTTP client of a data source to writers that create their own clients.
+type staticClientProvider struct {
+	client *http.Client
+}
+
+func (p staticClientProvider) New(...sdkhttpclient.Options) (*http.Client, error) {
+	return p.client, nil
+}
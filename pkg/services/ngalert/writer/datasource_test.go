@@ -0,0 +1,194 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestDatasourceWriter(t *testing.T) {
+	dsService := &fakes.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{UID: "prom", OrgID: 1, Type: datasources.DS_PROMETHEUS, URL: "http://prometheus:9090"},
+		{UID: "influx", OrgID: 1, Type: datasources.DS_INFLUXDB, URL: "http://influxdb:8086", JsonData: simplejson.NewFromAny(map[string]any{"dbName": "metrics"})},
+		{UID: "postgres", OrgID: 1, Type: datasources.DS_POSTGRES, URL: "postgres:5432", User: "grafana", JsonData: simplejson.NewFromAny(map[string]any{"database": "metrics", "sslmode": "disable", "recordingRulesTable": "recorded_metrics"})},
+		{UID: "mysql", OrgID: 1, Type: datasources.DS_MYSQL, URL: "mysql:3306", User: "grafana", Database: "metrics"},
+		{UID: "postgres-tls", OrgID: 1, Type: datasources.DS_POSTGRES, URL: "postgres:5432", User: "grafana", JsonData: simplejson.NewFromAny(map[string]any{"database": "metrics", "sslmode": "verify-ca", "tlsConfigurationMethod": "file-path", "sslRootCertFile": "/etc/ssl/ca.pem"})},
+		{UID: "mysql-tls", OrgID: 1, Type: datasources.DS_MYSQL, URL: "mysql:3306", User: "grafana", Database: "metrics", JsonData: simplejson.NewFromAny(map[string]any{"tlsSkipVerify": true})},
+		{UID: "mysql-invalid-table", OrgID: 1, Type: datasources.DS_MYSQL, URL: "mysql:3306", JsonData: simplejson.NewFromAny(map[string]any{"recordingRulesTable": "metrics; DROP TABLE user"})},
+		{UID: "loki", OrgID: 1, Type: datasources.DS_LOKI, URL: "http://loki:3100"},
+	}}
+	settings := setting.RecordingRuleSettings{Timeout: time.Second}
+	var defaultCalls int
+	defaultWriter := FakeWriter{WriteFunc: func(context.Context, string, time.Time, data.Frames, int64, map[string]string) error {
+		defaultCalls++
+		return nil
+	}}
+	w := NewDatasourceWriter(defaultWriter, dsService, httpclient.NewProvider(), settings, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	ctx := context.Background()
+
+	t.Run("writes rules without a target with the default writer", func(t *testing.T) {
+		require.NoError(t, w.WriteDatasource(ctx, "", "test", time.Now(), nil, 1, nil))
+		require.Equal(t, 1, defaultCalls)
+	})
+
+	t.Run("creates writers by the type of the target", func(t *testing.T) {
+		for uid, expected := range map[string]any{
+			"prom":     &PrometheusWriter{},
+			"influx":   &InfluxDBWriter{},
+			"postgres": &SQLWriter{},
+			"mysql":    &SQLWriter{},
+		} {
+			target, err := w.acquire(ctx, 1, uid)
+			require.NoError(t, err)
+			require.IsType(t, expected, target.writer)
+			w.release(target)
+		}
+	})
+
+	t.Run("reads the SQL settings from the data source", func(t *testing.T) {
+		for uid, expected := range map[string]SQLSettings{
+			"postgres":     {Dialect: SQLDialectPostgres, DSN: "postgres://grafana:@postgres:5432/metrics?sslmode=disable", Table: "recorded_metrics", Timeout: time.Second},
+			"postgres-tls": {Dialect: SQLDialectPostgres, DSN: "postgres://grafana:@postgres:5432/metrics?sslmode=verify-ca&sslrootcert=%2Fetc%2Fssl%2Fca.pem", Table: DefaultSQLTable, Timeout: time.Second},
+			"mysql":        {Dialect: SQLDialectMySQL, DSN: "grafana@tcp(mysql:3306)/metrics?parseTime=true", Table: DefaultSQLTable, Timeout: time.Second},
+			"mysql-tls":    {Dialect: SQLDialectMySQL, DSN: "grafana@tcp(mysql:3306)/metrics?parseTime=true&tls=ngalert-recording-ds0", Table: DefaultSQLTable, Timeout: time.Second},
+		} {
+			ds, err := dsService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: 1})
+			require.NoError(t, err)
+			settings, err := w.sqlSettings(ctx, ds)
+			require.NoError(t, err)
+			require.Equal(t, expected, settings, uid)
+		}
+	})
+
+	t.Run("caches writers until the data source changes", func(t *testing.T) {
+		first, err := w.acquire(ctx, 1, "influx")
+		require.NoError(t, err)
+		w.release(first)
+		second, err := w.acquire(ctx, 1, "influx")
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		dsService.DataSources[1].Version++
+		third, err := w.acquire(ctx, 1, "influx")
+		require.NoError(t, err)
+		w.release(third)
+		require.NotSame(t, first, third)
+		require.True(t, second.evicted)
+		require.Equal(t, 1, second.refs)
+		w.release(second)
+		require.Zero(t, second.refs)
+	})
+
+	t.Run("closes evicted writers when no write uses them", func(t *testing.T) {
+		closed := 0
+		key := datasourceWriterKey{orgID: 1, uid: "prom"}
+		inUse := &cachedWriter{version: -1, writer: closableWriter{closed: &closed}, refs: 1}
+		w.writers[key] = inUse
+
+		target, err := w.acquire(ctx, 1, "prom")
+		require.NoError(t, err)
+		w.release(target)
+		require.True(t, inUse.evicted)
+		require.Zero(t, closed)
+
+		w.release(inUse)
+		require.Equal(t, 1, closed)
+	})
+
+	t.Run("validates targets", func(t *testing.T) {
+		v := NewTargetValidator(dsService)
+		for _, uid := range []string{"prom", "influx", "postgres", "mysql"} {
+			require.NoError(t, v.ValidateTarget(ctx, 1, uid))
+		}
+		require.ErrorIs(t, v.ValidateTarget(ctx, 1, "loki"), ErrUnsupportedTarget)
+		require.ErrorIs(t, v.ValidateTarget(ctx, 1, "unknown"), datasources.ErrDataSourceNotFound)
+		require.ErrorContains(t, v.ValidateTarget(ctx, 1, "mysql-invalid-table"), "invalid table name")
+	})
+
+	t.Run("fails if the target is not supported", func(t *testing.T) {
+		err := w.WriteDatasource(ctx, "loki", "test", time.Now(), nil, 1, nil)
+		require.ErrorIs(t, err, ErrWriteFailure)
+		require.ErrorIs(t, err, ErrUnsupportedTarget)
+	})
+
+	t.Run("fails if the target does not exist", func(t *testing.T) {
+		err := w.WriteDatasource(ctx, "unknown", "test", time.Now(), nil, 1, nil)
+		require.ErrorIs(t, err, ErrWriteFailure)
+		require.ErrorIs(t, err, datasources.ErrDataSourceNotFound)
+	})
+}
+
+type closableWriter struct {
+	FakeWriter
+	closed *int
+}
+
+func (w closableWriter) Close() error {
+	*w.closed++
+	return nil
+}
+
+func TestNewSQLWriter(t *testing.T) {
+	m := metrics.NewRemoteWriterMetrics(prometheus.NewRegistry())
+	for _, tc := range []struct {
+		name     string
+		settings SQLSettings
+		err      bool
+	}{
+		{name: "valid", settings: SQLSettings{Dialect: SQLDialectPostgres, Table: "public.metrics", Timeout: time.Second}},
+		{name: "unsupported dialect", settings: SQLSettings{Dialect: "sqlite3", Table: "metrics", Timeout: time.Second}, err: true},
+		{name: "invalid table", settings: SQLSettings{Dialect: SQLDialectMySQL, Table: "metrics; DROP TABLE user", Timeout: time.Second}, err: true},
+		{name: "timeout is 0", settings: SQLSettings{Dialect: SQLDialectMySQL, Table: "metrics"}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSQLWriter(tc.settings, clock.New(), log.New("test"), m)
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckSQLWriteError(t *testing.T) {
+	t.Run("ignores duplicate rows", func(t *testing.T) {
+		for _, writeErr := range []error{
+			&pq.Error{Code: postgresUniqueViolation},
+			&mysql.MySQLError{Number: mysqlDuplicateEntry},
+		} {
+			err, ignored := checkSQLWriteError(writeErr)
+			require.NoError(t, err)
+			require.True(t, ignored)
+		}
+	})
+
+	t.Run("returns other errors", func(t *testing.T) {
+		writeErr := errors.New("connection refused")
+		err, ignored := checkSQLWriteError(writeErr)
+		require.ErrorIs(t, err, writeErr)
+		require.False(t, ignored)
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		err, ignored := checkSQLWriteError(nil)
+		require.NoError(t, err)
+		require.False(t, ignored)
+	})
+}
@@ -8,7 +8,8 @@ import (
 )
 
 type FakeWriter struct {
-	WriteFunc func(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	WriteFunc           func(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	WriteDatasourceFunc func(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
 }
 
 func (w FakeWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
@@ -18,3 +19,11 @@ func (w FakeWriter) Write(ctx context.Context, name string, t time.Time, frames
 
 	return w.WriteFunc(ctx, name, t, frames, orgID, extraLabels)
 }
+
+func (w FakeWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	if w.WriteDatasourceFunc == nil {
+		return w.Write(ctx, name, t, frames, orgID, extraLabels)
+	}
+
+	return w.WriteDatasourceFunc(ctx, dsUID, name, t, frames, orgID, extraLabels)
+}
@@ -0,0 +1,219 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+const influxDBBackendType = "influxdb"
+
+// InfluxDBSettings are the settings of an InfluxDB writer.
+type InfluxDBSettings struct {
+	// URL is the URL of the InfluxDB server, without the write path.
+	URL string
+	// Database is the database of InfluxDB 1.x, or the bucket of InfluxDB 2.x and 3.x.
+	Database string
+	// Organization is the organization of InfluxDB 2.x. Writes use the API of InfluxDB 1.x if it is empty.
+	Organization string
+	// Token is the API token of InfluxDB 2.x and 3.x.
+	Token string
+	// User and Password are the credentials of InfluxDB 1.x.
+	User     string
+	Password string
+	Timeout  time.Duration
+}
+
+// InfluxDBWriter writes the metrics of recording rules to InfluxDB in line protocol.
+type InfluxDBWriter struct {
+	client   *http.Client
+	settings InfluxDBSettings
+	clock    clock.Clock
+	logger   log.Logger
+	metrics  *metrics.RemoteWriter
+}
+
+func NewInfluxDBWriter(settings InfluxDBSettings, client *http.Client, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*InfluxDBWriter, error) {
+	if _, err := url.Parse(settings.URL); err != nil || settings.URL == "" {
+		return nil, fmt.Errorf("invalid URL: %q", settings.URL)
+	}
+	if settings.Database == "" {
+		return nil, fmt.Errorf("database or bucket is required")
+	}
+	if settings.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be greater than 0")
+	}
+	return &InfluxDBWriter{
+		client:   client,
+		settings: settings,
+		clock:    clock,
+		logger:   l,
+		metrics:  metrics,
+	}, nil
+}
+
+// influxDBWriteError is an error response of InfluxDB.
+type influxDBWriteError struct {
+	statusCode int
+	msg        string
+}
+
+func (e influxDBWriteError) Error() string {
+	return fmt.Sprintf("influxdb responded with status code %d: %s", e.statusCode, e.msg)
+}
+
+func (e influxDBWriteError) StatusCode() int {
+	return e.statusCode
+}
+
+// Write writes the given frames to InfluxDB. The name is the measurement, and the value of each series is written to
+// the field value.
+func (w InfluxDBWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), influxDBBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	var body bytes.Buffer
+	for _, p := range points {
+		// Line protocol does not support NaN and infinity.
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			l.Debug("Skipping series with a value that is not a number", "labels", p.Labels)
+			continue
+		}
+		writeLineProtocol(&body, p)
+	}
+	if body.Len() == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.settings.Timeout)
+	defer cancel()
+	req, err := w.newRequest(ctx, &body)
+	if err != nil {
+		return errors.Join(ErrWriteFailure, err)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	statusCode, writeErr := w.do(req)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	lvs = append(lvs, fmt.Sprint(statusCode))
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	var respErr influxDBWriteError
+	if errors.As(writeErr, &respErr) {
+		if err, ignored := checkWriteError(respErr); err != nil {
+			return errors.Join(ErrWriteFailure, err)
+		} else if ignored {
+			l.Debug("Ignored write error", "error", respErr, "status_code", statusCode)
+		}
+		return nil
+	}
+	if writeErr != nil {
+		return errors.Join(ErrWriteFailure, writeErr)
+	}
+	return nil
+}
+
+func (w InfluxDBWriter) newRequest(ctx context.Context, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(strings.TrimSuffix(w.settings.URL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Set("precision", "ms")
+	if w.settings.Organization != "" || w.settings.Token != "" {
+		// InfluxDB 2.x and 3.x
+		u = u.JoinPath("api", "v2", "write")
+		q.Set("bucket", w.settings.Database)
+		if w.settings.Organization != "" {
+			q.Set("org", w.settings.Organization)
+		}
+	} else {
+		// InfluxDB 1.x
+		u = u.JoinPath("write")
+		q.Set("db", w.settings.Database)
+		if w.settings.User != "" {
+			q.Set("u", w.settings.User)
+			q.Set("p", w.settings.Password)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", "grafana-recording-rule")
+	if w.settings.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.settings.Token)
+	}
+	return req, nil
+}
+
+func (w InfluxDBWriter) do(req *http.Request) (int, error) {
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 == 2 {
+		return resp.StatusCode, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, influxDBWriteError{statusCode: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `, "\n", `\n`)
+	tagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+)
+
+// writeLineProtocol writes the point in InfluxDB line protocol with a timestamp in milliseconds.
+func writeLineProtocol(b *bytes.Buffer, p Point) {
+	b.WriteString(measurementEscaper.Replace(p.Name))
+	keys := make([]string, 0, len(p.Labels))
+	for k := range p.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := p.Labels[k]
+		// Tags with empty values are not allowed.
+		if v == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(tagEscaper.Replace(k))
+		b.WriteByte('=')
+		b.WriteString(tagEscaper.Replace(v))
+	}
+	b.WriteString(" value=")
+	b.WriteString(strconv.FormatFloat(p.Metric.V, 'g', -1, 64))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(p.Metric.T.UnixMilli(), 10))
+	b.WriteByte('\n')
+}
@@ -0,0 +1,140 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+func TestWriteLineProtocol(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	for _, tc := range []struct {
+		name     string
+		point    Point
+		expected string
+	}{
+		{
+			name:     "without labels",
+			point:    Point{Name: "cpu", Metric: Metric{T: ts, V: 1.5}},
+			expected: "cpu value=1.5 1700000000123\n",
+		},
+		{
+			name: "sorts labels and skips empty values",
+			point: Point{
+				Name:   "cpu",
+				Labels: map[string]string{"pod": "b", "empty": "", "env": "prod"},
+				Metric: Metric{T: ts, V: 2},
+			},
+			expected: "cpu,env=prod,pod=b value=2 1700000000123\n",
+		},
+		{
+			name: "escapes special characters",
+			point: Point{
+				Name:   "cpu usage,total",
+				Labels: map[string]string{"a b": "c=d,e"},
+				Metric: Metric{T: ts, V: -3},
+			},
+			expected: `cpu\ usage\,total,a\ b=c\=d\,e value=-3 1700000000123` + "\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			writeLineProtocol(&b, tc.point)
+			require.Equal(t, tc.expected, b.String())
+		})
+	}
+}
+
+func TestInfluxDBWriter_Write(t *testing.T) {
+	var (
+		lastRequest *http.Request
+		lastBody    string
+		status      = http.StatusNoContent
+		response    = ""
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest, lastBody = r, string(b)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	newWriter := func(t *testing.T, settings InfluxDBSettings) *InfluxDBWriter {
+		settings.URL = srv.URL
+		settings.Timeout = time.Second
+		w, err := NewInfluxDBWriter(settings, srv.Client(), clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		return w
+	}
+	now := time.UnixMilli(1700000000000)
+	frames := data.Frames{data.NewFrame("",
+		data.NewField("", data.Labels{"pod": "a"}, []float64{1}),
+		data.NewField("", data.Labels{"pod": "b"}, []float64{math.NaN()}),
+	)}
+	frames[0].SetMeta(&data.FrameMeta{Type: data.FrameTypeNumericWide, TypeVersion: data.FrameTypeVersion{0, 1}})
+
+	t.Run("writes with the API of InfluxDB 1.x", func(t *testing.T) {
+		status, response = http.StatusNoContent, ""
+		w := newWriter(t, InfluxDBSettings{Database: "metrics", User: "user", Password: "pass"})
+
+		err := w.Write(context.Background(), "errors", now, frames, 1, map[string]string{"team": "a"})
+		require.NoError(t, err)
+		require.Equal(t, "/write", lastRequest.URL.Path)
+		require.Equal(t, "metrics", lastRequest.URL.Query().Get("db"))
+		require.Equal(t, "user", lastRequest.URL.Query().Get("u"))
+		require.Equal(t, "pass", lastRequest.URL.Query().Get("p"))
+		require.Equal(t, "ms", lastRequest.URL.Query().Get("precision"))
+		require.Equal(t, "errors,pod=a,team=a value=1 1700000000000\n", lastBody)
+	})
+
+	t.Run("writes with the API of InfluxDB 2.x", func(t *testing.T) {
+		status, response = http.StatusNoContent, ""
+		w := newWriter(t, InfluxDBSettings{Database: "bucket", Organization: "org", Token: "secret"})
+
+		err := w.Write(context.Background(), "errors", now, frames, 1, nil)
+		require.NoError(t, err)
+		require.Equal(t, "/api/v2/write", lastRequest.URL.Path)
+		require.Equal(t, "bucket", lastRequest.URL.Query().Get("bucket"))
+		require.Equal(t, "org", lastRequest.URL.Query().Get("org"))
+		require.Equal(t, "Token secret", lastRequest.Header.Get("Authorization"))
+	})
+
+	t.Run("returns error if InfluxDB fails", func(t *testing.T) {
+		status, response = http.StatusInternalServerError, "internal error"
+		w := newWriter(t, InfluxDBSettings{Database: "metrics"})
+
+		err := w.Write(context.Background(), "errors", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrWriteFailure)
+		require.ErrorContains(t, err, "internal error")
+	})
+
+	t.Run("ignores duplicate timestamp errors", func(t *testing.T) {
+		status, response = http.StatusBadRequest, PrometheusDuplicateTimestampError
+		w := newWriter(t, InfluxDBSettings{Database: "metrics"})
+
+		err := w.Write(context.Background(), "errors", now, frames, 1, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("returns error if frames are invalid", func(t *testing.T) {
+		w := newWriter(t, InfluxDBSettings{Database: "metrics"})
+
+		err := w.Write(context.Background(), "errors", now, data.Frames{data.NewFrame("test")}, 1, nil)
+		require.ErrorIs(t, err, ErrBadFrame)
+	})
+}
@@ -0,0 +1,185 @@
+package writer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/lib/pq"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+const (
+	SQLDialectPostgres = "postgres"
+	SQLDialectMySQL    = "mysql"
+
+	// postgresUniqueViolation is the error code of PostgreSQL for unique constraint violations.
+	postgresUniqueViolation = "23505"
+	// mysqlDuplicateEntry is the error number of MySQL for duplicate keys.
+	mysqlDuplicateEntry = 1062
+
+	// sqlInsertBatchSize is the maximum number of rows inserted by a single statement. It keeps the number of
+	// parameters of a statement below the limits of the databases, 65535 for both PostgreSQL and MySQL.
+	sqlInsertBatchSize = 1000
+)
+
+var sqlTableRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// SQLSettings are the settings of a SQL writer.
+type SQLSettings struct {
+	// Dialect is either postgres or mysql.
+	Dialect string
+	// DSN is the data source name of the database in the format of the driver of the dialect.
+	DSN string
+	// Table is the table to insert the metrics into. It must have the columns time, metric, labels and value.
+	Table   string
+	Timeout time.Duration
+}
+
+// SQLWriter writes the metrics of recording rules to a table of a SQL database. Each series is a row with the time
+// of the evaluation, the name of the metric, the labels of the series as a JSON object and the value.
+type SQLWriter struct {
+	db       *sql.DB
+	settings SQLSettings
+	clock    clock.Clock
+	logger   log.Logger
+	metrics  *metrics.RemoteWriter
+}
+
+func NewSQLWriter(settings SQLSettings, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*SQLWriter, error) {
+	if settings.Dialect != SQLDialectPostgres && settings.Dialect != SQLDialectMySQL {
+		return nil, fmt.Errorf("unsupported SQL dialect: %s", settings.Dialect)
+	}
+	if !sqlTableRegexp.MatchString(settings.Table) {
+		return nil, fmt.Errorf("invalid table name: %q", settings.Table)
+	}
+	if settings.Timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be greater than 0")
+	}
+	db, err := sql.Open(settings.Dialect, settings.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(2)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+	return &SQLWriter{
+		db:       db,
+		settings: settings,
+		clock:    clock,
+		logger:   l,
+		metrics:  metrics,
+	}, nil
+}
+
+// Write inserts the given frames into the table in a single transaction.
+func (w SQLWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), w.settings.Dialect}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.settings.Timeout)
+	defer cancel()
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	writeErr := w.insert(ctx, points)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	status := "ok"
+	if writeErr != nil {
+		status = "error"
+	}
+	lvs = append(lvs, status)
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	if err, ignored := checkSQLWriteError(writeErr); err != nil {
+		return errors.Join(ErrWriteFailure, err)
+	} else if ignored {
+		l.Debug("Ignored write error", "error", writeErr)
+	}
+	return nil
+}
+
+func (w SQLWriter) insert(ctx context.Context, points []Point) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(points); start += sqlInsertBatchSize {
+		end := min(start+sqlInsertBatchSize, len(points))
+		if err := w.insertBatch(ctx, tx, points[start:end]); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// insertBatch inserts the points with a single statement.
+func (w SQLWriter) insertBatch(ctx context.Context, tx *sql.Tx, points []Point) error {
+	var (
+		b    strings.Builder
+		args = make([]any, 0, len(points)*4)
+	)
+	b.WriteString("INSERT INTO ")
+	b.WriteString(w.settings.Table)
+	b.WriteString(" (time, metric, labels, value) VALUES ")
+	for i, p := range points {
+		labels, err := json.Marshal(p.Labels)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if w.settings.Dialect == SQLDialectPostgres {
+			fmt.Fprintf(&b, "($%d, $%d, $%d, $%d)", i*4+1, i*4+2, i*4+3, i*4+4)
+		} else {
+			b.WriteString("(?, ?, ?, ?)")
+		}
+		args = append(args, p.Metric.T.UTC(), p.Name, string(labels), p.Metric.V)
+	}
+	_, err := tx.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+// Close closes the connections to the database.
+func (w SQLWriter) Close() error {
+	return w.db.Close()
+}
+
+// checkSQLWriteError classifies the error of an insert like checkWriteError. Duplicate rows are ignored because
+// several Grafana instances in high availability mode can write the same metric for the same time.
+func checkSQLWriteError(writeErr error) (err error, ignored bool) {
+	if writeErr == nil {
+		return nil, false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(writeErr, &pqErr) && string(pqErr.Code) == postgresUniqueViolation {
+		return nil, true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(writeErr, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return nil, true
+	}
+
+	return writeErr, false
+}
@@ -0,0 +1,88 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+func TestSQLWriterInsert(t *testing.T) {
+	newWriter := func(t *testing.T) (*SQLWriter, sqlmock.Sqlmock, *[]string) {
+		t.Helper()
+		var statements []string
+		// Record the statements instead of matching them so that they can be checked after the insert.
+		matcher := sqlmock.QueryMatcherFunc(func(_, actual string) error {
+			statements = append(statements, actual)
+			return nil
+		})
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		return &SQLWriter{
+			db:       db,
+			settings: SQLSettings{Dialect: SQLDialectPostgres, Table: "recorded", Timeout: time.Second},
+			clock:    clock.New(),
+			logger:   log.NewNopLogger(),
+			metrics:  metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()),
+		}, mock, &statements
+	}
+	points := func(n int) []Point {
+		result := make([]Point, 0, n)
+		for i := 0; i < n; i++ {
+			result = append(result, Point{
+				Name:   "metric",
+				Labels: map[string]string{"series": fmt.Sprint(i)},
+				Metric: Metric{T: time.Now(), V: float64(i)},
+			})
+		}
+		return result
+	}
+
+	t.Run("should insert the points in batches in a single transaction", func(t *testing.T) {
+		w, mock, statements := newWriter(t)
+		// 16,500 points need 66,000 parameters in a single statement, more than PostgreSQL and MySQL allow.
+		n := 16*sqlInsertBatchSize + sqlInsertBatchSize/2
+		mock.ExpectBegin()
+		for start := 0; start < n; start += sqlInsertBatchSize {
+			mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, int64(min(sqlInsertBatchSize, n-start))))
+		}
+		mock.ExpectCommit()
+
+		require.NoError(t, w.insert(context.Background(), points(n)))
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		require.Len(t, *statements, 17)
+		for i, s := range *statements {
+			rows := sqlInsertBatchSize
+			if i == len(*statements)-1 {
+				rows = sqlInsertBatchSize / 2
+			}
+			require.True(t, strings.HasPrefix(s, "INSERT INTO recorded (time, metric, labels, value) VALUES ($1, $2, $3, $4), "))
+			require.True(t, strings.HasSuffix(s, fmt.Sprintf("($%d, $%d, $%d, $%d)", rows*4-3, rows*4-2, rows*4-1, rows*4)))
+		}
+	})
+
+	t.Run("should roll back if a batch fails", func(t *testing.T) {
+		w, mock, _ := newWriter(t)
+		expectedErr := errors.New("insert failed")
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(0, sqlInsertBatchSize))
+		mock.ExpectExec("INSERT").WillReturnError(expectedErr)
+		mock.ExpectRollback()
+
+		err := w.insert(context.Background(), points(3*sqlInsertBatchSize))
+		require.ErrorIs(t, err, expectedErr)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
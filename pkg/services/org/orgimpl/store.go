@@ -231,13 +231,18 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM ngalert_configuration WHERE org_id = ?",
 			"DELETE FROM alert_configuration WHERE org_id = ?",
 			"DELETE FROM alert_instance WHERE rule_org_id = ?",
+			"DELETE FROM alert_rule_state WHERE org_id = ?",
 			"DELETE FROM alert_notification WHERE org_id = ?",
 			"DELETE FROM alert_notification_state WHERE org_id = ?",
+			"DELETE FROM alert_notification_delivery WHERE org_id = ?",
+			"DELETE FROM alert_silence_schedule WHERE org_id = ?",
 			"DELETE FROM alert_rule WHERE org_id = ?",
 			"DELETE FROM alert_rule_tag WHERE EXISTS (SELECT 1 FROM alert WHERE alert.org_id = ? AND alert.id = alert_rule_tag.alert_id)",
 			"DELETE FROM alert_rule_version WHERE rule_org_id = ?",
 			"DELETE FROM alert WHERE org_id = ?",
 			"DELETE FROM annotation WHERE org_id = ?",
+			"DELETE FROM report WHERE org_id = ?",
+			"DELETE FROM report_send WHERE org_id = ?",
 			"DELETE FROM kv_store WHERE org_id = ?",
 			"DELETE FROM team WHERE org_id = ?",
 			"DELETE FROM team_member WHERE org_id = ?",
@@ -0,0 +1,139 @@
+package clientmiddleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins/queryrecording"
+)
+
+// RecordingSettings are the settings of the recording middleware.
+type RecordingSettings struct {
+	// Path is the directory recordings are written to.
+	Path string
+	// DatasourceUIDs are the UIDs of the data sources whose responses are recorded.
+	DatasourceUIDs []string
+	// MaxFileSize is the maximum size of a recording in bytes. Larger responses are not recorded.
+	MaxFileSize int64
+	// MaxFiles is the maximum number of recordings that are kept per data source.
+	MaxFiles int
+}
+
+// NewRecordingMiddleware creates a new backend.HandlerMiddleware that records the query
+// data responses of the data sources in settings.DatasourceUIDs to settings.Path.
+// Responses are only recorded when the request succeeded and none of the query responses
+// has an error. Recordings are stored per data source UID in the format used by the
+// TestData replay scenario, and the oldest recordings are deleted once a data source
+// has more than settings.MaxFiles recordings.
+func NewRecordingMiddleware(settings RecordingSettings) backend.HandlerMiddleware {
+	return backend.HandlerMiddlewareFunc(func(next backend.Handler) backend.Handler {
+		return &RecordingMiddleware{
+			BaseHandler: backend.NewBaseHandler(next),
+			log:         log.New("plugin.recording"),
+			settings:    settings,
+		}
+	})
+}
+
+type RecordingMiddleware struct {
+	backend.BaseHandler
+	log      log.Logger
+	settings RecordingSettings
+	mu       sync.Mutex
+}
+
+func (m *RecordingMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp, err := m.BaseHandler.QueryData(ctx, req)
+	if err != nil || req == nil || resp == nil {
+		return resp, err
+	}
+
+	dsSettings := req.PluginContext.DataSourceInstanceSettings
+	if dsSettings == nil || dsSettings.UID == "" || !slices.Contains(m.settings.DatasourceUIDs, dsSettings.UID) {
+		return resp, err
+	}
+
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return resp, err
+		}
+	}
+
+	if recErr := m.record(dsSettings.UID, queryrecording.New(req, resp)); recErr != nil {
+		m.log.FromContext(ctx).Error("Failed to record query data response", "datasourceUID", dsSettings.UID, "error", recErr)
+	}
+
+	return resp, err
+}
+
+func (m *RecordingMiddleware) record(datasourceUID string, recording queryrecording.Recording) error {
+	if !filepath.IsLocal(datasourceUID) {
+		return os.ErrInvalid
+	}
+
+	b, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	if m.settings.MaxFileSize > 0 && int64(len(b)) > m.settings.MaxFileSize {
+		return fmt.Errorf("recording of %d bytes exceeds the maximum file size of %d bytes", len(b), m.settings.MaxFileSize)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir := filepath.Join(m.settings.Path, datasourceUID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	fileName := strconv.FormatInt(time.Now().UnixNano(), 10) + ".json"
+	if err := os.WriteFile(filepath.Join(dir, fileName), b, 0640); err != nil {
+		return err
+	}
+
+	return m.prune(dir)
+}
+
+// prune deletes the oldest recordings of dir until at most MaxFiles recordings are left.
+func (m *RecordingMiddleware) prune(dir string) error {
+	if m.settings.MaxFiles <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var recordings []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			recordings = append(recordings, entry.Name())
+		}
+	}
+	if len(recordings) <= m.settings.MaxFiles {
+		return nil
+	}
+
+	// file names are the time of the recording in nanoseconds, so they sort from oldest to newest
+	sort.Strings(recordings)
+	for _, name := range recordings[:len(recordings)-m.settings.MaxFiles] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
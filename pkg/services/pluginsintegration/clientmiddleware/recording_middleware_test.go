@@ -0,0 +1,116 @@
+package clientmiddleware
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/handlertest"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/queryrecording"
+)
+
+func TestRecordingMiddleware(t *testing.T) {
+	queryDataResponse := func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		return &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("frame", data.NewField("value", nil, []float64{1}))}},
+		}}, nil
+	}
+
+	request := func(uid string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: uid},
+			},
+			Queries: []backend.DataQuery{{RefID: "A"}},
+		}
+	}
+
+	t.Run("Should record query data responses", func(t *testing.T) {
+		dir := t.TempDir()
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewRecordingMiddleware(RecordingSettings{Path: dir, DatasourceUIDs: []string{"ds1"}})))
+		cdt.TestHandler.QueryDataFunc = queryDataResponse
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), request("ds1"))
+		require.NoError(t, err)
+
+		files, err := os.ReadDir(filepath.Join(dir, "ds1"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		b, err := os.ReadFile(filepath.Join(dir, "ds1", files[0].Name()))
+		require.NoError(t, err)
+
+		var recording queryrecording.Recording
+		require.NoError(t, json.Unmarshal(b, &recording))
+		require.Len(t, recording.Response.Responses["A"].Frames, 1)
+	})
+
+	t.Run("Should only record configured data sources", func(t *testing.T) {
+		dir := t.TempDir()
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewRecordingMiddleware(RecordingSettings{Path: dir, DatasourceUIDs: []string{"ds2"}})))
+		cdt.TestHandler.QueryDataFunc = queryDataResponse
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), request("ds1"))
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+	t.Run("Should not record responses with errors", func(t *testing.T) {
+		dir := t.TempDir()
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewRecordingMiddleware(RecordingSettings{Path: dir, DatasourceUIDs: []string{"ds1"}})))
+		cdt.TestHandler.QueryDataFunc = func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			return &backend.QueryDataResponse{Responses: backend.Responses{
+				"A": backend.ErrDataResponse(backend.StatusBadRequest, "bad query"),
+			}}, nil
+		}
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), request("ds1"))
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Should not record responses larger than the maximum file size", func(t *testing.T) {
+		dir := t.TempDir()
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewRecordingMiddleware(RecordingSettings{Path: dir, DatasourceUIDs: []string{"ds1"}, MaxFileSize: 10})))
+		cdt.TestHandler.QueryDataFunc = queryDataResponse
+
+		_, err := cdt.MiddlewareHandler.QueryData(context.Background(), request("ds1"))
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Should only keep the newest recordings", func(t *testing.T) {
+		dir := t.TempDir()
+		cdt := handlertest.NewHandlerMiddlewareTest(t, handlertest.WithMiddlewares(NewRecordingMiddleware(RecordingSettings{Path: dir, DatasourceUIDs: []string{"ds1"}, MaxFiles: 2})))
+		cdt.TestHandler.QueryDataFunc = queryDataResponse
+
+		var names []string
+		for i := 0; i < 3; i++ {
+			_, err := cdt.MiddlewareHandler.QueryData(context.Background(), request("ds1"))
+			require.NoError(t, err)
+
+			files, err := os.ReadDir(filepath.Join(dir, "ds1"))
+			require.NoError(t, err)
+			names = append(names, files[len(files)-1].Name())
+		}
+
+		files, err := os.ReadDir(filepath.Join(dir, "ds1"))
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		require.Equal(t, names[1:], []string{files[0].Name(), files[1].Name()})
+	})
+}
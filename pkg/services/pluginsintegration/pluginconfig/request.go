@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
 	"github.com/grafana/grafana-azure-sdk-go/v2/azsettings"
 	"github.com/grafana/grafana/pkg/plugins/auth"
+	"github.com/grafana/grafana/pkg/plugins/queryrecording"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/proxy"
@@ -18,6 +19,8 @@ import (
 
 var _ PluginRequestConfigProvider = (*RequestConfigProvider)(nil)
 
+const testDataPluginID = "grafana-testdata-datasource"
+
 type PluginRequestConfigProvider interface {
 	PluginRequestConfig(ctx context.Context, pluginID string, externalService *auth.ExternalService) map[string]string
 }
@@ -167,6 +170,12 @@ func (s *RequestConfigProvider) PluginRequestConfig(ctx context.Context, pluginI
 		m[awsds.SigV4VerboseLoggingEnvVarKeyName] = strconv.FormatBool(s.cfg.SigV4VerboseLogging)
 	}
 
+	if pluginID == testDataPluginID {
+		if dir := s.cfg.PluginSettings[pluginID]["replay_directory"]; dir != "" {
+			m[queryrecording.ReplayDirectoryConfigKey] = dir
+		}
+	}
+
 	if externalService != nil {
 		m[backend.AppClientSecret] = externalService.ClientSecret
 	}
@@ -480,3 +480,16 @@ func TestRequestConfigProvider_PluginRequestConfig_appClientSecret(t *testing.T)
 		}), map[string]string{backend.AppClientSecret: "mysecret"})
 	})
 }
+
+func TestRequestConfigProvider_PluginRequestConfig_testDataReplayDirectory(t *testing.T) {
+	cfg := setting.NewCfg()
+	pCfg, err := ProvidePluginInstanceConfig(cfg, setting.ProvideProvider(cfg), featuremgmt.WithFeatures())
+	require.NoError(t, err)
+	pCfg.PluginSettings = setting.PluginSettings{
+		"grafana-testdata-datasource": {"replay_directory": "/var/lib/grafana/recordings"},
+	}
+
+	p := NewRequestConfigProvider(pCfg)
+	require.Equal(t, "/var/lib/grafana/recordings", p.PluginRequestConfig(context.Background(), "grafana-testdata-datasource", nil)["GF_TESTDATA_REPLAY_DIRECTORY"])
+	require.NotContains(t, p.PluginRequestConfig(context.Background(), "prometheus", nil), "GF_TESTDATA_REPLAY_DIRECTORY")
+}
@@ -200,6 +200,15 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		middlewares = append(middlewares, clientmiddleware.NewHostedGrafanaACHeaderMiddleware(cfg))
 	}
 
+	if cfg.PluginRecordQueryDataPath != "" && len(cfg.PluginRecordQueryDataDatasources) > 0 {
+		middlewares = append(middlewares, clientmiddleware.NewRecordingMiddleware(clientmiddleware.RecordingSettings{
+			Path:           cfg.PluginRecordQueryDataPath,
+			DatasourceUIDs: cfg.PluginRecordQueryDataDatasources,
+			MaxFileSize:    cfg.PluginRecordQueryDataMaxFileSize,
+			MaxFiles:       cfg.PluginRecordQueryDataMaxFiles,
+		}))
+	}
+
 	middlewares = append(middlewares, clientmiddleware.NewHTTPClientMiddleware())
 
 	// StatusSourceMiddleware should be at the very bottom, or any middlewares below it won't see the
@@ -77,6 +77,7 @@ type AlertRuleV1 struct {
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	InstanceLimit        values.Int64Value       `json:"instanceLimit" yaml:"instanceLimit"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -142,6 +143,10 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	alertRule.InstanceLimit = rule.InstanceLimit.Value()
+	if alertRule.InstanceLimit < 0 {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: instanceLimit cannot be negative", alertRule.Title)
+	}
 	if rule.NotificationSettings != nil {
 		ns, err := rule.NotificationSettings.mapToModel()
 		if err != nil {
@@ -267,11 +272,14 @@ func (nsV1 *NotificationSettingsV1) mapToModel() (models.NotificationSettings, e
 type RecordV1 struct {
 	Metric values.StringValue `json:"metric" yaml:"metric"`
 	From   values.StringValue `json:"from" yaml:"from"`
+	// TargetDatasourceUID is the UID of the data source to write the recorded metric to.
+	TargetDatasourceUID values.StringValue `json:"target_datasource_uid" yaml:"target_datasource_uid"`
 }
 
 func (record *RecordV1) mapToModel() (models.Record, error) {
 	return models.Record{
-		Metric: record.Metric.Value(),
-		From:   record.From.Value(),
+		Metric:              record.Metric.Value(),
+		From:                record.From.Value(),
+		TargetDatasourceUID: record.TargetDatasourceUID.Value(),
 	}, nil
 }
@@ -199,6 +199,25 @@ func TestRules(t *testing.T) {
 		require.Len(t, ruleMapped.NotificationSettings, 1)
 		require.Equal(t, models.NotificationSettings{Receiver: "test-receiver"}, ruleMapped.NotificationSettings[0])
 	})
+	t.Run("a rule with an instance limit should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		instanceLimit := values.Int64Value{}
+		err := yaml.Unmarshal([]byte("100"), &instanceLimit)
+		require.NoError(t, err)
+		rule.InstanceLimit = instanceLimit
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), ruleMapped.InstanceLimit)
+	})
+	t.Run("a rule with a negative instance limit should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		instanceLimit := values.Int64Value{}
+		err := yaml.Unmarshal([]byte("-1"), &instanceLimit)
+		require.NoError(t, err)
+		rule.InstanceLimit = instanceLimit
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func TestNotificationsSettingsV1MapToModel(t *testing.T) {
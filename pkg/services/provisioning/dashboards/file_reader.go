@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/util"
@@ -39,6 +40,8 @@ type FileReader struct {
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
 	folderService                folder.Service
+	linter                       *lint.Linter
+	lintMode                     string
 
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
@@ -64,6 +67,13 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
 	}
 
+	lintMode, _ := cfg.Options["lint"].(string)
+	switch lintMode {
+	case "", lintModeOff, lintModeWarn, lintModeFail:
+	default:
+		return nil, fmt.Errorf("invalid lint option %q, use off, warn or fail", lintMode)
+	}
+
 	return &FileReader{
 		Cfg:                          cfg,
 		Path:                         path,
@@ -72,6 +82,7 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardStore:               dashboardStore,
 		folderService:                folderService,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		lintMode:                     lintMode,
 		usageTracker:                 newUsageTracker(),
 	}, nil
 }
@@ -247,6 +258,13 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		upToDate = jsonFile.checkSum == provisionedData.CheckSum
 	}
 
+	if !upToDate {
+		if err := fr.lintDashboard(path, jsonFile.dashboard.Dashboard.Data); err != nil {
+			fr.log.Error("failed to provision dashboard", "file", path, "error", err)
+			return provisioningMetadata, nil
+		}
+	}
+
 	// keeps track of which UIDs and titles we have already provisioned
 	dash := jsonFile.dashboard
 	provisioningMetadata.uid = dash.Dashboard.UID
@@ -2,12 +2,21 @@ package dashboards
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 )
 
+// Lint modes of the lint option of dashboard providers
+const (
+	lintModeOff  = "off"
+	lintModeWarn = "warn"
+	lintModeFail = "fail"
+)
+
 type duplicate struct {
 	Sum             uint8
 	InvolvedReaders map[string]struct{}
@@ -151,6 +160,23 @@ func (c *duplicateValidator) validate() {
 	c.takeAwayWritePermissions(duplicates)
 }
 
+// lintDashboard lints a dashboard read from a file if the lint option of the provider is set, and logs the issues.
+// In the fail mode, it returns an error if the dashboard has lint errors, so that it is not provisioned.
+func (fr *FileReader) lintDashboard(path string, dashboard *simplejson.Json) error {
+	if fr.linter == nil || fr.lintMode == "" || fr.lintMode == lintModeOff {
+		return nil
+	}
+
+	result := fr.linter.Lint(dashboard)
+	for _, issue := range result.Issues {
+		fr.log.Warn("dashboard has lint issue", "file", path, "rule", issue.Rule, "severity", issue.Severity, "path", issue.Path, "message", issue.Message)
+	}
+	if fr.lintMode == lintModeFail && result.Errors > 0 {
+		return fmt.Errorf("dashboard has %d lint errors", result.Errors)
+	}
+	return nil
+}
+
 func keysToSlice(data map[string]struct{}) []string {
 	entries := make([]string, 0, len(data))
 
@@ -10,12 +10,14 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/lint"
 	"github.com/grafana/grafana/pkg/services/folder"
 )
 
 const (
 	dashboardContainingUID = "testdata/test-dashboards/dashboard-with-uid"
 	twoDashboardsWithUID   = "testdata/test-dashboards/two-dashboards-with-uid"
+	dashboardWithLintError = "testdata/test-dashboards/lint-errors"
 )
 
 func TestDuplicatesValidator(t *testing.T) {
@@ -233,3 +235,46 @@ func TestDuplicatesValidator(t *testing.T) {
 		require.True(t, reader3.isDatabaseAccessRestricted())
 	})
 }
+
+func TestLintValidator(t *testing.T) {
+	logger := log.New("test.logger")
+	newReader := func(t *testing.T, mode string, fakeService *dashboards.FakeDashboardProvisioning) *FileReader {
+		cfg := &config{
+			Name:    "Default",
+			Type:    "file",
+			OrgID:   1,
+			Options: map[string]any{"path": dashboardWithLintError, "lint": mode},
+		}
+		reader, err := NewDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		reader.dashboardProvisioningService = fakeService
+		reader.linter = lint.ProvideService()
+		return reader
+	}
+
+	t.Run("Should provision dashboards with lint errors in the warn mode", func(t *testing.T) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, "Default").Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&dashboards.Dashboard{}, nil).Once()
+
+		reader := newReader(t, lintModeWarn, fakeService)
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("Should not provision dashboards with lint errors in the fail mode", func(t *testing.T) {
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, "Default").Return(nil, nil).Once()
+
+		reader := newReader(t, lintModeFail, fakeService)
+		require.NoError(t, reader.walkDisk(context.Background()))
+		fakeService.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should fail on an invalid lint mode", func(t *testing.T) {
+		cfg := &config{Name: "Default", Type: "file", OrgID: 1, Options: map[string]any{"path": dashboardWithLintError, "lint": "strict"}}
+		_, err := NewDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{}, nil)
+		require.Error(t, err)
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
@@ -25,6 +26,7 @@ import (
 type Api struct {
 	PublicDashboardService publicdashboards.Service
 	Middleware             publicdashboards.Middleware
+	AccessGuard            *AccessGuard
 
 	accessControl accesscontrol.AccessControl
 	cfg           *setting.Cfg
@@ -42,10 +44,12 @@ func ProvideApi(
 	md publicdashboards.Middleware,
 	cfg *setting.Cfg,
 	license licensing.Licensing,
+	loginAttempts loginattempt.Service,
 ) *Api {
 	api := &Api{
 		PublicDashboardService: pd,
 		Middleware:             md,
+		AccessGuard:            NewAccessGuard(pd, loginAttempts, cfg),
 		accessControl:          ac,
 		cfg:                    cfg,
 		features:               features,
@@ -76,7 +80,9 @@ func (api *Api) RegisterAPIEndpoints() {
 		apiRoute.Get("/", routing.Wrap(api.ViewPublicDashboard))
 		apiRoute.Get("/annotations", routing.Wrap(api.GetPublicAnnotations))
 		apiRoute.Post("/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	}, api.Middleware.HandleApi)
+	}, api.Middleware.HandleApi, api.AccessGuard.RequireAccess)
+	// The login endpoint of password protected public dashboards enforces all access restrictions except the password
+	api.routeRegister.Post("/api/public/dashboards/:accessToken/login", api.Middleware.HandleApi, api.AccessGuard.RequireNetworkAccess, routing.Wrap(api.LoginPublicDashboard))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.accessControl)
@@ -166,7 +172,7 @@ func (api *Api) GetPublicDashboard(c *contextmodel.ReqContext) response.Response
 		return response.Err(ErrPublicDashboardNotFound.Errorf("GetPublicDashboard: public dashboard not found"))
 	}
 
-	return response.JSON(http.StatusOK, pd)
+	return publicDashboardResponse(pd)
 }
 
 // swagger:route POST /dashboards/uid/{dashboardUid}/public-dashboards dashboard_public createPublicDashboard
@@ -220,7 +226,7 @@ func (api *Api) CreatePublicDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Err(err)
 	}
 
-	return response.JSON(http.StatusOK, pd)
+	return publicDashboardResponse(pd)
 }
 
 // swagger:route PATCH /dashboards/uid/{dashboardUid}/public-dashboards/{uid} dashboard_public updatePublicDashboard
@@ -268,7 +274,7 @@ func (api *Api) UpdatePublicDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Err(err)
 	}
 
-	return response.JSON(http.StatusOK, pd)
+	return publicDashboardResponse(pd)
 }
 
 // swagger:route DELETE /dashboards/uid/{dashboardUid}/public-dashboards/{uid} dashboard_public deletePublicDashboard
@@ -300,6 +306,12 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.Empty(http.StatusOK)
 }
 
+// publicDashboardResponse returns the public dashboard with the fields derived from the fields that are never returned
+func publicDashboardResponse(pd *PublicDashboard) response.Response {
+	pd.PasswordProtected = pd.PasswordHash != ""
+	return response.JSON(http.StatusOK, pd)
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(ctx context.Context, features featuremgmt.FeatureToggles, qdr *backend.QueryDataResponse) response.Response {
 	statusCode := http.StatusOK
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -27,6 +28,7 @@ import (
 	datasourceService "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/licensing/licensingtest"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginconfig"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings/service"
@@ -75,10 +77,15 @@ func setupTestServer(
 		cfg.PublicDashboardsEnabled = true
 	}
 
+	// the access guard finds the public dashboard of every request to the public api
+	if fakeService, ok := service.(*publicdashboards.FakePublicDashboardService); ok {
+		fakeService.On("FindByAccessToken", mock.Anything, mock.Anything).Return(&publicdashboardModels.PublicDashboard{}, nil).Maybe()
+	}
+
 	// build api, this will mount the routes at the same time if the feature is enabled
 	license := licensingtest.NewFakeLicensing()
 	license.On("FeatureEnabled", publicdashboardModels.FeaturePublicDashboardsEmailSharing).Return(false)
-	ProvideApi(service, rr, ac, features, &Middleware{}, cfg, license)
+	ProvideApi(service, rr, ac, features, &Middleware{}, cfg, license, loginattempttest.FakeLoginAttemptService{ExpectedValid: true})
 
 	// connect routes to mux
 	rr.Register(m.Router)
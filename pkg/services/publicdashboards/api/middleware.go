@@ -1,12 +1,28 @@
 package api
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -62,6 +78,294 @@ func RequiresExistingAccessToken(publicDashboardService publicdashboards.Service
 	}
 }
 
+// passwordSessionDuration is how long the session of a password protected public dashboard is valid
+const passwordSessionDuration = 24 * time.Hour
+
+// passwordSessionCookieName is the name of the cookie with the session of a password protected public dashboard
+const passwordSessionCookieName = "grafana_public_dashboard_session"
+
+// limiterPruneInterval is how often the rate limiters of idle clients are removed. A limiter that has not been used
+// for a minute is full again, so removing it does not change the rate limit
+const limiterPruneInterval = time.Minute
+
+// AccessGuard enforces the access restrictions of public dashboards: the expiry time, the allowed IP ranges, the
+// rate limit and the password. Every access is logged with its outcome. The client address is the address of the
+// connection, unless the connection comes from a trusted proxy, in which case the X-Real-IP and X-Forwarded-For
+// headers are used.
+type AccessGuard struct {
+	publicDashboardService publicdashboards.Service
+	loginAttempts          loginattempt.Service
+	secretKey              []byte
+	appSubURL              string
+	trustedProxies         []string
+	log                    log.Logger
+	now                    func() time.Time
+
+	mu         sync.Mutex
+	limiters   map[limiterKey]*accessTokenLimiter
+	lastPruned time.Time
+}
+
+// limiterKey identifies the rate limiter of a client of a public dashboard
+type limiterKey struct {
+	accessToken string
+	clientAddr  string
+}
+
+type accessTokenLimiter struct {
+	limit    int64
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func NewAccessGuard(publicDashboardService publicdashboards.Service, loginAttempts loginattempt.Service, cfg *setting.Cfg) *AccessGuard {
+	g := &AccessGuard{
+		publicDashboardService: publicDashboardService,
+		loginAttempts:          loginAttempts,
+		secretKey:              []byte(cfg.SecretKey),
+		appSubURL:              cfg.AppSubURL,
+		log:                    log.New("publicdashboards.access"),
+		now:                    time.Now,
+		limiters:               make(map[limiterKey]*accessTokenLimiter),
+	}
+	for _, proxy := range cfg.PublicDashboardsTrustedProxies {
+		cidr := proxy
+		// single addresses are ranges with one address
+		if ip := net.ParseIP(proxy); ip != nil {
+			cidr = ip.String() + "/128"
+			if ip.To4() != nil {
+				cidr = ip.String() + "/32"
+			}
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			g.log.Warn("Ignoring invalid trusted proxy of public dashboards", "proxy", proxy, "error", err)
+			continue
+		}
+		g.trustedProxies = append(g.trustedProxies, cidr)
+	}
+	return g
+}
+
+// RequireAccess Middleware to enforce all the access restrictions of a public dashboard, including the password
+func (g *AccessGuard) RequireAccess(c *contextmodel.ReqContext) {
+	g.enforce(c, true)
+}
+
+// RequireNetworkAccess Middleware to enforce the access restrictions of a public dashboard except the password. Use
+// for the endpoint that checks the password
+func (g *AccessGuard) RequireNetworkAccess(c *contextmodel.ReqContext) {
+	g.enforce(c, false)
+}
+
+func (g *AccessGuard) enforce(c *contextmodel.ReqContext, requirePassword bool) {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		// the handlers reject invalid access tokens
+		return
+	}
+
+	pd, err := g.publicDashboardService.FindByAccessToken(c.Req.Context(), accessToken)
+	if err != nil {
+		// the handlers respond with not found or an internal error
+		return
+	}
+
+	remoteAddr := g.clientAddr(c.Req)
+	logger := g.log.FromContext(c.Req.Context()).New("publicDashboardUid", pd.Uid, "dashboardUid", pd.DashboardUid, "orgId", pd.OrgId, "remoteAddr", remoteAddr, "path", c.Req.URL.Path)
+
+	if err := g.check(c, pd, remoteAddr, requirePassword); err != nil {
+		logger.Info("Public dashboard access denied", "reason", err.Error())
+		c.WriteErr(err)
+		return
+	}
+
+	logger.Info("Public dashboard accessed")
+}
+
+func (g *AccessGuard) check(c *contextmodel.ReqContext, pd *PublicDashboard, remoteAddr string, requirePassword bool) error {
+	if pd.IsExpired(g.now()) {
+		return ErrPublicDashboardExpired.Errorf("public dashboard expired at %s", pd.ExpiresAt)
+	}
+
+	if len(pd.AllowedCIDRs) > 0 && !isAllowedAddr(remoteAddr, pd.AllowedCIDRs) {
+		return ErrPublicDashboardIPNotAllowed.Errorf("address %s is not in the allowed IP ranges", remoteAddr)
+	}
+
+	if !g.allow(pd, remoteAddr) {
+		return ErrPublicDashboardRateLimited.Errorf("rate limit of %d requests per minute exceeded", pd.RateLimit)
+	}
+
+	if requirePassword && pd.PasswordHash != "" {
+		cookie, err := c.Req.Cookie(passwordSessionCookieName)
+		if err != nil || !g.validSession(pd, cookie.Value) {
+			return ErrPublicDashboardPasswordRequired.Errorf("no valid session for password protected public dashboard")
+		}
+	}
+
+	return nil
+}
+
+// allow reports whether a request of a client to the public dashboard is within its rate limit
+func (g *AccessGuard) allow(pd *PublicDashboard, remoteAddr string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.prune(now)
+
+	if pd.RateLimit <= 0 {
+		return true
+	}
+
+	key := limiterKey{accessToken: pd.AccessToken, clientAddr: remoteAddr}
+	l, ok := g.limiters[key]
+	if !ok || l.limit != pd.RateLimit {
+		l = &accessTokenLimiter{
+			limit:   pd.RateLimit,
+			limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(pd.RateLimit)), int(pd.RateLimit)),
+		}
+		g.limiters[key] = l
+	}
+	l.lastUsed = now
+
+	return l.limiter.AllowN(now, 1)
+}
+
+// prune removes the rate limiters that have not been used within the prune interval. Must be called with the lock held
+func (g *AccessGuard) prune(now time.Time) {
+	if now.Sub(g.lastPruned) < limiterPruneInterval {
+		return
+	}
+	g.lastPruned = now
+
+	for key, l := range g.limiters {
+		if now.Sub(l.lastUsed) >= limiterPruneInterval {
+			delete(g.limiters, key)
+		}
+	}
+}
+
+// ValidateLogin returns an error if there were too many failed login attempts of the client to the public dashboard
+func (g *AccessGuard) ValidateLogin(ctx context.Context, pd *PublicDashboard, remoteAddr string) error {
+	ok, err := g.loginAttempts.Validate(ctx, loginAttemptsKey(pd, remoteAddr))
+	if err != nil {
+		return ErrInternalServerError.Errorf("ValidateLogin: failed to validate login attempts: %w", err)
+	}
+	if !ok {
+		return ErrPublicDashboardTooManyLoginAttempts.Errorf("too many failed login attempts for public dashboard %s", pd.Uid)
+	}
+	return nil
+}
+
+// AddFailedLogin records a failed login attempt of the client to the public dashboard
+func (g *AccessGuard) AddFailedLogin(ctx context.Context, pd *PublicDashboard, remoteAddr string) {
+	if err := g.loginAttempts.Add(ctx, loginAttemptsKey(pd, remoteAddr), remoteAddr); err != nil {
+		g.log.FromContext(ctx).Warn("Failed to record public dashboard login attempt", "publicDashboardUid", pd.Uid, "error", err)
+	}
+}
+
+// ResetLogins removes the failed login attempts of the client to the public dashboard after a successful login
+func (g *AccessGuard) ResetLogins(ctx context.Context, pd *PublicDashboard, remoteAddr string) {
+	if err := g.loginAttempts.Reset(ctx, loginAttemptsKey(pd, remoteAddr)); err != nil {
+		g.log.FromContext(ctx).Warn("Failed to reset public dashboard login attempts", "publicDashboardUid", pd.Uid, "error", err)
+	}
+}
+
+// loginAttemptsKey is the username the login attempts of a client to a public dashboard are recorded with. The
+// attempts are counted per client so that a client cannot lock out the other viewers of the public dashboard
+func loginAttemptsKey(pd *PublicDashboard, remoteAddr string) string {
+	return "public-dashboard:" + pd.Uid + ":" + remoteAddr
+}
+
+// StartSession sets the session cookie of a password protected public dashboard
+func (g *AccessGuard) StartSession(c *contextmodel.ReqContext, pd *PublicDashboard) {
+	expiresAt := g.now().Add(passwordSessionDuration)
+	value := strconv.FormatInt(expiresAt.Unix(), 10) + "." + g.sign(pd, expiresAt.Unix())
+	cookies.WriteCookie(c.Resp, passwordSessionCookieName, value, int(passwordSessionDuration.Seconds()), func() cookies.CookieOptions {
+		options := cookies.NewCookieOptions()
+		// scope the session to the api of the public dashboard
+		options.Path = g.appSubURL + "/api/public/dashboards/" + pd.AccessToken
+		return options
+	})
+}
+
+// validSession checks the signature and the expiry time of a session cookie
+func (g *AccessGuard) validSession(pd *PublicDashboard, value string) bool {
+	expiresAtStr, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(expiresAtStr, 10, 64)
+	if err != nil || g.now().Unix() >= expiresAt {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(g.sign(pd, expiresAt)))
+}
+
+// sign signs the session of a public dashboard. The signature includes the password hash so that changing the
+// password ends all sessions
+func (g *AccessGuard) sign(pd *PublicDashboard, expiresAt int64) string {
+	mac := hmac.New(sha256.New, g.secretKey)
+	mac.Write([]byte(pd.AccessToken))
+	mac.Write([]byte{0})
+	mac.Write([]byte(pd.PasswordHash))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expiresAt, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// clientAddr returns the IP address of the client of the request. It is the address of the connection, unless the
+// connection comes from a trusted proxy. Then it is the address in the X-Real-IP header, or the last address in the
+// X-Forwarded-For header that is not a trusted proxy
+func (g *AccessGuard) clientAddr(req *http.Request) string {
+	addr := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if len(g.trustedProxies) == 0 || !isAllowedAddr(addr, g.trustedProxies) {
+		return addr
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	// each proxy appends the address it received the request from, so only the addresses appended by trusted
+	// proxies can be relied on
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if ip == nil {
+			break
+		}
+		addr = ip.String()
+		if !isAllowedAddr(addr, g.trustedProxies) {
+			break
+		}
+	}
+	return addr
+}
+
+func isAllowedAddr(addr string, cidrs []string) bool {
+	ip := net.ParseIP(strings.Trim(addr, "[]"))
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
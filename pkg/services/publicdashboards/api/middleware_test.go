@@ -1,17 +1,25 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -185,3 +193,240 @@ func runMw(t *testing.T, ctx *contextmodel.ReqContext, httpmethod string, path s
 	// return result
 	return ctx, response
 }
+
+func TestAccessGuard(t *testing.T) {
+	now := time.Now()
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+
+	newGuardWithCfg := func(pd *PublicDashboard, cfg *setting.Cfg) *AccessGuard {
+		pd.AccessToken = validAccessToken
+		publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+		publicdashboardService.On("FindByAccessToken", mock.Anything, validAccessToken).Return(pd, nil)
+		guard := NewAccessGuard(publicdashboardService, loginattempttest.FakeLoginAttemptService{ExpectedValid: true}, cfg)
+		guard.now = func() time.Time { return now }
+		return guard
+	}
+	newGuard := func(pd *PublicDashboard) *AccessGuard {
+		return newGuardWithCfg(pd, cfg)
+	}
+
+	run := func(t *testing.T, mw func(c *contextmodel.ReqContext), setup func(r *http.Request)) *httptest.ResponseRecorder {
+		t.Helper()
+		request, err := http.NewRequest(http.MethodGet, "/api/public/dashboards/"+validAccessToken, nil)
+		require.NoError(t, err)
+		request.RemoteAddr = "10.0.0.1:1234"
+		if setup != nil {
+			setup(request)
+		}
+		request = web.SetURLParams(request, map[string]string{":accessToken": validAccessToken})
+		response := httptest.NewRecorder()
+		ctx := &contextmodel.ReqContext{
+			Context:      &web.Context{Req: request, Resp: web.NewResponseWriter(http.MethodGet, response)},
+			SignedInUser: &user.SignedInUser{},
+			Logger:       log.NewNopLogger(),
+		}
+		mw(ctx)
+		if !ctx.Resp.Written() {
+			ctx.Resp.WriteHeader(http.StatusOK)
+		}
+		return response
+	}
+
+	t.Run("allows access to public dashboards without restrictions", func(t *testing.T) {
+		guard := newGuard(&PublicDashboard{})
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+	})
+
+	t.Run("denies access to expired public dashboards", func(t *testing.T) {
+		expiresAt := now.Add(-time.Minute)
+		guard := newGuard(&PublicDashboard{ExpiresAt: &expiresAt})
+		assert.Equal(t, http.StatusForbidden, run(t, guard.RequireAccess, nil).Code)
+
+		expiresAt = now.Add(time.Minute)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+	})
+
+	t.Run("denies access from addresses outside of the allowed IP ranges", func(t *testing.T) {
+		guard := newGuard(&PublicDashboard{AllowedCIDRs: []string{"192.168.0.0/16", "2001:db8::/32"}})
+		assert.Equal(t, http.StatusForbidden, run(t, guard.RequireAccess, nil).Code)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.RemoteAddr = "192.168.1.10:1234"
+		}).Code)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.RemoteAddr = "[2001:db8::1]:1234"
+		}).Code)
+	})
+
+	t.Run("does not trust forwarded addresses", func(t *testing.T) {
+		guard := newGuard(&PublicDashboard{AllowedCIDRs: []string{"192.168.0.0/16"}})
+		assert.Equal(t, http.StatusForbidden, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.Header.Set("X-Forwarded-For", "192.168.1.10")
+			r.Header.Set("X-Real-IP", "192.168.1.10")
+		}).Code)
+	})
+
+	t.Run("trusts forwarded addresses of trusted proxies", func(t *testing.T) {
+		proxyCfg := setting.NewCfg()
+		proxyCfg.SecretKey = "secret"
+		proxyCfg.PublicDashboardsTrustedProxies = []string{"10.0.0.1", "172.16.0.0/12", "invalid"}
+		guard := newGuardWithCfg(&PublicDashboard{AllowedCIDRs: []string{"192.168.0.0/16"}}, proxyCfg)
+		require.Equal(t, []string{"10.0.0.1/32", "172.16.0.0/12"}, guard.trustedProxies)
+
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.Header.Set("X-Forwarded-For", "192.168.1.10")
+		}).Code)
+		assert.Equal(t, http.StatusForbidden, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.RemoteAddr = "10.0.0.2:1234"
+			r.Header.Set("X-Forwarded-For", "192.168.1.10")
+		}).Code)
+
+		for _, tc := range []struct {
+			name       string
+			remoteAddr string
+			headers    map[string]string
+			expected   string
+		}{
+			{
+				name:       "connection from an untrusted address",
+				remoteAddr: "10.0.0.2:1234",
+				headers:    map[string]string{"X-Real-IP": "192.168.1.10", "X-Forwarded-For": "192.168.1.10"},
+				expected:   "10.0.0.2",
+			},
+			{
+				name:       "real IP of a trusted proxy",
+				remoteAddr: "10.0.0.1:1234",
+				headers:    map[string]string{"X-Real-IP": "192.168.1.10", "X-Forwarded-For": "192.168.1.11"},
+				expected:   "192.168.1.10",
+			},
+			{
+				name:       "forwarded address after trusted proxies",
+				remoteAddr: "10.0.0.1:1234",
+				headers:    map[string]string{"X-Forwarded-For": "192.168.1.10, 172.16.0.2"},
+				expected:   "192.168.1.10",
+			},
+			{
+				name:       "forwarded addresses set by the client are ignored",
+				remoteAddr: "10.0.0.1:1234",
+				headers:    map[string]string{"X-Forwarded-For": "192.168.1.11, 192.168.1.10"},
+				expected:   "192.168.1.10",
+			},
+			{
+				name:       "invalid forwarded address",
+				remoteAddr: "10.0.0.1:1234",
+				headers:    map[string]string{"X-Real-IP": "unknown", "X-Forwarded-For": "unknown"},
+				expected:   "10.0.0.1",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.RemoteAddr = tc.remoteAddr
+				for k, v := range tc.headers {
+					r.Header.Set(k, v)
+				}
+				assert.Equal(t, tc.expected, guard.clientAddr(r))
+			})
+		}
+	})
+
+	t.Run("limits the rate of requests per access token and client", func(t *testing.T) {
+		guard := newGuard(&PublicDashboard{RateLimit: 2})
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, run(t, guard.RequireAccess, nil).Code)
+
+		// other clients have their own limit
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.RemoteAddr = "10.0.0.2:1234"
+		}).Code)
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+	})
+
+	t.Run("prunes the rate limiters of idle clients", func(t *testing.T) {
+		guard := newGuard(&PublicDashboard{RateLimit: 2})
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) {
+			r.RemoteAddr = "10.0.0.2:1234"
+		}).Code)
+		require.Len(t, guard.limiters, 2)
+
+		now = now.Add(limiterPruneInterval)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, nil).Code)
+		require.Len(t, guard.limiters, 1)
+	})
+
+	t.Run("blocks logins after too many failed attempts", func(t *testing.T) {
+		pd := &PublicDashboard{Uid: "pd-uid", PasswordHash: "hash", PasswordSalt: "salt"}
+		guard := newGuard(pd)
+		require.NoError(t, guard.ValidateLogin(context.Background(), pd, "10.0.0.1"))
+
+		guard.loginAttempts = loginattempttest.FakeLoginAttemptService{ExpectedValid: false}
+		err := guard.ValidateLogin(context.Background(), pd, "10.0.0.1")
+		require.ErrorIs(t, err, ErrPublicDashboardTooManyLoginAttempts)
+	})
+
+	t.Run("blocks logins per client", func(t *testing.T) {
+		pd := &PublicDashboard{Uid: "pd-uid", PasswordHash: "hash", PasswordSalt: "salt"}
+		guard := newGuard(pd)
+		attempts := &countingLoginAttempts{max: 2, attempts: map[string]int{}}
+		guard.loginAttempts = attempts
+
+		guard.AddFailedLogin(context.Background(), pd, "10.0.0.1")
+		guard.AddFailedLogin(context.Background(), pd, "10.0.0.1")
+		require.ErrorIs(t, guard.ValidateLogin(context.Background(), pd, "10.0.0.1"), ErrPublicDashboardTooManyLoginAttempts)
+		require.NoError(t, guard.ValidateLogin(context.Background(), pd, "10.0.0.2"))
+
+		guard.ResetLogins(context.Background(), pd, "10.0.0.1")
+		require.NoError(t, guard.ValidateLogin(context.Background(), pd, "10.0.0.1"))
+	})
+
+	t.Run("requires a session for password protected public dashboards", func(t *testing.T) {
+		pd := &PublicDashboard{PasswordHash: "hash", PasswordSalt: "salt"}
+		guard := newGuard(pd)
+		assert.Equal(t, http.StatusUnauthorized, run(t, guard.RequireAccess, nil).Code)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireNetworkAccess, nil).Code)
+
+		// start a session
+		login := run(t, func(c *contextmodel.ReqContext) { guard.StartSession(c, pd) }, nil)
+		cookies := login.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "/api/public/dashboards/"+validAccessToken, cookies[0].Path)
+		assert.Equal(t, http.StatusOK, run(t, guard.RequireAccess, func(r *http.Request) { r.AddCookie(cookies[0]) }).Code)
+
+		// tampered session
+		tampered := *cookies[0]
+		tampered.Value = strconv.FormatInt(now.Add(48*time.Hour).Unix(), 10) + tampered.Value[strings.Index(tampered.Value, "."):]
+		assert.Equal(t, http.StatusUnauthorized, run(t, guard.RequireAccess, func(r *http.Request) { r.AddCookie(&tampered) }).Code)
+
+		// changing the password ends the session
+		pd.PasswordHash = "other"
+		assert.Equal(t, http.StatusUnauthorized, run(t, guard.RequireAccess, func(r *http.Request) { r.AddCookie(cookies[0]) }).Code)
+
+		// expired session
+		pd.PasswordHash = "hash"
+		now = now.Add(passwordSessionDuration)
+		assert.Equal(t, http.StatusUnauthorized, run(t, guard.RequireAccess, func(r *http.Request) { r.AddCookie(cookies[0]) }).Code)
+	})
+}
+
+// countingLoginAttempts blocks the usernames with at least max login attempts
+type countingLoginAttempts struct {
+	max      int
+	attempts map[string]int
+}
+
+func (c *countingLoginAttempts) Add(_ context.Context, username, _ string) error {
+	c.attempts[username]++
+	return nil
+}
+
+func (c *countingLoginAttempts) Validate(_ context.Context, username string) (bool, error) {
+	return c.attempts[username] < c.max, nil
+}
+
+func (c *countingLoginAttempts) Reset(_ context.Context, username string) error {
+	delete(c.attempts, username)
+	return nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -24,6 +25,7 @@ import (
 // 401: unauthorisedPublicError
 // 403: forbiddenPublicError
 // 404: notFoundPublicError
+// 429: tooManyRequestsPublicError
 // 500: internalServerPublicError
 func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Response {
 	accessToken := web.Params(c.Req)[":accessToken"]
@@ -39,6 +41,57 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route POST /public/dashboards/{accessToken}/login dashboard_public loginPublicDashboard
+//
+//	Start a session for a password protected public dashboard
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 429: tooManyRequestsPublicError
+// 500: internalServerPublicError
+func (api *Api) LoginPublicDashboard(c *contextmodel.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("LoginPublicDashboard: invalid access token"))
+	}
+
+	reqDTO := PublicDashboardLoginDTO{}
+	if err := web.Bind(c.Req, &reqDTO); err != nil {
+		return response.Err(ErrBadRequest.Errorf("LoginPublicDashboard: error parsing request: %v", err))
+	}
+
+	pd, _, err := api.PublicDashboardService.FindEnabledPublicDashboardAndDashboardByAccessToken(c.Req.Context(), accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if pd.PasswordHash == "" {
+		return response.Success("Logged in")
+	}
+
+	remoteAddr := api.AccessGuard.clientAddr(c.Req)
+	if err := api.AccessGuard.ValidateLogin(c.Req.Context(), pd, remoteAddr); err != nil {
+		return response.Err(err)
+	}
+
+	if err := api.PublicDashboardService.VerifyPassword(c.Req.Context(), pd, reqDTO.Password); err != nil {
+		api.AccessGuard.log.Info("Public dashboard login failed", "publicDashboardUid", pd.Uid, "dashboardUid", pd.DashboardUid, "remoteAddr", remoteAddr)
+		if errors.Is(err, ErrPublicDashboardInvalidPassword) {
+			api.AccessGuard.AddFailedLogin(c.Req.Context(), pd, remoteAddr)
+		}
+		return response.Err(err)
+	}
+
+	api.AccessGuard.ResetLogins(c.Req.Context(), pd, remoteAddr)
+	api.AccessGuard.StartSession(c, pd)
+
+	return response.Success("Logged in")
+}
+
 // swagger:route POST /public/dashboards/{accessToken}/panels/{panelId}/query dashboard_public queryPublicDashboard
 //
 //	Get results for a given panel on a public dashboard
@@ -50,6 +103,7 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 // 404: panelNotFoundPublicError
 // 404: notFoundPublicError
 // 403: forbiddenPublicError
+// 429: tooManyRequestsPublicError
 // 500: internalServerPublicError
 func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Response {
 	accessToken := web.Params(c.Req)[":accessToken"]
@@ -85,6 +139,7 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 // 404: notFoundPublicError
 // 401: unauthorisedPublicError
 // 403: forbiddenPublicError
+// 429: tooManyRequestsPublicError
 // 500: internalServerPublicError
 func (api *Api) GetPublicAnnotations(c *contextmodel.ReqContext) response.Response {
 	accessToken := web.Params(c.Req)[":accessToken"]
@@ -117,6 +172,15 @@ type ViewPublicDashboardParams struct {
 	AccessToken string `json:"accessToken"`
 }
 
+// swagger:parameters loginPublicDashboard
+type LoginPublicDashboardParams struct {
+	// in: path
+	AccessToken string `json:"accessToken"`
+	// in: body
+	// required: true
+	Body PublicDashboardLoginDTO
+}
+
 // swagger:response queryPublicDashboardResponse
 type QueryPublicDashboardResponse struct {
 	// in: body
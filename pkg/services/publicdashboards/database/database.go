@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -28,6 +29,8 @@ type PublicDashboardStoreImpl struct {
 
 var LogPrefix = "publicdashboards.store"
 
+const dateTimeFormat = "2006-01-02 15:04:05"
+
 // Gives us a compile time error if our database does not adhere to contract of
 // the interface
 var _ publicdashboards.Store = (*PublicDashboardStoreImpl)(nil)
@@ -247,14 +250,29 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		allowedCIDRsJSON, err := json.Marshal(cmd.PublicDashboard.AllowedCIDRs)
+		if err != nil {
+			return err
+		}
+
+		var expiresAt any
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format(dateTimeFormat)
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, expires_at = ?, password_hash = ?, password_salt = ?, allowed_cidrs = ?, rate_limit = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			expiresAt,
+			cmd.PublicDashboard.PasswordHash,
+			cmd.PublicDashboard.PasswordSalt,
+			string(allowedCIDRsJSON),
+			cmd.PublicDashboard.RateLimit,
 			cmd.PublicDashboard.UpdatedBy,
-			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+			cmd.PublicDashboard.UpdatedAt.UTC().Format(dateTimeFormat),
 			cmd.PublicDashboard.Uid)
 
 		if err != nil {
@@ -269,6 +287,25 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
+// DisableExpired disables the enabled public dashboards that expired at or before now
+func (d *PublicDashboardStoreImpl) DisableExpired(ctx context.Context, now time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		nowStr := now.UTC().Format(dateTimeFormat)
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, updated_at = ? WHERE is_enabled = ? AND expires_at IS NOT NULL AND expires_at <= ?",
+			false, nowStr, true, nowStr)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = sqlResult.RowsAffected()
+
+		return err
+	})
+
+	return affectedRows, err
+}
+
 // Deletes a public dashboard
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
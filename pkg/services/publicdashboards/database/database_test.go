@@ -625,6 +625,97 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 	})
 }
 
+func TestIntegrationUpdatePublicDashboardAccessRestrictions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBWithCfg(t)
+	quotaService := quotatest.New(false, nil)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotaService)
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, "", true)
+	pubdash := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+	assert.Nil(t, pubdash.ExpiresAt)
+	assert.Empty(t, pubdash.AllowedCIDRs)
+
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	pubdash.ExpiresAt = &expiresAt
+	pubdash.PasswordHash = "hash"
+	pubdash.PasswordSalt = "salt"
+	pubdash.AllowedCIDRs = []string{"10.0.0.0/8"}
+	pubdash.RateLimit = 60
+	pubdash.UpdatedAt = time.Now()
+	_, err = publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *pubdash})
+	require.NoError(t, err)
+
+	retrieved, err := publicdashboardStore.FindByAccessToken(context.Background(), pubdash.AccessToken)
+	require.NoError(t, err)
+	require.NotNil(t, retrieved.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*retrieved.ExpiresAt))
+	assert.Equal(t, "hash", retrieved.PasswordHash)
+	assert.Equal(t, "salt", retrieved.PasswordSalt)
+	assert.Equal(t, []string{"10.0.0.0/8"}, retrieved.AllowedCIDRs)
+	assert.EqualValues(t, 60, retrieved.RateLimit)
+
+	// remove the restrictions
+	retrieved.ExpiresAt = nil
+	retrieved.PasswordHash, retrieved.PasswordSalt = "", ""
+	retrieved.AllowedCIDRs = nil
+	retrieved.RateLimit = 0
+	_, err = publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *retrieved})
+	require.NoError(t, err)
+
+	retrieved, err = publicdashboardStore.FindByAccessToken(context.Background(), pubdash.AccessToken)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.ExpiresAt)
+	assert.Empty(t, retrieved.PasswordHash)
+	assert.Empty(t, retrieved.AllowedCIDRs)
+	assert.Zero(t, retrieved.RateLimit)
+}
+
+func TestIntegrationDisableExpired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBWithCfg(t)
+	quotaService := quotatest.New(false, nil)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore), quotaService)
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	now := time.Now()
+
+	setExpiresAt := func(pubdash *PublicDashboard, expiresAt time.Time) {
+		pubdash.ExpiresAt = &expiresAt
+		pubdash.UpdatedAt = now
+		_, err := publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *pubdash})
+		require.NoError(t, err)
+	}
+
+	expired := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "expired", 1, "", true).UID, 1, true, PublicShareType)
+	setExpiresAt(expired, now.Add(-time.Hour))
+	notExpired := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "not expired", 1, "", true).UID, 1, true, PublicShareType)
+	setExpiresAt(notExpired, now.Add(time.Hour))
+	withoutExpiry := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "without expiry", 1, "", true).UID, 1, true, PublicShareType)
+
+	affectedRows, err := publicdashboardStore.DisableExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, affectedRows)
+
+	for _, tc := range []struct {
+		pubdash *PublicDashboard
+		enabled bool
+	}{
+		{expired, false},
+		{notExpired, true},
+		{withoutExpiry, true},
+	} {
+		retrieved, err := publicdashboardStore.Find(context.Background(), tc.pubdash.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, tc.enabled, retrieved.IsEnabled)
+	}
+}
+
 func TestIntegrationGetOrgIdByAccessToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -15,7 +15,9 @@ var (
 	ErrInvalidPanelId                      = errutil.BadRequest("publicdashboards.invalidPanelId", errutil.WithPublicMessage("Invalid panel id"))
 	ErrInvalidUid                          = errutil.BadRequest("publicdashboards.invalidUid", errutil.WithPublicMessage("Invalid Uid"))
 	ErrPublicDashboardIdentifierNotSet     = errutil.BadRequest("publicdashboards.identifierNotSet", errutil.WithPublicMessage("No Uid for dashboard specified"))
-	ErrPublicDashboardHasTemplateVariables = errutil.BadRequest("publicdashboards.hasTemplateVariables", errutil.WithPublicMessage("Dashboard has template variables"))
+	ErrPublicDashboardHasTemplateVariables = errutil.BadRequest("publicdashboards.hasTemplateVariables", errutil.WithPublicMessage("Dashboard has unsupported template variables"))
+	ErrInvalidTemplateVariableValue        = errutil.BadRequest("publicdashboards.invalidTemplateVariableValue", errutil.WithPublicMessage("Invalid template variable value"))
+	ErrTemplateVariableDatasourceForbidden = errutil.Forbidden("publicdashboards.templateVariableDatasourceForbidden", errutil.WithPublicMessage("The owner of the public dashboard cannot query the data source of a template variable"))
 	ErrInvalidInterval                     = errutil.BadRequest("publicdashboards.invalidInterval", errutil.WithPublicMessage("intervalMS should be greater than 0"))
 	ErrInvalidMaxDataPoints                = errutil.BadRequest("publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.BadRequest("publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
@@ -23,6 +25,16 @@ var (
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Dashboard Access Token already exists"))
+	ErrInvalidExpiresAt                    = errutil.BadRequest("publicdashboards.invalidExpiresAt", errutil.WithPublicMessage("Expiry time must be in the future"))
+	ErrInvalidAllowedCIDR                  = errutil.BadRequest("publicdashboards.invalidAllowedCidr", errutil.WithPublicMessage("Invalid IP range"))
+	ErrInvalidRateLimit                    = errutil.BadRequest("publicdashboards.invalidRateLimit", errutil.WithPublicMessage("Rate limit should not be negative"))
 
-	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Dashboard paused"))
+	ErrPublicDashboardPasswordRequired     = errutil.Unauthorized("publicdashboards.passwordRequired", errutil.WithPublicMessage("Password required"))
+	ErrPublicDashboardInvalidPassword      = errutil.Unauthorized("publicdashboards.invalidPassword", errutil.WithPublicMessage("Invalid password"))
+	ErrPublicDashboardRateLimited          = errutil.TooManyRequests("publicdashboards.rateLimited", errutil.WithPublicMessage("Too many requests"))
+	ErrPublicDashboardTooManyLoginAttempts = errutil.TooManyRequests("publicdashboards.tooManyLoginAttempts", errutil.WithPublicMessage("Too many failed login attempts, try again later"))
+
+	ErrPublicDashboardNotEnabled   = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Dashboard paused"))
+	ErrPublicDashboardExpired      = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Dashboard expired"))
+	ErrPublicDashboardIPNotAllowed = errutil.Forbidden("publicdashboards.ipNotAllowed", errutil.WithPublicMessage("Access denied"))
 )
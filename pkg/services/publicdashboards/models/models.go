@@ -54,6 +54,19 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	//access restrictions
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+	PasswordHash      string     `json:"-" xorm:"password_hash"`
+	PasswordSalt      string     `json:"-" xorm:"password_salt"`
+	PasswordProtected bool       `json:"passwordProtected" xorm:"-"`
+	AllowedCIDRs      []string   `json:"allowedCidrs,omitempty" xorm:"allowed_cidrs"`
+	// RateLimit is the maximum number of requests per minute to the public dashboard. 0 means unlimited.
+	RateLimit int64 `json:"rateLimit" xorm:"rate_limit"`
+}
+
+// IsExpired returns true if the public dashboard has an expiry time that is not after now
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.After(now)
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +76,18 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// ExpiresAt is the time after which the public dashboard is disabled. The zero time removes the expiry
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Password is the access password of the public dashboard. An empty password removes the password
+	Password *string `json:"password"`
+	// AllowedCIDRs are the IP ranges that can access the public dashboard. An empty list allows all addresses
+	AllowedCIDRs *[]string `json:"allowedCidrs"`
+	// RateLimit is the maximum number of requests per minute. 0 removes the limit
+	RateLimit *int64 `json:"rateLimit"`
+}
+
+type PublicDashboardLoginDTO struct {
+	Password string `json:"password"`
 }
 
 type EmailDTO struct {
@@ -154,6 +179,9 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the selected values of the template variables by name. Variables without values use the values
+	// that are saved in the dashboard.
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
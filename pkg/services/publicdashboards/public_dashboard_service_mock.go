@@ -77,6 +77,30 @@ func (_m *FakePublicDashboardService) DeleteByDashboard(ctx context.Context, das
 	return r0
 }
 
+// DisableExpired provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) DisableExpired(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -525,6 +549,20 @@ func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// VerifyPassword provides a mock function with given fields: ctx, publicDashboard, password
+func (_m *FakePublicDashboardService) VerifyPassword(ctx context.Context, publicDashboard *models.PublicDashboard, password string) error {
+	ret := _m.Called(ctx, publicDashboard, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboard, string) error); ok {
+		r0 = rf(ctx, publicDashboard, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewFakePublicDashboardService creates a new instance of FakePublicDashboardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewFakePublicDashboardService(t interface {
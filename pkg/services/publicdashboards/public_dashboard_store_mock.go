@@ -7,6 +7,8 @@ import (
 
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -62,6 +64,30 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int
 	return r0, r1
 }
 
+// DisableExpired provides a mock function with given fields: ctx, now
+func (_m *FakePublicDashboardStore) DisableExpired(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -35,6 +36,8 @@ type Service interface {
 	GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error)
 	NewPublicDashboardAccessToken(ctx context.Context) (string, error)
 	NewPublicDashboardUid(ctx context.Context) (string, error)
+	VerifyPassword(ctx context.Context, publicDashboard *PublicDashboard, password string) error
+	DisableExpired(ctx context.Context) (int64, error)
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
@@ -63,6 +66,7 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+	DisableExpired(ctx context.Context, now time.Time) (int64, error)
 }
 
 //go:generate mockery --name Middleware --structname FakePublicDashboardMiddleware --inpackage --filename public_dashboard_middleware_mock.go
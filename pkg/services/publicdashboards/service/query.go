@@ -120,9 +120,34 @@ func (pd *PublicDashboardServiceImpl) GetMetricRequest(ctx context.Context, dash
 		return dtos.MetricRequest{}, err
 	}
 
+	if err := pd.interpolateMetricRequest(ctx, dashboard, publicDashboard, queryDto, &metricReqDTO); err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
 	return metricReqDTO, nil
 }
 
+// interpolateMetricRequest replaces the template variables in the queries of the metric request with the requested
+// values, after validating them against the options of the variables
+func (pd *PublicDashboardServiceImpl) interpolateMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, queryDto models.PublicDashboardQueryDTO, metricReq *dtos.MetricRequest) error {
+	if len(dashboard.Data.GetPath("templating", "list").MustArray()) == 0 {
+		return nil
+	}
+
+	resolved, err := pd.resolveVariablesCached(ctx, dashboard, publicDashboard, queryDto)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range metricReq.Queries {
+		dsType := query.Get("datasource").Get("type").MustString()
+		if err := interpolateVariables(query, dsType, resolved.unsupported, resolved.values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetQueryDataResponse returns a query data response for the given panel and query
 func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context, skipDSCache bool, queryDto models.PublicDashboardQueryDTO, panelId int64, accessToken string) (*backend.QueryDataResponse, error) {
 	publicDashboard, dashboard, err := pd.FindEnabledPublicDashboardAndDashboardByAccessToken(ctx, accessToken)
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -43,6 +45,9 @@ type PublicDashboardServiceImpl struct {
 	serviceWrapper     publicdashboards.ServiceWrapper
 	dashboardService   dashboards.DashboardService
 	license            licensing.Licensing
+	userService        user.Service
+	acService          accesscontrol.Service
+	variableCache      *localcache.CacheService
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -64,6 +69,8 @@ func ProvideService(
 	serviceWrapper publicdashboards.ServiceWrapper,
 	dashboardService dashboards.DashboardService,
 	license licensing.Licensing,
+	userService user.Service,
+	acService accesscontrol.Service,
 ) *PublicDashboardServiceImpl {
 	return &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
@@ -77,6 +84,9 @@ func ProvideService(
 		serviceWrapper:     serviceWrapper,
 		dashboardService:   dashboardService,
 		license:            license,
+		userService:        userService,
+		acService:          acService,
+		variableCache:      localcache.New(variableCacheTTL, 2*variableCacheTTL),
 	}
 }
 
@@ -108,6 +118,7 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
+	pd.setVariableOptions(ctx, pubdash, dash)
 	sanitizeData(dash.Data)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
@@ -177,6 +188,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired accessToken: %s", accessToken)
+	}
+
 	if !pd.license.FeatureEnabled(FeaturePublicDashboardsEmailSharing) && pubdash.Share == EmailShareType {
 		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Dashboard not found accessToken: %s", accessToken)
 	}
@@ -293,7 +308,10 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrInvalidUid.Errorf("Update: the public dashboard does not belong to the dashboard")
 	}
 
-	publicDashboard := newUpdatePublicDashboard(dto, existingPubdash)
+	publicDashboard, err := newUpdatePublicDashboard(dto, existingPubdash)
+	if err != nil {
+		return nil, err
+	}
 
 	// set values to update
 	cmd := SavePublicDashboardCommand{
@@ -471,6 +489,42 @@ func (pd *PublicDashboardServiceImpl) getSafeIntervalAndMaxDataPoints(reqDTO Pub
 	return safeInterval.Value.Milliseconds(), safeResolution
 }
 
+// VerifyPassword checks the password of a password protected public dashboard
+func (pd *PublicDashboardServiceImpl) VerifyPassword(ctx context.Context, publicDashboard *PublicDashboard, password string) error {
+	_, span := tracer.Start(ctx, "publicdashboards.VerifyPassword")
+	defer span.End()
+
+	if publicDashboard.PasswordHash == "" {
+		return nil
+	}
+
+	hash, err := util.EncodePassword(password, publicDashboard.PasswordSalt)
+	if err != nil {
+		return ErrInternalServerError.Errorf("VerifyPassword: failed to hash password: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(publicDashboard.PasswordHash)) != 1 {
+		return ErrPublicDashboardInvalidPassword.Errorf("VerifyPassword: invalid password for public dashboard %s", publicDashboard.Uid)
+	}
+
+	return nil
+}
+
+// DisableExpired disables the public dashboards that expired
+func (pd *PublicDashboardServiceImpl) DisableExpired(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "publicdashboards.DisableExpired")
+	defer span.End()
+
+	affectedRows, err := pd.store.DisableExpired(ctx, time.Now())
+	if err != nil {
+		return 0, ErrInternalServerError.Errorf("DisableExpired: failed to disable expired public dashboards: %w", err)
+	}
+	if affectedRows > 0 {
+		pd.log.Info("Disabled expired public dashboards", "count", affectedRows)
+	}
+
+	return affectedRows, nil
+}
+
 // Log when PublicDashboard.ExistsEnabledByDashboardUid changed
 func (pd *PublicDashboardServiceImpl) logIsEnabledChanged(existingPubdash *PublicDashboard, newPubdash *PublicDashboard, u *user.SignedInUser) {
 	if publicDashboardIsEnabledChanged(existingPubdash, newPubdash) {
@@ -544,7 +598,7 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 
 	now := time.Now()
 
-	return &PublicDashboard{
+	publicDashboard := &PublicDashboard{
 		Uid:                  uid,
 		DashboardUid:         dto.DashboardUid,
 		OrgId:                dto.OrgID,
@@ -558,10 +612,16 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            now,
 		AccessToken:          accessToken,
-	}, nil
+	}
+
+	if err := applyAccessRestrictions(dto.PublicDashboard, publicDashboard); err != nil {
+		return nil, err
+	}
+
+	return publicDashboard, nil
 }
 
-func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) *PublicDashboard {
+func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) (*PublicDashboard, error) {
 	pubdashDTO := dto.PublicDashboard
 	timeSelectionEnabled := returnValueOrDefault(pubdashDTO.TimeSelectionEnabled, pd.TimeSelectionEnabled)
 	isEnabled := returnValueOrDefault(pubdashDTO.IsEnabled, pd.IsEnabled)
@@ -572,16 +632,68 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
-	return &PublicDashboard{
+	publicDashboard := &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
 		AnnotationsEnabled:   annotationsEnabled,
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		ExpiresAt:            pd.ExpiresAt,
+		PasswordHash:         pd.PasswordHash,
+		PasswordSalt:         pd.PasswordSalt,
+		AllowedCIDRs:         pd.AllowedCIDRs,
+		RateLimit:            pd.RateLimit,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
+
+	if err := applyAccessRestrictions(pubdashDTO, publicDashboard); err != nil {
+		return nil, err
+	}
+
+	return publicDashboard, nil
+}
+
+// applyAccessRestrictions sets the access restrictions of the dto that are set on the public dashboard
+func applyAccessRestrictions(dto *PublicDashboardDTO, pd *PublicDashboard) error {
+	if dto.ExpiresAt != nil {
+		if dto.ExpiresAt.IsZero() {
+			pd.ExpiresAt = nil
+		} else {
+			expiresAt := *dto.ExpiresAt
+			pd.ExpiresAt = &expiresAt
+		}
+	}
+
+	if dto.Password != nil {
+		if *dto.Password == "" {
+			pd.PasswordHash, pd.PasswordSalt = "", ""
+		} else {
+			salt, err := util.GetRandomString(10)
+			if err != nil {
+				return ErrInternalServerError.Errorf("applyAccessRestrictions: failed to generate password salt: %w", err)
+			}
+			hash, err := util.EncodePassword(*dto.Password, salt)
+			if err != nil {
+				return ErrInternalServerError.Errorf("applyAccessRestrictions: failed to hash password: %w", err)
+			}
+			pd.PasswordHash, pd.PasswordSalt = hash, salt
+		}
+	}
+
+	if dto.AllowedCIDRs != nil {
+		pd.AllowedCIDRs = nil
+		if len(*dto.AllowedCIDRs) > 0 {
+			pd.AllowedCIDRs = *dto.AllowedCIDRs
+		}
+	}
+
+	if dto.RateLimit != nil {
+		pd.RateLimit = *dto.RateLimit
+	}
+
+	return nil
 }
 
 func returnValueOrDefault(value *bool, defaultValue bool) bool {
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -1666,6 +1667,83 @@ func TestDeleteByDashboard(t *testing.T) {
 	})
 }
 
+func TestApplyAccessRestrictions(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	password := "secret"
+	cidrs := []string{"10.0.0.0/8"}
+	rateLimit := int64(10)
+
+	t.Run("sets the restrictions of the dto", func(t *testing.T) {
+		pubdash := &PublicDashboard{Uid: "pubdash"}
+		dto := &PublicDashboardDTO{ExpiresAt: &expiresAt, Password: &password, AllowedCIDRs: &cidrs, RateLimit: &rateLimit}
+
+		err := applyAccessRestrictions(dto, pubdash)
+		require.NoError(t, err)
+		assert.Equal(t, expiresAt, *pubdash.ExpiresAt)
+		assert.NotEmpty(t, pubdash.PasswordSalt)
+		assert.NotEqual(t, password, pubdash.PasswordHash)
+		assert.Equal(t, cidrs, pubdash.AllowedCIDRs)
+		assert.Equal(t, rateLimit, pubdash.RateLimit)
+	})
+
+	t.Run("keeps the restrictions that are not in the dto", func(t *testing.T) {
+		pubdash := &PublicDashboard{Uid: "pubdash", ExpiresAt: &expiresAt, PasswordHash: "hash", PasswordSalt: "salt", AllowedCIDRs: cidrs, RateLimit: rateLimit}
+
+		err := applyAccessRestrictions(&PublicDashboardDTO{}, pubdash)
+		require.NoError(t, err)
+		assert.Equal(t, expiresAt, *pubdash.ExpiresAt)
+		assert.Equal(t, "hash", pubdash.PasswordHash)
+		assert.Equal(t, cidrs, pubdash.AllowedCIDRs)
+		assert.Equal(t, rateLimit, pubdash.RateLimit)
+	})
+
+	t.Run("removes the restrictions with empty values", func(t *testing.T) {
+		pubdash := &PublicDashboard{Uid: "pubdash", ExpiresAt: &expiresAt, PasswordHash: "hash", PasswordSalt: "salt", AllowedCIDRs: cidrs, RateLimit: rateLimit}
+		noPassword := ""
+		noCIDRs := []string{}
+		noRateLimit := int64(0)
+		dto := &PublicDashboardDTO{ExpiresAt: &time.Time{}, Password: &noPassword, AllowedCIDRs: &noCIDRs, RateLimit: &noRateLimit}
+
+		err := applyAccessRestrictions(dto, pubdash)
+		require.NoError(t, err)
+		assert.Nil(t, pubdash.ExpiresAt)
+		assert.Empty(t, pubdash.PasswordHash)
+		assert.Empty(t, pubdash.PasswordSalt)
+		assert.Empty(t, pubdash.AllowedCIDRs)
+		assert.Zero(t, pubdash.RateLimit)
+	})
+}
+
+func TestVerifyPassword(t *testing.T) {
+	pd := &PublicDashboardServiceImpl{}
+	password := "secret"
+	pubdash := &PublicDashboard{Uid: "pubdash"}
+	require.NoError(t, applyAccessRestrictions(&PublicDashboardDTO{Password: &password}, pubdash))
+
+	t.Run("succeeds with the password", func(t *testing.T) {
+		require.NoError(t, pd.VerifyPassword(context.Background(), pubdash, password))
+	})
+
+	t.Run("fails with another password", func(t *testing.T) {
+		err := pd.VerifyPassword(context.Background(), pubdash, "wrong")
+		require.ErrorIs(t, err, ErrPublicDashboardInvalidPassword)
+	})
+
+	t.Run("succeeds if the public dashboard has no password", func(t *testing.T) {
+		require.NoError(t, pd.VerifyPassword(context.Background(), &PublicDashboard{Uid: "pubdash"}, ""))
+	})
+}
+
+func TestDisableExpired(t *testing.T) {
+	store := NewFakePublicDashboardStore(t)
+	pd := &PublicDashboardServiceImpl{store: store, log: log.New("test.logger")}
+	store.On("DisableExpired", mock.Anything, mock.Anything).Return(int64(2), nil)
+
+	affectedRows, err := pd.DisableExpired(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, affectedRows)
+}
+
 func TestGenerateAccessToken(t *testing.T) {
 	accessToken, err := GenerateAccessToken()
 
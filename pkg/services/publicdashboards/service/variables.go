@@ -0,0 +1,694 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Public dashboards support a safe subset of template variables. The options of these variables are resolved on the
+// server and are the allow-list of values that viewers can choose from. The values of a query are validated against
+// this list before the variables are interpolated into the queries of the dashboard. The options of query variables
+// are queried with the data source permissions of the owner of the public dashboard.
+var supportedVariableTypes = map[string]bool{
+	"custom":   true,
+	"constant": true,
+	"interval": true,
+	"query":    true,
+}
+
+const (
+	variableAllValue          = "$__all"
+	variableAutoIntervalValue = "$__auto_interval_"
+	variableQueryRefID        = "variable"
+)
+
+const (
+	// variableCacheTTL is how long resolved variables are cached. The panels of a public dashboard are queried with
+	// one request each, so the requests of the other panels reuse the variables that are resolved for the first one.
+	variableCacheTTL = 10 * time.Second
+	// variableCacheMaxEntries is the maximum number of cached variables. The time range and the values of requests
+	// are chosen by the viewers, so the number of keys is bounded to bound the memory used by the cache.
+	variableCacheMaxEntries = 1000
+)
+
+// variableRegex matches $var, [[var]], [[var:format]], ${var}, ${var.fieldPath} and ${var:format}, like the
+// interpolation of template variables in the frontend
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?\}`)
+
+// customVariableOptionRegex splits the query of custom variables by commas that are not escaped
+var customVariableOptionRegex = regexp.MustCompile(`(?:\\,|[^,])+`)
+
+var numericSortRegex = regexp.MustCompile(`.*?(\d+).*`)
+
+type templateVariable struct {
+	Name       string
+	Type       string
+	Datasource *simplejson.Json
+	Query      any
+	Regex      string
+	Sort       int
+	Multi      bool
+	IncludeAll bool
+	AllValue   string
+	Auto       bool
+	AutoCount  int
+	AutoMin    string
+	Options    []variableOption
+	// Current are the values of the variable that are saved in the dashboard
+	Current []string
+
+	json *simplejson.Json
+}
+
+type variableOption struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// variableValue is the value of a variable that is interpolated into queries
+type variableValue struct {
+	Values []string
+	// Raw is set if the variable is the custom all value, which is interpolated without formatting
+	Raw bool
+}
+
+// templateVariables are the template variables of a dashboard
+type templateVariables struct {
+	supported   []*templateVariable
+	unsupported map[string]string
+}
+
+// resolvedVariables are what is needed to interpolate the variables of a dashboard into queries
+type resolvedVariables struct {
+	unsupported map[string]string
+	values      map[string]variableValue
+}
+
+func getTemplateVariables(dashboard *simplejson.Json) templateVariables {
+	vars := templateVariables{unsupported: make(map[string]string)}
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		name := v.Get("name").MustString()
+		varType := v.Get("type").MustString()
+		if !supportedVariableTypes[varType] {
+			vars.unsupported[name] = varType
+			continue
+		}
+
+		variable := &templateVariable{
+			Name:       name,
+			Type:       varType,
+			Datasource: v.Get("datasource"),
+			Query:      v.Get("query").Interface(),
+			Regex:      v.Get("regex").MustString(),
+			Sort:       v.Get("sort").MustInt(),
+			Multi:      v.Get("multi").MustBool(),
+			IncludeAll: v.Get("includeAll").MustBool(),
+			AllValue:   v.Get("allValue").MustString(),
+			Auto:       v.Get("auto").MustBool(),
+			AutoCount:  v.Get("auto_count").MustInt(30),
+			AutoMin:    v.Get("auto_min").MustString("10s"),
+			Current:    stringOrStrings(v.GetPath("current", "value").Interface()),
+			json:       v,
+		}
+		for _, optObj := range v.Get("options").MustArray() {
+			opt := simplejson.NewFromAny(optObj)
+			variable.Options = append(variable.Options, variableOption{
+				Text:  fmt.Sprint(opt.Get("text").Interface()),
+				Value: fmt.Sprint(opt.Get("value").Interface()),
+			})
+		}
+		vars.supported = append(vars.supported, variable)
+	}
+	return vars
+}
+
+// resolveVariables resolves the options of the template variables of the dashboard in order and selects the requested
+// values, or the values that are saved in the dashboard if no values are requested. The options of a query variable
+// can depend on the values of the variables before it.
+func (pd *PublicDashboardServiceImpl) resolveVariables(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, ts models.TimeSettings, requested map[string][]string) (templateVariables, map[string]variableValue, error) {
+	vars := getTemplateVariables(dashboard.Data)
+	values := make(map[string]variableValue, len(vars.supported))
+	var queryUser *user.SignedInUser
+	for _, v := range vars.supported {
+		if v.Type == "query" {
+			if queryUser == nil {
+				var err error
+				if queryUser, err = pd.variableQueryUser(ctx, publicDashboard, dashboard.OrgID); err != nil {
+					return templateVariables{}, nil, err
+				}
+			}
+			options, err := pd.queryVariableOptions(ctx, queryUser, ts, v, vars.unsupported, values)
+			if err != nil {
+				return templateVariables{}, nil, err
+			}
+			v.Options = options
+		} else if len(v.Options) == 0 {
+			v.Options = parseVariableOptions(v)
+		}
+		if v.Type == "interval" && v.Auto && (len(v.Options) == 0 || v.Options[0].Value != variableAutoIntervalValue+v.Name) {
+			v.Options = append([]variableOption{{Text: "auto", Value: variableAutoIntervalValue + v.Name}}, v.Options...)
+		}
+
+		value, err := selectVariableValue(v, requested[v.Name])
+		if err != nil {
+			return templateVariables{}, nil, err
+		}
+		if v.Type == "interval" {
+			for i := range value.Values {
+				value.Values[i] = resolveAutoInterval(v, value.Values[i], ts)
+			}
+		}
+		values[v.Name] = value
+	}
+	return vars, values, nil
+}
+
+// resolveVariablesCached resolves the variables of a query request, or returns the variables that were resolved for
+// a request with the same time range and values within the cache TTL
+func (pd *PublicDashboardServiceImpl) resolveVariablesCached(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, queryDto models.PublicDashboardQueryDTO) (resolvedVariables, error) {
+	from, to, timezone := getTimeRangeValuesOrDefault(queryDto, dashboard, publicDashboard.TimeSelectionEnabled)
+	// json sorts the keys of maps, so that the same values result in the same key
+	requested, err := json.Marshal(queryDto.Variables)
+	if err != nil {
+		return resolvedVariables{}, models.ErrInternalServerError.Errorf("resolveVariablesCached: failed to marshal variables: %w", err)
+	}
+	// the key is hashed so that its size does not depend on the request
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d/%s/%s/%s/%s", dashboard.Version, from, to, timezone, requested)))
+	key := publicDashboard.Uid + "/" + hex.EncodeToString(hash[:])
+
+	if cached, ok := pd.variableCache.Get(key); ok {
+		return cached.(resolvedVariables), nil
+	}
+
+	ts := buildTimeSettings(dashboard, queryDto, publicDashboard)
+	vars, values, err := pd.resolveVariables(ctx, dashboard, publicDashboard, ts, queryDto.Variables)
+	if err != nil {
+		return resolvedVariables{}, err
+	}
+
+	resolved := resolvedVariables{unsupported: vars.unsupported, values: values}
+	// when the cache is full, the variables are resolved for every request until the expired entries are removed
+	if pd.variableCache.ItemCount() < variableCacheMaxEntries {
+		pd.variableCache.SetDefault(key, resolved)
+	}
+	return resolved, nil
+}
+
+// variableQueryUser returns the user that queries the options of query variables. It has the data source permissions
+// of the owner of the public dashboard, so that viewers only see options from data sources that the owner can query.
+func (pd *PublicDashboardServiceImpl) variableQueryUser(ctx context.Context, publicDashboard *models.PublicDashboard, orgID int64) (*user.SignedInUser, error) {
+	queryUser := &user.SignedInUser{OrgID: orgID, Permissions: map[int64]map[string][]string{orgID: {}}}
+
+	owner, err := pd.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: publicDashboard.CreatedBy, OrgID: orgID})
+	if errors.Is(err, user.ErrUserNotFound) {
+		// a deleted owner can't query any data source
+		return queryUser, nil
+	}
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("variableQueryUser: failed to get owner of public dashboard: %w", err)
+	}
+
+	permissions, err := pd.acService.GetUserPermissions(ctx, owner, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("variableQueryUser: failed to get permissions of owner of public dashboard: %w", err)
+	}
+	grouped := accesscontrol.GroupScopesByActionContext(ctx, permissions)
+	queryUser.Permissions[orgID][datasources.ActionQuery] = grouped[datasources.ActionQuery]
+	queryUser.Permissions[orgID][datasources.ActionRead] = grouped[datasources.ActionRead]
+	return queryUser, nil
+}
+
+// setVariableOptions replaces the options of the template variables of the dashboard with the options that viewers
+// can choose from, and removes the queries of query variables
+func (pd *PublicDashboardServiceImpl) setVariableOptions(ctx context.Context, publicDashboard *models.PublicDashboard, dashboard *dashboards.Dashboard) {
+	list := dashboard.Data.GetPath("templating", "list").MustArray()
+	if len(list) == 0 {
+		return
+	}
+
+	ts := buildTimeSettings(dashboard, models.PublicDashboardQueryDTO{}, publicDashboard)
+	vars, _, err := pd.resolveVariables(ctx, dashboard, publicDashboard, ts, nil)
+	if err != nil {
+		pd.log.Warn("Failed to resolve template variables of public dashboard", "publicDashboardUid", publicDashboard.Uid, "dashboardUid", dashboard.UID, "error", err)
+	}
+
+	for _, v := range vars.supported {
+		options := v.Options
+		if v.IncludeAll {
+			options = append([]variableOption{{Text: "All", Value: variableAllValue}}, options...)
+		}
+		// the saved values are selected if they are options, or the first option otherwise
+		current := v.Current
+		if _, err := selectVariableValue(v, current); err != nil || len(current) == 0 {
+			defaultValue, _ := selectVariableValue(v, nil)
+			current = defaultValue.Values
+		}
+		selected := make(map[string]bool, len(current))
+		for _, value := range current {
+			selected[value] = true
+		}
+
+		optionsJson := make([]any, 0, len(options))
+		var currentText, currentValue []string
+		for _, opt := range options {
+			optionsJson = append(optionsJson, map[string]any{"text": opt.Text, "value": opt.Value, "selected": selected[opt.Value]})
+			if selected[opt.Value] {
+				currentText = append(currentText, opt.Text)
+				currentValue = append(currentValue, opt.Value)
+			}
+		}
+		v.json.Set("options", optionsJson)
+		if v.Multi {
+			v.json.Set("current", map[string]any{"text": currentText, "value": currentValue})
+		} else if len(currentValue) > 0 {
+			v.json.Set("current", map[string]any{"text": currentText[0], "value": currentValue[0]})
+		}
+	}
+
+	for _, obj := range list {
+		v := simplejson.NewFromAny(obj)
+		if v.Get("type").MustString() == "query" {
+			v.Del("query")
+			v.Del("definition")
+			// options are resolved on every request to the public dashboard
+			v.Set("refresh", 0)
+		}
+	}
+}
+
+// parseVariableOptions returns the options of custom, constant and interval variables without saved options
+func parseVariableOptions(v *templateVariable) []variableOption {
+	query, _ := v.Query.(string)
+	if v.Type == "constant" {
+		return []variableOption{{Text: query, Value: query}}
+	}
+
+	var options []variableOption
+	// custom variables use "text : value" to set the text of an option
+	for _, part := range customVariableOptionRegex.FindAllString(query, -1) {
+		part = strings.TrimSpace(strings.ReplaceAll(part, `\,`, ","))
+		text, value, found := strings.Cut(part, " : ")
+		if !found {
+			value = text
+		}
+		options = append(options, variableOption{Text: strings.TrimSpace(text), Value: strings.TrimSpace(value)})
+	}
+	return options
+}
+
+// selectVariableValue validates the requested values against the options of the variable
+func selectVariableValue(v *templateVariable, requested []string) (variableValue, error) {
+	defaultValue := false
+	if len(requested) == 0 {
+		requested, defaultValue = v.Current, true
+	}
+	if len(requested) > 1 && !v.Multi {
+		return variableValue{}, models.ErrInvalidTemplateVariableValue.Errorf("selectVariableValue: variable %s does not allow multiple values", v.Name)
+	}
+
+	if len(requested) == 1 && requested[0] == variableAllValue && v.IncludeAll {
+		if v.AllValue != "" {
+			return variableValue{Values: []string{v.AllValue}, Raw: true}, nil
+		}
+		values := make([]string, 0, len(v.Options))
+		for _, opt := range v.Options {
+			values = append(values, opt.Value)
+		}
+		return variableValue{Values: values}, nil
+	}
+
+	allowed := make(map[string]bool, len(v.Options))
+	for _, opt := range v.Options {
+		allowed[opt.Value] = true
+	}
+	for _, value := range requested {
+		if allowed[value] {
+			continue
+		}
+		// the saved value can be an option that no longer exists, in which case the first option is selected
+		if defaultValue {
+			if len(v.Options) == 0 {
+				return variableValue{Values: []string{}}, nil
+			}
+			return variableValue{Values: []string{v.Options[0].Value}}, nil
+		}
+		return variableValue{}, models.ErrInvalidTemplateVariableValue.Errorf("selectVariableValue: value %q is not an option of variable %s", value, v.Name)
+	}
+	if len(requested) == 0 && len(v.Options) > 0 {
+		requested = []string{v.Options[0].Value}
+	}
+	return variableValue{Values: requested}, nil
+}
+
+// queryVariableOptions runs the query of a query variable against its data source and returns the values of the result
+// as options
+func (pd *PublicDashboardServiceImpl) queryVariableOptions(ctx context.Context, queryUser *user.SignedInUser, ts models.TimeSettings, v *templateVariable, unsupported map[string]string, values map[string]variableValue) ([]variableOption, error) {
+	dsUID := getDataSourceUidFromJson(v.json)
+	if dsUID == "" {
+		return nil, models.ErrPublicDashboardHasTemplateVariables.Errorf("queryVariableOptions: query variable %s has no data source", v.Name)
+	}
+	evaluator := accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(dsUID))
+	if !evaluator.Evaluate(queryUser.Permissions[queryUser.OrgID]) {
+		return nil, models.ErrTemplateVariableDatasourceForbidden.Errorf("queryVariableOptions: owner of public dashboard cannot query data source %s of variable %s", dsUID, v.Name)
+	}
+
+	var target *simplejson.Json
+	switch q := v.Query.(type) {
+	case map[string]any:
+		b, err := json.Marshal(q)
+		if err != nil {
+			return nil, models.ErrInternalServerError.Errorf("queryVariableOptions: failed to copy query of variable %s: %w", v.Name, err)
+		}
+		if target, err = simplejson.NewJson(b); err != nil {
+			return nil, models.ErrInternalServerError.Errorf("queryVariableOptions: failed to copy query of variable %s: %w", v.Name, err)
+		}
+	case string:
+		// older data sources save the query of variables as a string
+		target = simplejson.NewFromAny(map[string]any{"query": q, "rawSql": q, "format": "table"})
+	default:
+		return nil, models.ErrPublicDashboardHasTemplateVariables.Errorf("queryVariableOptions: query variable %s has no query", v.Name)
+	}
+	target.Set("refId", variableQueryRefID)
+	target.Set("datasource", map[string]any{"uid": dsUID, "type": v.Datasource.Get("type").MustString()})
+
+	if err := interpolateVariables(target, v.Datasource.Get("type").MustString(), unsupported, values); err != nil {
+		return nil, err
+	}
+
+	resp, err := pd.QueryDataService.QueryData(ctx, queryUser, false, dtos.MetricRequest{
+		From:    ts.From,
+		To:      ts.To,
+		Queries: []*simplejson.Json{target},
+	})
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("queryVariableOptions: failed to query options of variable %s: %w", v.Name, err)
+	}
+	res, ok := resp.Responses[variableQueryRefID]
+	if !ok {
+		return []variableOption{}, nil
+	}
+	if res.Error != nil {
+		return nil, models.ErrInternalServerError.Errorf("queryVariableOptions: failed to query options of variable %s: %w", v.Name, res.Error)
+	}
+
+	options, err := filterVariableOptions(optionsFromFrames(res.Frames), v.Regex)
+	if err != nil {
+		return nil, models.ErrInternalServerError.Errorf("queryVariableOptions: invalid regex of variable %s: %w", v.Name, err)
+	}
+	sortVariableOptions(options, v.Sort)
+	return options, nil
+}
+
+// optionsFromFrames returns the values of the __text and __value fields of the frames, or the values of their first
+// field
+func optionsFromFrames(frames data.Frames) []variableOption {
+	var options []variableOption
+	seen := make(map[string]bool)
+	for _, frame := range frames {
+		if len(frame.Fields) == 0 {
+			continue
+		}
+		textField, valueField := frame.Fields[0], frame.Fields[0]
+		if f, idx := frame.FieldByName("__text"); idx >= 0 {
+			textField, valueField = f, f
+		}
+		if f, idx := frame.FieldByName("__value"); idx >= 0 {
+			valueField = f
+		}
+		for i := 0; i < valueField.Len(); i++ {
+			value, ok := fieldValueString(valueField, i)
+			if !ok || seen[value] {
+				continue
+			}
+			text, ok := fieldValueString(textField, i)
+			if !ok {
+				text = value
+			}
+			seen[value] = true
+			options = append(options, variableOption{Text: text, Value: value})
+		}
+	}
+	return options
+}
+
+func fieldValueString(field *data.Field, idx int) (string, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return "", false
+	}
+	if t, isTime := v.(time.Time); isTime {
+		return strconv.FormatInt(t.UnixMilli(), 10), true
+	}
+	return fmt.Sprint(v), true
+}
+
+// filterVariableOptions applies the regex of a query variable to the text of its options. Options that don't match
+// are removed. The named groups text and value, or the first group, select the text and value of an option.
+func filterVariableOptions(options []variableOption, pattern string) ([]variableOption, error) {
+	if pattern == "" {
+		return options, nil
+	}
+	re, err := parseVariableRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]variableOption, 0, len(options))
+	for _, opt := range options {
+		match := re.FindStringSubmatch(opt.Text)
+		if match == nil {
+			continue
+		}
+		textIdx, valueIdx := re.SubexpIndex("text"), re.SubexpIndex("value")
+		switch {
+		case textIdx > 0 || valueIdx > 0:
+			if textIdx > 0 && match[textIdx] != "" {
+				opt.Text = match[textIdx]
+			}
+			if valueIdx > 0 && match[valueIdx] != "" {
+				opt.Value = match[valueIdx]
+			}
+			if textIdx <= 0 || match[textIdx] == "" {
+				opt.Text = opt.Value
+			}
+		case len(match) > 1 && match[1] != "":
+			opt.Text, opt.Value = match[1], match[1]
+		}
+		filtered = append(filtered, opt)
+	}
+	return filtered, nil
+}
+
+// parseVariableRegex parses a regex in the format of the frontend, either a plain pattern or /pattern/flags
+func parseVariableRegex(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "/") {
+		if end := strings.LastIndex(pattern, "/"); end > 0 {
+			flags := pattern[end+1:]
+			pattern = pattern[1:end]
+			if strings.Contains(flags, "i") {
+				pattern = "(?i)" + pattern
+			}
+		}
+	}
+	return regexp.Compile(pattern)
+}
+
+// sortVariableOptions sorts the options by text like the sort setting of query variables
+func sortVariableOptions(options []variableOption, sortOrder int) {
+	numeric := func(s string) float64 {
+		match := numericSortRegex.FindStringSubmatch(s)
+		if match == nil {
+			return -1
+		}
+		n, _ := strconv.ParseFloat(match[1], 64)
+		return n
+	}
+	less := map[int]func(a, b variableOption) bool{
+		1: func(a, b variableOption) bool { return a.Text < b.Text },
+		2: func(a, b variableOption) bool { return a.Text > b.Text },
+		3: func(a, b variableOption) bool { return numeric(a.Text) < numeric(b.Text) },
+		4: func(a, b variableOption) bool { return numeric(a.Text) > numeric(b.Text) },
+		5: func(a, b variableOption) bool { return strings.ToLower(a.Text) < strings.ToLower(b.Text) },
+		6: func(a, b variableOption) bool { return strings.ToLower(a.Text) > strings.ToLower(b.Text) },
+	}[sortOrder]
+	if less != nil {
+		sort.SliceStable(options, func(i, j int) bool { return less(options[i], options[j]) })
+	}
+}
+
+// interpolateVariables replaces the references to template variables in all strings of the query with their values.
+// References to unsupported variables are rejected, references to unknown variables such as global variables are left
+// for the data source.
+func interpolateVariables(query *simplejson.Json, dsType string, unsupported map[string]string, values map[string]variableValue) error {
+	var err error
+	var interpolate func(v any) any
+	interpolate = func(v any) any {
+		switch val := v.(type) {
+		case string:
+			return variableRegex.ReplaceAllStringFunc(val, func(match string) string {
+				groups := variableRegex.FindStringSubmatch(match)
+				name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[6]
+				if varType, ok := unsupported[name]; ok {
+					err = models.ErrPublicDashboardHasTemplateVariables.Errorf("interpolateVariables: variable %s of type %s is not supported", name, varType)
+					return match
+				}
+				value, ok := values[name]
+				if !ok {
+					return match
+				}
+				return formatVariableValue(value, format, dsType)
+			})
+		case map[string]any:
+			for k, item := range val {
+				val[k] = interpolate(item)
+			}
+		case []any:
+			for i, item := range val {
+				val[i] = interpolate(item)
+			}
+		}
+		return v
+	}
+
+	for key, item := range query.MustMap() {
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+		query.Set(key, interpolate(item))
+	}
+	return err
+}
+
+// formatVariableValue formats the values of a variable with the given format, or with the format that the data source
+// uses for multiple values if no format is given
+func formatVariableValue(value variableValue, format string, dsType string) string {
+	values := value.Values
+	if value.Raw {
+		return strings.Join(values, ",")
+	}
+
+	if format == "" {
+		if len(values) == 1 {
+			return values[0]
+		}
+		switch dsType {
+		case datasources.DS_PROMETHEUS, datasources.DS_LOKI:
+			format = "regex"
+		case datasources.DS_POSTGRES, "postgres", datasources.DS_MYSQL, datasources.DS_MSSQL:
+			format = "sqlstring"
+		default:
+			format = "glob"
+		}
+	}
+
+	switch format {
+	case "raw", "text":
+		return strings.Join(values, ",")
+	case "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = regexp.QuoteMeta(v)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "json":
+		b, _ := json.Marshal(values)
+		return string(b)
+	case "singlequote":
+		return quoteEach(values, "'", `\'`)
+	case "doublequote":
+		return quoteEach(values, `"`, `\"`)
+	case "sqlstring":
+		return quoteEach(values, "'", "''")
+	case "percentencode":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = url.QueryEscape(v)
+		}
+		return strings.Join(escaped, ",")
+	default:
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+func quoteEach(values []string, quote string, escapedQuote string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote + strings.ReplaceAll(v, quote, escapedQuote) + quote
+	}
+	return strings.Join(quoted, ",")
+}
+
+// resolveAutoInterval returns the interval of the auto option of an interval variable, which divides the time range
+// into auto_count intervals of at least auto_min. Other values are returned as is.
+func resolveAutoInterval(v *templateVariable, value string, ts models.TimeSettings) string {
+	if value != variableAutoIntervalValue+v.Name {
+		return value
+	}
+	from, errFrom := strconv.ParseInt(ts.From, 10, 64)
+	to, errTo := strconv.ParseInt(ts.To, 10, 64)
+	if errFrom != nil || errTo != nil {
+		return value
+	}
+	count := v.AutoCount
+	if count <= 0 {
+		count = 30
+	}
+	minInterval, err := gtime.ParseDuration(v.AutoMin)
+	if err != nil {
+		minInterval = 10 * time.Second
+	}
+
+	interval := gtime.RoundInterval(time.Duration(to-from) * time.Millisecond / time.Duration(count))
+	if interval < minInterval {
+		interval = minInterval
+	}
+	return gtime.FormatInterval(interval)
+}
+
+// stringOrStrings returns the value of a variable that is saved as a string or as a list of strings
+func stringOrStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		values := make([]string, 0, len(val))
+		for _, item := range val {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/service/intervalv2"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+const dashboardWithTemplateVariables = `{
+  "time": {"from": "now-6h", "to": "now"},
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "Production : prod,Staging : staging",
+        "current": {"text": "Production", "value": "prod"}
+      },
+      {
+        "name": "pod",
+        "type": "query",
+        "datasource": {"type": "mysql", "uid": "mysql-ds"},
+        "query": "SELECT pod FROM pods WHERE env = '$env'",
+        "regex": "/pod-(.*)/",
+        "sort": 1,
+        "multi": true,
+        "includeAll": true,
+        "current": {"text": "All", "value": "$__all"}
+      },
+      {
+        "name": "interval",
+        "type": "interval",
+        "query": "1m,10m",
+        "auto": true,
+        "auto_count": 6,
+        "auto_min": "1m",
+        "current": {"text": "1m", "value": "1m"}
+      },
+      {
+        "name": "search",
+        "type": "textbox",
+        "query": ""
+      }
+    ]
+  },
+  "panels": [
+    {
+      "id": 1,
+      "datasource": {"type": "prometheus", "uid": "prom-ds"},
+      "targets": [
+        {
+          "datasource": {"type": "prometheus", "uid": "prom-ds"},
+          "expr": "rate(requests{env=\"$env\", pod=~\"$pod\"}[$interval])",
+          "refId": "A"
+        }
+      ]
+    },
+    {
+      "id": 2,
+      "datasource": {"type": "prometheus", "uid": "prom-ds"},
+      "targets": [
+        {
+          "datasource": {"type": "prometheus", "uid": "prom-ds"},
+          "expr": "requests{path=\"${search}\"}",
+          "refId": "A"
+        }
+      ]
+    }
+  ]
+}`
+
+func newVariablesTestService(t *testing.T, ownerPermissions ...accesscontrol.Permission) (*PublicDashboardServiceImpl, *query.FakeQueryService) {
+	t.Helper()
+
+	if ownerPermissions == nil {
+		ownerPermissions = []accesscontrol.Permission{{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("mysql-ds")}}
+	}
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedSignedInUser = &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	fakeQueryService := &query.FakeQueryService{}
+	fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+		Responses: backend.Responses{
+			variableQueryRefID: {Frames: data.Frames{data.NewFrame("", data.NewField("pod", nil, []string{"pod-b", "pod-a", "other", "pod-a"}))}},
+		},
+	}, nil)
+
+	return &PublicDashboardServiceImpl{
+		log:                log.New("test.logger"),
+		features:           featuremgmt.WithFeatures(),
+		intervalCalculator: intervalv2.NewCalculator(),
+		QueryDataService:   fakeQueryService,
+		userService:        userService,
+		acService:          actest.FakeService{ExpectedPermissions: ownerPermissions},
+		variableCache:      localcache.New(variableCacheTTL, 2*variableCacheTTL),
+	}, fakeQueryService
+}
+
+func newVariablesTestDashboard(t *testing.T) *dashboards.Dashboard {
+	t.Helper()
+
+	dashboardData, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	return &dashboards.Dashboard{UID: "dash", OrgID: 1, Data: dashboardData}
+}
+
+func TestGetMetricRequestWithTemplateVariables(t *testing.T) {
+	publicDashboard := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", IsEnabled: true, CreatedBy: 1}
+	queryDto := PublicDashboardQueryDTO{IntervalMs: 1, MaxDataPoints: 1}
+
+	t.Run("interpolates the saved values", func(t *testing.T) {
+		service, fakeQueryService := newVariablesTestService(t)
+
+		metricReq, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		require.Len(t, metricReq.Queries, 1)
+		assert.Equal(t, `rate(requests{env="prod", pod=~"(a|b)"}[1m])`, metricReq.Queries[0].Get("expr").MustString())
+
+		// the query of the variable is interpolated with the values of the variables before it
+		variableReq := fakeQueryService.Calls[0].Arguments.Get(3).(dtos.MetricRequest)
+		assert.Equal(t, "SELECT pod FROM pods WHERE env = 'prod'", variableReq.Queries[0].Get("rawSql").MustString())
+		assert.Equal(t, "mysql-ds", variableReq.Queries[0].Get("datasource").Get("uid").MustString())
+
+		// the query of the variable is run with the data source permissions of the owner
+		queryUser := fakeQueryService.Calls[0].Arguments.Get(1).(*user.SignedInUser)
+		assert.Equal(t, []string{datasources.ScopeProvider.GetResourceScopeUID("mysql-ds")}, queryUser.Permissions[1][datasources.ActionQuery])
+	})
+
+	t.Run("caches the resolved variables of a request", func(t *testing.T) {
+		service, fakeQueryService := newVariablesTestService(t)
+
+		_, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		_, err = service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		require.Len(t, fakeQueryService.Calls, 1)
+
+		// other values are resolved again
+		queryDto := queryDto
+		queryDto.Variables = map[string][]string{"env": {"staging"}}
+		_, err = service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		require.Len(t, fakeQueryService.Calls, 2)
+	})
+
+	t.Run("bounds the number of cached variables", func(t *testing.T) {
+		service, fakeQueryService := newVariablesTestService(t)
+		publicDashboard := *publicDashboard
+		publicDashboard.TimeSelectionEnabled = true
+
+		for i := 0; i < variableCacheMaxEntries+10; i++ {
+			queryDto := queryDto
+			queryDto.TimeRange = TimeRangeDTO{From: strconv.Itoa(1700000000000 + i), To: "now"}
+			_, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), &publicDashboard, 1, queryDto)
+			require.NoError(t, err)
+		}
+		require.Len(t, fakeQueryService.Calls, variableCacheMaxEntries+10)
+		require.Equal(t, variableCacheMaxEntries, service.variableCache.ItemCount())
+	})
+
+	t.Run("returns an error when the owner cannot query the data source of a variable", func(t *testing.T) {
+		service, fakeQueryService := newVariablesTestService(t, accesscontrol.Permission{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("prom-ds")})
+
+		_, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.ErrorIs(t, err, ErrTemplateVariableDatasourceForbidden)
+		require.Empty(t, fakeQueryService.Calls)
+	})
+
+	t.Run("interpolates the requested values", func(t *testing.T) {
+		service, _ := newVariablesTestService(t)
+		queryDto := queryDto
+		queryDto.Variables = map[string][]string{"env": {"staging"}, "pod": {"b"}, "interval": {"10m"}}
+
+		metricReq, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		assert.Equal(t, `rate(requests{env="staging", pod=~"b"}[10m])`, metricReq.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("interpolates the auto interval", func(t *testing.T) {
+		service, _ := newVariablesTestService(t)
+		queryDto := queryDto
+		queryDto.Variables = map[string][]string{"interval": {"$__auto_interval_interval"}}
+
+		metricReq, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+		require.NoError(t, err)
+		assert.Equal(t, `rate(requests{env="prod", pod=~"(a|b)"}[1h])`, metricReq.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("returns an error when a value is not an option", func(t *testing.T) {
+		service, _ := newVariablesTestService(t)
+		for _, variables := range []map[string][]string{
+			{"env": {`prod"} or vector(1) or {x="`}},
+			{"pod": {"other"}},
+			{"env": {"prod", "staging"}},
+			{"env": {"$__all"}},
+		} {
+			queryDto := queryDto
+			queryDto.Variables = variables
+
+			_, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 1, queryDto)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariableValue)
+		}
+	})
+
+	t.Run("returns an error when a query uses an unsupported variable", func(t *testing.T) {
+		service, _ := newVariablesTestService(t)
+
+		_, err := service.GetMetricRequest(context.Background(), newVariablesTestDashboard(t), publicDashboard, 2, queryDto)
+		require.ErrorIs(t, err, ErrPublicDashboardHasTemplateVariables)
+	})
+}
+
+func TestSetVariableOptions(t *testing.T) {
+	service, _ := newVariablesTestService(t)
+	dashboard := newVariablesTestDashboard(t)
+
+	service.setVariableOptions(context.Background(), &PublicDashboard{Uid: "pubdash", CreatedBy: 1}, dashboard)
+
+	list := dashboard.Data.GetPath("templating", "list")
+	pod := list.GetIndex(1)
+	assert.Equal(t, []any{
+		map[string]any{"text": "All", "value": "$__all", "selected": true},
+		map[string]any{"text": "a", "value": "a", "selected": false},
+		map[string]any{"text": "b", "value": "b", "selected": false},
+	}, pod.Get("options").MustArray())
+	assert.Equal(t, []string{"$__all"}, pod.GetPath("current", "value").Interface())
+	_, hasQuery := pod.CheckGet("query")
+	assert.False(t, hasQuery)
+
+	env := list.GetIndex(0)
+	assert.Len(t, env.Get("options").MustArray(), 2)
+	assert.Equal(t, "prod", env.GetPath("current", "value").MustString())
+
+	interval := list.GetIndex(2)
+	assert.Equal(t, "$__auto_interval_interval", interval.Get("options").GetIndex(0).Get("value").MustString())
+}
+
+func TestParseVariableOptions(t *testing.T) {
+	options := parseVariableOptions(&templateVariable{Type: "custom", Query: `a, Text : b,c\,d`})
+	assert.Equal(t, []variableOption{{Text: "a", Value: "a"}, {Text: "Text", Value: "b"}, {Text: "c,d", Value: "c,d"}}, options)
+
+	options = parseVariableOptions(&templateVariable{Type: "constant", Query: "a,b"})
+	assert.Equal(t, []variableOption{{Text: "a,b", Value: "a,b"}}, options)
+}
+
+func TestFilterVariableOptions(t *testing.T) {
+	options := []variableOption{{Text: "host-a.prod", Value: "host-a.prod"}, {Text: "HOST-b.dev", Value: "HOST-b.dev"}}
+
+	filtered, err := filterVariableOptions(options, "/host-(.*)\\./i")
+	require.NoError(t, err)
+	assert.Equal(t, []variableOption{{Text: "a", Value: "a"}, {Text: "b", Value: "b"}}, filtered)
+
+	filtered, err = filterVariableOptions(options, `/(?P<text>host-\w)\.(?P<value>\w+)/`)
+	require.NoError(t, err)
+	assert.Equal(t, []variableOption{{Text: "host-a", Value: "prod"}}, filtered)
+
+	_, err = filterVariableOptions(options, "/(/")
+	require.Error(t, err)
+}
+
+func TestFormatVariableValue(t *testing.T) {
+	multi := variableValue{Values: []string{"a.b", "it's"}}
+	for _, tc := range []struct {
+		format   string
+		dsType   string
+		expected string
+	}{
+		{format: "", dsType: "prometheus", expected: `(a\.b|it's)`},
+		{format: "", dsType: "mysql", expected: `'a.b','it''s'`},
+		{format: "", dsType: "testdata", expected: `{a.b,it's}`},
+		{format: "csv", expected: `a.b,it's`},
+		{format: "pipe", expected: `a.b|it's`},
+		{format: "json", expected: `["a.b","it's"]`},
+		{format: "singlequote", expected: `'a.b','it\'s'`},
+		{format: "doublequote", expected: `"a.b","it's"`},
+		{format: "percentencode", expected: `a.b,it%27s`},
+	} {
+		t.Run(tc.format+tc.dsType, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatVariableValue(multi, tc.format, tc.dsType))
+		})
+	}
+
+	assert.Equal(t, "a.b", formatVariableValue(variableValue{Values: []string{"a.b"}}, "", "prometheus"))
+	assert.Equal(t, ".*", formatVariableValue(variableValue{Values: []string{".*"}, Raw: true}, "regex", "prometheus"))
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	values := map[string]variableValue{"env": {Values: []string{"prod"}}}
+	query := simplejson.NewFromAny(map[string]any{
+		"refId": "$env",
+		"expr":  "$env [[env]] ${env} ${env:json} $__interval $other",
+		"nested": map[string]any{
+			"list": []any{"$env", 1},
+		},
+	})
+
+	err := interpolateVariables(query, "", map[string]string{}, values)
+	require.NoError(t, err)
+	assert.Equal(t, `prod prod prod ["prod"] $__interval $other`, query.Get("expr").MustString())
+	assert.Equal(t, []any{"prod", 1}, query.GetPath("nested", "list").MustArray())
+	assert.Equal(t, "$env", query.Get("refId").MustString())
+
+	err = interpolateVariables(query, "", map[string]string{"other": "textbox"}, values)
+	require.ErrorIs(t, err, ErrPublicDashboardHasTemplateVariables)
+}
+
+func TestGetUniqueDashboardDatasourceUidsWithQueryVariables(t *testing.T) {
+	// the data sources of query variables are queried with the permissions of the owner instead
+	uids := getUniqueDashboardDatasourceUids(newVariablesTestDashboard(t).Data)
+	assert.Equal(t, []string{"prom-ds"}, uids)
+}
@@ -1,6 +1,9 @@
 package validation
 
 import (
+	"net"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -13,6 +16,22 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if expiresAt := dto.PublicDashboard.ExpiresAt; expiresAt != nil && !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return ErrInvalidExpiresAt.Errorf("ValidateSavePublicDashboard: expiry time %s is not in the future", expiresAt)
+	}
+
+	if cidrs := dto.PublicDashboard.AllowedCIDRs; cidrs != nil {
+		for _, cidr := range *cidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return ErrInvalidAllowedCIDR.Errorf("ValidateSavePublicDashboard: invalid IP range %q: %w", cidr, err)
+			}
+		}
+	}
+
+	if rateLimit := dto.PublicDashboard.RateLimit; rateLimit != nil && *rateLimit < 0 {
+		return ErrInvalidRateLimit.Errorf("ValidateSavePublicDashboard: rate limit %d is negative", *rateLimit)
+	}
+
 	return nil
 }
 
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +23,37 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when expiry time is in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{ExpiresAt: &expiresAt}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidExpiresAt)
+	})
+
+	t.Run("Returns no error when expiry time is zero", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{ExpiresAt: &time.Time{}}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when an allowed IP range is invalid", func(t *testing.T) {
+		cidrs := []string{"10.0.0.0/8", "10.0.0.1"}
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{AllowedCIDRs: &cidrs}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidAllowedCIDR)
+	})
+
+	t.Run("Returns error when rate limit is negative", func(t *testing.T) {
+		rateLimit := int64(-1)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{RateLimit: &rateLimit}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidRateLimit)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
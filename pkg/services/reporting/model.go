@@ -0,0 +1,157 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// Typed errors
+var (
+	ErrReportNotFound    = errutil.NotFound("reporting.not-found", errutil.WithPublicMessage("Report not found"))
+	ErrReportForbidden   = errutil.Forbidden("reporting.forbidden", errutil.WithPublicMessage("Only the owner of the report or an admin can change it"))
+	ErrInvalidReport     = errutil.ValidationFailed("reporting.invalid", errutil.WithPublicMessage("Invalid report"))
+	ErrDashboardNotFound = errutil.NotFound("reporting.dashboard-not-found", errutil.WithPublicMessage("Dashboard not found"))
+	ErrReportingDisabled = errutil.BadRequest("reporting.disabled", errutil.WithPublicMessage("Reporting is disabled"))
+	ErrSendFailed        = errutil.Internal("reporting.send-failed", errutil.WithPublicMessage("Failed to send report"))
+	ErrInternal          = errutil.Internal("reporting.internal")
+)
+
+const (
+	ActionReportsRead   = "reports:read"
+	ActionReportsCreate = "reports:create"
+	ActionReportsWrite  = "reports:write"
+	ActionReportsDelete = "reports:delete"
+	ActionReportsSend   = "reports:send"
+)
+
+var (
+	ScopeReportsProvider = ac.NewScopeProvider("reports")
+	ScopeReportsAll      = ScopeReportsProvider.GetResourceAllScope()
+)
+
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatPDF Format = "pdf"
+)
+
+type SendState string
+
+const (
+	SendStateSuccess SendState = "success"
+	SendStateFailed  SendState = "failed"
+)
+
+// Report is a dashboard that is rendered and sent by email on a schedule
+type Report struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	UserID       int64  `json:"userId" xorm:"user_id"`
+	Name         string `json:"name"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	// Recipients are the email addresses that the report is sent to
+	Recipients []string `json:"recipients"`
+	ReplyTo    string   `json:"replyTo"`
+	Subject    string   `json:"subject"`
+	Message    string   `json:"message"`
+	// Schedule is a cron expression with five fields, or a descriptor such as @daily
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA name of the time zone of the schedule and of the rendered dashboard
+	Timezone string `json:"timezone"`
+	// TimeFrom and TimeTo override the time range of the dashboard, for example now-7d and now
+	TimeFrom string `json:"timeFrom"`
+	TimeTo   string `json:"timeTo"`
+	// Variables override the values of the template variables of the dashboard
+	Variables  map[string][]string `json:"variables"`
+	Format     Format              `json:"format"`
+	Enabled    bool                `json:"enabled"`
+	NextSendAt *time.Time          `json:"nextSendAt,omitempty" xorm:"next_send_at"`
+	Created    time.Time           `json:"created"`
+	Updated    time.Time           `json:"updated"`
+	// UpdatedBy is the user who created or last changed the report. The dashboard is rendered as this user.
+	UpdatedBy int64 `json:"updatedBy" xorm:"updated_by"`
+}
+
+func (r Report) TableName() string {
+	return "report"
+}
+
+// ReportSend is an entry in the history of sent reports
+type ReportSend struct {
+	ID         int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgID      int64     `json:"-" xorm:"org_id"`
+	ReportID   int64     `json:"-" xorm:"report_id"`
+	SentAt     time.Time `json:"sentAt"`
+	State      SendState `json:"state"`
+	Error      string    `json:"error,omitempty"`
+	Recipients []string  `json:"recipients"`
+	// Scheduled is false if the report was sent on demand
+	Scheduled bool `json:"scheduled"`
+}
+
+func (r ReportSend) TableName() string {
+	return "report_send"
+}
+
+//
+// COMMANDS
+//
+
+// ReportSpec are the settings of a report that can be changed
+type ReportSpec struct {
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Subject      string              `json:"subject"`
+	Message      string              `json:"message"`
+	Schedule     string              `json:"schedule"`
+	Timezone     string              `json:"timezone"`
+	TimeFrom     string              `json:"timeFrom"`
+	TimeTo       string              `json:"timeTo"`
+	Variables    map[string][]string `json:"variables"`
+	Format       Format              `json:"format"`
+	Enabled      bool                `json:"enabled"`
+}
+
+type CreateReportCommand struct {
+	ReportSpec
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	ReportSpec
+	UID    string `json:"-"`
+	OrgID  int64  `json:"-"`
+	UserID int64  `json:"-"`
+}
+
+type DeleteReportCommand struct {
+	UID   string
+	OrgID int64
+}
+
+//
+// QUERIES
+//
+
+type GetReportQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type ListReportsQuery struct {
+	OrgID        int64
+	DashboardUID string
+}
+
+type ListReportSendsQuery struct {
+	OrgID    int64
+	ReportID int64
+	Limit    int
+}
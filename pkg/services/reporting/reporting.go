@@ -0,0 +1,19 @@
+package reporting
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+)
+
+// Service manages reports, which send a rendered dashboard by email on a schedule
+type Service interface {
+	Create(ctx context.Context, user identity.Requester, cmd *CreateReportCommand) (*Report, error)
+	Update(ctx context.Context, user identity.Requester, cmd *UpdateReportCommand) (*Report, error)
+	Delete(ctx context.Context, cmd *DeleteReportCommand) error
+	Get(ctx context.Context, query *GetReportQuery) (*Report, error)
+	List(ctx context.Context, query *ListReportsQuery) ([]*Report, error)
+	ListSends(ctx context.Context, query *ListReportSendsQuery) ([]*ReportSend, error)
+	// Send renders and sends the report now, regardless of its schedule
+	Send(ctx context.Context, query *GetReportQuery) (*ReportSend, error)
+}
@@ -0,0 +1,324 @@
+package reportingimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/reporting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const defaultHistoryLimit = 100
+
+func (s *Service) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := reporting.ScopeReportsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	// users who can create reports can update, send and delete the reports they own, which the handlers check
+	s.routeRegister.Group("/api/reports", func(reports routing.RouteRegister) {
+		reports.Get("/", authorize(ac.EvalPermission(reporting.ActionReportsRead)), routing.Wrap(s.listHandler))
+		reports.Post("/", authorize(ac.EvalPermission(reporting.ActionReportsCreate)), routing.Wrap(s.createHandler))
+		reports.Get("/:uid", authorize(ac.EvalPermission(reporting.ActionReportsRead, uidScope)), routing.Wrap(s.getHandler))
+		reports.Put("/:uid", authorize(ac.EvalAny(
+			ac.EvalPermission(reporting.ActionReportsWrite, uidScope),
+			ac.EvalPermission(reporting.ActionReportsCreate),
+		)), routing.Wrap(s.updateHandler))
+		reports.Delete("/:uid", authorize(ac.EvalAny(
+			ac.EvalPermission(reporting.ActionReportsDelete, uidScope),
+			ac.EvalPermission(reporting.ActionReportsCreate),
+		)), routing.Wrap(s.deleteHandler))
+		reports.Post("/:uid/send", authorize(ac.EvalAny(
+			ac.EvalPermission(reporting.ActionReportsSend, uidScope),
+			ac.EvalPermission(reporting.ActionReportsCreate),
+		)), routing.Wrap(s.sendHandler))
+		reports.Get("/:uid/history", authorize(ac.EvalPermission(reporting.ActionReportsRead, uidScope)), routing.Wrap(s.historyHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// authorizeReport allows an action on a report to its owner and to users with the permission for the action on it
+func (s *Service) authorizeReport(c *contextmodel.ReqContext, report *reporting.Report, action string) error {
+	if report.UserID == c.SignedInUser.UserID {
+		return nil
+	}
+
+	evaluator := ac.EvalPermission(action, reporting.ScopeReportsProvider.GetResourceScopeUID(report.UID))
+	ok, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+	if err != nil {
+		return reporting.ErrInternal.Errorf("failed to evaluate permissions: %w", err)
+	}
+	if !ok {
+		return reporting.ErrReportForbidden.Errorf("user %d is not the owner of report %s", c.SignedInUser.UserID, report.UID)
+	}
+	return nil
+}
+
+// swagger:route GET /reports reports listReports
+//
+// Get all reports of the current organization.
+//
+// Responses:
+// 200: listReportsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	reports, err := s.List(c.Req.Context(), &reporting.ListReportsQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: c.Query("dashboardUid"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	readable := make([]*reporting.Report, 0, len(reports))
+	for _, report := range reports {
+		evaluator := ac.EvalPermission(reporting.ActionReportsRead, reporting.ScopeReportsProvider.GetResourceScopeUID(report.UID))
+		ok, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if ok {
+			readable = append(readable, report)
+		}
+	}
+	return response.JSON(http.StatusOK, readable)
+}
+
+// swagger:route POST /reports reports createReport
+//
+// Create a report.
+//
+// Responses:
+// 200: reportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reporting.CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if c.SignedInUser.UserID == 0 {
+		return response.Error(http.StatusBadRequest, "Reports can only be created by users", nil)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	report, err := s.Create(c.Req.Context(), c.SignedInUser, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route GET /reports/{uid} reports getReport
+//
+// Get a report by uid.
+//
+// Responses:
+// 200: reportResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), &reporting.GetReportQuery{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route PUT /reports/{uid} reports updateReport
+//
+// Update a report.
+//
+// Responses:
+// 200: reportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) updateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reporting.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if c.SignedInUser.UserID == 0 {
+		return response.Error(http.StatusBadRequest, "Reports can only be updated by users", nil)
+	}
+	cmd.UID = web.Params(c.Req)[":uid"]
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	existing, err := s.Get(c.Req.Context(), &reporting.GetReportQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	if err := s.authorizeReport(c, existing, reporting.ActionReportsWrite); err != nil {
+		return response.Err(err)
+	}
+
+	report, err := s.Update(c.Req.Context(), c.SignedInUser, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route DELETE /reports/{uid} reports deleteReport
+//
+// Delete a report.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), &reporting.GetReportQuery{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	if err := s.authorizeReport(c, report, reporting.ActionReportsDelete); err != nil {
+		return response.Err(err)
+	}
+
+	err = s.Delete(c.Req.Context(), &reporting.DeleteReportCommand{UID: report.UID, OrgID: report.OrgID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+	return response.Success("Report deleted")
+}
+
+// swagger:route POST /reports/{uid}/send reports sendReport
+//
+// Send a report now, regardless of its schedule.
+//
+// Responses:
+// 200: reportSendResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) sendHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), &reporting.GetReportQuery{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	if err := s.authorizeReport(c, report, reporting.ActionReportsSend); err != nil {
+		return response.Err(err)
+	}
+
+	reportSend, err := s.Send(c.Req.Context(), &reporting.GetReportQuery{UID: report.UID, OrgID: report.OrgID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.JSON(http.StatusOK, reportSend)
+}
+
+// swagger:route GET /reports/{uid}/history reports getReportHistory
+//
+// Get the history of sends of a report, most recent first.
+//
+// Responses:
+// 200: reportHistoryResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) historyHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), &reporting.GetReportQuery{UID: web.Params(c.Req)[":uid"], OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+
+	limit := defaultHistoryLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return response.Error(http.StatusBadRequest, "limit must be a positive number", err)
+		}
+	}
+
+	sends, err := s.ListSends(c.Req.Context(), &reporting.ListReportSendsQuery{OrgID: report.OrgID, ReportID: report.ID, Limit: limit})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report history", err)
+	}
+	return response.JSON(http.StatusOK, sends)
+}
+
+// swagger:parameters listReports
+type ListReportsParams struct {
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUid"`
+}
+
+// swagger:parameters createReport
+type CreateReportParams struct {
+	// in:body
+	// required:true
+	Body reporting.ReportSpec `json:"body"`
+}
+
+// swagger:parameters getReport deleteReport sendReport
+type ReportUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters updateReport
+type UpdateReportParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body reporting.ReportSpec `json:"body"`
+}
+
+// swagger:parameters getReportHistory
+type GetReportHistoryParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:response listReportsResponse
+type ListReportsResponse struct {
+	// in: body
+	Body []*reporting.Report `json:"body"`
+}
+
+// swagger:response reportResponse
+type ReportResponse struct {
+	// in: body
+	Body *reporting.Report `json:"body"`
+}
+
+// swagger:response reportSendResponse
+type ReportSendResponse struct {
+	// in: body
+	Body *reporting.ReportSend `json:"body"`
+}
+
+// swagger:response reportHistoryResponse
+type ReportHistoryResponse struct {
+	// in: body
+	Body []*reporting.ReportSend `json:"body"`
+}
@@ -0,0 +1,260 @@
+package reportingimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reporting"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const maxNameLength = 190
+
+type Service struct {
+	cfg               *setting.Cfg
+	store             store
+	routeRegister     routing.RouteRegister
+	accessControl     ac.AccessControl
+	dashboardService  dashboards.DashboardService
+	renderService     rendering.Service
+	notifications     notifications.EmailSender
+	userService       user.Service
+	serverLockService *serverlock.ServerLockService
+	log               log.Logger
+	now               func() time.Time
+}
+
+var _ reporting.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	database db.DB,
+	routeRegister routing.RouteRegister,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	dashboardService dashboards.DashboardService,
+	renderService rendering.Service,
+	notificationService notifications.Service,
+	userService user.Service,
+	serverLockService *serverlock.ServerLockService,
+) (*Service, error) {
+	s := &Service{
+		cfg:               cfg,
+		store:             &sqlStore{db: database},
+		routeRegister:     routeRegister,
+		accessControl:     accessControl,
+		dashboardService:  dashboardService,
+		renderService:     renderService,
+		notifications:     notificationService,
+		userService:       userService,
+		serverLockService: serverLockService,
+		log:               log.New("reporting"),
+		now:               time.Now,
+	}
+
+	// Register roles and routes only when reporting is enabled
+	if cfg.Reporting.Enabled {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+		s.registerAPIEndpoints()
+	}
+
+	return s, nil
+}
+
+func (s *Service) Create(ctx context.Context, user identity.Requester, cmd *reporting.CreateReportCommand) (*reporting.Report, error) {
+	if err := s.validate(ctx, user, cmd.OrgID, &cmd.ReportSpec); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report := &reporting.Report{
+		UID:       util.GenerateShortUID(),
+		OrgID:     cmd.OrgID,
+		UserID:    cmd.UserID,
+		UpdatedBy: cmd.UserID,
+		Created:   now,
+	}
+	if err := s.apply(report, &cmd.ReportSpec, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Insert(ctx, report); err != nil {
+		return nil, reporting.ErrInternal.Errorf("failed to create report: %w", err)
+	}
+	return report, nil
+}
+
+func (s *Service) Update(ctx context.Context, user identity.Requester, cmd *reporting.UpdateReportCommand) (*reporting.Report, error) {
+	report, err := s.store.Get(ctx, &reporting.GetReportQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, user, cmd.OrgID, &cmd.ReportSpec); err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(report, &cmd.ReportSpec, s.now()); err != nil {
+		return nil, err
+	}
+	// the report is rendered as the user who changed it last, so that users who can change the dashboard or the
+	// recipients of a report can't send what only its owner can see
+	report.UpdatedBy = cmd.UserID
+
+	if err := s.store.Update(ctx, report); err != nil {
+		if errors.Is(err, reporting.ErrReportNotFound) {
+			return nil, err
+		}
+		return nil, reporting.ErrInternal.Errorf("failed to update report: %w", err)
+	}
+	return report, nil
+}
+
+func (s *Service) Delete(ctx context.Context, cmd *reporting.DeleteReportCommand) error {
+	return s.store.Delete(ctx, cmd)
+}
+
+func (s *Service) Get(ctx context.Context, query *reporting.GetReportQuery) (*reporting.Report, error) {
+	return s.store.Get(ctx, query)
+}
+
+func (s *Service) List(ctx context.Context, query *reporting.ListReportsQuery) ([]*reporting.Report, error) {
+	return s.store.List(ctx, query)
+}
+
+func (s *Service) ListSends(ctx context.Context, query *reporting.ListReportSendsQuery) ([]*reporting.ReportSend, error) {
+	return s.store.ListSends(ctx, query)
+}
+
+func (s *Service) Send(ctx context.Context, query *reporting.GetReportQuery) (*reporting.ReportSend, error) {
+	report, err := s.store.Get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, report, false)
+}
+
+// apply sets the spec on the report and calculates the time of its next send
+func (s *Service) apply(report *reporting.Report, spec *reporting.ReportSpec, now time.Time) error {
+	report.Name = strings.TrimSpace(spec.Name)
+	report.DashboardUID = spec.DashboardUID
+	report.Recipients = spec.Recipients
+	report.ReplyTo = spec.ReplyTo
+	report.Subject = spec.Subject
+	report.Message = spec.Message
+	report.Schedule = strings.TrimSpace(spec.Schedule)
+	report.Timezone = spec.Timezone
+	report.TimeFrom = spec.TimeFrom
+	report.TimeTo = spec.TimeTo
+	report.Variables = spec.Variables
+	report.Format = spec.Format
+	if report.Format == "" {
+		report.Format = reporting.FormatPNG
+	}
+	report.Enabled = spec.Enabled
+	report.Updated = now
+
+	report.NextSendAt = nil
+	if report.Enabled {
+		next, err := nextSendAt(report.Schedule, report.Timezone, now)
+		if err != nil {
+			return reporting.ErrInvalidReport.Errorf("invalid schedule: %w", err)
+		}
+		report.NextSendAt = &next
+	}
+	return nil
+}
+
+// validate checks the spec of a report and that the user can read its dashboard
+func (s *Service) validate(ctx context.Context, user identity.Requester, orgID int64, spec *reporting.ReportSpec) error {
+	name := strings.TrimSpace(spec.Name)
+	if name == "" {
+		return reporting.ErrInvalidReport.Errorf("name is required")
+	}
+	if len(name) > maxNameLength {
+		return reporting.ErrInvalidReport.Errorf("name must be at most %d characters", maxNameLength)
+	}
+
+	if len(spec.Recipients) == 0 {
+		return reporting.ErrInvalidReport.Errorf("at least one recipient is required")
+	}
+	for _, recipient := range spec.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return reporting.ErrInvalidReport.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+	}
+	if spec.ReplyTo != "" {
+		if _, err := mail.ParseAddress(spec.ReplyTo); err != nil {
+			return reporting.ErrInvalidReport.Errorf("invalid reply-to address %q: %w", spec.ReplyTo, err)
+		}
+	}
+
+	if _, err := time.LoadLocation(spec.Timezone); err != nil {
+		return reporting.ErrInvalidReport.Errorf("invalid timezone %q: %w", spec.Timezone, err)
+	}
+	if _, err := nextSendAt(spec.Schedule, spec.Timezone, s.now()); err != nil {
+		return reporting.ErrInvalidReport.Errorf("invalid schedule %q: %w", spec.Schedule, err)
+	}
+	if (spec.TimeFrom == "") != (spec.TimeTo == "") {
+		return reporting.ErrInvalidReport.Errorf("both timeFrom and timeTo must be set to override the time range")
+	}
+	if spec.Format != "" && spec.Format != reporting.FormatPNG && spec.Format != reporting.FormatPDF {
+		return reporting.ErrInvalidReport.Errorf("format must be %s or %s", reporting.FormatPNG, reporting.FormatPDF)
+	}
+
+	if spec.DashboardUID == "" {
+		return reporting.ErrInvalidReport.Errorf("dashboardUid is required")
+	}
+	if _, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: spec.DashboardUID, OrgID: orgID}); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return reporting.ErrDashboardNotFound.Errorf("dashboard %s not found", spec.DashboardUID)
+		}
+		return reporting.ErrInternal.Errorf("failed to get dashboard: %w", err)
+	}
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(spec.DashboardUID))
+	canRead, err := s.accessControl.Evaluate(ctx, user, evaluator)
+	if err != nil {
+		return reporting.ErrInternal.Errorf("failed to evaluate permissions: %w", err)
+	}
+	if !canRead {
+		// the dashboard is reported as not found to not leak its existence
+		return reporting.ErrDashboardNotFound.Errorf("dashboard %s not found", spec.DashboardUID)
+	}
+
+	return nil
+}
+
+// nextSendAt returns the first time after the given time that matches the schedule in the timezone
+func nextSendAt(schedule string, timezone string, after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := sched.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule never matches")
+	}
+	return next.UTC(), nil
+}
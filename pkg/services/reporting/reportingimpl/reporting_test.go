@@ -0,0 +1,305 @@
+package reportingimpl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reporting"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var testNow = time.Date(2024, time.March, 4, 8, 30, 0, 0, time.UTC)
+
+func newTestService(t *testing.T, canRead bool) (*Service, *dashboards.FakeDashboardService) {
+	t.Helper()
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	cfg := setting.NewCfg()
+	cfg.Reporting = setting.ReportingSettings{Enabled: true, HistoryRetention: time.Hour}
+
+	return &Service{
+		cfg:              cfg,
+		accessControl:    actest.FakeAccessControl{ExpectedEvaluate: canRead},
+		dashboardService: dashboardService,
+		log:              log.New("test.logger"),
+		now:              func() time.Time { return testNow },
+	}, dashboardService
+}
+
+func validSpec() reporting.ReportSpec {
+	return reporting.ReportSpec{
+		Name:         "Weekly",
+		DashboardUID: "dash",
+		Recipients:   []string{"a@example.com", "B <b@example.com>"},
+		Schedule:     "0 9 * * 1",
+		Timezone:     "Europe/Berlin",
+		Enabled:      true,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+
+	t.Run("accepts a valid report", func(t *testing.T) {
+		service, dashboardService := newTestService(t, true)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash"}, nil)
+
+		spec := validSpec()
+		require.NoError(t, service.validate(context.Background(), signedInUser, 1, &spec))
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		for name, modify := range map[string]func(*reporting.ReportSpec){
+			"missing name":       func(s *reporting.ReportSpec) { s.Name = " " },
+			"missing recipients": func(s *reporting.ReportSpec) { s.Recipients = nil },
+			"invalid recipient":  func(s *reporting.ReportSpec) { s.Recipients = []string{"not an address"} },
+			"invalid reply-to":   func(s *reporting.ReportSpec) { s.ReplyTo = "nope" },
+			"invalid schedule":   func(s *reporting.ReportSpec) { s.Schedule = "every monday" },
+			"invalid timezone":   func(s *reporting.ReportSpec) { s.Timezone = "Mars/Olympus" },
+			"partial time range": func(s *reporting.ReportSpec) { s.TimeFrom = "now-7d" },
+			"invalid format":     func(s *reporting.ReportSpec) { s.Format = "gif" },
+			"missing dashboard":  func(s *reporting.ReportSpec) { s.DashboardUID = "" },
+		} {
+			t.Run(name, func(t *testing.T) {
+				service, _ := newTestService(t, true)
+				spec := validSpec()
+				modify(&spec)
+
+				err := service.validate(context.Background(), signedInUser, 1, &spec)
+				require.ErrorIs(t, err, reporting.ErrInvalidReport)
+			})
+		}
+	})
+
+	t.Run("rejects a dashboard that does not exist", func(t *testing.T) {
+		service, dashboardService := newTestService(t, true)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+
+		spec := validSpec()
+		err := service.validate(context.Background(), signedInUser, 1, &spec)
+		require.ErrorIs(t, err, reporting.ErrDashboardNotFound)
+	})
+
+	t.Run("rejects a dashboard that the user cannot read", func(t *testing.T) {
+		service, dashboardService := newTestService(t, false)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash"}, nil)
+
+		spec := validSpec()
+		err := service.validate(context.Background(), signedInUser, 1, &spec)
+		require.ErrorIs(t, err, reporting.ErrDashboardNotFound)
+	})
+}
+
+func TestAuthorizeReport(t *testing.T) {
+	report := &reporting.Report{UID: "report", OrgID: 1, UserID: 1}
+	newContext := func(userID int64) *contextmodel.ReqContext {
+		return &contextmodel.ReqContext{
+			Context:      &web.Context{Req: httptest.NewRequest(http.MethodPut, "/api/reports/report", nil)},
+			SignedInUser: &user.SignedInUser{UserID: userID, OrgID: 1},
+		}
+	}
+
+	t.Run("allows the owner", func(t *testing.T) {
+		service, _ := newTestService(t, false)
+		require.NoError(t, service.authorizeReport(newContext(1), report, reporting.ActionReportsWrite))
+	})
+
+	t.Run("allows users with the permission on the report", func(t *testing.T) {
+		service, _ := newTestService(t, true)
+		require.NoError(t, service.authorizeReport(newContext(2), report, reporting.ActionReportsWrite))
+	})
+
+	t.Run("rejects other users", func(t *testing.T) {
+		service, _ := newTestService(t, false)
+		err := service.authorizeReport(newContext(2), report, reporting.ActionReportsWrite)
+		require.ErrorIs(t, err, reporting.ErrReportForbidden)
+	})
+}
+
+func TestNextSendAt(t *testing.T) {
+	// 09:00 in Berlin is 08:00 UTC in winter and 07:00 UTC in summer
+	next, err := nextSendAt("0 9 * * 1", "Europe/Berlin", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.March, 4, 8, 0, 0, 0, time.UTC).Add(7*24*time.Hour), next)
+
+	next, err = nextSendAt("0 9 * * 1", "Europe/Berlin", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.April, 1, 7, 0, 0, 0, time.UTC), next)
+
+	next, err = nextSendAt("@daily", "UTC", testNow)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), next)
+
+	_, err = nextSendAt("0 9 * *", "UTC", testNow)
+	require.Error(t, err)
+}
+
+func TestRenderPath(t *testing.T) {
+	report := &reporting.Report{
+		OrgID:     2,
+		TimeFrom:  "now-7d",
+		TimeTo:    "now",
+		Variables: map[string][]string{"pod": {"a", "b"}, "env": {"prod"}},
+	}
+	dashboard := &dashboards.Dashboard{UID: "dash", Slug: "my-dashboard"}
+
+	assert.Equal(t, "d/dash/my-dashboard?from=now-7d&orgId=2&to=now&var-env=prod&var-pod=a&var-pod=b&kiosk", renderPath(report, dashboard))
+}
+
+func TestIntegrationSendDueReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	service, dashboardService := newTestService(t, true)
+	service.store = &sqlStore{db: db.InitTestDB(t)}
+	service.userService = &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor}}
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash", Slug: "my-dashboard", Title: "My dashboard"}, nil)
+
+	notificationService := &notifications.NotificationServiceMock{}
+	service.notifications = notificationService
+
+	renderService := rendering.NewMockService(gomock.NewController(t))
+	renderService.EXPECT().HasCapability(gomock.Any(), rendering.FullHeightImages).Return(rendering.CapabilitySupportRequestResult{IsSupported: true}, nil).AnyTimes()
+	renderService.EXPECT().Render(gomock.Any(), rendering.RenderPNG, gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, _ rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+			assert.Equal(t, org.RoleEditor, opts.AuthOpts.OrgRole)
+			assert.Equal(t, "Europe/Berlin", opts.Timezone)
+			assert.Equal(t, -1, opts.Height)
+
+			path := filepath.Join(t.TempDir(), "render.png")
+			require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+			return &rendering.RenderResult{FilePath: path}, nil
+		}).Times(1)
+	service.renderService = renderService
+
+	due := testNow.Add(-time.Minute)
+	report := &reporting.Report{
+		UID:          "report",
+		OrgID:        1,
+		UserID:       1,
+		Name:         "Weekly",
+		DashboardUID: "dash",
+		Recipients:   []string{"a@example.com"},
+		Schedule:     "0 9 * * 1",
+		Timezone:     "Europe/Berlin",
+		Format:       reporting.FormatPNG,
+		Enabled:      true,
+		NextSendAt:   &due,
+		Created:      testNow,
+		Updated:      testNow,
+	}
+	require.NoError(t, service.store.Insert(ctx, report))
+
+	service.sendDueReports(ctx)
+	// the report is not due anymore, so it is not sent again
+	service.sendDueReports(ctx)
+
+	email := notificationService.EmailSync.SendEmailCommand
+	assert.Equal(t, []string{"a@example.com"}, email.To)
+	assert.Equal(t, "Weekly", email.Subject)
+	assert.Equal(t, reportEmailTemplate, email.Template)
+	require.Len(t, email.AttachedFiles, 1)
+	assert.Equal(t, "my-dashboard.png", email.AttachedFiles[0].Name)
+	assert.Equal(t, []byte("png"), email.AttachedFiles[0].Content)
+
+	sends, err := service.ListSends(ctx, &reporting.ListReportSendsQuery{OrgID: 1, ReportID: report.ID})
+	require.NoError(t, err)
+	require.Len(t, sends, 1)
+	assert.Equal(t, reporting.SendStateSuccess, sends[0].State)
+	assert.True(t, sends[0].Scheduled)
+
+	updated, err := service.Get(ctx, &reporting.GetReportQuery{UID: "report", OrgID: 1})
+	require.NoError(t, err)
+	require.NotNil(t, updated.NextSendAt)
+	assert.Equal(t, time.Date(2024, time.March, 11, 8, 0, 0, 0, time.UTC), updated.NextSendAt.UTC())
+
+	t.Run("records a failed send", func(t *testing.T) {
+		notificationService.ShouldError = assert.AnError
+		renderService.EXPECT().Render(gomock.Any(), gomock.Any(), gomock.Any(), nil).DoAndReturn(
+			func(_ context.Context, _ rendering.RenderType, _ rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+				path := filepath.Join(t.TempDir(), "render.png")
+				require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+				return &rendering.RenderResult{FilePath: path}, nil
+			}).Times(1)
+
+		reportSend, err := service.Send(ctx, &reporting.GetReportQuery{UID: "report", OrgID: 1})
+		require.ErrorIs(t, err, reporting.ErrSendFailed)
+		assert.Equal(t, reporting.SendStateFailed, reportSend.State)
+		assert.False(t, reportSend.Scheduled)
+		assert.NotEmpty(t, reportSend.Error)
+	})
+}
+
+func TestIntegrationSendAsLastEditor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	service, dashboardService := newTestService(t, true)
+	service.store = &sqlStore{db: db.InitTestDB(t)}
+	service.notifications = &notifications.NotificationServiceMock{}
+	service.userService = &usertest.FakeUserService{
+		GetSignedInUserFn: func(_ context.Context, query *user.GetSignedInUserQuery) (*user.SignedInUser, error) {
+			return &user.SignedInUser{UserID: query.UserID, OrgID: query.OrgID, OrgRole: org.RoleEditor}, nil
+		},
+	}
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "dash", Slug: "my-dashboard"}, nil)
+
+	var renderedAs []int64
+	renderService := rendering.NewMockService(gomock.NewController(t))
+	renderService.EXPECT().HasCapability(gomock.Any(), rendering.FullHeightImages).Return(rendering.CapabilitySupportRequestResult{}, nil).AnyTimes()
+	renderService.EXPECT().Render(gomock.Any(), gomock.Any(), gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, _ rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+			renderedAs = append(renderedAs, opts.AuthOpts.UserID)
+			path := filepath.Join(t.TempDir(), "render.png")
+			require.NoError(t, os.WriteFile(path, []byte("png"), 0600))
+			return &rendering.RenderResult{FilePath: path}, nil
+		}).Times(2)
+	service.renderService = renderService
+
+	owner := &user.SignedInUser{UserID: 1, OrgID: 1}
+	report, err := service.Create(ctx, owner, &reporting.CreateReportCommand{ReportSpec: validSpec(), OrgID: 1, UserID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.UpdatedBy)
+
+	_, err = service.Send(ctx, &reporting.GetReportQuery{UID: report.UID, OrgID: 1})
+	require.NoError(t, err)
+
+	// another user with the permission to write the report changes its recipients
+	editor := &user.SignedInUser{UserID: 2, OrgID: 1}
+	spec := validSpec()
+	spec.Recipients = []string{"c@example.com"}
+	updated, err := service.Update(ctx, editor, &reporting.UpdateReportCommand{ReportSpec: spec, UID: report.UID, OrgID: 1, UserID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated.UserID)
+	assert.Equal(t, int64(2), updated.UpdatedBy)
+
+	_, err = service.Send(ctx, &reporting.GetReportQuery{UID: report.UID, OrgID: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{1, 2}, renderedAs)
+}
@@ -0,0 +1,60 @@
+package reportingimpl
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/reporting"
+)
+
+var (
+	reportsReaderRole = ac.RoleDTO{
+		Name:        "fixed:reports:reader",
+		DisplayName: "Report reader",
+		Description: "Read all reports and their history",
+		Group:       "Reports",
+		Permissions: []ac.Permission{
+			{Action: reporting.ActionReportsRead, Scope: reporting.ScopeReportsAll},
+		},
+	}
+
+	reportsCreatorRole = ac.RoleDTO{
+		Name:        "fixed:reports:creator",
+		DisplayName: "Report creator",
+		Description: "Create reports, and update, send and delete own reports",
+		Group:       "Reports",
+		Permissions: []ac.Permission{
+			{Action: reporting.ActionReportsCreate},
+		},
+	}
+
+	reportsWriterRole = ac.RoleDTO{
+		Name:        "fixed:reports:writer",
+		DisplayName: "Report writer",
+		Description: "Create, read, update, send and delete all reports",
+		Group:       "Reports",
+		Permissions: []ac.Permission{
+			{Action: reporting.ActionReportsRead, Scope: reporting.ScopeReportsAll},
+			{Action: reporting.ActionReportsCreate},
+			{Action: reporting.ActionReportsWrite, Scope: reporting.ScopeReportsAll},
+			{Action: reporting.ActionReportsDelete, Scope: reporting.ScopeReportsAll},
+			{Action: reporting.ActionReportsSend, Scope: reporting.ScopeReportsAll},
+		},
+	}
+)
+
+func declareFixedRoles(service ac.Service) error {
+	reportsReader := ac.RoleRegistration{
+		Role:   reportsReaderRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+	reportsCreator := ac.RoleRegistration{
+		Role:   reportsCreatorRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+	reportsWriter := ac.RoleRegistration{
+		Role:   reportsWriterRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	return service.DeclareFixedRoles(reportsReader, reportsCreator, reportsWriter)
+}
@@ -0,0 +1,236 @@
+package reportingimpl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reporting"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	reportEmailTemplate = "report"
+	reportWidth         = 1600
+	reportHeight        = 900
+	sendLockName        = "send scheduled reports"
+	sendLockInterval    = 10 * time.Minute
+)
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.Reporting.Enabled
+}
+
+// Run sends the reports that are due at every poll interval. Only one instance sends reports at a time.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Reporting.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.serverLockService.LockExecuteAndRelease(ctx, sendLockName, sendLockInterval, func(ctx context.Context) {
+				s.sendDueReports(ctx)
+			})
+			if err != nil {
+				s.log.Debug("Skipped sending scheduled reports", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) sendDueReports(ctx context.Context) {
+	now := s.now()
+
+	reports, err := s.store.ListDue(ctx, now)
+	if err != nil {
+		s.log.Error("Failed to list scheduled reports", "error", err)
+		return
+	}
+
+	for _, report := range reports {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Claim the report by moving its next send time forward first, so that it is not sent twice
+		// if another instance sends it concurrently or sending takes longer than the poll interval.
+		var next *time.Time
+		if nextAt, err := nextSendAt(report.Schedule, report.Timezone, now); err != nil {
+			s.log.Error("Failed to calculate next send time of report, disabling its schedule", "report", report.UID, "error", err)
+		} else {
+			next = &nextAt
+		}
+		claimed, err := s.store.ClaimDue(ctx, report.ID, now, next)
+		if err != nil {
+			s.log.Error("Failed to update next send time of report", "report", report.UID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.send(ctx, report, true); err != nil {
+			s.log.Warn("Failed to send scheduled report", "report", report.UID, "error", err)
+		}
+	}
+
+	if s.cfg.Reporting.HistoryRetention > 0 {
+		deleted, err := s.store.DeleteSendsBefore(ctx, now.Add(-s.cfg.Reporting.HistoryRetention))
+		if err != nil {
+			s.log.Error("Failed to delete report history", "error", err)
+		} else if deleted > 0 {
+			s.log.Debug("Deleted report history", "count", deleted)
+		}
+	}
+}
+
+// send renders the dashboard of the report as the user who last changed it, emails it to the recipients and records the outcome
+func (s *Service) send(ctx context.Context, report *reporting.Report, scheduled bool) (*reporting.ReportSend, error) {
+	reportSend := &reporting.ReportSend{
+		OrgID:      report.OrgID,
+		ReportID:   report.ID,
+		SentAt:     s.now(),
+		State:      reporting.SendStateSuccess,
+		Recipients: report.Recipients,
+		Scheduled:  scheduled,
+	}
+
+	sendErr := s.renderAndEmail(ctx, report)
+	if sendErr != nil {
+		reportSend.State = reporting.SendStateFailed
+		reportSend.Error = sendErr.Error()
+	}
+
+	if err := s.store.InsertSend(ctx, reportSend); err != nil {
+		s.log.Error("Failed to record report send", "report", report.UID, "error", err)
+	}
+
+	if sendErr != nil {
+		return reportSend, reporting.ErrSendFailed.Errorf("failed to send report %s: %w", report.UID, sendErr)
+	}
+	return reportSend, nil
+}
+
+func (s *Service) renderAndEmail(ctx context.Context, report *reporting.Report) error {
+	// reports created before the last change was recorded are rendered as their owner
+	renderUserID := report.UpdatedBy
+	if renderUserID == 0 {
+		renderUserID = report.UserID
+	}
+	renderUser, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: renderUserID, OrgID: report.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get user to render report as: %w", err)
+	}
+
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: report.DashboardUID, OrgID: report.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get dashboard: %w", err)
+	}
+
+	renderType := rendering.RenderPNG
+	if report.Format == reporting.FormatPDF {
+		renderType = rendering.RenderPDF
+	}
+
+	height := reportHeight
+	if capability, err := s.renderService.HasCapability(ctx, rendering.FullHeightImages); err == nil && capability.IsSupported {
+		height = -1
+	}
+
+	result, err := s.renderService.Render(ctx, renderType, rendering.Opts{
+		CommonOpts: rendering.CommonOpts{
+			AuthOpts: rendering.AuthOpts{
+				OrgID:   report.OrgID,
+				UserID:  renderUser.UserID,
+				OrgRole: renderUser.OrgRole,
+			},
+			TimeoutOpts: rendering.TimeoutOpts{
+				Timeout: s.cfg.Reporting.RenderTimeout,
+			},
+			Path:            renderPath(report, dashboard),
+			Timezone:        report.Timezone,
+			ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+		},
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Width:  reportWidth,
+		Height: height,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to render dashboard: %w", err)
+	}
+
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read rendered dashboard: %w", err)
+	}
+	if err := os.Remove(result.FilePath); err != nil {
+		s.log.Warn("Failed to remove rendered dashboard", "path", result.FilePath, "error", err)
+	}
+
+	subject := report.Subject
+	if subject == "" {
+		subject = report.Name
+	}
+
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:          report.Recipients,
+			SingleEmail: false,
+			Template:    reportEmailTemplate,
+			Subject:     subject,
+			Data: map[string]any{
+				"ReportName":     report.Name,
+				"Message":        report.Message,
+				"DashboardTitle": dashboard.Title,
+				"DashboardURL":   dashboards.GetFullDashboardURL(dashboard.UID, dashboard.Slug),
+			},
+			AttachedFiles: []*notifications.SendEmailAttachFile{{
+				Name:    fmt.Sprintf("%s.%s", dashboard.Slug, report.Format),
+				Content: content,
+			}},
+		},
+	}
+	if report.ReplyTo != "" {
+		cmd.ReplyTo = []string{report.ReplyTo}
+	}
+
+	if err := s.notifications.SendEmailCommandHandlerSync(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// renderPath returns the path of the dashboard in kiosk mode with the time range and variables of the report
+func renderPath(report *reporting.Report, dashboard *dashboards.Dashboard) string {
+	params := url.Values{}
+	params.Set("orgId", fmt.Sprintf("%d", report.OrgID))
+	if report.TimeFrom != "" && report.TimeTo != "" {
+		params.Set("from", report.TimeFrom)
+		params.Set("to", report.TimeTo)
+	}
+
+	names := make([]string, 0, len(report.Variables))
+	for name := range report.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range report.Variables[name] {
+			params.Add("var-"+name, value)
+		}
+	}
+
+	return fmt.Sprintf("d/%s/%s?%s&kiosk", dashboard.UID, dashboard.Slug, params.Encode())
+}
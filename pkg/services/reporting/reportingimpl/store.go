@@ -0,0 +1,25 @@
+package reportingimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/reporting"
+)
+
+type store interface {
+	Insert(ctx context.Context, report *reporting.Report) error
+	Update(ctx context.Context, report *reporting.Report) error
+	Delete(ctx context.Context, cmd *reporting.DeleteReportCommand) error
+	Get(ctx context.Context, query *reporting.GetReportQuery) (*reporting.Report, error)
+	List(ctx context.Context, query *reporting.ListReportsQuery) ([]*reporting.Report, error)
+	// ListDue returns the enabled reports of all organizations that are due to be sent at the given time
+	ListDue(ctx context.Context, now time.Time) ([]*reporting.Report, error)
+	// ClaimDue sets the time of the next send of a report if it is still due at the given time,
+	// and returns false if it is not, for example because another instance claimed it first
+	ClaimDue(ctx context.Context, reportID int64, now time.Time, next *time.Time) (bool, error)
+
+	InsertSend(ctx context.Context, send *reporting.ReportSend) error
+	ListSends(ctx context.Context, query *reporting.ListReportSendsQuery) ([]*reporting.ReportSend, error)
+	DeleteSendsBefore(ctx context.Context, before time.Time) (int64, error)
+}
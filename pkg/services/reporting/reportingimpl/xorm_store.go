@@ -0,0 +1,134 @@
+package reportingimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reporting"
+)
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+func (s *sqlStore) Insert(ctx context.Context, report *reporting.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+}
+
+func (s *sqlStore) Update(ctx context.Context, report *reporting.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(report.ID).Where("org_id = ?", report.OrgID).AllCols().Omit("id", "uid", "org_id", "user_id", "created").Update(report)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reporting.ErrReportNotFound.Errorf("report %s not found", report.UID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, cmd *reporting.DeleteReportCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		report := reporting.Report{}
+		has, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return reporting.ErrReportNotFound.Errorf("report %s not found", cmd.UID)
+		}
+
+		if _, err := sess.Exec("DELETE FROM report_send WHERE org_id = ? AND report_id = ?", cmd.OrgID, report.ID); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM report WHERE id = ?", report.ID)
+		return err
+	})
+}
+
+func (s *sqlStore) Get(ctx context.Context, query *reporting.GetReportQuery) (*reporting.Report, error) {
+	report := reporting.Report{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return reporting.ErrReportNotFound.Errorf("report %s not found", query.UID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, query *reporting.ListReportsQuery) ([]*reporting.Report, error) {
+	reports := make([]*reporting.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		return q.Asc("name").Find(&reports)
+	})
+	return reports, err
+}
+
+func (s *sqlStore) ListDue(ctx context.Context, now time.Time) ([]*reporting.Report, error) {
+	reports := make([]*reporting.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("enabled = ? AND next_send_at <= ?", true, now.UTC()).Asc("next_send_at").Find(&reports)
+	})
+	return reports, err
+}
+
+func (s *sqlStore) ClaimDue(ctx context.Context, reportID int64, now time.Time, next *time.Time) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(reportID).Where("enabled = ? AND next_send_at <= ?", true, now.UTC()).Cols("next_send_at").Update(&reporting.Report{NextSendAt: next})
+		claimed = affected > 0
+		return err
+	})
+	return claimed, err
+}
+
+func (s *sqlStore) InsertSend(ctx context.Context, send *reporting.ReportSend) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(send)
+		return err
+	})
+}
+
+func (s *sqlStore) ListSends(ctx context.Context, query *reporting.ListReportSendsQuery) ([]*reporting.ReportSend, error) {
+	sends := make([]*reporting.ReportSend, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ? AND report_id = ?", query.OrgID, query.ReportID).Desc("sent_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&sends)
+	})
+	return sends, err
+}
+
+func (s *sqlStore) DeleteSendsBefore(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM report_send WHERE sent_at < ?", before.UTC())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,147 @@
+package reportingimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reporting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationReportStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 4, 8, 0, 0, 0, time.UTC)
+	newReport := func(uid string, next *time.Time) *reporting.Report {
+		return &reporting.Report{
+			UID:          uid,
+			OrgID:        1,
+			UserID:       1,
+			Name:         "Report " + uid,
+			DashboardUID: "dash",
+			Recipients:   []string{"a@example.com"},
+			Schedule:     "0 8 * * 1",
+			Timezone:     "UTC",
+			Variables:    map[string][]string{"env": {"prod"}},
+			Format:       reporting.FormatPNG,
+			Enabled:      next != nil,
+			NextSendAt:   next,
+			Created:      now,
+			Updated:      now,
+		}
+	}
+
+	t.Run("can create, update, get and delete reports", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+
+		report := newReport("a", nil)
+		require.NoError(t, store.Insert(ctx, report))
+
+		report.Name = "Renamed"
+		report.Recipients = []string{"b@example.com", "c@example.com"}
+		require.NoError(t, store.Update(ctx, report))
+
+		got, err := store.Get(ctx, &reporting.GetReportQuery{UID: "a", OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", got.Name)
+		assert.Equal(t, []string{"b@example.com", "c@example.com"}, got.Recipients)
+		assert.Equal(t, map[string][]string{"env": {"prod"}}, got.Variables)
+
+		_, err = store.Get(ctx, &reporting.GetReportQuery{UID: "a", OrgID: 2})
+		require.ErrorIs(t, err, reporting.ErrReportNotFound)
+
+		require.NoError(t, store.InsertSend(ctx, &reporting.ReportSend{OrgID: 1, ReportID: report.ID, SentAt: now, State: reporting.SendStateSuccess}))
+		require.NoError(t, store.Delete(ctx, &reporting.DeleteReportCommand{UID: "a", OrgID: 1}))
+
+		_, err = store.Get(ctx, &reporting.GetReportQuery{UID: "a", OrgID: 1})
+		require.ErrorIs(t, err, reporting.ErrReportNotFound)
+		sends, err := store.ListSends(ctx, &reporting.ListReportSendsQuery{OrgID: 1, ReportID: report.ID})
+		require.NoError(t, err)
+		assert.Empty(t, sends)
+
+		err = store.Delete(ctx, &reporting.DeleteReportCommand{UID: "a", OrgID: 1})
+		require.ErrorIs(t, err, reporting.ErrReportNotFound)
+	})
+
+	t.Run("lists reports by dashboard", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+
+		other := newReport("b", nil)
+		other.DashboardUID = "other"
+		require.NoError(t, store.Insert(ctx, newReport("a", nil)))
+		require.NoError(t, store.Insert(ctx, other))
+
+		reports, err := store.List(ctx, &reporting.ListReportsQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.Len(t, reports, 2)
+
+		reports, err = store.List(ctx, &reporting.ListReportsQuery{OrgID: 1, DashboardUID: "other"})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, "b", reports[0].UID)
+	})
+
+	t.Run("lists due reports and claims them once", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+
+		past, future := now.Add(-time.Minute), now.Add(time.Hour)
+		due := newReport("due", &past)
+		require.NoError(t, store.Insert(ctx, due))
+		require.NoError(t, store.Insert(ctx, newReport("later", &future)))
+		require.NoError(t, store.Insert(ctx, newReport("disabled", nil)))
+
+		reports, err := store.ListDue(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, "due", reports[0].UID)
+
+		next := now.Add(24 * time.Hour)
+		claimed, err := store.ClaimDue(ctx, due.ID, now, &next)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = store.ClaimDue(ctx, due.ID, now, &next)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		reports, err = store.ListDue(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("lists and prunes the history of sends", func(t *testing.T) {
+		store := &sqlStore{db: db.InitTestDB(t)}
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, store.InsertSend(ctx, &reporting.ReportSend{
+				OrgID:      1,
+				ReportID:   1,
+				SentAt:     now.Add(time.Duration(-i) * time.Hour),
+				State:      reporting.SendStateSuccess,
+				Recipients: []string{"a@example.com"},
+			}))
+		}
+
+		sends, err := store.ListSends(ctx, &reporting.ListReportSendsQuery{OrgID: 1, ReportID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, sends, 2)
+		assert.True(t, sends[0].SentAt.After(sends[1].SentAt))
+		assert.Equal(t, []string{"a@example.com"}, sends[0].Recipients)
+
+		deleted, err := store.DeleteSendsBefore(ctx, now.Add(-90*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+}
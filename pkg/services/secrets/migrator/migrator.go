@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	dashsnapstore "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -33,9 +34,11 @@ func ProvideSecretsMigrator(
 	sqlStore db.DB,
 	settings setting.Provider,
 	features featuremgmt.FeatureToggles,
+	snapshotStore *dashsnapstore.DashboardSnapshotStore,
 ) *SecretsMigrator {
 	rotators := []SecretsRotator{
 		simpleSecret{tableName: "dashboard_snapshot", columnName: "dashboard_encrypted"},
+		snapshotPayloadSecret{store: snapshotStore},
 		b64Secret{simpleSecret: simpleSecret{tableName: "user_auth", columnName: "o_auth_access_token"}, encoding: base64.StdEncoding},
 		b64Secret{simpleSecret: simpleSecret{tableName: "user_auth", columnName: "o_auth_refresh_token"}, encoding: base64.StdEncoding},
 		b64Secret{simpleSecret: simpleSecret{tableName: "user_auth", columnName: "o_auth_token_type"}, encoding: base64.StdEncoding},
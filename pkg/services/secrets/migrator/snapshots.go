@@ -0,0 +1,43 @@
+package migrator
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	dashsnapstore "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+// snapshotPayloadSecret rotates the encrypted dashboards of snapshots that are stored
+// outside the database, in the storage configured with the storage_url of snapshots.
+type snapshotPayloadSecret struct {
+	store *dashsnapstore.DashboardSnapshotStore
+}
+
+func (s snapshotPayloadSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, _ db.DB) bool {
+	return s.store.RotatePayloads(ctx, func(ctx context.Context, encrypted []byte) ([]byte, error) {
+		decrypted, err := secretsSrv.Decrypt(ctx, encrypted)
+		if err != nil {
+			return nil, err
+		}
+		return secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
+	})
+}
+
+func (s snapshotPayloadSecret) Rollback(
+	ctx context.Context,
+	secretsSrv *manager.SecretsService,
+	encryptionSrv encryption.Internal,
+	_ db.DB,
+	secretKey string,
+) (anyFailure bool) {
+	return !s.store.RotatePayloads(ctx, func(ctx context.Context, encrypted []byte) ([]byte, error) {
+		decrypted, err := secretsSrv.Decrypt(ctx, encrypted)
+		if err != nil {
+			return nil, err
+		}
+		return encryptionSrv.Encrypt(ctx, decrypted, secretKey)
+	})
+}
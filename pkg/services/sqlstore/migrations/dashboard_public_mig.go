@@ -96,4 +96,37 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add password_hash column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "password_hash",
+		Type:     DB_NVarchar,
+		Length:   255,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add password_salt column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "password_salt",
+		Type:     DB_NVarchar,
+		Length:   50,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add allowed_cidrs column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "allowed_cidrs",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add rate_limit column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "rate_limit",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
 }
@@ -71,4 +71,8 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add column payload_path to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "payload_path", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
 }
@@ -138,6 +138,21 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	accesscontrol.AddActionSetPermissionsMigrator(mg)
 
 	externalsession.AddMigration(mg)
+
+	ualert.AddRuleVersionCreatedBy(mg)
+
+	ualert.AddRuleDependencies(mg)
+
+	ualert.AddAlertInstanceAcknowledgementColumns(mg)
+
+	ualert.AddRuleInstanceLimit(mg)
+
+	ualert.AddNotificationDeliveryMigrations(mg)
+
+	ualert.AddSilenceScheduleMigrations(mg)
+	ualert.AddRuleStateSnapshotMigrations(mg)
+
+	addReportingMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addReportingMigrations(mg *Migrator) {
+	reportV1 := Table{
+		Name: "report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "reply_to", Type: DB_NVarchar, Length: 190, Nullable: true},
+			{Name: "subject", Type: DB_NVarchar, Length: 255, Nullable: true},
+			{Name: "message", Type: DB_Text, Nullable: true},
+			{Name: "schedule", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 50, Nullable: true},
+			{Name: "time_from", Type: DB_NVarchar, Length: 50, Nullable: true},
+			{Name: "time_to", Type: DB_NVarchar, Length: 50, Nullable: true},
+			{Name: "variables", Type: DB_Text, Nullable: true},
+			{Name: "format", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "next_send_at", Type: DB_DateTime, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "dashboard_uid"}},
+			{Cols: []string{"enabled", "next_send_at"}},
+		},
+	}
+
+	mg.AddMigration("create report table v1", NewAddTableMigration(reportV1))
+	mg.AddMigration("add unique index report.org_id_uid", NewAddIndexMigration(reportV1, reportV1.Indices[0]))
+	mg.AddMigration("add index report.org_id_dashboard_uid", NewAddIndexMigration(reportV1, reportV1.Indices[1]))
+	mg.AddMigration("add index report.enabled_next_send_at", NewAddIndexMigration(reportV1, reportV1.Indices[2]))
+	mg.AddMigration("add column updated_by to report", NewAddColumnMigration(reportV1, &Column{
+		Name: "updated_by", Type: DB_BigInt, Nullable: true,
+	}))
+
+	reportSendV1 := Table{
+		Name: "report_send",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "report_id", Type: DB_BigInt, Nullable: false},
+			{Name: "sent_at", Type: DB_DateTime, Nullable: false},
+			{Name: "state", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "error", Type: DB_Text, Nullable: true},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "scheduled", Type: DB_Bool, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "report_id", "sent_at"}},
+			{Cols: []string{"sent_at"}},
+		},
+	}
+
+	mg.AddMigration("create report_send table v1", NewAddTableMigration(reportSendV1))
+	mg.AddMigration("add index report_send.org_id_report_id_sent_at", NewAddIndexMigration(reportSendV1, reportSendV1.Indices[0]))
+	mg.AddMigration("add index report_send.sent_at", NewAddIndexMigration(reportSendV1, reportSendV1.Indices[1]))
+}
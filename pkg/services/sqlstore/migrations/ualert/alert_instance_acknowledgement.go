@@ -0,0 +1,47 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertInstanceAcknowledgementColumns adds columns to alert_instance to store the acknowledgement of an alert instance.
+func AddAlertInstanceAcknowledgementColumns(mg *migrator.Migrator) {
+	table := migrator.Table{Name: "alert_instance"}
+
+	mg.AddMigration("add ack_by column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_by",
+		Type:     migrator.DB_NVarchar,
+		Length:   DefaultFieldMaxLength,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add ack_comment column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_comment",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add ack_at column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_at",
+		Type:     migrator.DB_BigInt, // BigInt, to match existing time fields.
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add ack_expires_at column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_expires_at",
+		Type:     migrator.DB_BigInt,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add ack_suppress_notifications column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_suppress_notifications",
+		Type:     migrator.DB_Bool,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	mg.AddMigration("add ack_silence_id column to alert_instance table", migrator.NewAddColumnMigration(table, &migrator.Column{
+		Name:     "ack_silence_id",
+		Type:     migrator.DB_NVarchar,
+		Length:   DefaultFieldMaxLength,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,21 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleDependencies adds column to store the rules an alert rule depends on.
+func AddRuleDependencies(mg *migrator.Migrator) {
+	column := &migrator.Column{
+		Name:     "dependencies",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}
+
+	mg.AddMigration(
+		"add dependencies column to alert_rule table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, column),
+	)
+	mg.AddMigration(
+		"add dependencies column to alert_rule_version table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, column),
+	)
+}
@@ -0,0 +1,22 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleInstanceLimit adds column to store the maximum number of alert instances an evaluation of an alert rule can produce.
+func AddRuleInstanceLimit(mg *migrator.Migrator) {
+	column := &migrator.Column{
+		Name:     "instance_limit",
+		Type:     migrator.DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}
+
+	mg.AddMigration(
+		"add instance_limit column to alert_rule table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, column),
+	)
+	mg.AddMigration(
+		"add instance_limit column to alert_rule_version table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, column),
+	)
+}
@@ -0,0 +1,16 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleVersionCreatedBy adds a column that stores the identity that created an alert rule version.
+func AddRuleVersionCreatedBy(mg *migrator.Migrator) {
+	mg.AddMigration(
+		"add created_by column to alert_rule_version table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+			Name:     "created_by",
+			Type:     migrator.DB_NVarchar,
+			Length:   190,
+			Nullable: true,
+		}),
+	)
+}
@@ -0,0 +1,34 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddNotificationDeliveryMigrations creates a table to store the history of notification deliveries.
+func AddNotificationDeliveryMigrations(mg *migrator.Migrator) {
+	notificationDelivery := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alert_fingerprints", Type: migrator.DB_Text, Nullable: false},
+			{Name: "firing", Type: migrator.DB_Int, Nullable: false},
+			{Name: "resolved", Type: migrator.DB_Int, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "retry", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "sent_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "sent_at"}, Type: migrator.IndexType},
+			{Cols: []string{"sent_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table", migrator.NewAddTableMigration(notificationDelivery))
+	mg.AddMigration("add index in alert_notification_delivery on org_id and sent_at columns", migrator.NewAddIndexMigration(notificationDelivery, notificationDelivery.Indices[0]))
+	mg.AddMigration("add index in alert_notification_delivery on sent_at column", migrator.NewAddIndexMigration(notificationDelivery, notificationDelivery.Indices[1]))
+}
@@ -0,0 +1,23 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleStateSnapshotMigrations creates a table to store compressed snapshots of the alert instances of each rule.
+func AddRuleStateSnapshotMigrations(mg *migrator.Migrator) {
+	ruleState := migrator.Table{
+		Name: "alert_rule_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongBlob, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_state table", migrator.NewAddTableMigration(ruleState))
+	mg.AddMigration("add unique index in alert_rule_state on org_id and rule_uid columns", migrator.NewAddIndexMigration(ruleState, ruleState.Indices[0]))
+}
@@ -0,0 +1,35 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddSilenceScheduleMigrations creates a table to store recurring silences.
+func AddSilenceScheduleMigrations(mg *migrator.Migrator) {
+	silenceSchedule := migrator.Table{
+		Name: "alert_silence_schedule",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: false},
+			{Name: "time_intervals", Type: migrator.DB_Text, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created_by_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "requires_approval_by", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "state", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "approved_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "approved_at", Type: migrator.DB_DateTime, Nullable: true},
+			{Name: "silence_id", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_silence_schedule table", migrator.NewAddTableMigration(silenceSchedule))
+	mg.AddMigration("add unique index in alert_silence_schedule on org_id and uid columns", migrator.NewAddIndexMigration(silenceSchedule, silenceSchedule.Indices[0]))
+	mg.AddMigration("add version column to alert_silence_schedule table", migrator.NewAddColumnMigration(silenceSchedule, &migrator.Column{
+		Name: "version", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+}
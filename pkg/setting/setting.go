@@ -205,6 +205,11 @@ type Cfg struct {
 	PluginsCDNURLTemplate    string
 	PluginLogBackendRequests bool
 
+	PluginRecordQueryDataPath        string
+	PluginRecordQueryDataDatasources []string
+	PluginRecordQueryDataMaxFileSize int64
+	PluginRecordQueryDataMaxFiles    int
+
 	// Panels
 	DisableSanitizeHtml bool
 
@@ -350,6 +355,7 @@ type Cfg struct {
 	ExternalSnapshotUrl  string
 	ExternalSnapshotName string
 	ExternalEnabled      bool
+	SnapshotStorageURL   string
 
 	// Only used in https://snapshots.raintank.io/
 	SnapshotPublicMode bool
@@ -458,6 +464,9 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	// Reporting
+	Reporting ReportingSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -497,6 +506,9 @@ type Cfg struct {
 
 	// Public dashboards
 	PublicDashboardsEnabled bool
+	// PublicDashboardsTrustedProxies are the addresses and IP ranges of the proxies whose X-Real-IP and
+	// X-Forwarded-For headers are trusted to find the address of the clients of public dashboards.
+	PublicDashboardsTrustedProxies []string
 
 	// Cloud Migration
 	CloudMigration CloudMigrationSettings
@@ -1262,6 +1274,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.Reporting = readReportingSettings(iniFile)
 
 	var err error
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
@@ -1884,6 +1897,7 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 
 	cfg.ExternalEnabled = snapshots.Key("external_enabled").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
+	cfg.SnapshotStorageURL = valueAsString(snapshots, "storage_url", "")
 
 	return nil
 }
@@ -2062,6 +2076,7 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 func (cfg *Cfg) readPublicDashboardsSettings() {
 	publicDashboards := cfg.Raw.Section("public_dashboards")
 	cfg.PublicDashboardsEnabled = publicDashboards.Key("enabled").MustBool(true)
+	cfg.PublicDashboardsTrustedProxies = util.SplitString(publicDashboards.Key("trusted_proxies").String())
 }
 
 func (cfg *Cfg) DefaultOrgID() int64 {
@@ -67,6 +67,12 @@ func (cfg *Cfg) readPluginSettings(iniFile *ini.File) error {
 	cfg.PluginsCDNURLTemplate = strings.TrimRight(pluginsSection.Key("cdn_base_url").MustString(""), "/")
 	cfg.PluginLogBackendRequests = pluginsSection.Key("log_backend_requests").MustBool(false)
 
+	// Recording of query data responses
+	cfg.PluginRecordQueryDataPath = pluginsSection.Key("record_query_data_path").MustString("")
+	cfg.PluginRecordQueryDataDatasources = util.SplitString(pluginsSection.Key("record_query_data_datasources").MustString(""))
+	cfg.PluginRecordQueryDataMaxFileSize = pluginsSection.Key("record_query_data_max_file_size_bytes").MustInt64(10 * 1024 * 1024)
+	cfg.PluginRecordQueryDataMaxFiles = pluginsSection.Key("record_query_data_max_files").MustInt(100)
+
 	// Installation token for managed plugins
 	cfg.PluginInstallToken = pluginsSection.Key("install_token").MustString("")
 
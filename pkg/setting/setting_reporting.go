@@ -0,0 +1,28 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type ReportingSettings struct {
+	Enabled bool
+	// PollInterval is the interval between checks for reports that are due to be sent
+	PollInterval time.Duration
+	// RenderTimeout is the timeout of rendering the dashboard of a report
+	RenderTimeout time.Duration
+	// HistoryRetention is how long the history of sent reports is kept
+	HistoryRetention time.Duration
+}
+
+func readReportingSettings(iniFile *ini.File) ReportingSettings {
+	s := ReportingSettings{}
+
+	reportingSection := iniFile.Section("reporting")
+	s.Enabled = reportingSection.Key("enabled").MustBool(true)
+	s.PollInterval = reportingSection.Key("poll_interval").MustDuration(time.Minute)
+	s.RenderTimeout = reportingSection.Key("render_timeout").MustDuration(time.Minute)
+	s.HistoryRetention = reportingSection.Key("history_retention").MustDuration(30 * 24 * time.Hour)
+	return s
+}
@@ -90,6 +90,7 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HAShardRuleEvaluation           bool
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
 	EvaluationTimeout               time.Duration
@@ -114,11 +115,23 @@ type UnifiedAlertingSettings struct {
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency   int
 	StatePeriodicSaveInterval time.Duration
-	RulesPerRuleGroupLimit    int64
+	// StateCompressedSnapshots enables saving the alert instances of each rule as a compressed snapshot every
+	// StatePeriodicSaveInterval instead of saving every alert instance to its own row.
+	StateCompressedSnapshots bool
+	RulesPerRuleGroupLimit   int64
+
+	// TemplateQueryTimeout is the total time that the queries run by the query function of annotation and label
+	// templates can take in each evaluation of a rule.
+	TemplateQueryTimeout time.Duration
+	// TemplateQueryMaxResults is the maximum number of results of a query run by the query function of templates.
+	TemplateQueryMaxResults int
 
 	// Retention period for Alertmanager notification log entries.
 	NotificationLogRetention time.Duration
 
+	// Retention period for the history of notification deliveries. Zero disables the history.
+	NotificationDeliveryRetention time.Duration
+
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedAlertRetention time.Duration
 
@@ -282,6 +295,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HAShardRuleEvaluation = ua.Key("ha_shard_rule_evaluation").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -449,12 +463,33 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	if err != nil {
 		return err
 	}
+	uaCfg.StateCompressedSnapshots = ua.Key("state_compressed_snapshots").MustBool(false)
+
+	uaCfg.TemplateQueryTimeout, err = gtime.ParseDuration(valueAsString(ua, "template_query_timeout", (10 * time.Second).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.TemplateQueryTimeout <= 0 {
+		return fmt.Errorf("setting 'template_query_timeout' is invalid, only a positive duration is allowed")
+	}
+	uaCfg.TemplateQueryMaxResults = ua.Key("template_query_max_results").MustInt(100)
+	if uaCfg.TemplateQueryMaxResults <= 0 {
+		return fmt.Errorf("setting 'template_query_max_results' is invalid, only a positive integer is allowed")
+	}
 
 	uaCfg.NotificationLogRetention, err = gtime.ParseDuration(valueAsString(ua, "notification_log_retention", (5 * 24 * time.Hour).String()))
 	if err != nil {
 		return err
 	}
 
+	uaCfg.NotificationDeliveryRetention, err = gtime.ParseDuration(valueAsString(ua, "notification_delivery_retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.NotificationDeliveryRetention < 0 {
+		return fmt.Errorf("setting 'notification_delivery_retention' is invalid, only 0 or a positive duration are allowed")
+	}
+
 	uaCfg.ResolvedAlertRetention, err = gtime.ParseDuration(valueAsString(ua, "resolved_alert_retention", (15 * time.Minute).String()))
 	if err != nil {
 		return err
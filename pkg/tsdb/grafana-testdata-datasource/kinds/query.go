@@ -65,6 +65,7 @@ const (
 	TestDataQueryTypeRandomWalkTable              TestDataQueryType = "random_walk_table"
 	TestDataQueryTypeRandomWalkWithError          TestDataQueryType = "random_walk_with_error"
 	TestDataQueryTypeRawFrame                     TestDataQueryType = "raw_frame"
+	TestDataQueryTypeReplay                       TestDataQueryType = "replay"
 	TestDataQueryTypeServerError500               TestDataQueryType = "server_error_500"
 	TestDataQueryTypeSimulation                   TestDataQueryType = "simulation"
 	TestDataQueryTypeSlowQuery                    TestDataQueryType = "slow_query"
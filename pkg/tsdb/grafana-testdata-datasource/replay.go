@@ -0,0 +1,173 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/plugins/queryrecording"
+)
+
+func (s *Service) handleReplayScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+	dir := backend.GrafanaConfigFromContext(ctx).Get(queryrecording.ReplayDirectoryConfigKey)
+
+	for _, q := range req.Queries {
+		model, err := GetJSONModel(q.JSON)
+		if err != nil {
+			return nil, err
+		}
+
+		if dir == "" {
+			resp.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, "replay directory is not configured")
+			continue
+		}
+
+		fileName := strings.TrimSpace(model.StringInput)
+		if fileName == "" {
+			continue
+		}
+
+		frames, err := loadRecordedFrames(dir, fileName, q)
+		if err != nil {
+			s.logger.FromContext(ctx).Warn("Failed to load recording", "file", fileName, "error", err)
+			resp.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to load recording %q", fileName))
+			continue
+		}
+
+		dropPercent := model.DropPercent
+		for i, frame := range frames {
+			frame.RefID = q.RefID
+			if dropPercent > 0 {
+				if frames[i], err = dropValues(frame, dropPercent); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		respD := resp.Responses[q.RefID]
+		respD.Frames = append(respD.Frames, frames...)
+		resp.Responses[q.RefID] = respD
+	}
+
+	return resp, nil
+}
+
+// loadRecordedFrames loads the frames of a recording and shifts them so that the
+// end of the recording lines up with the end of the requested time range.
+// JSON files may either contain a Recording or a bare QueryDataResponse; Arrow
+// files contain a single frame.
+func loadRecordedFrames(dir string, fileName string, q backend.DataQuery) (data.Frames, error) {
+	if !filepath.IsLocal(fileName) {
+		return nil, fmt.Errorf("invalid file name")
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var frames data.Frames
+	var recordedTo time.Time
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".arrow":
+		frame, err := data.UnmarshalArrowFrame(b)
+		if err != nil {
+			return nil, err
+		}
+		frames = data.Frames{frame}
+	case ".json":
+		rec := queryrecording.Recording{}
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, err
+		}
+		if rec.Response == nil {
+			// not a recording, fall back to a bare query data response
+			rec.Response = &backend.QueryDataResponse{}
+			if err := json.Unmarshal(b, rec.Response); err != nil {
+				return nil, err
+			}
+		}
+		frames = recordedFramesForQuery(rec.Response, q.RefID)
+		recordedTo = rec.To
+	default:
+		return nil, fmt.Errorf("unsupported file type, expected .json or .arrow")
+	}
+
+	if recordedTo.IsZero() {
+		recordedTo = latestTimestamp(frames)
+	}
+	if !recordedTo.IsZero() {
+		shiftFrames(frames, q.TimeRange.To.Sub(recordedTo))
+	}
+
+	return frames, nil
+}
+
+// recordedFramesForQuery returns the frames recorded for refID. When there is no
+// response for refID the frames of all responses are returned.
+func recordedFramesForQuery(resp *backend.QueryDataResponse, refID string) data.Frames {
+	if dr, ok := resp.Responses[refID]; ok {
+		return dr.Frames
+	}
+
+	refIDs := make([]string, 0, len(resp.Responses))
+	for id := range resp.Responses {
+		refIDs = append(refIDs, id)
+	}
+	sort.Strings(refIDs)
+
+	var frames data.Frames
+	for _, id := range refIDs {
+		frames = append(frames, resp.Responses[id].Frames...)
+	}
+	return frames
+}
+
+func latestTimestamp(frames data.Frames) time.Time {
+	var latest time.Time
+	forEachTime(frames, func(field *data.Field, idx int, t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	})
+	return latest
+}
+
+func shiftFrames(frames data.Frames, offset time.Duration) {
+	if offset == 0 {
+		return
+	}
+	forEachTime(frames, func(field *data.Field, idx int, t time.Time) {
+		field.SetConcrete(idx, t.Add(offset))
+	})
+}
+
+func forEachTime(frames data.Frames, fn func(field *data.Field, idx int, t time.Time)) {
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		for _, field := range frame.Fields {
+			if field.Type() != data.FieldTypeTime && field.Type() != data.FieldTypeNullableTime {
+				continue
+			}
+			for i := 0; i < field.Len(); i++ {
+				v, ok := field.ConcreteAt(i)
+				if !ok {
+					continue
+				}
+				fn(field, i, v.(time.Time))
+			}
+		}
+	}
+}
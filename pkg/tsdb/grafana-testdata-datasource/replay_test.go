@@ -0,0 +1,102 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/queryrecording"
+	"github.com/grafana/grafana/pkg/tsdb/grafana-testdata-datasource/kinds"
+)
+
+func TestReplayScenario(t *testing.T) {
+	s := ProvideService()
+	dir := t.TempDir()
+	ctx := backend.WithGrafanaConfig(context.Background(), backend.NewGrafanaCfg(map[string]string{
+		queryrecording.ReplayDirectoryConfigKey: dir,
+	}))
+
+	recordedFrom := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	recordedTo := recordedFrom.Add(time.Hour)
+	recordedReq := &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A", TimeRange: backend.TimeRange{From: recordedFrom, To: recordedTo}}},
+	}
+	recordedResp := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("cpu",
+			data.NewField("time", nil, []time.Time{recordedFrom.Add(30 * time.Minute), recordedTo}),
+			data.NewField("value", nil, []float64{1, 2}),
+		)}},
+	}}
+
+	b, err := json.Marshal(queryrecording.New(recordedReq, recordedResp))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "recording.json"), b, 0600))
+
+	arrow, err := recordedResp.Responses["A"].Frames[0].MarshalArrow()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "frame.arrow"), arrow, 0600))
+
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	query := func(refID string, fileName string) backend.DataQuery {
+		return backend.DataQuery{
+			RefID:     refID,
+			QueryType: string(kinds.TestDataQueryTypeReplay),
+			TimeRange: backend.TimeRange{From: to.Add(-time.Hour), To: to},
+			JSON:      []byte(`{"stringInput": "` + fileName + `"}`),
+		}
+	}
+
+	t.Run("replays a JSON recording shifted to the requested time range", func(t *testing.T) {
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{Queries: []backend.DataQuery{query("B", "recording.json")}})
+		require.NoError(t, err)
+
+		dr := resp.Responses["B"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 1)
+		require.Equal(t, "B", dr.Frames[0].RefID)
+		require.Equal(t, to.Add(-30*time.Minute), dr.Frames[0].Fields[0].At(0))
+		require.Equal(t, to, dr.Frames[0].Fields[0].At(1))
+		require.Equal(t, 2.0, dr.Frames[0].Fields[1].At(1))
+	})
+
+	t.Run("replays an Arrow frame aligned to the end of the time range", func(t *testing.T) {
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{Queries: []backend.DataQuery{query("A", "frame.arrow")}})
+		require.NoError(t, err)
+
+		dr := resp.Responses["A"]
+		require.NoError(t, dr.Error)
+		require.Len(t, dr.Frames, 1)
+		require.True(t, to.Equal(dr.Frames[0].Fields[0].At(1).(time.Time)))
+	})
+
+	t.Run("rejects files outside of the replay directory", func(t *testing.T) {
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{Queries: []backend.DataQuery{query("A", "../recording.json")}})
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+	})
+
+	t.Run("returns an error when no directory is configured", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{Queries: []backend.DataQuery{query("A", "recording.json")}})
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+	})
+}
+
+func TestRegisterScenario(t *testing.T) {
+	s := ProvideService()
+	handler := func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+		return backend.NewQueryDataResponse(), nil
+	}
+
+	require.NoError(t, s.RegisterScenario(Scenario{ID: "custom", Name: "Custom"}, handler))
+	require.Error(t, s.RegisterScenario(Scenario{ID: "custom", Name: "Custom"}, handler))
+	require.Error(t, s.RegisterScenario(Scenario{ID: kinds.TestDataQueryTypeRandomWalk}, handler))
+	require.Error(t, s.RegisterScenario(Scenario{ID: "no_handler"}, nil))
+}
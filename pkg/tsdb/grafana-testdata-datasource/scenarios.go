@@ -198,6 +198,15 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		Name: "Trace",
 	})
 
+	s.registerScenario(&Scenario{
+		ID:          kinds.TestDataQueryTypeReplay,
+		Name:        "Replay Recording",
+		StringInput: "recording.json",
+		handler:     s.handleReplayScenario,
+		Description: `Replay loads a recorded query response (.json or .arrow) from the configured replay directory.
+The String Input is the file name relative to that directory. Time fields are shifted so that the end of the recording lines up with the end of the requested time range.`,
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
@@ -206,6 +215,24 @@ func (s *Service) registerScenario(scenario *Scenario) {
 	s.queryMux.HandleFunc(string(scenario.ID), instrumentScenarioHandler(s.logger, scenario.ID, scenario.handler))
 }
 
+// RegisterScenario adds a custom scenario to the data source. It can be used to
+// extend TestData with scenarios that are not part of the built-in registry.
+func (s *Service) RegisterScenario(scenario Scenario, handler backend.QueryDataHandlerFunc) error {
+	if scenario.ID == "" {
+		return fmt.Errorf("missing scenario id")
+	}
+	if handler == nil {
+		return fmt.Errorf("invalid scenario -- missing handler: %s", scenario.ID)
+	}
+	if _, ok := s.scenarios[scenario.ID]; ok {
+		return fmt.Errorf("scenario already registered: %s", scenario.ID)
+	}
+
+	scenario.handler = handler
+	s.registerScenario(&scenario)
+	return nil
+}
+
 func instrumentScenarioHandler(logger log.Logger, scenario kinds.TestDataQueryType, fn backend.QueryDataHandlerFunc) backend.QueryDataHandlerFunc {
 	return backend.QueryDataHandlerFunc(func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 		ctx, span := tracing.DefaultTracer().Start(ctx, "testdatasource.queryData",
@@ -66,6 +66,9 @@ func NewSimulationEngine() (*SimulationEngine, error) {
 		newFlightSimInfo,
 		newSinewaveInfo,
 		newTankSimInfo,
+		newMarkovSimInfo,
+		newFaultSimInfo,
+		newLatencyHistogramSimInfo,
 	}
 
 	for _, init := range initializers {
@@ -143,10 +146,19 @@ func (s *SimulationEngine) QueryData(ctx context.Context, req *backend.QueryData
 			return nil, fmt.Errorf("invalid simulation: %v", sq)
 		}
 
+		if fs, ok := sim.(simulationWithError); ok {
+			if err := fs.QueryError(q.TimeRange.To); err != nil {
+				resp.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusInternal, err.Error())
+				continue
+			}
+		}
+
 		frame := sim.NewFrame(0)
 		if sq.Last {
 			v := sim.GetValues(q.TimeRange.To)
-			appendFrameRow(frame, v)
+			if v != nil { // nil is returned for a gap, the frame is empty
+				appendFrameRow(frame, v)
+			}
 		} else {
 			timeWalkerMs := q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 			to := q.TimeRange.To.UnixNano() / int64(time.Millisecond)
@@ -261,8 +273,10 @@ func (s *SimulationEngine) SubscribeStream(ctx context.Context, req *backend.Sub
 		return nil, err
 	}
 
-	frame := sim.NewFrame(1)
-	setFrameRow(frame, 0, sim.GetValues(time.Now()))
+	frame := sim.NewFrame(0)
+	if v := sim.GetValues(time.Now()); v != nil { // nil is returned for a gap, the initial frame is empty
+		appendFrameRow(frame, v)
+	}
 	initial, err := backend.NewInitialFrame(frame, data.IncludeAll)
 
 	return &backend.SubscribeStreamResponse{
@@ -291,7 +305,11 @@ func (s *SimulationEngine) RunStream(ctx context.Context, req *backend.RunStream
 			return ctx.Err()
 
 		case t := <-ticker.C:
-			setFrameRow(frame, 0, sim.GetValues(t))
+			v := sim.GetValues(t)
+			if v == nil { // nil is returned for a gap, nothing is sent
+				continue
+			}
+			setFrameRow(frame, 0, v)
 			err := sender.SendFrame(frame, mode)
 			if err != nil {
 				return err
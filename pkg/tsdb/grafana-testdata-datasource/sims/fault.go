@@ -0,0 +1,149 @@
+package sims
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+type faultSim struct {
+	key  simulationKey
+	cfg  faultConfig
+	prev time.Time // time of the previous requested point
+}
+
+var (
+	_ Simulation          = (*faultSim)(nil)
+	_ simulationWithError = (*faultSim)(nil)
+)
+
+type faultConfig struct {
+	Period    float64 `json:"period"`    // seconds
+	Amplitude float64 `json:"amplitude"` // Y size
+	Offset    float64 `json:"offset"`    // Y shift
+	Seed      int64   `json:"seed"`      // changes which points are affected
+
+	Error             bool    `json:"error"`             // fail every query
+	ErrorPercent      float64 `json:"errorPercent"`      // chance a query fails (0-100)
+	GapPercent        float64 `json:"gapPercent"`        // chance a point is missing (0-100)
+	DuplicatePercent  float64 `json:"duplicatePercent"`  // chance a point repeats the previous timestamp (0-100)
+	OutOfOrderPercent float64 `json:"outOfOrderPercent"` // chance a point is placed before the previous one (0-100)
+}
+
+func (s *faultSim) GetState() simulationState {
+	return simulationState{
+		Key:    s.key,
+		Config: s.cfg,
+	}
+}
+
+func (s *faultSim) SetConfig(vals map[string]any) error {
+	return updateConfigObjectFromJSON(&s.cfg, vals)
+}
+
+func (s *faultSim) NewFrame(size int) *data.Frame {
+	frame := data.NewFrameOfFieldTypes("", size,
+		data.FieldTypeTime,    // time
+		data.FieldTypeFloat64, // value
+	)
+	frame.Fields[0].Name = data.TimeSeriesTimeFieldName
+	frame.Fields[1].Name = data.TimeSeriesValueFieldName
+	return frame
+}
+
+// QueryError fails the query when errors are enabled. The outcome only depends
+// on the second the query ends in, so refreshing within that second is stable.
+func (s *faultSim) QueryError(t time.Time) error {
+	if s.cfg.Error {
+		return fmt.Errorf("simulated error")
+	}
+	if chance(s.cfg.Seed^t.Unix(), s.cfg.ErrorPercent) {
+		return fmt.Errorf("simulated error at %s", t.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (s *faultSim) GetValues(t time.Time) map[string]any {
+	prev := s.prev
+	s.prev = t
+
+	gen := rand.New(rand.NewSource(s.cfg.Seed ^ t.UnixMilli())) // consistent for the value
+	if gen.Float64()*100 < s.cfg.GapPercent {
+		return nil
+	}
+
+	v := s.cfg.Offset
+	if s.cfg.Period > 0 {
+		periodMS := s.cfg.Period * 1000
+		ms := t.UnixMilli() % int64(periodMS)
+		v += math.Sin((float64(ms)/periodMS)*2*math.Pi) * s.cfg.Amplitude
+	}
+
+	// the previous point is only meaningful when walking forward in time
+	ts := t
+	if !prev.IsZero() && prev.Before(t) {
+		switch r := gen.Float64() * 100; {
+		case r < s.cfg.DuplicatePercent:
+			ts = prev
+		case r < s.cfg.DuplicatePercent+s.cfg.OutOfOrderPercent:
+			ts = prev.Add(-t.Sub(prev))
+		}
+	}
+
+	return map[string]any{
+		data.TimeSeriesTimeFieldName:  ts,
+		data.TimeSeriesValueFieldName: v,
+	}
+}
+
+func (s *faultSim) Close() error {
+	return nil
+}
+
+// chance reports whether an event with the given percent chance happens for seed.
+func chance(seed int64, percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	return rand.New(rand.NewSource(seed)).Float64()*100 < percent
+}
+
+func newFaultSimInfo() simulationInfo {
+	fc := faultConfig{
+		Period:    60,
+		Amplitude: 1,
+	}
+
+	df := data.NewFrame("")
+	df.Fields = append(df.Fields, data.NewField("period", nil, []float64{fc.Period}).SetConfig(&data.FieldConfig{
+		Unit: "s",
+	}))
+	df.Fields = append(df.Fields, data.NewField("amplitude", nil, []float64{fc.Amplitude}))
+	df.Fields = append(df.Fields, data.NewField("offset", nil, []float64{fc.Offset}))
+	df.Fields = append(df.Fields, data.NewField("seed", nil, []int64{fc.Seed}))
+	df.Fields = append(df.Fields, data.NewField("error", nil, []bool{fc.Error}))
+	for _, name := range []string{"errorPercent", "gapPercent", "duplicatePercent", "outOfOrderPercent"} {
+		df.Fields = append(df.Fields, data.NewField(name, nil, []float64{0}).SetConfig(&data.FieldConfig{
+			Unit: "percent",
+		}))
+	}
+
+	return simulationInfo{
+		Type:         "fault",
+		Name:         "Fault injection",
+		Description:  "Waveform with injected errors, gaps, duplicated timestamps and out of order points",
+		ConfigFields: df,
+		OnlyForward:  false,
+		create: func(cfg simulationState) (Simulation, error) {
+			s := &faultSim{
+				key: cfg.Key,
+				cfg: fc, // default value
+			}
+			err := updateConfigObjectFromJSON(&s.cfg, cfg.Config) // override any fields
+			return s, err
+		},
+	}
+}
@@ -0,0 +1,127 @@
+package sims
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultSimulation(t *testing.T) {
+	s, err := NewSimulationEngine()
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	queryLast := func(uid string, config map[string]any, last bool) backend.DataResponse {
+		sq := &simulationQuery{Last: last}
+		sq.Key = simulationKey{Type: "fault", TickHZ: 1, UID: uid}
+		sq.Config = config
+		sb, err := json.Marshal(map[string]any{"sim": sq})
+		require.NoError(t, err)
+
+		rsp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     backend.TimeRange{From: start, To: start.Add(100 * time.Second)},
+				Interval:      time.Second,
+				MaxDataPoints: 100,
+				JSON:          sb,
+			}},
+		})
+		require.NoError(t, err)
+		return rsp.Responses["A"]
+	}
+	query := func(uid string, config map[string]any) backend.DataResponse {
+		return queryLast(uid, config, false)
+	}
+
+	t.Run("no faults by default", func(t *testing.T) {
+		dr := query("none", nil)
+		require.NoError(t, dr.Error)
+		require.Equal(t, 100, dr.Frames[0].Rows())
+	})
+
+	t.Run("error fails the query", func(t *testing.T) {
+		dr := query("error", map[string]any{"error": true})
+		require.Error(t, dr.Error)
+		require.Empty(t, dr.Frames)
+	})
+
+	t.Run("gaps drop points", func(t *testing.T) {
+		dr := query("gaps", map[string]any{"gapPercent": 50})
+		require.NoError(t, dr.Error)
+		require.Less(t, dr.Frames[0].Rows(), 100)
+		require.Greater(t, dr.Frames[0].Rows(), 0)
+	})
+
+	t.Run("gaps return an empty frame for the last value", func(t *testing.T) {
+		dr := queryLast("last-gap", map[string]any{"gapPercent": 100}, true)
+		require.NoError(t, dr.Error)
+		require.Equal(t, 0, dr.Frames[0].Rows())
+
+		dr = queryLast("last", nil, true)
+		require.NoError(t, dr.Error)
+		require.Equal(t, 1, dr.Frames[0].Rows())
+	})
+
+	t.Run("gaps are not streamed", func(t *testing.T) {
+		stream := func(uid string, config map[string]any) (*backend.SubscribeStreamResponse, int) {
+			sim, err := s.Lookup(simulationState{Key: simulationKey{Type: "fault", TickHZ: 20, UID: uid}, Config: config})
+			require.NoError(t, err)
+			path := "sim/" + sim.GetState().Key.String()
+
+			sub, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: path})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			sender := &countingPacketSender{}
+			err = s.RunStream(ctx, &backend.RunStreamRequest{Path: path}, backend.NewStreamSender(sender))
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			return sub, sender.count
+		}
+
+		sub, sent := stream("stream-gap", map[string]any{"gapPercent": 100})
+		frame := &data.Frame{}
+		require.NoError(t, frame.UnmarshalJSON(sub.InitialData.Data()))
+		require.Equal(t, 0, frame.Rows())
+		require.Equal(t, 0, sent)
+
+		sub, sent = stream("stream", nil)
+		frame = &data.Frame{}
+		require.NoError(t, frame.UnmarshalJSON(sub.InitialData.Data()))
+		require.Equal(t, 1, frame.Rows())
+		require.Greater(t, sent, 0)
+	})
+
+	t.Run("duplicates and out of order points", func(t *testing.T) {
+		dr := query("order", map[string]any{"duplicatePercent": 20, "outOfOrderPercent": 20})
+		require.NoError(t, dr.Error)
+
+		times := dr.Frames[0].Fields[0]
+		duplicates, outOfOrder := 0, 0
+		for i := 1; i < times.Len(); i++ {
+			prev, cur := times.At(i-1).(time.Time), times.At(i).(time.Time)
+			if cur.Equal(prev) {
+				duplicates++
+			} else if cur.Before(prev) {
+				outOfOrder++
+			}
+		}
+		require.Greater(t, duplicates, 0)
+		require.Greater(t, outOfOrder, 0)
+	})
+}
+
+type countingPacketSender struct {
+	count int
+}
+
+func (s *countingPacketSender) Send(*backend.StreamPacket) error {
+	s.count++
+	return nil
+}
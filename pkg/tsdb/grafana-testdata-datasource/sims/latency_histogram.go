@@ -0,0 +1,168 @@
+package sims
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+type latencyHistogramSim struct {
+	key simulationKey
+	cfg latencyHistogramConfig
+}
+
+var (
+	_ Simulation = (*latencyHistogramSim)(nil)
+)
+
+type latencyHistogramConfig struct {
+	Buckets []float64 `json:"buckets"` // upper bounds in seconds, +Inf is always added
+	Rate    float64   `json:"rate"`    // requests per point
+	Median  float64   `json:"median"`  // median latency in seconds
+	Spread  float64   `json:"spread"`  // standard deviation of the log of the latency
+	Noise   float64   `json:"noise"`   // random noise added to each bucket (0-1)
+}
+
+func (cfg latencyHistogramConfig) validate() error {
+	if len(cfg.Buckets) == 0 {
+		return fmt.Errorf("at least one bucket is required")
+	}
+	if !slices.IsSorted(cfg.Buckets) {
+		return fmt.Errorf("buckets must be sorted")
+	}
+	if cfg.Median <= 0 {
+		return fmt.Errorf("median must be greater than 0")
+	}
+	if cfg.Spread <= 0 {
+		return fmt.Errorf("spread must be greater than 0")
+	}
+	return nil
+}
+
+func (s *latencyHistogramSim) GetState() simulationState {
+	return simulationState{
+		Key:    s.key,
+		Config: s.cfg,
+	}
+}
+
+func (s *latencyHistogramSim) SetConfig(vals map[string]any) error {
+	next := s.cfg
+	next.Buckets = slices.Clone(s.cfg.Buckets)
+	if err := updateConfigObjectFromJSON(&next, vals); err != nil {
+		return err
+	}
+	if err := next.validate(); err != nil {
+		return err
+	}
+	s.cfg = next
+	return nil
+}
+
+func (s *latencyHistogramSim) bucketNames() []string {
+	names := make([]string, 0, len(s.cfg.Buckets)+1)
+	for _, b := range s.cfg.Buckets {
+		names = append(names, strconv.FormatFloat(b, 'f', -1, 64))
+	}
+	return append(names, "+Inf")
+}
+
+func (s *latencyHistogramSim) NewFrame(size int) *data.Frame {
+	names := s.bucketNames()
+	frame := data.NewFrameOfFieldTypes("", size, data.FieldTypeTime)
+	frame.Fields[0].Name = "time"
+	for _, name := range names {
+		f := data.NewFieldFromFieldType(data.FieldTypeFloat64, size)
+		f.Name = name
+		frame.Fields = append(frame.Fields, f)
+	}
+	frame.Meta = &data.FrameMeta{
+		Type: "heatmap-rows",
+		Custom: map[string]any{
+			"yMatchWithLabel": "le",
+		},
+	}
+	return frame
+}
+
+// GetValues returns the number of requests that fall into each bucket, assuming
+// log-normal distributed latencies.
+func (s *latencyHistogramSim) GetValues(t time.Time) map[string]any {
+	gen := rand.New(rand.NewSource(t.UnixMilli())) // consistent for the value
+	names := s.bucketNames()
+
+	values := make(map[string]any, len(names)+1)
+	values["time"] = t
+
+	mu := math.Log(s.cfg.Median)
+	lower := 0.0
+	for i, name := range names {
+		upper := 1.0
+		if i < len(s.cfg.Buckets) {
+			upper = logNormalCDF(s.cfg.Buckets[i], mu, s.cfg.Spread)
+		}
+
+		count := (upper - lower) * s.cfg.Rate
+		if s.cfg.Noise > 0 {
+			count *= 1 + (gen.Float64()*2-1)*s.cfg.Noise
+		}
+		values[name] = math.Max(0, math.Round(count))
+		lower = upper
+	}
+
+	return values
+}
+
+func (s *latencyHistogramSim) Close() error {
+	return nil
+}
+
+func logNormalCDF(x, mu, sigma float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return 0.5 * math.Erfc(-(math.Log(x)-mu)/(sigma*math.Sqrt2))
+}
+
+func newLatencyHistogramSimInfo() simulationInfo {
+	lc := latencyHistogramConfig{
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		Rate:    1000,
+		Median:  0.1,
+		Spread:  1,
+		Noise:   0.1,
+	}
+
+	df := data.NewFrame("")
+	df.Fields = append(df.Fields, data.NewField("rate", nil, []float64{lc.Rate}))
+	df.Fields = append(df.Fields, data.NewField("median", nil, []float64{lc.Median}).SetConfig(&data.FieldConfig{
+		Unit: "s",
+	}))
+	df.Fields = append(df.Fields, data.NewField("spread", nil, []float64{lc.Spread}))
+	df.Fields = append(df.Fields, data.NewField("noise", nil, []float64{lc.Noise}))
+
+	return simulationInfo{
+		Type:         "latency_histogram",
+		Name:         "Latency histogram",
+		Description:  "Request latency histogram buckets",
+		ConfigFields: df,
+		OnlyForward:  false,
+		create: func(cfg simulationState) (Simulation, error) {
+			s := &latencyHistogramSim{
+				key: cfg.Key,
+				cfg: lc, // default value
+			}
+			s.cfg.Buckets = slices.Clone(lc.Buckets)
+			err := updateConfigObjectFromJSON(&s.cfg, cfg.Config) // override any fields
+			if err == nil {
+				err = s.cfg.validate()
+			}
+			return s, err
+		},
+	}
+}
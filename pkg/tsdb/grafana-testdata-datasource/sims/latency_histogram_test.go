@@ -0,0 +1,35 @@
+package sims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogramSimulation(t *testing.T) {
+	s, err := NewSimulationEngine()
+	require.NoError(t, err)
+
+	sim, err := s.Lookup(simulationState{
+		Key: simulationKey{Type: "latency_histogram", TickHZ: 1},
+		Config: map[string]any{
+			"buckets": []float64{0.1, 1},
+			"median":  0.1,
+			"rate":    100,
+			"noise":   0,
+		},
+	})
+	require.NoError(t, err)
+
+	frame := sim.NewFrame(0)
+	require.Equal(t, data.FrameType("heatmap-rows"), frame.Meta.Type)
+	require.Equal(t, []string{"time", "0.1", "1", "+Inf"}, []string{frame.Fields[0].Name, frame.Fields[1].Name, frame.Fields[2].Name, frame.Fields[3].Name})
+
+	vals := sim.GetValues(time.Now())
+	require.Equal(t, 50.0, vals["0.1"]) // half of the requests are below the median
+	require.Equal(t, 100.0, vals["0.1"].(float64)+vals["1"].(float64)+vals["+Inf"].(float64))
+
+	require.Error(t, sim.SetConfig(map[string]any{"buckets": []float64{1, 0.1}}))
+}
@@ -0,0 +1,212 @@
+package sims
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// markovBlockSize is the number of steps walked from a fixed starting point.
+// Walking in blocks keeps the state for a given time deterministic without
+// replaying the chain from the epoch.
+const markovBlockSize = 1024
+
+type markovSim struct {
+	key   simulationKey
+	cfg   markovConfig
+	state markovState
+}
+
+var (
+	_ Simulation = (*markovSim)(nil)
+)
+
+type markovConfig struct {
+	Interval    float64     `json:"interval"`    // seconds between transitions
+	Seed        int64       `json:"seed"`        // changes the generated sequence
+	States      []string    `json:"states"`      // state names, the first one is the initial state
+	Values      []float64   `json:"values"`      // numeric value reported for each state
+	Transitions [][]float64 `json:"transitions"` // row i holds the weight of moving from state i to each state
+}
+
+type markovState struct {
+	Step  int64
+	State int
+	Valid bool
+}
+
+// clone returns a deep copy so updating the config never writes to shared slices.
+func (cfg markovConfig) clone() markovConfig {
+	c := cfg
+	c.States = slices.Clone(cfg.States)
+	c.Values = slices.Clone(cfg.Values)
+	c.Transitions = make([][]float64, len(cfg.Transitions))
+	for i, row := range cfg.Transitions {
+		c.Transitions[i] = slices.Clone(row)
+	}
+	return c
+}
+
+func (cfg markovConfig) validate() error {
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if len(cfg.States) == 0 {
+		return fmt.Errorf("at least one state is required")
+	}
+	if len(cfg.Values) != len(cfg.States) {
+		return fmt.Errorf("expected %d values, got %d", len(cfg.States), len(cfg.Values))
+	}
+	if len(cfg.Transitions) != len(cfg.States) {
+		return fmt.Errorf("expected %d transition rows, got %d", len(cfg.States), len(cfg.Transitions))
+	}
+	for i, row := range cfg.Transitions {
+		if len(row) != len(cfg.States) {
+			return fmt.Errorf("transition row %d: expected %d weights, got %d", i, len(cfg.States), len(row))
+		}
+		total := 0.0
+		for _, w := range row {
+			if w < 0 {
+				return fmt.Errorf("transition row %d: weights must not be negative", i)
+			}
+			total += w
+		}
+		if total <= 0 {
+			return fmt.Errorf("transition row %d: weights must not all be zero", i)
+		}
+	}
+	return nil
+}
+
+func (s *markovSim) GetState() simulationState {
+	return simulationState{
+		Key:    s.key,
+		Config: s.cfg,
+	}
+}
+
+func (s *markovSim) SetConfig(vals map[string]any) error {
+	next := s.cfg.clone()
+	if err := updateConfigObjectFromJSON(&next, vals); err != nil {
+		return err
+	}
+	if err := next.validate(); err != nil {
+		return err
+	}
+	s.cfg = next
+	s.state = markovState{}
+	return nil
+}
+
+func (s *markovSim) NewFrame(size int) *data.Frame {
+	frame := data.NewFrameOfFieldTypes("", size,
+		data.FieldTypeTime,    // time
+		data.FieldTypeString,  // state
+		data.FieldTypeFloat64, // value
+	)
+	frame.Fields[0].Name = "time"
+	frame.Fields[1].Name = "state"
+	frame.Fields[2].Name = "value"
+	return frame
+}
+
+func (s *markovSim) GetValues(t time.Time) map[string]any {
+	intervalMs := int64(s.cfg.Interval * 1000)
+	if intervalMs <= 0 {
+		intervalMs = 1
+	}
+	state := s.stateAt(t.UnixMilli() / intervalMs)
+
+	return map[string]any{
+		"time":  t,
+		"state": s.cfg.States[state],
+		"value": s.cfg.Values[state],
+	}
+}
+
+// stateAt returns the state at the given step. The chain restarts in the initial
+// state at the beginning of every block, the last computed state is reused when
+// walking forward within the same block.
+func (s *markovSim) stateAt(step int64) int {
+	blockStart := step - (step % markovBlockSize)
+	if step < 0 && step%markovBlockSize != 0 {
+		blockStart -= markovBlockSize
+	}
+
+	current := markovState{Step: blockStart, State: 0}
+	if s.state.Valid && s.state.Step >= blockStart && s.state.Step <= step {
+		current = s.state
+	}
+
+	for current.Step < step {
+		current.Step++
+		current.State = s.next(current.State, current.Step)
+	}
+
+	current.Valid = true
+	s.state = current
+	return current.State
+}
+
+func (s *markovSim) next(state int, step int64) int {
+	row := s.cfg.Transitions[state]
+	total := 0.0
+	for _, w := range row {
+		total += w
+	}
+
+	gen := rand.New(rand.NewSource(s.cfg.Seed ^ step)) // consistent for the step
+	r := gen.Float64() * total
+	for i, w := range row {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return state
+}
+
+func (s *markovSim) Close() error {
+	return nil
+}
+
+func newMarkovSimInfo() simulationInfo {
+	mc := markovConfig{
+		Interval: 10,
+		States:   []string{"up", "degraded", "down"},
+		Values:   []float64{1, 0.5, 0},
+		Transitions: [][]float64{
+			{0.95, 0.04, 0.01}, // up
+			{0.30, 0.60, 0.10}, // degraded
+			{0.20, 0.10, 0.70}, // down
+		},
+	}
+
+	df := data.NewFrame("")
+	df.Fields = append(df.Fields, data.NewField("interval", nil, []float64{mc.Interval}).SetConfig(&data.FieldConfig{
+		Unit: "s",
+	}))
+	df.Fields = append(df.Fields, data.NewField("seed", nil, []int64{mc.Seed}))
+
+	return simulationInfo{
+		Type:         "markov",
+		Name:         "Markov state machine",
+		Description:  "Service state driven by a configurable Markov chain",
+		ConfigFields: df,
+		OnlyForward:  false,
+		create: func(cfg simulationState) (Simulation, error) {
+			s := &markovSim{
+				key: cfg.Key,
+				cfg: mc.clone(), // default value
+			}
+			err := updateConfigObjectFromJSON(&s.cfg, cfg.Config) // override any fields
+			if err == nil {
+				err = s.cfg.validate()
+			}
+			return s, err
+		},
+	}
+}
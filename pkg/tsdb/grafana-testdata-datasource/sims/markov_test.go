@@ -0,0 +1,59 @@
+package sims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkovSimulation(t *testing.T) {
+	s, err := NewSimulationEngine()
+	require.NoError(t, err)
+
+	lookup := func(uid string, config map[string]any) Simulation {
+		sim, err := s.Lookup(simulationState{
+			Key:    simulationKey{Type: "markov", TickHZ: 1, UID: uid},
+			Config: config,
+		})
+		require.NoError(t, err)
+		return sim
+	}
+
+	t.Run("states are deterministic regardless of query order", func(t *testing.T) {
+		start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		forward := lookup("a", map[string]any{"interval": 1})
+		states := make([]any, 0, 300)
+		for i := 0; i < 300; i++ {
+			states = append(states, forward.GetValues(start.Add(time.Duration(i) * time.Second))["state"])
+		}
+		require.Contains(t, states, "up")
+
+		other := lookup("b", map[string]any{"interval": 1})
+		for i := 299; i >= 0; i -= 7 {
+			require.Equal(t, states[i], other.GetValues(start.Add(time.Duration(i) * time.Second))["state"])
+		}
+	})
+
+	t.Run("only reaches allowed states", func(t *testing.T) {
+		sim := lookup("c", map[string]any{
+			"interval":    1,
+			"states":      []string{"ok", "broken"},
+			"values":      []float64{1, 0},
+			"transitions": [][]float64{{0, 1}, {0, 1}},
+		})
+		start := time.Date(2024, time.January, 1, 0, 0, 1, 0, time.UTC)
+		for i := 0; i < 10; i++ {
+			vals := sim.GetValues(start.Add(time.Duration(i) * time.Second))
+			require.Equal(t, "broken", vals["state"])
+			require.Equal(t, 0.0, vals["value"])
+		}
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		sim := lookup("d", nil)
+		require.Error(t, sim.SetConfig(map[string]any{"transitions": [][]float64{{1}}}))
+		require.Error(t, sim.SetConfig(map[string]any{"interval": 0}))
+		require.Equal(t, []string{"up", "degraded", "down"}, sim.GetState().Config.(markovConfig).States)
+	})
+}
@@ -49,3 +49,9 @@ type Simulation interface {
 	NewFrame(size int) *data.Frame
 	GetValues(t time.Time) map[string]any
 }
+
+// simulationWithError is implemented by simulations that can fail a query
+type simulationWithError interface {
+	// QueryError returns the error for a query ending at t, or nil
+	QueryError(t time.Time) error
+}
@@ -0,0 +1,435 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+const (
+	// defaultStateHistoryLimit is the maximum number of state transitions returned when the query does not set a limit
+	defaultStateHistoryLimit = 1000
+	// stateHistoryConcurrency is the number of rules whose state history is read at the same time
+	stateHistoryConcurrency = 4
+
+	stateHistoryFormatTable      = "table"
+	stateHistoryFormatTimeSeries = "timeseries"
+)
+
+var errAlertingUnavailable = errors.New("alerting is not available")
+
+// AlertRuleStore is the subset of the alerting store used to read alert rules.
+type AlertRuleStore interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
+// AlertStateReader reads the current state of alert instances from the state manager of the scheduler.
+// When rule evaluation is sharded between instances in high availability mode, it only has the states
+// of the rules evaluated by this instance.
+type AlertStateReader interface {
+	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State
+}
+
+// SetAlertStateReader sets the reader of the current state of alert instances. The alerting service sets it
+// once its state manager is created, which happens after the data source is created.
+func (s *Service) SetAlertStateReader(reader AlertStateReader) {
+	s.stateReaderMtx.Lock()
+	defer s.stateReaderMtx.Unlock()
+	s.stateReader = reader
+}
+
+func (s *Service) alertStateReader() AlertStateReader {
+	s.stateReaderMtx.RLock()
+	defer s.stateReaderMtx.RUnlock()
+	return s.stateReader
+}
+
+// StateHistoryReader reads the state history of alert rules from the configured state history backend.
+type StateHistoryReader interface {
+	Query(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
+}
+
+// SetStateHistoryReader sets the reader of the state history. The alerting service sets it once the state history
+// backend is configured, which happens after the data source is created.
+func (s *Service) SetStateHistoryReader(reader StateHistoryReader) {
+	s.stateReaderMtx.Lock()
+	defer s.stateReaderMtx.Unlock()
+	s.historyReader = reader
+}
+
+func (s *Service) stateHistoryReader() StateHistoryReader {
+	s.stateReaderMtx.RLock()
+	defer s.stateReaderMtx.RUnlock()
+	return s.historyReader
+}
+
+type ruleAccessControlService interface {
+	HasAccessInFolder(ctx context.Context, user identity.Requester, rule ngmodels.Namespaced) (bool, error)
+}
+
+// readableRules returns the alert rules matching the query that the user is allowed to read.
+func (s *Service) readableRules(ctx context.Context, requester identity.Requester, orgID int64, q alertRulesFilter) ([]*ngmodels.AlertRule, error) {
+	if s.ruleStore == nil || s.ruleAccess == nil {
+		return nil, errAlertingUnavailable
+	}
+
+	rules, err := s.ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		RuleUIDs:      q.RuleUIDs,
+		NamespaceUIDs: q.FolderUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	canRead := make(map[string]bool)
+	result := make([]*ngmodels.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		ok, checked := canRead[rule.NamespaceUID]
+		if !checked {
+			ok, err = s.ruleAccess.HasAccessInFolder(ctx, requester, rule)
+			if err != nil {
+				return nil, err
+			}
+			canRead[rule.NamespaceUID] = ok
+		}
+		if ok {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) doAlertInstancesQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &alertInstancesQueryModel{}
+	if err := json.Unmarshal(query.JSON, q); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	requester, err := identity.GetRequester(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "missing user")
+	}
+
+	rules, err := s.readableRules(ctx, requester, req.PluginContext.OrgID, q.alertRulesFilter)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	reader := s.alertStateReader()
+	if reader == nil {
+		return backend.DataResponse{Error: errAlertingUnavailable}
+	}
+
+	frame := data.NewFrame("alertInstances",
+		data.NewField("ruleUID", nil, []string{}),
+		data.NewField("ruleTitle", nil, []string{}),
+		data.NewField("folderUID", nil, []string{}),
+		data.NewField("group", nil, []string{}),
+		data.NewField("state", nil, []string{}),
+		data.NewField("reason", nil, []string{}),
+		data.NewField("labels", nil, []json.RawMessage{}),
+		data.NewField("stateSince", nil, []time.Time{}),
+		data.NewField("lastEvaluation", nil, []time.Time{}),
+	)
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTable}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].UID < rules[j].UID
+	})
+
+	for _, rule := range rules {
+		states := reader.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		sort.Slice(states, func(i, j int) bool {
+			return states[i].Labels.String() < states[j].Labels.String()
+		})
+
+		for _, st := range states {
+			if len(q.States) > 0 && !slices.Contains(q.States, st.State.String()) {
+				continue
+			}
+
+			labels, err := json.Marshal(st.Labels)
+			if err != nil {
+				return backend.DataResponse{Error: err}
+			}
+
+			frame.AppendRow(
+				rule.UID,
+				rule.Title,
+				rule.NamespaceUID,
+				rule.RuleGroup,
+				st.State.String(),
+				st.StateReason,
+				json.RawMessage(labels),
+				st.StartsAt,
+				st.LastEvaluationTime,
+			)
+		}
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func (s *Service) doStateHistoryQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &stateHistoryQueryModel{}
+	if err := json.Unmarshal(query.JSON, q); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	if q.Format == "" {
+		q.Format = stateHistoryFormatTable
+	}
+	if q.Format != stateHistoryFormatTable && q.Format != stateHistoryFormatTimeSeries {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("unsupported format %q", q.Format))
+	}
+
+	requester, err := identity.GetRequester(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "missing user")
+	}
+
+	rules, err := s.readableRules(ctx, requester, req.PluginContext.OrgID, q.alertRulesFilter)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	reader := s.stateHistoryReader()
+	if reader == nil {
+		return backend.DataResponse{Error: errAlertingUnavailable}
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultStateHistoryLimit
+	}
+
+	// The history is read per rule, so that the limit applies to the transitions of the requested rules
+	results := make([][]ruleStateTransition, len(rules))
+	err = concurrency.ForEachJob(ctx, len(rules), stateHistoryConcurrency, func(ctx context.Context, i int) error {
+		frame, err := reader.Query(ctx, ngmodels.HistoryQuery{
+			RuleUID:      rules[i].UID,
+			OrgID:        req.PluginContext.OrgID,
+			From:         query.TimeRange.From,
+			To:           query.TimeRange.To,
+			Limit:        int(limit),
+			SignedInUser: requester,
+		})
+		if err != nil {
+			return err
+		}
+		transitions, err := stateTransitions(frame)
+		if err != nil {
+			return err
+		}
+		for _, transition := range transitions {
+			results[i] = append(results[i], ruleStateTransition{rule: rules[i], stateTransition: transition})
+		}
+		return nil
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	transitions := make([]ruleStateTransition, 0)
+	for _, result := range results {
+		transitions = append(transitions, result...)
+	}
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].time.Before(transitions[j].time)
+	})
+	// keep the newest transitions
+	if int64(len(transitions)) > limit {
+		transitions = transitions[int64(len(transitions))-limit:]
+	}
+
+	if q.Format == stateHistoryFormatTimeSeries {
+		return backend.DataResponse{Frames: stateHistoryTimeSeries(transitions)}
+	}
+
+	frame := data.NewFrame("stateHistory",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("ruleUID", nil, []string{}),
+		data.NewField("ruleTitle", nil, []string{}),
+		data.NewField("previous", nil, []string{}),
+		data.NewField("current", nil, []string{}),
+		data.NewField("text", nil, []string{}),
+	)
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTable}
+
+	for _, t := range transitions {
+		frame.AppendRow(t.time.UTC(), t.rule.UID, t.rule.Title, t.previous, t.current, t.text)
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// stateTransition is a state transition of an alert instance read from the state history.
+type stateTransition struct {
+	time     time.Time
+	previous string
+	current  string
+	text     string
+}
+
+type ruleStateTransition struct {
+	rule *ngmodels.AlertRule
+	stateTransition
+}
+
+// stateTransitions reads the state transitions from a frame of the state history. The annotations backend returns
+// the fields time, text, prev and next, and the Loki backend returns the fields time and line with the transition as JSON.
+func stateTransitions(frame *data.Frame) ([]stateTransition, error) {
+	if frame == nil {
+		return nil, nil
+	}
+	times, _ := frame.FieldByName("time")
+	if times == nil {
+		return nil, nil
+	}
+
+	result := make([]stateTransition, 0, times.Len())
+	if lines, _ := frame.FieldByName("line"); lines != nil {
+		for i := 0; i < lines.Len(); i++ {
+			var entry historian.LokiEntry
+			if err := json.Unmarshal(lines.At(i).(json.RawMessage), &entry); err != nil {
+				return nil, fmt.Errorf("failed to read state history entry: %w", err)
+			}
+			result = append(result, stateTransition{
+				time:     times.At(i).(time.Time),
+				previous: entry.Previous,
+				current:  entry.Current,
+				text:     fmt.Sprintf("{%s}", data.Labels(entry.InstanceLabels).String()),
+			})
+		}
+		return result, nil
+	}
+
+	texts, _ := frame.FieldByName("text")
+	prev, _ := frame.FieldByName("prev")
+	next, _ := frame.FieldByName("next")
+	if texts == nil || prev == nil || next == nil {
+		return nil, fmt.Errorf("unsupported state history format")
+	}
+	for i := 0; i < times.Len(); i++ {
+		result = append(result, stateTransition{
+			time:     times.At(i).(time.Time),
+			previous: prev.At(i).(string),
+			current:  next.At(i).(string),
+			text:     texts.At(i).(string),
+		})
+	}
+	return result, nil
+}
+
+// stateHistoryTimeSeries returns a series per rule with a value of 1 for every state transition,
+// which can be counted by server side expressions to alert on flapping rules.
+func stateHistoryTimeSeries(transitions []ruleStateTransition) data.Frames {
+	frames := make(map[string]*data.Frame)
+	uids := make([]string, 0)
+
+	for _, t := range transitions {
+		frame, ok := frames[t.rule.UID]
+		if !ok {
+			frame = data.NewFrame("stateHistory",
+				data.NewField(data.TimeSeriesTimeFieldName, nil, []time.Time{}),
+				data.NewField(data.TimeSeriesValueFieldName, data.Labels{"ruleUID": t.rule.UID, "ruleTitle": t.rule.Title}, []float64{}),
+			)
+			frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}}
+			frames[t.rule.UID] = frame
+			uids = append(uids, t.rule.UID)
+		}
+		frame.AppendRow(t.time.UTC(), 1.0)
+	}
+
+	result := make(data.Frames, 0, len(uids))
+	for _, uid := range uids {
+		result = append(result, frames[uid])
+	}
+	return result
+}
+
+func (s *Service) doAnnotationsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &annotationsQueryModel{}
+	if err := json.Unmarshal(query.JSON, q); err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	if s.annotations == nil {
+		return backend.DataResponse{Error: errors.New("annotations are not available")}
+	}
+
+	requester, err := identity.GetRequester(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "missing user")
+	}
+
+	items, err := s.annotations.Find(ctx, &annotations.ItemQuery{
+		OrgID:        req.PluginContext.OrgID,
+		From:         query.TimeRange.From.UnixMilli(),
+		To:           query.TimeRange.To.UnixMilli(),
+		DashboardUID: q.DashboardUID,
+		Tags:         q.Tags,
+		MatchAny:     q.MatchAny,
+		Type:         q.Type,
+		Limit:        q.Limit,
+		SignedInUser: requester,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("timeEnd", nil, []time.Time{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("tags", nil, []json.RawMessage{}),
+		data.NewField("dashboardUID", nil, []string{}),
+		data.NewField("panelId", nil, []int64{}),
+		data.NewField("id", nil, []int64{}),
+	)
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTable}
+
+	for _, item := range items {
+		tags, err := json.Marshal(item.Tags)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+
+		dashboardUID := ""
+		if item.DashboardUID != nil {
+			dashboardUID = *item.DashboardUID
+		}
+
+		timeEnd := item.TimeEnd
+		if timeEnd == 0 {
+			timeEnd = item.Time
+		}
+
+		frame.AppendRow(
+			time.UnixMilli(item.Time).UTC(),
+			time.UnixMilli(timeEnd).UTC(),
+			item.Text,
+			json.RawMessage(tags),
+			dashboardUID,
+			item.PanelID,
+			item.ID,
+		)
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
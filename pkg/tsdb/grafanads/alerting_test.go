@@ -0,0 +1,206 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeAlertRuleStore struct {
+	rules ngmodels.RulesGroup
+}
+
+func (f *fakeAlertRuleStore) ListAlertRules(_ context.Context, _ *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error) {
+	return f.rules, nil
+}
+
+type fakeAlertStateReader map[string][]*state.State
+
+func (f fakeAlertStateReader) GetStatesForRuleUID(_ int64, alertRuleUID string) []*state.State {
+	return f[alertRuleUID]
+}
+
+func TestAlertInstancesQuery(t *testing.T) {
+	store := &fakeAlertRuleStore{
+		rules: ngmodels.RulesGroup{
+			{ID: 1, OrgID: 1, UID: "rule-1", Title: "Allowed", NamespaceUID: "folder-1", RuleGroup: "group"},
+			{ID: 2, OrgID: 1, UID: "rule-2", Title: "Hidden", NamespaceUID: "folder-2", RuleGroup: "group"},
+		},
+	}
+	states := fakeAlertStateReader{
+		"rule-1": {
+			{AlertRuleUID: "rule-1", OrgID: 1, State: eval.Normal, Labels: data.Labels{"job": "worker"}},
+			{AlertRuleUID: "rule-1", OrgID: 1, State: eval.Alerting, Labels: data.Labels{"job": "api"}},
+		},
+		"rule-2": {
+			{AlertRuleUID: "rule-2", OrgID: 1, State: eval.Alerting},
+		},
+	}
+	s := newService(nil, nil, nil, nil, store, nil, acimpl.ProvideAccessControlTest())
+
+	folderScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder-1")
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{1: {
+			accesscontrol.ActionAlertingRuleRead: {folderScope},
+			dashboards.ActionFoldersRead:         {folderScope},
+		}},
+	})
+	req := &backend.QueryDataRequest{PluginContext: backend.PluginContext{OrgID: 1}}
+
+	t.Run("requires the state of alerting", func(t *testing.T) {
+		res := s.doAlertInstancesQuery(ctx, req, backend.DataQuery{JSON: []byte(`{}`)})
+		require.ErrorIs(t, res.Error, errAlertingUnavailable)
+	})
+
+	s.SetAlertStateReader(states)
+
+	t.Run("returns instances of readable rules", func(t *testing.T) {
+		res := s.doAlertInstancesQuery(ctx, req, backend.DataQuery{JSON: []byte(`{}`)})
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, 2, res.Frames[0].Rows())
+		require.Equal(t, "rule-1", res.Frames[0].Fields[0].At(0))
+		require.Equal(t, "Alerting", res.Frames[0].Fields[4].At(0))
+	})
+
+	t.Run("filters by state", func(t *testing.T) {
+		res := s.doAlertInstancesQuery(ctx, req, backend.DataQuery{JSON: []byte(`{"states": ["Normal"]}`)})
+		require.NoError(t, res.Error)
+		require.Equal(t, 1, res.Frames[0].Rows())
+	})
+
+	t.Run("requires a user", func(t *testing.T) {
+		res := s.doAlertInstancesQuery(context.Background(), req, backend.DataQuery{JSON: []byte(`{}`)})
+		require.Error(t, res.Error)
+	})
+}
+
+type fakeStateHistoryReader struct {
+	frames  map[string]*data.Frame
+	queries []ngmodels.HistoryQuery
+	mtx     sync.Mutex
+}
+
+func (f *fakeStateHistoryReader) Query(_ context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.queries = append(f.queries, query)
+	return f.frames[query.RuleUID], nil
+}
+
+// annotationHistoryFrame returns a frame in the format of the annotations state history backend.
+func annotationHistoryFrame(times ...time.Time) *data.Frame {
+	frame := data.NewFrame("states",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("prev", nil, []string{}),
+		data.NewField("next", nil, []string{}),
+		data.NewField("data", nil, []string{}),
+	)
+	for _, t := range times {
+		frame.AppendRow(t, "{job=api}", "Normal", "Alerting", "{}")
+	}
+	return frame
+}
+
+func TestStateHistoryQuery(t *testing.T) {
+	store := &fakeAlertRuleStore{
+		rules: ngmodels.RulesGroup{
+			{ID: 1, OrgID: 1, UID: "rule-1", Title: "Allowed", NamespaceUID: "folder-1"},
+			{ID: 2, OrgID: 1, UID: "rule-2", Title: "Hidden", NamespaceUID: "folder-2"},
+		},
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reader := &fakeStateHistoryReader{frames: map[string]*data.Frame{
+		"rule-1": annotationHistoryFrame(start, start.Add(time.Minute), start.Add(2*time.Minute)),
+		"rule-2": annotationHistoryFrame(start),
+	}}
+	s := newService(nil, nil, nil, nil, store, nil, acimpl.ProvideAccessControlTest())
+
+	folderScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder-1")
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{1: {
+			accesscontrol.ActionAlertingRuleRead: {folderScope},
+			dashboards.ActionFoldersRead:         {folderScope},
+		}},
+	})
+	req := &backend.QueryDataRequest{PluginContext: backend.PluginContext{OrgID: 1}}
+	query := backend.DataQuery{
+		TimeRange: backend.TimeRange{From: start, To: start.Add(time.Hour)},
+		JSON:      []byte(`{"limit": 2}`),
+	}
+
+	t.Run("requires the state history of alerting", func(t *testing.T) {
+		res := s.doStateHistoryQuery(ctx, req, query)
+		require.ErrorIs(t, res.Error, errAlertingUnavailable)
+	})
+
+	s.SetStateHistoryReader(reader)
+
+	t.Run("returns the newest transitions of readable rules", func(t *testing.T) {
+		res := s.doStateHistoryQuery(ctx, req, query)
+		require.NoError(t, res.Error)
+		require.Len(t, reader.queries, 1)
+		require.Equal(t, "rule-1", reader.queries[0].RuleUID)
+		require.Equal(t, 2, reader.queries[0].Limit)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, start.Add(time.Minute), frame.Fields[0].At(0))
+		require.Equal(t, "rule-1", frame.Fields[1].At(0))
+		require.Equal(t, "Normal", frame.Fields[3].At(0))
+		require.Equal(t, "Alerting", frame.Fields[4].At(0))
+	})
+}
+
+func TestStateTransitions(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("reads the annotations backend", func(t *testing.T) {
+		transitions, err := stateTransitions(annotationHistoryFrame(start))
+		require.NoError(t, err)
+		require.Equal(t, []stateTransition{{time: start, previous: "Normal", current: "Alerting", text: "{job=api}"}}, transitions)
+	})
+
+	t.Run("reads the Loki backend", func(t *testing.T) {
+		frame := data.NewFrame("states",
+			data.NewField("time", nil, []time.Time{start}),
+			data.NewField("line", nil, []json.RawMessage{json.RawMessage(`{"previous":"Normal","current":"Alerting","ruleUID":"rule-1","labels":{"job":"api"}}`)}),
+			data.NewField("labels", nil, []json.RawMessage{json.RawMessage(`{}`)}),
+		)
+		transitions, err := stateTransitions(frame)
+		require.NoError(t, err)
+		require.Equal(t, []stateTransition{{time: start, previous: "Normal", current: "Alerting", text: "{job=api}"}}, transitions)
+	})
+}
+
+func TestStateHistoryTimeSeries(t *testing.T) {
+	rule := &ngmodels.AlertRule{ID: 1, UID: "rule-1", Title: "Flapping"}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transitions := []ruleStateTransition{
+		{rule: rule, stateTransition: stateTransition{time: start}},
+		{rule: rule, stateTransition: stateTransition{time: start.Add(2 * time.Minute)}},
+	}
+
+	frames := stateHistoryTimeSeries(transitions)
+	require.Len(t, frames, 1)
+	require.Equal(t, 2, frames[0].Rows())
+	require.Equal(t, data.Labels{"ruleUID": "rule-1", "ruleTitle": "Flapping"}, frames[0].Fields[1].Labels)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -14,8 +15,12 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ngac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/unifiedSearch"
@@ -53,17 +58,28 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, searchNext, store, features)
+func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles,
+	ruleStore *ngstore.DBstore, annotationsRepo annotations.Repository, ac accesscontrol.AccessControl) *Service {
+	var rules AlertRuleStore
+	if ruleStore != nil {
+		rules = ruleStore
+	}
+	return newService(search, searchNext, store, features, rules, annotationsRepo, ac)
 }
 
-func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles,
+	ruleStore AlertRuleStore, annotationsRepo annotations.Repository, ac accesscontrol.AccessControl) *Service {
 	s := &Service{
-		search:     search,
-		searchNext: searchNext,
-		store:      store,
-		log:        log.New("grafanads"),
-		features:   features,
+		search:      search,
+		searchNext:  searchNext,
+		store:       store,
+		log:         log.New("grafanads"),
+		features:    features,
+		ruleStore:   ruleStore,
+		annotations: annotationsRepo,
+	}
+	if ac != nil {
+		s.ruleAccess = ngac.NewRuleService(ac)
 	}
 
 	return s
@@ -76,6 +92,14 @@ type Service struct {
 	store      store.StorageService
 	log        log.Logger
 	features   featuremgmt.FeatureToggles
+
+	ruleStore   AlertRuleStore
+	ruleAccess  ruleAccessControlService
+	annotations annotations.Repository
+
+	stateReaderMtx sync.RWMutex
+	stateReader    AlertStateReader
+	historyReader  StateHistoryReader
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -103,6 +127,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeAlertInstances:
+			response.Responses[q.RefID] = s.doAlertInstancesQuery(ctx, req, q)
+		case queryTypeStateHistory:
+			response.Responses[q.RefID] = s.doStateHistoryQuery(ctx, req, q)
+		case queryTypeAnnotationList:
+			response.Responses[q.RefID] = s.doAnnotationsQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -17,6 +17,15 @@ const (
 	// currently only .csv files are supported,
 	// other file types will eventually be supported (parquet, etc)
 	queryTypeRead = "read"
+
+	// queryTypeAlertInstances returns the current instances of the alert rules the user can read
+	queryTypeAlertInstances = "alertInstances"
+
+	// queryTypeStateHistory returns the state transitions of the alert rules the user can read
+	queryTypeStateHistory = "stateHistory"
+
+	// queryTypeAnnotationList returns annotations filtered by tags and dashboard
+	queryTypeAnnotationList = "annotationList"
 )
 
 type listQueryModel struct {
@@ -25,3 +34,29 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+type alertRulesFilter struct {
+	RuleUIDs   []string `json:"ruleUIDs,omitempty"`
+	FolderUIDs []string `json:"folderUIDs,omitempty"`
+}
+
+type alertInstancesQueryModel struct {
+	alertRulesFilter
+	States []string `json:"states,omitempty"`
+}
+
+type stateHistoryQueryModel struct {
+	alertRulesFilter
+	// Format is either "table" or "timeseries"
+	Format string `json:"format,omitempty"`
+	Limit  int64  `json:"limit,omitempty"`
+}
+
+type annotationsQueryModel struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	MatchAny     bool     `json:"matchAny,omitempty"`
+	// Type is either "alert", "annotation" or empty for both
+	Type  string `json:"type,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
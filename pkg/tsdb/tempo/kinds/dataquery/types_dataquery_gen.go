@@ -9,6 +9,12 @@
 
 package dataquery
 
+// Defines values for MetricsQueryType.
+const (
+	MetricsQueryTypeInstant MetricsQueryType = "instant"
+	MetricsQueryTypeRange   MetricsQueryType = "range"
+)
+
 // Defines values for SearchStreamingState.
 const (
 	SearchStreamingStateDone      SearchStreamingState = "done"
@@ -66,6 +72,9 @@ type DataQuery struct {
 	RefId string `json:"refId"`
 }
 
+// The type of the metrics query, range or instant
+type MetricsQueryType string
+
 // The state of the TraceQL streaming search query
 type SearchStreamingState string
 
@@ -96,6 +105,9 @@ type TempoQuery struct {
 	// @deprecated Define the maximum duration to select traces. Use duration format, for example: 1.2s, 100ms
 	MaxDuration *string `json:"maxDuration,omitempty"`
 
+	// The type of the metrics query, range or instant. TraceQL queries are only run as metrics queries when it is set
+	MetricsQueryType *MetricsQueryType `json:"metricsQueryType,omitempty"`
+
 	// @deprecated Define the minimum duration to select traces. Use duration format, for example: 1.2s, 100ms
 	MinDuration *string `json:"minDuration,omitempty"`
 
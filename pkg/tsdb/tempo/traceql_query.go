@@ -0,0 +1,267 @@
+package tempo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/tracing"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const metricsQueryRangePath = "/api/metrics/query_range"
+
+func (s *Service) runTraceQlQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+	ctxLogger.Debug("Running TraceQL metrics query", "function", logEntrypoint())
+
+	ctx, span := tracing.DefaultTracer().Start(ctx, "datasource.tempo.runTraceQlQuery", trace.WithAttributes(
+		attribute.String("queryType", query.QueryType),
+	))
+	defer span.End()
+
+	result := &backend.DataResponse{}
+
+	model := &dataquery.TempoQuery{}
+	err := json.Unmarshal(query.JSON, model)
+	if err != nil {
+		ctxLogger.Error("Failed to unmarshall Tempo query model", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	if model.Query == nil || *model.Query == "" {
+		err := fmt.Errorf("query is required")
+		ctxLogger.Error("Failed to validate model query", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	// TraceQL searches are run by the frontend, only queries that are marked as metrics queries are run here
+	if model.MetricsQueryType == nil {
+		err := fmt.Errorf("TraceQL search queries are not supported in the backend, set the metrics query type to run a metrics query")
+		ctxLogger.Error("Failed to validate model query", "error", err, "function", logEntrypoint())
+		return result, err
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		ctxLogger.Error("Failed to get datasource information", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	instant := *model.MetricsQueryType == dataquery.MetricsQueryTypeInstant
+	span.SetAttributes(attribute.Bool("instant", instant))
+
+	request, err := s.createMetricsQuery(ctx, dsInfo, model, query.TimeRange, instant)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result, err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		ctxLogger.Error("Failed to send request to Tempo", "error", err, "function", logEntrypoint())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result, fmt.Errorf("failed get to tempo: %w", err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			ctxLogger.Error("Failed to close response body", "error", err, "function", logEntrypoint())
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Error("Failed to read response body", "error", err, "function", logEntrypoint())
+		return &backend.DataResponse{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		ctxLogger.Error("Failed to run TraceQL metrics query", "status", resp.Status, "function", logEntrypoint())
+		result.Error = fmt.Errorf("failed to run TraceQL metrics query: %s Status: %s Body: %s", *model.Query, resp.Status, string(body))
+		result.Status = backend.Status(resp.StatusCode)
+		span.RecordError(result.Error)
+		span.SetStatus(codes.Error, result.Error.Error())
+		return result, nil
+	}
+
+	queryResp := &tempopb.QueryRangeResponse{}
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(body), queryResp); err != nil {
+		ctxLogger.Error("Failed to unmarshal TraceQL metrics response", "error", err, "function", logEntrypoint())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return &backend.DataResponse{}, fmt.Errorf("failed to unmarshal TraceQL metrics response: %w", err)
+	}
+
+	var frames []*data.Frame
+	if instant {
+		frames = transformInstantMetricsResponse(*model.Query, queryResp)
+	} else {
+		frames = transformRangeMetricsResponse(*model.Query, queryResp)
+	}
+	for _, frame := range frames {
+		frame.RefID = query.RefID
+	}
+
+	result.Frames = frames
+	ctxLogger.Debug("Successfully ran TraceQL metrics query", "function", logEntrypoint())
+	return result, nil
+}
+
+// createMetricsQuery builds the request for Tempo's query_range endpoint. Instant
+// queries are sent as a range query with a single step spanning the whole time
+// range, which works with every Tempo version that supports TraceQL metrics.
+func (s *Service) createMetricsQuery(ctx context.Context, dsInfo *Datasource, model *dataquery.TempoQuery, timeRange backend.TimeRange, instant bool) (*http.Request, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+
+	params := url.Values{}
+	params.Set("q", *model.Query)
+	params.Set("start", strconv.FormatInt(timeRange.From.Unix(), 10))
+	params.Set("end", strconv.FormatInt(timeRange.To.Unix(), 10))
+
+	if instant {
+		step := timeRange.To.Sub(timeRange.From)
+		if step < time.Second {
+			step = time.Second
+		}
+		params.Set("step", step.String())
+	} else if model.Step != nil && *model.Step != "" {
+		params.Set("step", *model.Step)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", dsInfo.URL+metricsQueryRangePath+"?"+params.Encode(), nil)
+	if err != nil {
+		ctxLogger.Error("Failed to create request", "error", err, "function", logEntrypoint())
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func transformRangeMetricsResponse(query string, resp *tempopb.QueryRangeResponse) []*data.Frame {
+	frames := make([]*data.Frame, 0, len(resp.Series))
+	for _, series := range resp.Series {
+		if series == nil {
+			continue
+		}
+
+		name, labels := metricsSeriesName(query, series, len(resp.Series))
+		timeField := data.NewFieldFromFieldType(data.FieldTypeTime, len(series.Samples))
+		timeField.Name = data.TimeSeriesTimeFieldName
+		valueField := data.NewFieldFromFieldType(data.FieldTypeFloat64, len(series.Samples))
+		valueField.Name = data.TimeSeriesValueFieldName
+		valueField.Labels = labels
+		valueField.Config = &data.FieldConfig{DisplayNameFromDS: name}
+
+		for i, sample := range series.Samples {
+			timeField.Set(i, time.UnixMilli(sample.TimestampMs).UTC())
+			valueField.Set(i, sample.Value)
+		}
+
+		frame := data.NewFrame(name, timeField, valueField)
+		frame.Meta = &data.FrameMeta{
+			Type:                   data.FrameTypeTimeSeriesMulti,
+			TypeVersion:            data.FrameTypeVersion{0, 1},
+			PreferredVisualization: data.VisTypeGraph,
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// transformInstantMetricsResponse reduces every series to its latest sample.
+func transformInstantMetricsResponse(query string, resp *tempopb.QueryRangeResponse) []*data.Frame {
+	frames := make([]*data.Frame, 0, len(resp.Series))
+	for _, series := range resp.Series {
+		if series == nil || len(series.Samples) == 0 {
+			continue
+		}
+
+		name, labels := metricsSeriesName(query, series, len(resp.Series))
+		sample := series.Samples[len(series.Samples)-1]
+
+		timeField := data.NewField(data.TimeSeriesTimeFieldName, nil, []time.Time{time.UnixMilli(sample.TimestampMs).UTC()})
+		valueField := data.NewField(data.TimeSeriesValueFieldName, labels, []float64{sample.Value})
+		valueField.Config = &data.FieldConfig{DisplayNameFromDS: name}
+
+		frame := data.NewFrame(name, timeField, valueField)
+		frame.Meta = &data.FrameMeta{
+			Type:        data.FrameTypeNumericMulti,
+			TypeVersion: data.FrameTypeVersion{0, 1},
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// metricsSeriesName mirrors the naming used by the frontend: a single series
+// falls back to the query, a single label uses its value and multiple labels
+// are rendered as a label set.
+func metricsSeriesName(query string, series *tempopb.TimeSeries, seriesCount int) (string, data.Labels) {
+	labels := data.Labels{}
+	keys := make([]string, 0, len(series.Labels))
+	for _, label := range series.Labels {
+		labels[label.Key] = metricsValueToString(label.Value)
+		keys = append(keys, label.Key)
+	}
+
+	name := ""
+	if seriesCount == 1 {
+		name = query
+	}
+
+	switch len(keys) {
+	case 0:
+	case 1:
+		name = labels[keys[0]]
+	default:
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s=%s", key, labels[key]))
+		}
+		name = "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	return name, labels
+}
+
+func metricsValueToString(value *v1.AnyValue) string {
+	if value == nil {
+		return ""
+	}
+
+	switch v := value.GetValue().(type) {
+	case *v1.AnyValue_StringValue:
+		return v.StringValue
+	case *v1.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *v1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'f', -1, 64)
+	case *v1.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	default:
+		return ""
+	}
+}
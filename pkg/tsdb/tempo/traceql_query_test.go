@@ -0,0 +1,103 @@
+package tempo
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metricsResponse = `{
+  "series": [
+    {
+      "labels": [{"key": "resource.service.name", "value": {"stringValue": "checkout"}}],
+      "samples": [
+        {"timestampMs": "1700000000000", "value": 1.5},
+        {"timestampMs": "1700000060000", "value": 2.5}
+      ],
+      "promLabels": "{resource.service.name=\"checkout\"}"
+    },
+    {
+      "labels": [{"key": "resource.service.name", "value": {"stringValue": "cart"}}],
+      "samples": [
+        {"timestampMs": "1700000000000", "value": 3}
+      ]
+    }
+  ],
+  "metrics": {"inspectedTraces": 10}
+}`
+
+func TestTraceQlMetrics(t *testing.T) {
+	service := &Service{logger: backend.NewLoggerWith("logger", "tempo-test")}
+	timeRange := backend.TimeRange{From: time.Unix(1700000000, 0), To: time.Unix(1700003600, 0)}
+	query := "{} | rate() by (resource.service.name)"
+
+	t.Run("createMetricsQuery for range query uses step", func(t *testing.T) {
+		step := "30s"
+		req, err := service.createMetricsQuery(context.Background(), &Datasource{}, &dataquery.TempoQuery{Query: &query, Step: &step}, timeRange, false)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/metrics/query_range", req.URL.Path)
+		assert.Equal(t, query, req.URL.Query().Get("q"))
+		assert.Equal(t, "1700000000", req.URL.Query().Get("start"))
+		assert.Equal(t, "1700003600", req.URL.Query().Get("end"))
+		assert.Equal(t, "30s", req.URL.Query().Get("step"))
+	})
+
+	t.Run("createMetricsQuery for instant query uses whole range as step", func(t *testing.T) {
+		req, err := service.createMetricsQuery(context.Background(), &Datasource{}, &dataquery.TempoQuery{Query: &query}, timeRange, true)
+		require.NoError(t, err)
+		assert.Equal(t, "1h0m0s", req.URL.Query().Get("step"))
+	})
+
+	t.Run("search queries are not run as metrics queries", func(t *testing.T) {
+		search := "{ resource.service.name = \"checkout\" }"
+		_, err := service.runTraceQlQuery(context.Background(), backend.PluginContext{}, backend.DataQuery{
+			RefID:     "A",
+			QueryType: string(dataquery.TempoQueryTypeTraceql),
+			JSON:      []byte(`{"query": ` + strconv.Quote(search) + `}`),
+		})
+		require.ErrorContains(t, err, "TraceQL search queries are not supported in the backend")
+	})
+
+	resp := &tempopb.QueryRangeResponse{}
+	require.NoError(t, (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader([]byte(metricsResponse)), resp))
+
+	t.Run("range response is converted to time series frames", func(t *testing.T) {
+		frames := transformRangeMetricsResponse(query, resp)
+		require.Len(t, frames, 2)
+
+		frame := frames[0]
+		assert.Equal(t, data.FrameTypeTimeSeriesMulti, frame.Meta.Type)
+		require.Equal(t, 2, frame.Rows())
+		assert.Equal(t, time.UnixMilli(1700000060000).UTC(), frame.Fields[0].At(1))
+		assert.Equal(t, 2.5, frame.Fields[1].At(1))
+		assert.Equal(t, data.Labels{"resource.service.name": "checkout"}, frame.Fields[1].Labels)
+		assert.Equal(t, "checkout", frame.Fields[1].Config.DisplayNameFromDS)
+	})
+
+	t.Run("instant response keeps the latest sample of each series", func(t *testing.T) {
+		frames := transformInstantMetricsResponse(query, resp)
+		require.Len(t, frames, 2)
+		assert.Equal(t, data.FrameTypeNumericMulti, frames[0].Meta.Type)
+		require.Equal(t, 1, frames[0].Rows())
+		assert.Equal(t, 2.5, frames[0].Fields[1].At(0))
+		assert.Equal(t, 3.0, frames[1].Fields[1].At(0))
+	})
+
+	t.Run("series without labels fall back to the query as name", func(t *testing.T) {
+		frames := transformRangeMetricsResponse(query, &tempopb.QueryRangeResponse{
+			Series: []*tempopb.TimeSeries{{Samples: []tempopb.Sample{{TimestampMs: 1, Value: 1}}}},
+		})
+		require.Len(t, frames, 1)
+		assert.Equal(t, query, frames[0].Name)
+	})
+}
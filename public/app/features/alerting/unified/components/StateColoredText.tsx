@@ -25,6 +25,9 @@ const getStyles = (theme: GrafanaTheme2) => ({
   [PromAlertingRuleState.Firing]: css({
     color: theme.colors.error.text,
   }),
+  [PromAlertingRuleState.Suppressed]: css({
+    color: theme.colors.info.text,
+  }),
   neutral: css({
     color: theme.colors.text.secondary,
   }),
@@ -27,18 +27,21 @@ export function RuleListIcon({
     [PromAlertingRuleState.Inactive]: 'check-circle',
     [PromAlertingRuleState.Pending]: 'circle',
     [PromAlertingRuleState.Firing]: 'exclamation-circle',
+    [PromAlertingRuleState.Suppressed]: 'bell-slash',
   };
 
-  const color: Record<PromAlertingRuleState, 'success' | 'error' | 'warning'> = {
+  const color: Record<PromAlertingRuleState, 'success' | 'error' | 'warning' | 'secondary'> = {
     [PromAlertingRuleState.Inactive]: 'success',
     [PromAlertingRuleState.Pending]: 'warning',
     [PromAlertingRuleState.Firing]: 'error',
+    [PromAlertingRuleState.Suppressed]: 'secondary',
   };
 
   const stateNames: Record<PromAlertingRuleState, string> = {
     [PromAlertingRuleState.Inactive]: 'Normal',
     [PromAlertingRuleState.Pending]: 'Pending',
     [PromAlertingRuleState.Firing]: 'Firing',
+    [PromAlertingRuleState.Suppressed]: 'Suppressed',
   };
 
   let iconName: IconName = state ? icons[state] : 'circle';
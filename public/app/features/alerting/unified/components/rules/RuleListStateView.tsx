@@ -23,6 +23,7 @@ export const RuleListStateView = ({ namespaces }: Props) => {
       [PromAlertingRuleState.Firing]: [],
       [PromAlertingRuleState.Inactive]: [],
       [PromAlertingRuleState.Pending]: [],
+      [PromAlertingRuleState.Suppressed]: [],
     };
 
     namespaces.forEach((namespace) =>
@@ -53,6 +54,13 @@ export const RuleListStateView = ({ namespaces }: Props) => {
           rules={groupedRules[PromAlertingRuleState.Pending]}
         />
       )}
+      {(!filters.alertState || filters.alertState === PromAlertingRuleState.Suppressed) && (
+        <RuleListStateSection
+          defaultCollapsed={filters.alertState !== PromAlertingRuleState.Suppressed}
+          state={PromAlertingRuleState.Suppressed}
+          rules={groupedRules[PromAlertingRuleState.Suppressed]}
+        />
+      )}
       {(!filters.alertState || filters.alertState === PromAlertingRuleState.Inactive) && (
         <RuleListStateSection
           defaultCollapsed={filters.alertState !== PromAlertingRuleState.Inactive}
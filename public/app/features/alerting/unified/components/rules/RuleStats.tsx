@@ -22,6 +22,7 @@ const emptyStats: Required<AlertGroupTotals> = {
   alerting: 0,
   [PromAlertingRuleState.Pending]: 0,
   [PromAlertingRuleState.Inactive]: 0,
+  [PromAlertingRuleState.Suppressed]: 0,
   paused: 0,
   error: 0,
   nodata: 0,
@@ -81,7 +82,7 @@ function statsFromNamespaces(namespaces: CombinedRuleNamespace[]): AlertGroupTot
 export function totalFromStats(stats: AlertGroupTotals): number {
   // countable stats will pick only the states that indicate a single rule – health indicators like "error" and "nodata" should
   // not be counted because they are already counted by their state
-  const countableStats = pick(stats, ['alerting', 'pending', 'inactive', 'recording']);
+  const countableStats = pick(stats, ['alerting', 'pending', 'inactive', 'suppressed', 'recording']);
   const total = sum(Object.values(countableStats));
 
   return total;
@@ -134,6 +135,12 @@ export function getComponentsFromStats(
     );
   }
 
+  if (stats[AlertInstanceTotalState.Suppressed]) {
+    statsComponents.push(
+      <Badge color="blue" key="suppressed" text={`${stats[AlertInstanceTotalState.Suppressed]} suppressed`} />
+    );
+  }
+
   if (stats[AlertInstanceTotalState.Normal] && stats.paused) {
     statsComponents.push(
       <Badge
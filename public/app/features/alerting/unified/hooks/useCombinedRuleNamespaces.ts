@@ -354,6 +354,7 @@ export function calculateGroupTotals(group: Pick<RuleGroup, 'rules' | 'totals'>)
     nodata: countsByHealth.nodata,
     inactive: countsByState[PromAlertingRuleState.Inactive],
     pending: countsByState[PromAlertingRuleState.Pending],
+    suppressed: countsByState[PromAlertingRuleState.Suppressed],
     recording: recordingCount,
   };
 }
@@ -7,6 +7,7 @@ import {
   SceneQueryRunner,
   SceneTimeRange,
   SceneVariableSet,
+  TextBoxVariable,
   VizPanel,
   VizPanelState,
 } from '@grafana/scenes';
@@ -26,7 +27,21 @@ beforeEach(() => {
 
 describe('ShareAlerts', () => {
   describe('UnsupportedTemplateVariablesAlert', () => {
-    it('should render alert when hasPermission and the dashboard has template vars', async () => {
+    it('should render alert when hasPermission and the dashboard has unsupported template vars', async () => {
+      await setup(undefined, {
+        $variables: new SceneVariableSet({
+          variables: [
+            new TextBoxVariable({
+              name: 'textVar',
+              value: 'test',
+            }),
+          ],
+        }),
+      });
+
+      expect(await screen.findByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
+    });
+    it('should not render alert when hasPermission and the dashboard has supported template vars', async () => {
       await setup(undefined, {
         $variables: new SceneVariableSet({
           variables: [
@@ -40,7 +55,7 @@ describe('ShareAlerts', () => {
         }),
       });
 
-      expect(await screen.findByTestId(selectors.TemplateVariablesWarningAlert)).toBeInTheDocument();
+      expect(screen.queryByTestId(selectors.TemplateVariablesWarningAlert)).not.toBeInTheDocument();
     });
     it('should not render alert when hasPermission but the dashboard has no template vars', async () => {
       await setup();
@@ -4,6 +4,7 @@ import { UnsupportedDataSourcesAlert } from 'app/features/dashboard/components/S
 import { UnsupportedTemplateVariablesAlert } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ModalAlerts/UnsupportedTemplateVariablesAlert';
 import {
   isEmailSharingEnabled,
+  isUnsupportedTemplateVariableType,
   PublicDashboard,
   PublicDashboardShareType,
 } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
@@ -18,7 +19,9 @@ export default function ShareAlerts({ publicDashboard }: { publicDashboard?: Pub
   const { dashboard } = useShareDrawerContext();
   const hasWritePermissions = contextSrv.hasPermission(AccessControlAction.DashboardsPublicWrite);
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const hasTemplateVariables = !!dashboard.state.$variables?.state.variables.some((variable) =>
+    isUnsupportedTemplateVariableType(variable.state.type)
+  );
 
   return (
     <>
@@ -53,7 +53,7 @@ describe('Alerts', () => {
     expect(screen.queryByTestId(selectors.NoUpsertPermissionsWarningAlert)).toBeInTheDocument();
   });
   it('when dashboard has template variables, warning is shown', async () => {
-    jest.spyOn(sharePublicDashboardUtils, 'dashboardHasTemplateVariables').mockReturnValue(true);
+    jest.spyOn(sharePublicDashboardUtils, 'dashboardHasUnsupportedTemplateVariables').mockReturnValue(true);
 
     await buildAndRenderScenario({
       overrides: {
@@ -6,7 +6,10 @@ import { useStyles2 } from '@grafana/ui';
 import { contextSrv } from 'app/core/core';
 import { useDeletePublicDashboardMutation } from 'app/features/dashboard/api/publicDashboardApi';
 import { ConfigPublicDashboardBase } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/ConfigPublicDashboard/ConfigPublicDashboard';
-import { PublicDashboard } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
+import {
+  isUnsupportedTemplateVariableType,
+  PublicDashboard,
+} from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/SharePublicDashboardUtils';
 import { AccessControlAction } from 'app/types';
 
 import { shareDashboardType } from '../../../dashboard/components/ShareModal/utils';
@@ -30,7 +33,9 @@ export function ConfigPublicDashboard({ model, publicDashboard, isGetLoading }:
   const dashboard = getDashboardSceneFor(model);
   const { isDirty } = dashboard.useState();
   const [deletePublicDashboard] = useDeletePublicDashboardMutation();
-  const hasTemplateVariables = (dashboard.state.$variables?.state.variables.length ?? 0) > 0;
+  const hasTemplateVariables = !!dashboard.state.$variables?.state.variables.some((variable) =>
+    isUnsupportedTemplateVariableType(variable.state.type)
+  );
   const unsupportedDataSources = useUnsupportedDatasources(dashboard);
   const timeRangeState = sceneGraph.getTimeRange(model);
   const timeRange = timeRangeState.useState();
@@ -15,7 +15,7 @@ import { AccessControlAction, useSelector } from 'app/types';
 import { NoUpsertPermissionsAlert } from '../ModalAlerts/NoUpsertPermissionsAlert';
 import { UnsupportedDataSourcesAlert } from '../ModalAlerts/UnsupportedDataSourcesAlert';
 import { UnsupportedTemplateVariablesAlert } from '../ModalAlerts/UnsupportedTemplateVariablesAlert';
-import { dashboardHasTemplateVariables } from '../SharePublicDashboardUtils';
+import { dashboardHasUnsupportedTemplateVariables } from '../SharePublicDashboardUtils';
 import { useGetUnsupportedDataSources } from '../useGetUnsupportedDataSources';
 
 import { AcknowledgeCheckboxes } from './AcknowledgeCheckboxes';
@@ -64,7 +64,7 @@ export const CreatePublicDashboardBase = ({
         </p>
         <p className={styles.description}>
           <Trans i18nKey="public-dashboard.create-page.unsupported-features-desc">
-            Currently, we don’t support template variables or frontend data sources
+            Currently, we don’t support text box, ad hoc filter or data source variables, or frontend data sources
           </Trans>
         </p>
       </div>
@@ -96,7 +96,7 @@ export function CreatePublicDashboard({ hasError }: { hasError?: boolean }) {
   const dashboardState = useSelector((store) => store.dashboard);
   const dashboard = dashboardState.getModel()!;
   const { unsupportedDataSources } = useGetUnsupportedDataSources(dashboard);
-  const hasTemplateVariables = dashboardHasTemplateVariables(dashboard.getVariables());
+  const hasTemplateVariables = dashboardHasUnsupportedTemplateVariables(dashboard.getVariables());
 
   return (
     <CreatePublicDashboardBase
@@ -7,7 +7,7 @@ import { PanelModel } from 'app/features/dashboard/state/PanelModel';
 
 import {
   PublicDashboard,
-  dashboardHasTemplateVariables,
+  dashboardHasUnsupportedTemplateVariables,
   publicDashboardPersisted,
   generatePublicDashboardUrl,
   getUnsupportedDashboardDatasources,
@@ -36,16 +36,22 @@ jest.mock('@grafana/runtime/src/services/dataSourceSrv', () => {
   };
 });
 
-describe('dashboardHasTemplateVariables', () => {
-  it('false', () => {
+describe('dashboardHasUnsupportedTemplateVariables', () => {
+  it('false when there are no variables', () => {
     let variables: TypedVariableModel[] = [];
-    expect(dashboardHasTemplateVariables(variables)).toBe(false);
+    expect(dashboardHasUnsupportedTemplateVariables(variables)).toBe(false);
   });
 
-  it('true', () => {
+  it('false when all variables are supported', () => {
+    //@ts-ignore
+    let variables: TypedVariableModel[] = [{ type: 'query' }, { type: 'custom' }, { type: 'interval' }];
+    expect(dashboardHasUnsupportedTemplateVariables(variables)).toBe(false);
+  });
+
+  it.each(['textbox', 'adhoc', 'datasource'])('true when a %s variable is used', (type) => {
     //@ts-ignore
-    let variables: TypedVariableModel[] = ['a'];
-    expect(dashboardHasTemplateVariables(variables)).toBe(true);
+    let variables: TypedVariableModel[] = [{ type: 'query' }, { type }];
+    expect(dashboardHasUnsupportedTemplateVariables(variables)).toBe(true);
   });
 });
 
@@ -42,9 +42,16 @@ export interface SessionUser {
   totalDashboards: number;
 }
 
+// Types of template variables whose values public dashboards can't resolve on the server
+const unsupportedTemplateVariableTypes: string[] = ['textbox', 'adhoc', 'datasource'];
+
+export const isUnsupportedTemplateVariableType = (type: string): boolean => {
+  return unsupportedTemplateVariableTypes.includes(type);
+};
+
 // Instance methods
-export const dashboardHasTemplateVariables = (variables: TypedVariableModel[]): boolean => {
-  return variables.length > 0;
+export const dashboardHasUnsupportedTemplateVariables = (variables: TypedVariableModel[]): boolean => {
+  return variables.some((variable) => isUnsupportedTemplateVariableType(variable.type));
 };
 
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
@@ -25,6 +25,7 @@ export enum TestDataQueryType {
   RandomWalkTable = 'random_walk_table',
   RandomWalkWithError = 'random_walk_with_error',
   RawFrame = 'raw_frame',
+  Replay = 'replay',
   ServerError500 = 'server_error_500',
   Simulation = 'simulation',
   SlowQuery = 'slow_query',
@@ -20,6 +20,9 @@ export enum GrafanaQueryType {
   Read = 'read',
   Search = 'search',
   SearchNext = 'searchNext',
+  AlertInstances = 'alertInstances',
+  StateHistory = 'stateHistory',
+  AnnotationList = 'annotationList',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -33,6 +36,14 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  ruleUIDs?: string[]; // for alert instances and state history
+  folderUIDs?: string[]; // for alert instances and state history
+  states?: string[]; // for alert instances
+  format?: 'table' | 'timeseries'; // for state history
+  dashboardUID?: string; // for annotation list
+  tags?: string[]; // for annotation list
+  matchAny?: boolean; // for annotation list
+  limit?: number; // for state history and annotation list
 }
 
 export interface GrafanaQueryFile {
@@ -53,10 +53,15 @@ composableKinds: DataQuery: {
 					tableType?: #SearchTableType
 					// For metric queries, the step size to use
 					step?: string
+					// The type of the metrics query, range or instant. TraceQL queries are only run as metrics queries when it is set
+					metricsQueryType?: #MetricsQueryType
 				} @cuetsy(kind="interface") @grafana(TSVeneer="type")
 
 				#TempoQueryType: "traceql" | "traceqlSearch" | "serviceMap" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
 
+				// The type of the metrics query, range or instant
+				#MetricsQueryType: "range" | "instant" @cuetsy(kind="enum")
+
 				// The state of the TraceQL streaming search query
 				#SearchStreamingState: "pending" | "streaming" | "done" | "error" @cuetsy(kind="enum")
 
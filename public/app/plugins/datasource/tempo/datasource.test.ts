@@ -25,12 +25,13 @@ import {
   TemplateSrv,
   DataSourceSrv,
   BackendSrv,
+  DataSourceWithBackend,
 } from '@grafana/runtime';
 import { BarGaugeDisplayMode, DataQuery, TableCellDisplayMode } from '@grafana/schema';
 
 import { TempoVariableQueryType } from './VariableQueryEditor';
 import { createFetchResponse } from './_importedDependencies/test/helpers/createFetchResponse';
-import { TraceqlSearchScope } from './dataquery.gen';
+import { MetricsQueryType, TraceqlSearchScope } from './dataquery.gen';
 import {
   TempoDatasource,
   buildExpr,
@@ -131,6 +132,20 @@ describe('Tempo data source', () => {
       expect(handleStreamingQuery).toHaveBeenCalledTimes(1);
       expect(request).toHaveBeenCalledTimes(1);
     });
+
+    it('for instant traceql metrics queries in the backend', async () => {
+      const backendQuery = jest.spyOn(DataSourceWithBackend.prototype, 'query').mockReturnValue(of({ data: [] }));
+      const ds = new TempoDatasource(defaultSettings, templateSrv);
+      const target = {
+        refId: 'refid1',
+        queryType: 'traceql',
+        query: '{} | rate()',
+        metricsQueryType: MetricsQueryType.Instant,
+      };
+      await lastValueFrom(ds.query({ targets: [target], range } as DataQueryRequest<TempoQuery>));
+      expect(backendQuery).toHaveBeenCalledWith(expect.objectContaining({ targets: [target] }));
+      backendQuery.mockRestore();
+    });
   });
 
   it('returns empty response when traceId is empty', async () => {
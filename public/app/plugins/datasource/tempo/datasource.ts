@@ -42,7 +42,7 @@ import {
 } from './SearchTraceQLEditor/utils';
 import { TempoVariableQuery, TempoVariableQueryType } from './VariableQueryEditor';
 import { PrometheusDatasource, PromQuery } from './_importedDependencies/datasources/prometheus/types';
-import { SearchTableType, TraceqlFilter, TraceqlSearchScope } from './dataquery.gen';
+import { MetricsQueryType, SearchTableType, TraceqlFilter, TraceqlSearchScope } from './dataquery.gen';
 import {
   defaultTableFilter,
   durationMetric,
@@ -359,7 +359,12 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
               grafana_version: config.buildInfo.version,
               query: queryValue ?? '',
             });
-            subQueries.push(this.handleTraceQlMetricsQuery(options, queryValue));
+            if (appliedQuery.metricsQueryType === MetricsQueryType.Instant) {
+              // Instant queries are only supported by the backend
+              subQueries.push(super.query({ ...options, targets: [targets.traceql[0]] }));
+            } else {
+              subQueries.push(this.handleTraceQlMetricsQuery(options, queryValue));
+            }
           } else {
             reportInteraction('grafana_traces_traceql_queried', {
               datasourceType: 'tempo',
@@ -1,5 +1,5 @@
 import { css } from '@emotion/css';
-import { defaults } from 'lodash';
+import { defaults, omit } from 'lodash';
 import { useState } from 'react';
 
 import { GrafanaTheme2, QueryEditorProps } from '@grafana/data';
@@ -7,6 +7,7 @@ import { config, reportInteraction } from '@grafana/runtime';
 import { Button, InlineLabel, useStyles2 } from '@grafana/ui';
 
 import { generateQueryFromFilters } from '../SearchTraceQLEditor/utils';
+import { MetricsQueryType } from '../dataquery.gen';
 import { TempoDatasource } from '../datasource';
 import { defaultQuery, MyDataSourceOptions, TempoQuery } from '../types';
 
@@ -26,6 +27,18 @@ export function QueryEditor(props: Props) {
     const genQuery = generateQueryFromFilters(query.filters || []);
     return genQuery === query.query || genQuery === '{}';
   });
+  const isMetricsQuery = props.datasource.isTraceQlMetricsQuery(query.query || '');
+
+  // Metrics queries need a metrics query type to run in the backend, for example in alert rules
+  const onChange = (value: TempoQuery) => {
+    const valueIsMetricsQuery = props.datasource.isTraceQlMetricsQuery(value.query || '');
+    if (valueIsMetricsQuery && !value.metricsQueryType) {
+      value = { ...value, metricsQueryType: MetricsQueryType.Range };
+    } else if (!valueIsMetricsQuery && value.metricsQueryType) {
+      value = omit(value, 'metricsQueryType');
+    }
+    props.onChange(value);
+  };
 
   return (
     <>
@@ -49,7 +62,7 @@ export function QueryEditor(props: Props) {
               });
 
               props.onClearResults();
-              props.onChange({
+              onChange({
                 ...query,
                 query: generateQueryFromFilters(query.filters || []),
               });
@@ -64,15 +77,16 @@ export function QueryEditor(props: Props) {
       <TraceQLEditor
         placeholder="Enter a TraceQL query or trace ID (run with Shift+Enter)"
         query={query}
-        onChange={props.onChange}
+        onChange={onChange}
         datasource={props.datasource}
         onRunQuery={props.onRunQuery}
       />
       <div className={styles.optionsContainer}>
         <TempoQueryBuilderOptions
           query={query}
-          onChange={props.onChange}
+          onChange={onChange}
           isStreaming={props.datasource.isStreamingSearchEnabled() ?? false}
+          isMetricsQuery={isMetricsQuery}
         />
       </div>
     </>
@@ -4,7 +4,7 @@ import { EditorField, EditorRow } from '@grafana/experimental';
 import { AutoSizeInput, RadioButtonGroup } from '@grafana/ui';
 
 import { QueryOptionGroup } from '../_importedDependencies/datasources/prometheus/QueryOptionGroup';
-import { SearchTableType } from '../dataquery.gen';
+import { MetricsQueryType, SearchTableType } from '../dataquery.gen';
 import { DEFAULT_LIMIT, DEFAULT_SPSS } from '../datasource';
 import { TempoQuery } from '../types';
 
@@ -12,6 +12,7 @@ interface Props {
   onChange: (value: TempoQuery) => void;
   query: Partial<TempoQuery> & TempoQuery;
   isStreaming: boolean;
+  isMetricsQuery?: boolean;
 }
 
 /**
@@ -27,7 +28,7 @@ const parseIntWithFallback = (val: string, fallback: number) => {
   return isNaN(parsed) ? fallback : parsed;
 };
 
-export const TempoQueryBuilderOptions = React.memo<Props>(({ onChange, query, isStreaming }) => {
+export const TempoQueryBuilderOptions = React.memo<Props>(({ onChange, query, isStreaming, isMetricsQuery }) => {
   if (!query.hasOwnProperty('limit')) {
     query.limit = DEFAULT_LIMIT;
   }
@@ -48,6 +49,9 @@ export const TempoQueryBuilderOptions = React.memo<Props>(({ onChange, query, is
   const onStepChange = (e: React.FormEvent<HTMLInputElement>) => {
     onChange({ ...query, step: e.currentTarget.value });
   };
+  const onMetricsQueryTypeChange = (val: MetricsQueryType) => {
+    onChange({ ...query, metricsQueryType: val });
+  };
 
   const collapsedInfoList = [
     `Limit: ${query.limit || DEFAULT_LIMIT}`,
@@ -56,6 +60,9 @@ export const TempoQueryBuilderOptions = React.memo<Props>(({ onChange, query, is
     `Step: ${query.step || 'auto'}`,
     `Streaming: ${isStreaming ? 'Enabled' : 'Disabled'}`,
   ];
+  if (isMetricsQuery) {
+    collapsedInfoList.push(`Metrics Type: ${query.metricsQueryType === MetricsQueryType.Instant ? 'Instant' : 'Range'}`);
+  }
 
   return (
     <>
@@ -106,6 +113,21 @@ export const TempoQueryBuilderOptions = React.memo<Props>(({ onChange, query, is
               value={query.step}
             />
           </EditorField>
+          {isMetricsQuery && (
+            <EditorField
+              label="Metrics Type"
+              tooltip="Range queries return a value per step, instant queries a single value for the whole time range. Alert rules use this type to run the query."
+            >
+              <RadioButtonGroup
+                options={[
+                  { label: 'Range', value: MetricsQueryType.Range },
+                  { label: 'Instant', value: MetricsQueryType.Instant },
+                ]}
+                value={query.metricsQueryType || MetricsQueryType.Range}
+                onChange={onMetricsQueryTypeChange}
+              />
+            </EditorField>
+          )}
           <EditorField label="Streaming" tooltip={<StreamingTooltip />} tooltipInteractive>
             <div>{isStreaming ? 'Enabled' : 'Disabled'}</div>
           </EditorField>
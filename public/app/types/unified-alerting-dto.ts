@@ -12,6 +12,7 @@ export enum PromAlertingRuleState {
   Firing = 'firing',
   Inactive = 'inactive',
   Pending = 'pending',
+  Suppressed = 'suppressed',
 }
 
 export enum GrafanaAlertState {
@@ -119,6 +119,7 @@ export enum AlertInstanceTotalState {
   Normal = 'inactive',
   NoData = 'nodata',
   Error = 'error',
+  Suppressed = 'suppressed',
 }
 
 export type AlertInstanceTotals = Partial<Record<AlertInstanceTotalState, number>>;